@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/labstack/echo/v4"
@@ -20,7 +21,10 @@ import (
 	"pasargamex/internal/adapter/api/router"
 	"pasargamex/internal/adapter/repository"
 	"pasargamex/internal/domain/service"
+	"pasargamex/internal/infrastructure/crypto"
 	"pasargamex/internal/infrastructure/firebase"
+	"pasargamex/internal/infrastructure/moderation"
+	"pasargamex/internal/infrastructure/search"
 	"pasargamex/internal/infrastructure/storage"
 	"pasargamex/internal/infrastructure/websocket"
 	"pasargamex/internal/usecase"
@@ -117,23 +121,118 @@ func main() {
 	// Gamification repository
 	gamificationRepo := repository.NewFirestoreGamificationRepository(firestoreClient)
 
+	// Account handover checklist repository
+	handoverChecklistRepo := repository.NewFirestoreHandoverChecklistRepository(firestoreClient)
+
+	// Auto-release inspection policies
+	autoReleasePolicyRepo := repository.NewFirestoreAutoReleasePolicyRepository(firestoreClient)
+
+	// Admin-editable fraud rules
+	fraudRuleRepo := repository.NewFirestoreFraudRuleRepository(firestoreClient)
+
+	// Background job leases and run history
+	jobRepo := repository.NewFirestoreJobRepository(firestoreClient)
+
+	// Device and IP security events
+	securityLogRepo := repository.NewFirestoreSecurityLogRepository(firestoreClient)
+
+	// Nightly seller risk scores and trust tiers
+	sellerTrustRepo := repository.NewFirestoreSellerTrustRepository(firestoreClient)
+
+	// Chat violations and repeat offenders
+	chatModerationRepo := repository.NewFirestoreChatModerationRepository(firestoreClient)
+
+	// Velocity rules and per-user/per-device action history
+	velocityRepo := repository.NewFirestoreVelocityRepository(firestoreClient)
+
+	// Sealed voucher codes delivered one per paid order
+	inventoryRepo := repository.NewFirestoreInventoryRepository(firestoreClient)
+
+	// Price changes per product and stored user notifications
+	priceHistoryRepo := repository.NewFirestorePriceHistoryRepository(firestoreClient)
+	notificationRepo := repository.NewFirestoreNotificationRepository(firestoreClient)
+
+	// Paid promotions and the impression/click counts of promoted products
+	promotionRepo := repository.NewFirestorePromotionRepository(firestoreClient)
+
+	// Review rounds of listings held for moderation
+	listingReviewRepo := repository.NewFirestoreListingReviewRepository(firestoreClient)
+
+	// Bulk listing imports and their validated rows
+	listingImportRepo := repository.NewFirestoreListingImportRepository(firestoreClient)
+
+	// Product images that closely match images of other sellers' listings
+	imageMatchRepo := repository.NewFirestoreImageMatchRepository(firestoreClient)
+
+	// Product views and the precomputed related-listing lists
+	recommendationRepo := repository.NewFirestoreRecommendationRepository(firestoreClient)
+
 	firebaseAuthClient := firebase.NewFirebaseAuthClient(authClient, cfg.FirebaseApiKey)
 
 	wsManager := websocket.NewManager(userRepo)
 	wsManager.Start(ctx)
 
-	handler.SetupFileHandler(storageClient, fileMetadataRepo, productRepo)
 	handler.SetupDevTokenHandler(firebaseAuthClient, userRepo)
 
-	authUseCase := usecase.NewAuthUseCase(userRepo, firebaseAuthClient)
+	securityLogUseCase := usecase.NewSecurityLogUseCase(securityLogRepo)
+	velocityUseCase := usecase.NewVelocityUseCase(velocityRepo)
+	if err := velocityUseCase.SeedDefaultRules(ctx); err != nil {
+		log.Fatalf("Failed to seed default velocity rules: %v", err)
+	}
+	authUseCase := usecase.NewAuthUseCase(userRepo, firebaseAuthClient, securityLogUseCase)
 	userUseCase := usecase.NewUserUseCase(userRepo, firebaseAuthClient)
+	// Envelope encryption for stored credentials. Outside development a
+	// missing key file is fatal; a fresh key could not open existing data.
+	var keyProvider service.KeyProvider
+	switch cfg.CredentialKeyProvider {
+	case "kms":
+		if cfg.CredentialKMSKey == "" {
+			log.Fatalf("CREDENTIAL_KMS_KEY is required for the kms credential key provider")
+		}
+		kmsClient, err := crypto.NewCloudKMSClient(ctx, opt)
+		if err != nil {
+			log.Fatalf("Failed to initialize credential key provider: %v", err)
+		}
+		keyProvider = crypto.NewKMSKeyProvider(kmsClient, cfg.CredentialKMSKey)
+	case "local":
+		localKeyProvider, err := crypto.NewLocalFileKeyProvider(cfg.CredentialKeyFile, cfg.Environment == "development")
+		if err != nil {
+			log.Fatalf("Failed to initialize credential key provider: %v", err)
+		}
+		keyProvider = localKeyProvider
+	default:
+		log.Fatalf("Unknown credential key provider: %s", cfg.CredentialKeyProvider)
+	}
+	credentialVault := crypto.NewEnvelopeVault(keyProvider)
+
 	gameTitleUseCase := usecase.NewGameTitleUseCase(gameTitleRepo)
-	productUseCase := usecase.NewProductUseCase(productRepo, gameTitleRepo, userRepo, transactionRepo)
+	// Price history and price drop alerts for wishlisted products
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, wsManager)
+	priceWatchUseCase := usecase.NewPriceWatchUseCase(priceHistoryRepo, productRepo, wishlistRepo, notificationUseCase)
+	// Embedded full-text product search, rebuilt from Firestore on start and every 30 minutes
+	// and caught up with products changed through other instances every minute
+	productSearchIndex := search.NewInvertedIndex()
+	// Listings of low-trust sellers are checked and held for admin review
+	listingModerationUseCase := usecase.NewListingModerationUseCase(listingReviewRepo, productRepo, gameTitleRepo, moderation.NewPatternScanner(nil), productSearchIndex, notificationUseCase, cfg.ListingReviewMinTier)
+	// Perceptual hashes of product images flag listings reusing other sellers' pictures
+	imageMatchUseCase := usecase.NewImageMatchUseCase(fileMetadataRepo, imageMatchRepo, productRepo, listingModerationUseCase)
+	handler.SetupFileHandler(storageClient, fileMetadataRepo, productRepo, imageMatchUseCase)
+	productUseCase := usecase.NewProductUseCase(productRepo, gameTitleRepo, userRepo, transactionRepo, credentialVault, productSearchIndex, priceWatchUseCase, promotionRepo, listingModerationUseCase, recommendationRepo)
+	productUseCase.StartSearchIndexRefresh(ctx, 30*time.Minute, time.Minute)
+	// Spreadsheet import and export of seller catalogs
+	listingImportUseCase := usecase.NewListingImportUseCase(listingImportRepo, productRepo, gameTitleRepo, userRepo, productUseCase, notificationUseCase)
+	// Listings expire when their seller stops tending them
+	listingExpiryUseCase := usecase.NewListingExpiryUseCase(productRepo, userRepo, productSearchIndex, notificationUseCase,
+		time.Duration(cfg.ListingExpiryDays)*24*time.Hour, time.Duration(cfg.ListingExpiryReminderDays)*24*time.Hour)
 	reviewUseCase := usecase.NewReviewUseCase(reviewRepo, userRepo)
 	// Wallet use case
-	walletUseCase := usecase.NewWalletUseCase(walletRepo, walletTxnRepo, paymentMethodRepo, topupRepo, withdrawRepo, userRepo)
+	walletUseCase := usecase.NewWalletUseCase(walletRepo, walletTxnRepo, paymentMethodRepo, topupRepo, withdrawRepo, userRepo, securityLogUseCase, velocityUseCase)
+	// Catalog promotions paid from the seller wallet
+	promotionUseCase := usecase.NewPromotionUseCase(promotionRepo, productRepo, walletUseCase, productSearchIndex)
 	// Wishlist use case
 	wishlistUseCase := usecase.NewWishlistUseCase(wishlistRepo, productRepo)
+	// Similar, also-viewed and personal recommendations built from views, wishlists and purchases
+	recommendationUseCase := usecase.NewRecommendationUseCase(recommendationRepo, productRepo, wishlistRepo, transactionRepo)
 	
 	// Gamification use case  
 	gamificationUseCase := usecase.NewGamificationUseCase(gamificationRepo, userRepo)
@@ -142,9 +241,30 @@ func main() {
 	isProduction := cfg.MidtransEnvironment == "production"
 	paymentService := service.NewMidtransPaymentService(cfg.MidtransServerKey, cfg.MidtransClientKey, isProduction)
 	
+	// Screens chat for off-platform payment solicitation (REST and WebSocket)
+	chatModerationUseCase := usecase.NewChatModerationUseCase(moderation.NewPatternScanner(nil), chatModerationRepo)
+	wsManager.SetContentScreener(chatModerationUseCase)
+
 	// New: Pass chatUseCase and walletUseCase to TransactionUseCase
-	chatUseCase := usecase.NewChatUseCase(chatRepo, userRepo, productRepo, wsManager)
-	transactionUseCase := usecase.NewTransactionUseCase(transactionRepo, productRepo, userRepo, chatUseCase, walletUseCase)
+	chatUseCase := usecase.NewChatUseCase(chatRepo, userRepo, productRepo, wsManager, chatModerationUseCase, priceWatchUseCase)
+
+	// Checkout fraud scoring and review queue
+	fraudRuleUseCase := usecase.NewFraudRuleUseCase(fraudRuleRepo, transactionRepo, userRepo, productRepo, securityLogUseCase)
+	if err := fraudRuleUseCase.SeedDefaultRules(ctx); err != nil {
+		log.Fatalf("Failed to seed default fraud rules: %v", err)
+	}
+	fraudDetectionUseCase := usecase.NewFraudDetectionUseCase(transactionRepo, userRepo, chatUseCase, fraudRuleUseCase, securityLogUseCase)
+
+	// Code pools with low-stock alerts and invalid-code replacement
+	inventoryUseCase := usecase.NewInventoryUseCase(inventoryRepo, productRepo, transactionRepo, credentialVault, wsManager)
+
+	// Multi-party approval trail for escrow release
+	approvalUseCase := usecase.NewApprovalUseCase(transactionRepo)
+
+	// Guided account handover checklist
+	handoverUseCase := usecase.NewHandoverUseCase(handoverChecklistRepo, transactionRepo, productRepo, gameTitleRepo, chatUseCase)
+
+	transactionUseCase := usecase.NewTransactionUseCase(transactionRepo, productRepo, userRepo, chatUseCase, walletUseCase, credentialVault, fraudDetectionUseCase, velocityUseCase, inventoryUseCase, approvalUseCase, handoverUseCase)
 	
 	// Enhanced transaction use case with Payment Gateway
 	enhancedTransactionUseCase := usecase.NewEnhancedTransactionUseCase(
@@ -154,16 +274,53 @@ func main() {
 		paymentService, 
 		chatUseCase, 
 		walletUseCase,
+		approvalUseCase,
+		credentialVault,
+		fraudDetectionUseCase,
+		velocityUseCase,
+		inventoryUseCase,
 		wsManager,
 	)
 
+	// Anti-fraud checks (credential access audit, refund abuse)
+	antiFraudUseCase := usecase.NewAntiFraudUseCase(transactionRepo, userRepo, securityLogUseCase)
+
+	// Admin-managed inspection windows for auto-release
+	autoReleasePolicyUseCase := usecase.NewAutoReleasePolicyUseCase(autoReleasePolicyRepo, productRepo)
+
+	// Seller risk scoring; tiers drive escrow hold, listing limits and badges
+	sellerTrustUseCase := usecase.NewSellerTrustUseCase(sellerTrustRepo, userRepo, transactionRepo, handoverChecklistRepo, securityLogUseCase)
+
 	// Escrow manager for credentials and auto-release
 	escrowManagerUseCase := usecase.NewEscrowManagerUseCase(
 		transactionRepo,
+		productRepo,
 		walletUseCase,
 		chatUseCase,
+		approvalUseCase,
+		handoverUseCase,
+		credentialVault,
+		antiFraudUseCase,
+		autoReleasePolicyUseCase,
+		sellerTrustUseCase,
 	)
 
+	// Re-encrypts credentials after key rotation
+	credentialRotationUseCase := usecase.NewCredentialRotationUseCase(productRepo, transactionRepo, credentialVault, keyProvider)
+
+	// Lease-based scheduler so each job runs on one instance at a time
+	jobSchedulerUseCase := usecase.NewJobSchedulerUseCase(jobRepo)
+	jobSchedulerUseCase.Register("escrow_auto_release", 10*time.Minute, escrowManagerUseCase.ProcessAutoRelease)
+	jobSchedulerUseCase.Register("credential_redaction", 1*time.Hour, escrowManagerUseCase.RedactExpiredCredentials)
+	jobSchedulerUseCase.Register("handover_sla", 15*time.Minute, handoverUseCase.ProcessOverdueChecklists)
+	jobSchedulerUseCase.Register("credential_rotation", 24*time.Hour, credentialRotationUseCase.RunRotationJob)
+	// Nightly at 02:00 WIB, away from peak trading hours
+	jobSchedulerUseCase.RegisterDaily("seller_risk_scoring", 2*time.Hour, time.FixedZone("WIB", 7*60*60), sellerTrustUseCase.RunScoringJob)
+	jobSchedulerUseCase.Register("promotion_scheduler", 15*time.Minute, promotionUseCase.RunPromotionJob)
+	jobSchedulerUseCase.Register("listing_expiry", time.Hour, listingExpiryUseCase.RunExpiryJob)
+	jobSchedulerUseCase.Register("recommendations", 6*time.Hour, recommendationUseCase.RunRecommendationJob)
+	jobSchedulerUseCase.Register("listing_import", time.Minute, listingImportUseCase.RunImportJob)
+
 	handler.Setup(authUseCase, userUseCase, gameTitleUseCase, productUseCase, reviewUseCase, transactionUseCase, walletUseCase)
 
 	e := echo.New()
@@ -171,23 +328,46 @@ func main() {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
+	e.Use(apimiddleware.ClientInfo())
 
 	e.Validator = api.NewValidator()
 
 	authMiddleware := apimiddleware.NewAuthMiddleware(authClient, userRepo)
 	adminMiddleware := apimiddleware.NewAdminMiddleware(userRepo)
+	jobTriggerMiddleware := apimiddleware.NewJobTriggerMiddleware(cfg.JobTriggerToken)
 
 	chatHandler := handler.NewChatHandler(chatUseCase)
 	wsHandler := handler.NewWebSocketHandlerWithAuth(wsManager, authClient, chatUseCase)
 	paymentHandler := handler.NewPaymentHandler(enhancedTransactionUseCase)
 	escrowHandler := handler.NewEscrowHandler(escrowManagerUseCase)
+	handoverHandler := handler.NewHandoverHandler(handoverUseCase)
+	credentialHandler := handler.NewCredentialHandler(credentialRotationUseCase)
+	jobHandler := handler.NewJobHandler(jobSchedulerUseCase)
+	autoReleasePolicyHandler := handler.NewAutoReleasePolicyHandler(autoReleasePolicyUseCase)
+	fraudReviewHandler := handler.NewFraudReviewHandler(fraudDetectionUseCase)
+	fraudRuleHandler := handler.NewFraudRuleHandler(fraudRuleUseCase)
+	securityHandler := handler.NewSecurityHandler(securityLogUseCase)
+	sellerTrustHandler := handler.NewSellerTrustHandler(sellerTrustUseCase)
+	chatModerationHandler := handler.NewChatModerationHandler(chatModerationUseCase)
+	velocityHandler := handler.NewVelocityHandler(velocityUseCase)
+	inventoryHandler := handler.NewInventoryHandler(inventoryUseCase)
 	wishlistHandler := handler.NewWishlistHandler(wishlistUseCase)
+	notificationHandler := handler.NewNotificationHandler(notificationUseCase)
+	priceHistoryHandler := handler.NewPriceHistoryHandler(priceWatchUseCase)
+	promotionHandler := handler.NewPromotionHandler(promotionUseCase)
+	listingModerationHandler := handler.NewListingModerationHandler(listingModerationUseCase)
+	listingImportHandler := handler.NewListingImportHandler(listingImportUseCase)
+	imageMatchHandler := handler.NewImageMatchHandler(imageMatchUseCase)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationUseCase)
 	gamificationHandler := handler.NewGamificationHandler(gamificationUseCase)
 	// Start cleanup routine for rate limiters
 	wsHandler.CleanupRateLimiters()
 
-	// Start auto-release background job
-	go escrowManagerUseCase.StartAutoReleaseJob(ctx)
+	// Start background jobs (auto-release, redaction, handover SLA, re-encryption, seller risk, promotions, listing expiry, recommendations).
+	// With an external scheduler they only run through the job trigger endpoint.
+	if cfg.JobScheduler != "external" {
+		jobSchedulerUseCase.Start(ctx)
+	}
 
 	e.GET("/health", func(c echo.Context) error {
 		return c.JSON(200, map[string]string{"status": "ok"})
@@ -229,7 +409,25 @@ func main() {
 	router.SetupChatRouter(e, chatHandler, authMiddleware, adminMiddleware)
 	router.SetupWebSocketRouter(e, wsHandler)
 	router.SetupEscrowRoutes(e, escrowHandler, authMiddleware)
+	router.SetupHandoverRoutes(e, handoverHandler, authMiddleware)
+	router.SetupCredentialRoutes(e, credentialHandler, authMiddleware, adminMiddleware)
+	router.SetupJobRoutes(e, jobHandler, authMiddleware, adminMiddleware, jobTriggerMiddleware)
+	router.SetupAutoReleasePolicyRoutes(e, autoReleasePolicyHandler, authMiddleware, adminMiddleware)
+	router.SetupFraudReviewRoutes(e, fraudReviewHandler, authMiddleware, adminMiddleware)
+	router.SetupFraudRuleRoutes(e, fraudRuleHandler, authMiddleware, adminMiddleware)
+	router.SetupSecurityRoutes(e, securityHandler, authMiddleware, adminMiddleware)
+	router.SetupSellerTrustRoutes(e, sellerTrustHandler, authMiddleware, adminMiddleware)
+	router.SetupChatModerationRoutes(e, chatModerationHandler, authMiddleware, adminMiddleware)
+	router.SetupVelocityRoutes(e, velocityHandler, authMiddleware, adminMiddleware)
+	router.SetupInventoryRoutes(e, inventoryHandler, authMiddleware)
 	router.SetupWishlistRouter(e, wishlistHandler, authMiddleware)
+	router.SetupNotificationRoutes(e, notificationHandler, authMiddleware)
+	router.SetupPriceHistoryRoutes(e, priceHistoryHandler)
+	router.SetupPromotionRoutes(e, promotionHandler, authMiddleware)
+	router.SetupListingModerationRoutes(e, listingModerationHandler, authMiddleware, adminMiddleware)
+	router.SetupListingImportRoutes(e, listingImportHandler, authMiddleware)
+	router.SetupImageMatchRoutes(e, imageMatchHandler, authMiddleware, adminMiddleware)
+	router.SetupRecommendationRoutes(e, recommendationHandler, authMiddleware)
 	router.SetupGamificationRoutes(e, gamificationHandler, authMiddleware)
 
 	// Serve static files for chat testing
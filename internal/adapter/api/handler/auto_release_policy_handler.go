@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+)
+
+type AutoReleasePolicyHandler struct {
+	policyUseCase *usecase.AutoReleasePolicyUseCase
+}
+
+func NewAutoReleasePolicyHandler(policyUseCase *usecase.AutoReleasePolicyUseCase) *AutoReleasePolicyHandler {
+	return &AutoReleasePolicyHandler{
+		policyUseCase: policyUseCase,
+	}
+}
+
+type autoReleasePolicyRequest struct {
+	Name            string  `json:"name" validate:"required"`
+	SecurityLevel   string  `json:"security_level"`
+	GameTitleID     string  `json:"game_title_id"`
+	ProductType     string  `json:"product_type"`
+	MinAmount       float64 `json:"min_amount"`
+	MaxAmount       float64 `json:"max_amount"`
+	InspectionHours int     `json:"inspection_hours" validate:"required"`
+	ExtensionHours  int     `json:"extension_hours"`
+	WarningHours    int     `json:"warning_hours"`
+	Priority        int     `json:"priority"`
+	IsActive        bool    `json:"is_active"`
+}
+
+func (r autoReleasePolicyRequest) toInput() usecase.AutoReleasePolicyInput {
+	return usecase.AutoReleasePolicyInput{
+		Name:            r.Name,
+		SecurityLevel:   r.SecurityLevel,
+		GameTitleID:     r.GameTitleID,
+		ProductType:     r.ProductType,
+		MinAmount:       r.MinAmount,
+		MaxAmount:       r.MaxAmount,
+		InspectionHours: r.InspectionHours,
+		ExtensionHours:  r.ExtensionHours,
+		WarningHours:    r.WarningHours,
+		Priority:        r.Priority,
+		IsActive:        r.IsActive,
+	}
+}
+
+func (h *AutoReleasePolicyHandler) CreatePolicy(c echo.Context) error {
+	var req autoReleasePolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	policy, err := h.policyUseCase.CreatePolicy(c.Request().Context(), adminID, req.toInput())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Created(c, policy)
+}
+
+func (h *AutoReleasePolicyHandler) UpdatePolicy(c echo.Context) error {
+	id := c.Param("id")
+
+	var req autoReleasePolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	policy, err := h.policyUseCase.UpdatePolicy(c.Request().Context(), id, req.toInput())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, policy)
+}
+
+func (h *AutoReleasePolicyHandler) DeletePolicy(c echo.Context) error {
+	id := c.Param("id")
+
+	if err := h.policyUseCase.DeletePolicy(c.Request().Context(), id); err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]string{
+		"message": "Auto-release policy deleted successfully",
+	})
+}
+
+func (h *AutoReleasePolicyHandler) GetPolicy(c echo.Context) error {
+	policy, err := h.policyUseCase.GetPolicy(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, policy)
+}
+
+func (h *AutoReleasePolicyHandler) ListPolicies(c echo.Context) error {
+	policies, err := h.policyUseCase.ListPolicies(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"policies": policies,
+		"default":  usecase.DefaultAutoReleasePolicy,
+	})
+}
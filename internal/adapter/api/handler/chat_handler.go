@@ -9,6 +9,7 @@ import (
 	"pasargamex/internal/usecase"
 	"pasargamex/pkg/errors"
 	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
 )
 
 type ChatHandler struct {
@@ -205,12 +206,20 @@ func (h *ChatHandler) GetChatMessages(c echo.Context) error {
 		}
 	}
 
-	messages, total, err := h.chatUseCase.GetChatMessages(c.Request().Context(), userID, chatID, limit, offset)
+	// ?cursor= from next_cursor takes precedence over offset
+	pagination := utils.PaginationParams{
+		Page:     offset/limit + 1,
+		PageSize: limit,
+		Offset:   offset,
+		Cursor:   c.QueryParam("cursor"),
+	}
+
+	messages, pageInfo, err := h.chatUseCase.GetChatMessages(c.Request().Context(), userID, chatID, pagination)
 	if err != nil {
 		return response.Error(c, err)
 	}
 
-	return response.SuccessPaginated(c, messages, total, limit, offset)
+	return response.Paginated(c, messages, pageInfo.Total, pagination.Page, limit, pageInfo.NextCursor)
 }
 
 // MarkChatAsRead marks a chat as read for the authenticated user
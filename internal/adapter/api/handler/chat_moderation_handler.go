@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type ChatModerationHandler struct {
+	moderationUseCase *usecase.ChatModerationUseCase
+}
+
+func NewChatModerationHandler(moderationUseCase *usecase.ChatModerationUseCase) *ChatModerationHandler {
+	return &ChatModerationHandler{
+		moderationUseCase: moderationUseCase,
+	}
+}
+
+type resolveChatOffenderRequest struct {
+	Notes string `json:"notes"`
+}
+
+// ListOffenders returns users flagged for repeated chat violations.
+// Defaults to ?status=pending_review; pass status=all for every offender.
+func (h *ChatModerationHandler) ListOffenders(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = usecase.ChatOffenderPendingReview
+	} else if status == "all" {
+		status = ""
+	}
+
+	pagination := utils.GetPaginationParams(c)
+
+	offenders, total, err := h.moderationUseCase.ListOffenders(
+		c.Request().Context(),
+		status,
+		pagination.Page,
+		pagination.PageSize,
+	)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, offenders, total, pagination.Page, pagination.PageSize)
+}
+
+func (h *ChatModerationHandler) GetOffender(c echo.Context) error {
+	offender, err := h.moderationUseCase.GetOffender(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, offender)
+}
+
+func (h *ChatModerationHandler) ResolveOffender(c echo.Context) error {
+	var req resolveChatOffenderRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	offender, err := h.moderationUseCase.ResolveOffender(c.Request().Context(), adminID, c.Param("id"), req.Notes)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, offender)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/response"
+)
+
+type CredentialHandler struct {
+	rotationUseCase *usecase.CredentialRotationUseCase
+}
+
+func NewCredentialHandler(rotationUseCase *usecase.CredentialRotationUseCase) *CredentialHandler {
+	return &CredentialHandler{
+		rotationUseCase: rotationUseCase,
+	}
+}
+
+// ReencryptCredentials re-seals stored credentials under the current key
+func (h *CredentialHandler) ReencryptCredentials(c echo.Context) error {
+	result, err := h.rotationUseCase.ReencryptAll(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, result)
+}
+
+// RotateKey switches to a new master key and re-seals stored credentials
+func (h *CredentialHandler) RotateKey(c echo.Context) error {
+	result, err := h.rotationUseCase.RotateKey(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, result)
+}
@@ -23,12 +23,35 @@ func NewEscrowHandler(escrowManagerUC *usecase.EscrowManagerUseCase) *EscrowHand
 type DeliverCredentialsRequest struct {
 	TransactionID string                 `json:"transaction_id" validate:"required"`
 	Credentials   map[string]interface{} `json:"credentials" validate:"required"`
+	EvidenceURL   string                 `json:"evidence_url,omitempty"`
+	Notes         string                 `json:"notes,omitempty"`
 }
 
 type ConfirmCredentialsRequest struct {
 	TransactionID string `json:"transaction_id" validate:"required"`
 	IsWorking     bool   `json:"is_working" validate:"required"`
 	Notes         string `json:"notes,omitempty"`
+	EvidenceURL   string `json:"evidence_url,omitempty"`
+}
+
+type ExtendAutoReleaseRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type VerifyDeliveryRequest struct {
+	IsVerified  bool   `json:"is_verified"`
+	Notes       string `json:"notes,omitempty"`
+	EvidenceURL string `json:"evidence_url,omitempty"`
+}
+
+// approvalEvidence captures the request context stored with an approval
+func approvalEvidence(c echo.Context, evidenceURL, notes string) usecase.ApprovalEvidence {
+	return usecase.ApprovalEvidence{
+		EvidenceURL: evidenceURL,
+		Notes:       notes,
+		IPAddress:   c.RealIP(),
+		UserAgent:   c.Request().UserAgent(),
+	}
 }
 
 // DeliverCredentials - Seller delivers account credentials
@@ -65,7 +88,8 @@ func (h *EscrowHandler) DeliverCredentials(c echo.Context) error {
 		}
 	}
 
-	err := h.escrowManagerUC.DeliverCredentials(c.Request().Context(), req.TransactionID, userID, req.Credentials)
+	evidence := approvalEvidence(c, req.EvidenceURL, req.Notes)
+	transaction, err := h.escrowManagerUC.DeliverCredentials(c.Request().Context(), req.TransactionID, userID, req.Credentials, evidence)
 	if err != nil {
 		log.Printf("Failed to deliver credentials: %v", err)
 		return response.Error(c, err)
@@ -74,7 +98,7 @@ func (h *EscrowHandler) DeliverCredentials(c echo.Context) error {
 	return response.Success(c, map[string]interface{}{
 		"message": "Credentials delivered successfully",
 		"transaction_id": req.TransactionID,
-		"auto_release_in": "24 hours",
+		"auto_release_at": transaction.AutoReleaseAt,
 	})
 }
 
@@ -100,26 +124,91 @@ func (h *EscrowHandler) ConfirmCredentials(c echo.Context) error {
 		return response.Error(c, errors.BadRequest("Notes are required when reporting non-working credentials", nil))
 	}
 
-	err := h.escrowManagerUC.ConfirmCredentials(c.Request().Context(), req.TransactionID, userID, req.IsWorking, req.Notes)
+	evidence := approvalEvidence(c, req.EvidenceURL, req.Notes)
+	transaction, err := h.escrowManagerUC.ConfirmCredentials(c.Request().Context(), req.TransactionID, userID, req.IsWorking, req.Notes, evidence)
 	if err != nil {
 		log.Printf("Failed to confirm credentials: %v", err)
 		return response.Error(c, err)
 	}
 
 	var message string
-	if req.IsWorking {
+	if !req.IsWorking {
+		message = "Credentials reported as not working. Dispute created for admin review."
+	} else if transaction.EscrowStatus == "released" {
 		message = "Credentials confirmed as working. Funds released to seller."
 	} else {
-		message = "Credentials reported as not working. Dispute created for admin review."
+		message = "Credentials confirmed as working. Funds will be released once all approvals are complete."
 	}
 
 	return response.Success(c, map[string]interface{}{
-		"message": message,
-		"transaction_id": req.TransactionID,
-		"status": req.IsWorking,
+		"message":             message,
+		"transaction_id":      req.TransactionID,
+		"status":              req.IsWorking,
+		"escrow_status":       transaction.EscrowStatus,
+		"required_approvals":  transaction.RequiredApprovals,
+		"completed_approvals": transaction.CompletedApprovals,
+	})
+}
+
+// VerifyDelivery - Assigned middleman verifies the delivered account
+func (h *EscrowHandler) VerifyDelivery(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return response.Error(c, errors.BadRequest("Transaction ID is required", nil))
+	}
+
+	var req VerifyDeliveryRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	// Get user ID from JWT token
+	userID, ok := c.Get("uid").(string)
+	if !ok {
+		return response.Error(c, errors.Unauthorized("User not authenticated", nil))
+	}
+
+	if !req.IsVerified && req.Notes == "" {
+		return response.Error(c, errors.BadRequest("Notes are required when rejecting a delivery", nil))
+	}
+
+	evidence := approvalEvidence(c, req.EvidenceURL, req.Notes)
+	transaction, err := h.escrowManagerUC.VerifyDelivery(c.Request().Context(), transactionID, userID, req.IsVerified, evidence)
+	if err != nil {
+		log.Printf("Failed to verify delivery: %v", err)
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"transaction_id":      transactionID,
+		"status":              transaction.Status,
+		"escrow_status":       transaction.EscrowStatus,
+		"required_approvals":  transaction.RequiredApprovals,
+		"completed_approvals": transaction.CompletedApprovals,
 	})
 }
 
+// GetTransactionApprovals - Approval trail for buyer, seller or middleman
+func (h *EscrowHandler) GetTransactionApprovals(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return response.Error(c, errors.BadRequest("Transaction ID is required", nil))
+	}
+
+	// Get user ID from JWT token
+	userID, ok := c.Get("uid").(string)
+	if !ok {
+		return response.Error(c, errors.Unauthorized("User not authenticated", nil))
+	}
+
+	approvals, err := h.escrowManagerUC.GetApprovals(c.Request().Context(), transactionID, userID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, approvals)
+}
+
 // GetTransactionCredentials - Get delivered credentials (buyer only)
 func (h *EscrowHandler) GetTransactionCredentials(c echo.Context) error {
 	transactionID := c.Param("id")
@@ -133,16 +222,64 @@ func (h *EscrowHandler) GetTransactionCredentials(c echo.Context) error {
 		return response.Error(c, errors.Unauthorized("User not authenticated", nil))
 	}
 
-	// TODO: Implement get credentials logic
-	// This would involve:
-	// 1. Get transaction from repository
-	// 2. Verify user is the buyer
-	// 3. Verify credentials have been delivered
-	// 4. Return credentials (securely)
+	credentials, err := h.escrowManagerUC.RevealCredentials(c.Request().Context(), transactionID, userID, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return response.Error(c, err)
+	}
 
 	return response.Success(c, map[string]interface{}{
-		"message": "TODO: Implement get credentials",
 		"transaction_id": transactionID,
-		"user_id": userID,
+		"credentials":    credentials,
+	})
+}
+
+// GetCredentialAccessLog - When and how often the buyer revealed the credentials
+func (h *EscrowHandler) GetCredentialAccessLog(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return response.Error(c, errors.BadRequest("Transaction ID is required", nil))
+	}
+
+	// Get user ID from JWT token
+	userID, ok := c.Get("uid").(string)
+	if !ok {
+		return response.Error(c, errors.Unauthorized("User not authenticated", nil))
+	}
+
+	summary, err := h.escrowManagerUC.GetCredentialAccessSummary(c.Request().Context(), transactionID, userID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, summary)
+}
+
+// ExtendAutoRelease - Buyer requests the one-time inspection window extension
+func (h *EscrowHandler) ExtendAutoRelease(c echo.Context) error {
+	transactionID := c.Param("id")
+
+	var req ExtendAutoReleaseRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	userID, ok := c.Get("uid").(string)
+	if !ok {
+		return response.Error(c, errors.Unauthorized("User not authenticated", nil))
+	}
+
+	transaction, err := h.escrowManagerUC.ExtendAutoRelease(c.Request().Context(), transactionID, userID, req.Reason)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"transaction_id":  transaction.ID,
+		"auto_release_at": transaction.AutoReleaseAt,
+		"extended":        transaction.AutoReleaseExtended,
 	})
-}
\ No newline at end of file
+}
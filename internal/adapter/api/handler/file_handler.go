@@ -18,29 +18,32 @@ import (
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
 	"pasargamex/internal/domain/service"
+	"pasargamex/internal/usecase"
 	"pasargamex/pkg/errors"
 	"pasargamex/pkg/logger"
 	"pasargamex/pkg/response"
 )
 
 type FileHandler struct {
-	fileService      service.FileUploadService
-	fileMetadataRepo repository.FileMetadataRepository
-	productRepo      repository.ProductRepository
-	maxFileSize      int64
+	fileService       service.FileUploadService
+	fileMetadataRepo  repository.FileMetadataRepository
+	productRepo       repository.ProductRepository
+	imageMatchUseCase *usecase.ImageMatchUseCase
+	maxFileSize       int64
 }
 
-func NewFileHandler(fileService service.FileUploadService, fileMetadataRepo repository.FileMetadataRepository, productRepo repository.ProductRepository) *FileHandler {
+func NewFileHandler(fileService service.FileUploadService, fileMetadataRepo repository.FileMetadataRepository, productRepo repository.ProductRepository, imageMatchUseCase *usecase.ImageMatchUseCase) *FileHandler {
 	return &FileHandler{
-		fileService:      fileService,
-		fileMetadataRepo: fileMetadataRepo,
-		productRepo:      productRepo,
-		maxFileSize:      5 * 1024 * 1024,
+		fileService:       fileService,
+		fileMetadataRepo:  fileMetadataRepo,
+		productRepo:       productRepo,
+		imageMatchUseCase: imageMatchUseCase,
+		maxFileSize:       5 * 1024 * 1024,
 	}
 }
 
-func SetupFileHandler(fileService service.FileUploadService, fileMetadataRepo repository.FileMetadataRepository, productRepo repository.ProductRepository) {
-	fileHandler = NewFileHandler(fileService, fileMetadataRepo, productRepo)
+func SetupFileHandler(fileService service.FileUploadService, fileMetadataRepo repository.FileMetadataRepository, productRepo repository.ProductRepository, imageMatchUseCase *usecase.ImageMatchUseCase) {
+	fileHandler = NewFileHandler(fileService, fileMetadataRepo, productRepo, imageMatchUseCase)
 }
 
 var (
@@ -561,6 +564,8 @@ func (h *FileHandler) UploadAndLinkProductImage(c echo.Context) error {
 		return response.Error(c, err)
 	}
 
+	h.checkProductImage(c, product.ID, fileID)
+
 	return response.Success(c, map[string]interface{}{
 		"file_id": fileID,
 		"url":     fileURL,
@@ -786,6 +791,7 @@ func (h *FileHandler) UploadMultipleImagesToProduct(c echo.Context) error {
 
 	var newImages []entity.ProductImage
 	var uploadErrors []string
+	var hashedFiles []*entity.FileMetadata
 
 	// Process each file
 	for _, fileHeader := range files {
@@ -839,7 +845,13 @@ func (h *FileHandler) UploadMultipleImagesToProduct(c echo.Context) error {
 			UpdatedAt:  time.Now(),
 		}
 
+		if hashSrc, err := fileHeader.Open(); err == nil {
+			usecase.HashImage(metadata, hashSrc)
+			hashSrc.Close()
+		}
+
 		h.fileMetadataRepo.Create(c.Request().Context(), metadata)
+		hashedFiles = append(hashedFiles, metadata)
 
 		// Create product image entry
 		displayOrder := len(product.Images) + len(newImages)
@@ -861,6 +873,11 @@ func (h *FileHandler) UploadMultipleImagesToProduct(c echo.Context) error {
 		if err != nil {
 			return response.Error(c, errors.Internal("Failed to update product", err))
 		}
+
+		// Images reused from other sellers' listings hold the listing for review
+		if h.imageMatchUseCase != nil {
+			h.imageMatchUseCase.CheckProductImagesAsync(productID, hashedFiles)
+		}
 	}
 
 	// Prepare response
@@ -878,6 +895,33 @@ func (h *FileHandler) UploadMultipleImagesToProduct(c echo.Context) error {
 	return response.Success(c, responseData)
 }
 
+// checkProductImage hashes an image just linked to a product and checks it
+// against other sellers' listings in the background
+func (h *FileHandler) checkProductImage(c echo.Context, productID, fileID string) {
+	if h.imageMatchUseCase == nil || fileID == "" {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		logger.Warn("Failed to reopen image %s for hashing: %v", fileID, err)
+		return
+	}
+	defer src.Close()
+
+	metadata, err := h.imageMatchUseCase.HashStoredImage(c.Request().Context(), fileID, src)
+	if err != nil {
+		logger.Warn("Failed to hash image %s: %v", fileID, err)
+		return
+	}
+
+	h.imageMatchUseCase.CheckProductImagesAsync(productID, []*entity.FileMetadata{metadata})
+}
+
 type recResponse struct {
 	header http.Header
 	body   strings.Builder
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type FraudReviewHandler struct {
+	fraudUseCase *usecase.FraudDetectionUseCase
+}
+
+func NewFraudReviewHandler(fraudUseCase *usecase.FraudDetectionUseCase) *FraudReviewHandler {
+	return &FraudReviewHandler{
+		fraudUseCase: fraudUseCase,
+	}
+}
+
+type fraudReviewDecisionRequest struct {
+	Notes string `json:"notes"`
+}
+
+// ListReviewQueue returns transactions held for fraud review
+func (h *FraudReviewHandler) ListReviewQueue(c echo.Context) error {
+	pagination := utils.GetPaginationParams(c)
+
+	transactions, total, err := h.fraudUseCase.ListReviewQueue(
+		c.Request().Context(),
+		pagination.Page,
+		pagination.PageSize,
+	)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, transactions, total, pagination.Page, pagination.PageSize)
+}
+
+func (h *FraudReviewHandler) ApproveTransaction(c echo.Context) error {
+	return h.decide(c, true)
+}
+
+func (h *FraudReviewHandler) RejectTransaction(c echo.Context) error {
+	return h.decide(c, false)
+}
+
+func (h *FraudReviewHandler) decide(c echo.Context, approve bool) error {
+	transactionID := c.Param("id")
+
+	var req fraudReviewDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if !approve && req.Notes == "" {
+		return response.Error(c, errors.BadRequest("Notes are required when rejecting a transaction", nil))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	transaction, err := h.fraudUseCase.ReviewTransaction(c.Request().Context(), adminID, transactionID, approve, req.Notes)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, transaction)
+}
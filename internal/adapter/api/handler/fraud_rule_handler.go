@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+)
+
+type FraudRuleHandler struct {
+	ruleUseCase *usecase.FraudRuleUseCase
+}
+
+func NewFraudRuleHandler(ruleUseCase *usecase.FraudRuleUseCase) *FraudRuleHandler {
+	return &FraudRuleHandler{
+		ruleUseCase: ruleUseCase,
+	}
+}
+
+type fraudRuleRequest struct {
+	Flag        string                      `json:"flag" validate:"required"`
+	Name        string                      `json:"name" validate:"required"`
+	Description string                      `json:"description"`
+	Conditions  []entity.FraudRuleCondition `json:"conditions" validate:"required"`
+	Weight      float64                     `json:"weight"`
+	Enabled     bool                        `json:"enabled"`
+}
+
+func (r fraudRuleRequest) toInput() usecase.FraudRuleInput {
+	return usecase.FraudRuleInput{
+		Flag:        r.Flag,
+		Name:        r.Name,
+		Description: r.Description,
+		Conditions:  r.Conditions,
+		Weight:      r.Weight,
+		Enabled:     r.Enabled,
+	}
+}
+
+type fraudRuleChangeRequest struct {
+	ID     string           `json:"id,omitempty"`
+	Delete bool             `json:"delete,omitempty"`
+	Rule   fraudRuleRequest `json:"rule"`
+}
+
+type fraudRuleDryRunRequest struct {
+	Changes    []fraudRuleChangeRequest `json:"changes"`
+	SampleSize int                      `json:"sample_size"`
+}
+
+func (h *FraudRuleHandler) ListRules(c echo.Context) error {
+	rules, err := h.ruleUseCase.ListRules(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"rules":     rules,
+		"fields":    usecase.FraudRuleFields,
+		"operators": usecase.FraudRuleOperators,
+	})
+}
+
+func (h *FraudRuleHandler) CreateRule(c echo.Context) error {
+	var req fraudRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	rule, err := h.ruleUseCase.CreateRule(c.Request().Context(), adminID, req.toInput())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Created(c, rule)
+}
+
+func (h *FraudRuleHandler) UpdateRule(c echo.Context) error {
+	id := c.Param("id")
+
+	var req fraudRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	rule, err := h.ruleUseCase.UpdateRule(c.Request().Context(), adminID, id, req.toInput())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, rule)
+}
+
+func (h *FraudRuleHandler) DeleteRule(c echo.Context) error {
+	id := c.Param("id")
+	adminID := c.Get("uid").(string)
+
+	if err := h.ruleUseCase.DeleteRule(c.Request().Context(), adminID, id); err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]string{
+		"message": "Fraud rule deleted successfully",
+	})
+}
+
+// DryRun replays recent transactions against proposed rule changes
+func (h *FraudRuleHandler) DryRun(c echo.Context) error {
+	var req fraudRuleDryRunRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	changes := make([]usecase.FraudRuleChange, len(req.Changes))
+	for i, change := range req.Changes {
+		changes[i] = usecase.FraudRuleChange{
+			ID:     change.ID,
+			Delete: change.Delete,
+			Input:  change.Rule.toInput(),
+		}
+	}
+
+	result, err := h.ruleUseCase.DryRun(c.Request().Context(), changes, req.SampleSize)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, result)
+}
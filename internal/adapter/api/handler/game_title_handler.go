@@ -26,6 +26,28 @@ type gameTitleAttributeRequest struct {
 	Description string   `json:"description,omitempty"`
 }
 
+type handoverStepRequest struct {
+	Key              string `json:"key" validate:"required"`
+	Title            string `json:"title" validate:"required"`
+	Description      string `json:"description,omitempty"`
+	Required         bool   `json:"required"`
+	RequiresEvidence bool   `json:"requires_evidence"`
+}
+
+func toHandoverStepInputs(steps []handoverStepRequest) []usecase.HandoverStepInput {
+	inputs := make([]usecase.HandoverStepInput, len(steps))
+	for i, step := range steps {
+		inputs[i] = usecase.HandoverStepInput{
+			Key:              step.Key,
+			Title:            step.Title,
+			Description:      step.Description,
+			Required:         step.Required,
+			RequiresEvidence: step.RequiresEvidence,
+		}
+	}
+	return inputs
+}
+
 type createGameTitleRequest struct {
     Name        string                     `json:"name" validate:"required"`
     Description string                     `json:"description"`
@@ -33,6 +55,7 @@ type createGameTitleRequest struct {
     Banner      string                     `json:"banner" validate:"omitempty,url"`
     Attributes  []gameTitleAttributeRequest `json:"attributes"`
     Status      string                     `json:"status" validate:"required,oneof=active inactive"`
+    HandoverSteps []handoverStepRequest    `json:"handover_steps,omitempty" validate:"omitempty,dive"`
 }
 
 func (h *GameTitleHandler) CreateGameTitle(c echo.Context) error {
@@ -64,6 +87,7 @@ func (h *GameTitleHandler) CreateGameTitle(c echo.Context) error {
 		Banner:      req.Banner,
 		Attributes:  attributes,
 		Status:      req.Status,
+		HandoverSteps: toHandoverStepInputs(req.HandoverSteps),
 	})
 
 	if err != nil {
@@ -142,6 +166,7 @@ func (h *GameTitleHandler) UpdateGameTitle(c echo.Context) error {
 		Banner:      req.Banner,     
 		Attributes:  attributes,
 		Status:      req.Status,
+		HandoverSteps: toHandoverStepInputs(req.HandoverSteps),
 	})
 
 	if err != nil {
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+)
+
+type HandoverHandler struct {
+	handoverUseCase *usecase.HandoverUseCase
+}
+
+func NewHandoverHandler(handoverUseCase *usecase.HandoverUseCase) *HandoverHandler {
+	return &HandoverHandler{
+		handoverUseCase: handoverUseCase,
+	}
+}
+
+type CompleteHandoverStepRequest struct {
+	EvidenceURL string `json:"evidence_url,omitempty" validate:"omitempty,url"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+type ConfirmHandoverStepRequest struct {
+	Notes string `json:"notes,omitempty"`
+}
+
+// GetChecklist - Handover checklist for buyer, seller or middleman
+func (h *HandoverHandler) GetChecklist(c echo.Context) error {
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		return response.Error(c, errors.BadRequest("Transaction ID is required", nil))
+	}
+
+	userID := c.Get("uid").(string)
+
+	checklist, err := h.handoverUseCase.GetChecklist(c.Request().Context(), transactionID, userID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, checklist)
+}
+
+// CompleteStep - Seller ticks a handover step
+func (h *HandoverHandler) CompleteStep(c echo.Context) error {
+	transactionID := c.Param("id")
+	stepKey := c.Param("key")
+	if transactionID == "" || stepKey == "" {
+		return response.Error(c, errors.BadRequest("Transaction ID and step key are required", nil))
+	}
+
+	var req CompleteHandoverStepRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	userID := c.Get("uid").(string)
+
+	checklist, err := h.handoverUseCase.CompleteStep(c.Request().Context(), transactionID, userID, stepKey, usecase.CompleteHandoverStepInput{
+		EvidenceURL: req.EvidenceURL,
+		Notes:       req.Notes,
+	})
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, checklist)
+}
+
+// ConfirmStep - Buyer confirms a completed handover step
+func (h *HandoverHandler) ConfirmStep(c echo.Context) error {
+	transactionID := c.Param("id")
+	stepKey := c.Param("key")
+	if transactionID == "" || stepKey == "" {
+		return response.Error(c, errors.BadRequest("Transaction ID and step key are required", nil))
+	}
+
+	var req ConfirmHandoverStepRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	userID := c.Get("uid").(string)
+
+	checklist, err := h.handoverUseCase.ConfirmStep(c.Request().Context(), transactionID, userID, stepKey, req.Notes)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, checklist)
+}
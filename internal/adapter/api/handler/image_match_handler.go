@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type ImageMatchHandler struct {
+	imageMatchUseCase *usecase.ImageMatchUseCase
+}
+
+func NewImageMatchHandler(imageMatchUseCase *usecase.ImageMatchUseCase) *ImageMatchHandler {
+	return &ImageMatchHandler{
+		imageMatchUseCase: imageMatchUseCase,
+	}
+}
+
+// ListImageMatches returns every recorded match, newest first
+func (h *ImageMatchHandler) ListImageMatches(c echo.Context) error {
+	pagination := utils.GetPaginationParams(c)
+
+	matches, pageInfo, err := h.imageMatchUseCase.ListImageMatches(c.Request().Context(), pagination)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, matches, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
+}
+
+// CompareProductImages shows a listing side by side with the listings its
+// images match
+func (h *ImageMatchHandler) CompareProductImages(c echo.Context) error {
+	comparisons, err := h.imageMatchUseCase.CompareImageMatches(c.Request().Context(), c.Param("productId"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, comparisons)
+}
+
+// CompareReviewImages is CompareProductImages for the listing under review
+func (h *ImageMatchHandler) CompareReviewImages(c echo.Context) error {
+	comparisons, err := h.imageMatchUseCase.CompareReviewImageMatches(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, comparisons)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+// maxInventoryFileSize bounds an uploaded code file
+const maxInventoryFileSize = 2 << 20
+
+type InventoryHandler struct {
+	inventoryUseCase *usecase.InventoryUseCase
+}
+
+func NewInventoryHandler(inventoryUseCase *usecase.InventoryUseCase) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryUseCase: inventoryUseCase,
+	}
+}
+
+// UploadCodes accepts either JSON {variant_id, format, content} with a pasted
+// list, or a multipart form with a "file" (CSV or one code per line) and an
+// optional variant_id
+func (h *InventoryHandler) UploadCodes(c echo.Context) error {
+	var input usecase.UploadInventoryInput
+
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return response.Error(c, errors.BadRequest("Missing or invalid file", err))
+		}
+		if file.Size > maxInventoryFileSize {
+			return response.Error(c, errors.BadRequest("Code files can be at most 2 MB", nil))
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return response.Error(c, errors.BadRequest("Failed to read file", err))
+		}
+		defer src.Close()
+
+		content, err := io.ReadAll(io.LimitReader(src, maxInventoryFileSize))
+		if err != nil {
+			return response.Error(c, errors.BadRequest("Failed to read file", err))
+		}
+
+		input.VariantID = c.FormValue("variant_id")
+		input.Format = c.FormValue("format")
+		input.Content = string(content)
+		if input.Format == "" && strings.EqualFold(filepath.Ext(file.Filename), ".csv") {
+			input.Format = usecase.InventoryFormatCSV
+		}
+	} else if err := c.Bind(&input); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	sellerID := c.Get("uid").(string)
+
+	result, err := h.inventoryUseCase.UploadCodes(c.Request().Context(), sellerID, c.Param("id"), input)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Created(c, result)
+}
+
+// ListCodes lists a product's codes; filter with ?status= and ?variant_id=
+func (h *InventoryHandler) ListCodes(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+	pagination := utils.GetPaginationParams(c)
+
+	codes, pageInfo, err := h.inventoryUseCase.ListCodes(
+		c.Request().Context(),
+		sellerID,
+		c.Param("id"),
+		c.QueryParam("variant_id"),
+		c.QueryParam("status"),
+		pagination,
+	)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, codes, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
+}
+
+func (h *InventoryHandler) GetSummary(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+
+	summary, err := h.inventoryUseCase.Summary(c.Request().Context(), sellerID, c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, summary)
+}
+
+func (h *InventoryHandler) RemoveCode(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+
+	if err := h.inventoryUseCase.RemoveCode(c.Request().Context(), sellerID, c.Param("id"), c.Param("codeId")); err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]string{
+		"message": "Code removed from inventory",
+	})
+}
+
+type reportCodeRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// ReportCode swaps an invalid delivered code for a new one
+func (h *InventoryHandler) ReportCode(c echo.Context) error {
+	var req reportCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	buyerID := c.Get("uid").(string)
+
+	transaction, err := h.inventoryUseCase.ReportInvalidCode(c.Request().Context(), buyerID, c.Param("id"), req.Reason)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"message":     "A replacement code has been delivered. Reveal the credentials again to see it",
+		"transaction": transaction,
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type JobHandler struct {
+	jobSchedulerUseCase *usecase.JobSchedulerUseCase
+}
+
+func NewJobHandler(jobSchedulerUseCase *usecase.JobSchedulerUseCase) *JobHandler {
+	return &JobHandler{
+		jobSchedulerUseCase: jobSchedulerUseCase,
+	}
+}
+
+// ListJobs returns the registered background jobs and their lease holders
+func (h *JobHandler) ListJobs(c echo.Context) error {
+	jobs, err := h.jobSchedulerUseCase.ListJobs(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, jobs)
+}
+
+// RunJob executes a job now and returns its run record
+func (h *JobHandler) RunJob(c echo.Context) error {
+	run, err := h.jobSchedulerUseCase.RunNow(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, run)
+}
+
+// ListJobRuns returns the run history, optionally filtered by ?job=
+func (h *JobHandler) ListJobRuns(c echo.Context) error {
+	jobName := c.QueryParam("job")
+
+	pagination := utils.GetPaginationParams(c)
+
+	runs, total, err := h.jobSchedulerUseCase.ListRuns(
+		c.Request().Context(),
+		jobName,
+		pagination.Page,
+		pagination.PageSize,
+	)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, runs, total, pagination.Page, pagination.PageSize)
+}
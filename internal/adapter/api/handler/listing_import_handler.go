@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/infrastructure/spreadsheet"
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+)
+
+// maxImportFileSize bounds an uploaded import sheet
+const maxImportFileSize = 5 << 20
+
+type ListingImportHandler struct {
+	listingImportUseCase *usecase.ListingImportUseCase
+}
+
+func NewListingImportHandler(listingImportUseCase *usecase.ListingImportUseCase) *ListingImportHandler {
+	return &ListingImportHandler{
+		listingImportUseCase: listingImportUseCase,
+	}
+}
+
+// PreviewImport validates a multipart "file" (.csv or .xlsx) and returns the
+// import job with the errors of each row; nothing is written to the catalog
+// until the job is started
+func (h *ListingImportHandler) PreviewImport(c echo.Context) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Missing or invalid file", err))
+	}
+	if file.Size > maxImportFileSize {
+		return response.Error(c, errors.BadRequest("Import sheets can be at most 5 MB", nil))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Failed to read file", err))
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(io.LimitReader(src, maxImportFileSize))
+	if err != nil {
+		return response.Error(c, errors.BadRequest("Failed to read file", err))
+	}
+
+	sellerID := c.Get("uid").(string)
+
+	preview, err := h.listingImportUseCase.PreviewImport(c.Request().Context(), sellerID, file.Filename, content)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Created(c, preview)
+}
+
+// StartImport queues a previewed job to create and update its listings in
+// the background; poll GetImportJob for progress
+func (h *ListingImportHandler) StartImport(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+
+	job, err := h.listingImportUseCase.StartImport(c.Request().Context(), sellerID, c.Param("jobId"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, job)
+}
+
+func (h *ListingImportHandler) GetImportJob(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+
+	job, err := h.listingImportUseCase.GetImportJob(c.Request().Context(), sellerID, c.Param("jobId"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, job)
+}
+
+// ExportCatalog downloads the seller's listings in the import format;
+// ?format=csv (default) or xlsx
+func (h *ListingImportHandler) ExportCatalog(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+
+	sellerID := c.Get("uid").(string)
+
+	data, err := h.listingImportUseCase.ExportCatalog(c.Request().Context(), sellerID, format)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	fileName := fmt.Sprintf("listings-%s.%s", time.Now().Format("20060102"), format)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	return c.Blob(http.StatusOK, spreadsheet.ContentTypes[format], data)
+}
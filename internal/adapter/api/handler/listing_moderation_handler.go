@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type ListingModerationHandler struct {
+	moderationUseCase *usecase.ListingModerationUseCase
+}
+
+func NewListingModerationHandler(moderationUseCase *usecase.ListingModerationUseCase) *ListingModerationHandler {
+	return &ListingModerationHandler{
+		moderationUseCase: moderationUseCase,
+	}
+}
+
+type resubmitListingRequest struct {
+	Note string `json:"note"`
+}
+
+type approveListingRequest struct {
+	Note string `json:"note"`
+}
+
+type rejectListingRequest struct {
+	Reasons []string `json:"reasons" validate:"required,min=1,dive,required"`
+	Note    string   `json:"note"`
+}
+
+// GetMyListingReview returns the latest review round of one of the seller's listings
+func (h *ListingModerationHandler) GetMyListingReview(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+
+	review, err := h.moderationUseCase.GetListingReview(c.Request().Context(), sellerID, c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, review)
+}
+
+func (h *ListingModerationHandler) ResubmitListing(c echo.Context) error {
+	var req resubmitListingRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	sellerID := c.Get("uid").(string)
+
+	review, err := h.moderationUseCase.Resubmit(c.Request().Context(), sellerID, c.Param("id"), req.Note)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, review)
+}
+
+// ListReviews returns the moderation queue; ?status= defaults to pending
+func (h *ListingModerationHandler) ListReviews(c echo.Context) error {
+	pagination := utils.GetPaginationParams(c)
+
+	status := c.QueryParam("status")
+	if status == "" {
+		status = usecase.ListingReviewPending
+	} else if status == "all" {
+		status = ""
+	}
+
+	reviews, pageInfo, err := h.moderationUseCase.ListReviews(c.Request().Context(), status, pagination)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, reviews, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
+}
+
+func (h *ListingModerationHandler) GetReview(c echo.Context) error {
+	review, err := h.moderationUseCase.GetReview(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, review)
+}
+
+func (h *ListingModerationHandler) ApproveReview(c echo.Context) error {
+	var req approveListingRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	review, err := h.moderationUseCase.ApproveReview(c.Request().Context(), adminID, c.Param("id"), req.Note)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, review)
+}
+
+func (h *ListingModerationHandler) RejectReview(c echo.Context) error {
+	var req rejectListingRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	adminID := c.Get("uid").(string)
+
+	review, err := h.moderationUseCase.RejectReview(c.Request().Context(), adminID, c.Param("id"), req.Reasons, req.Note)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, review)
+}
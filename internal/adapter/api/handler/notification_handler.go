@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type NotificationHandler struct {
+	notificationUseCase *usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUseCase *usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// ListNotifications lists the user's notifications, newest first; pass
+// ?unread=true for unread ones only
+func (h *NotificationHandler) ListNotifications(c echo.Context) error {
+	userID := c.Get("uid").(string)
+	pagination := utils.GetPaginationParams(c)
+
+	notifications, pageInfo, err := h.notificationUseCase.ListNotifications(
+		c.Request().Context(),
+		userID,
+		c.QueryParam("unread") == "true",
+		pagination,
+	)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, notifications, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
+}
+
+func (h *NotificationHandler) GetUnreadCount(c echo.Context) error {
+	userID := c.Get("uid").(string)
+
+	count, err := h.notificationUseCase.CountUnread(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"count": count,
+	})
+}
+
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	userID := c.Get("uid").(string)
+
+	if err := h.notificationUseCase.MarkRead(c.Request().Context(), userID, c.Param("id")); err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]string{
+		"message": "Notification marked as read",
+	})
+}
+
+func (h *NotificationHandler) MarkAllRead(c echo.Context) error {
+	userID := c.Get("uid").(string)
+
+	updated, err := h.notificationUseCase.MarkAllRead(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"updated": updated,
+	})
+}
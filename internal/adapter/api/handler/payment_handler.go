@@ -29,6 +29,7 @@ func NewPaymentHandler(enhancedTransactionUC *usecase.EnhancedTransactionUseCase
 
 type CreateSecureTransactionRequest struct {
 	ProductID      string `json:"product_id" validate:"required"`
+	VariantID      string `json:"variant_id,omitempty"`
 	DeliveryMethod string `json:"delivery_method" validate:"required,oneof=instant middleman"`
 	PaymentMethod  string `json:"payment_method" validate:"required,oneof=midtrans_snap midtrans_bank_transfer wallet"`
 	MiddlemanID    string `json:"middleman_id,omitempty"`
@@ -65,6 +66,7 @@ func (h *PaymentHandler) CreateSecureTransaction(c echo.Context) error {
 	// Create transaction input
 	input := usecase.CreateSecureTransactionInput{
 		ProductID:      req.ProductID,
+		VariantID:      req.VariantID,
 		DeliveryMethod: req.DeliveryMethod,
 		PaymentMethod:  req.PaymentMethod,
 		MiddlemanID:    req.MiddlemanID,
@@ -86,6 +88,48 @@ func (h *PaymentHandler) CreateSecureTransaction(c echo.Context) error {
 	return response.Success(c, result)
 }
 
+type ContinuePaymentRequest struct {
+	Embed bool `json:"embed"`
+
+	CustomerFirstName string `json:"customer_first_name" validate:"required"`
+	CustomerLastName  string `json:"customer_last_name,omitempty"`
+	CustomerEmail     string `json:"customer_email" validate:"required,email"`
+	CustomerPhone     string `json:"customer_phone" validate:"required"`
+}
+
+// ContinuePayment creates the payment for a transaction approved after fraud review
+func (h *PaymentHandler) ContinuePayment(c echo.Context) error {
+	transactionID := c.Param("id")
+
+	var req ContinuePaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	userID, ok := c.Get("uid").(string)
+	if !ok {
+		return response.Error(c, errors.Unauthorized("User not authenticated", nil))
+	}
+
+	customer := service.CustomerDetails{
+		FirstName: req.CustomerFirstName,
+		LastName:  req.CustomerLastName,
+		Email:     req.CustomerEmail,
+		Phone:     req.CustomerPhone,
+	}
+
+	result, err := h.enhancedTransactionUC.ContinuePayment(c.Request().Context(), transactionID, userID, customer, req.Embed)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, result)
+}
+
 // MidtransCallback handles payment callbacks from Midtrans with security verification
 func (h *PaymentHandler) MidtransCallback(c echo.Context) error {
 	log.Printf("Received Midtrans webhook callback from IP: %s", c.RealIP())
@@ -172,6 +216,7 @@ func (h *PaymentHandler) GetPaymentStatus(c echo.Context) error {
 func (h *PaymentHandler) CreateInstantTransaction(c echo.Context) error {
 	var req struct {
 		ProductID      string `json:"product_id" validate:"required"`
+		VariantID      string `json:"variant_id,omitempty"`
 		DeliveryMethod string `json:"delivery_method" validate:"required"`
 		PaymentMethod  string `json:"payment_method" validate:"required"`
 		Embed          bool   `json:"embed" default:"false"`
@@ -194,6 +239,7 @@ func (h *PaymentHandler) CreateInstantTransaction(c echo.Context) error {
 	// Create simplified transaction for instant delivery
 	input := usecase.CreateSecureTransactionInput{
 		ProductID:      req.ProductID,
+		VariantID:      req.VariantID,
 		DeliveryMethod: req.DeliveryMethod,
 		PaymentMethod:  req.PaymentMethod,
 		Embed:          req.Embed,
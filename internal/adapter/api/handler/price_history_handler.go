@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+)
+
+type PriceHistoryHandler struct {
+	priceWatchUseCase *usecase.PriceWatchUseCase
+}
+
+func NewPriceHistoryHandler(priceWatchUseCase *usecase.PriceWatchUseCase) *PriceHistoryHandler {
+	return &PriceHistoryHandler{
+		priceWatchUseCase: priceWatchUseCase,
+	}
+}
+
+// GetPriceHistory returns a step chart of the product's listed price, or of
+// one variant's with ?variant_id=, over the last ?days= (90 by default)
+func (h *PriceHistoryHandler) GetPriceHistory(c echo.Context) error {
+	days := 0
+	if daysParam := c.QueryParam("days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 {
+			return response.Error(c, errors.BadRequest("days must be a positive number", err))
+		}
+		days = parsed
+	}
+
+	chart, err := h.priceWatchUseCase.GetPriceHistory(c.Request().Context(), c.Param("id"), c.QueryParam("variant_id"), days)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, chart)
+}
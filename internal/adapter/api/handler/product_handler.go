@@ -33,16 +33,20 @@ type validateCredentialsRequest struct {
 }
 
 type createProductRequest struct {
-	GameTitleID    string                 `json:"game_title_id" validate:"required"`
-	Title          string                 `json:"title" validate:"required"`
-	Description    string                 `json:"description"`
-	Price          float64                `json:"price" validate:"required,gt=0"`
-	Type           string                 `json:"type" validate:"required,oneof=account topup boosting item"`
-	Attributes     map[string]interface{} `json:"attributes"`
-	Images         []productImageRequest  `json:"images"`
-	Status         string                 `json:"status" validate:"required,oneof=draft active"`
-	DeliveryMethod string                 `json:"delivery_method" validate:"required,oneof=instant middleman both"`
-	Credentials    map[string]interface{} `json:"credentials,omitempty"`
+	GameTitleID    string                        `json:"game_title_id" validate:"required"`
+	Title          string                        `json:"title" validate:"required"`
+	Description    string                        `json:"description"`
+	Price          float64                       `json:"price" validate:"gte=0"` // required unless variants are given
+	Type           string                        `json:"type" validate:"required,oneof=account topup boosting item"`
+	Attributes     map[string]interface{}        `json:"attributes"`
+	Images         []productImageRequest         `json:"images"`
+	Status         string                        `json:"status" validate:"required,oneof=draft active"`
+	DeliveryMethod string                        `json:"delivery_method" validate:"required,oneof=instant middleman both"`
+	Credentials    map[string]interface{}        `json:"credentials,omitempty"`
+	Variants       []usecase.ProductVariantInput `json:"variants"`
+	// Sellers upload codes through /v1/my-products/:id/inventory
+	UsesCodeInventory bool `json:"uses_code_inventory"`
+	LowStockThreshold int  `json:"low_stock_threshold" validate:"gte=0"`
 }
 
 func (h *ProductHandler) CreateProduct(c echo.Context) error {
@@ -78,6 +82,10 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 			Status:         req.Status,
 			DeliveryMethod: req.DeliveryMethod,
 			Credentials:    req.Credentials,
+			Variants:       req.Variants,
+
+			UsesCodeInventory: req.UsesCodeInventory,
+			LowStockThreshold: req.LowStockThreshold,
 		},
 		images,
 	)
@@ -125,6 +133,10 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 			Status:         req.Status,
 			DeliveryMethod: req.DeliveryMethod,
 			Credentials:    req.Credentials,
+			Variants:       req.Variants,
+
+			UsesCodeInventory: req.UsesCodeInventory,
+			LowStockThreshold: req.LowStockThreshold,
 		},
 		images,
 	)
@@ -181,7 +193,8 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 
 	pagination := utils.GetPaginationParams(c)
 
-	products, total, err := h.productUseCase.ListProducts(
+	// attr.<name>=value, attr.<name>.min / .max for numeric attributes
+	products, total, facets, err := h.productUseCase.ListProducts(
 		c.Request().Context(),
 		gameTitleID,
 		productType,
@@ -189,6 +202,7 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 		minPrice,
 		maxPrice,
 		sort,
+		c.QueryParams(),
 		pagination.Page,
 		pagination.PageSize,
 	)
@@ -197,7 +211,7 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 		return response.Error(c, err)
 	}
 
-	return response.Paginated(c, products, total, pagination.Page, pagination.PageSize)
+	return response.PaginatedWithFacets(c, products, total, pagination.Page, pagination.PageSize, facets)
 }
 
 func (h *ProductHandler) SearchProducts(c echo.Context) error {
@@ -248,7 +262,7 @@ func (h *ProductHandler) SearchProducts(c echo.Context) error {
 
 	pagination := utils.GetPaginationParams(c)
 
-	products, total, err := h.productUseCase.SearchProducts(
+	products, total, facets, err := h.productUseCase.SearchProducts(
 		c.Request().Context(),
 		query,
 		gameTitleID,
@@ -256,6 +270,7 @@ func (h *ProductHandler) SearchProducts(c echo.Context) error {
 		status,
 		minPrice,
 		maxPrice,
+		c.QueryParams(),
 		pagination.Page,
 		pagination.PageSize,
 	)
@@ -267,7 +282,7 @@ func (h *ProductHandler) SearchProducts(c echo.Context) error {
 
 	log.Printf("Search returned %d products", len(products))
 
-	return response.Paginated(c, products, total, pagination.Page, pagination.PageSize)
+	return response.PaginatedWithFacets(c, products, total, pagination.Page, pagination.PageSize, facets)
 }
 
 func (h *ProductHandler) ListMyProducts(c echo.Context) error {
@@ -278,19 +293,18 @@ func (h *ProductHandler) ListMyProducts(c echo.Context) error {
 
 	status := c.QueryParam("status")
 
-	products, total, err := h.productUseCase.ListBySellerID(
+	products, pageInfo, err := h.productUseCase.ListBySellerID(
 		c.Request().Context(),
 		sellerID,
 		status,
-		pagination.PageSize,
-		pagination.Offset,
+		pagination,
 	)
 
 	if err != nil {
 		return response.Error(c, err)
 	}
 
-	return response.Paginated(c, products, total, pagination.Page, pagination.PageSize)
+	return response.Paginated(c, products, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
 }
 
 func (h *ProductHandler) DeleteProduct(c echo.Context) error {
@@ -349,6 +363,41 @@ func (h *ProductHandler) BumpProduct(c echo.Context) error {
 	})
 }
 
+// RenewProduct relists an expired product, or restarts the inactivity
+// period of an active one
+func (h *ProductHandler) RenewProduct(c echo.Context) error {
+	id := c.Param("id")
+
+	sellerID := c.Get("uid").(string)
+
+	product, err := h.productUseCase.RenewProduct(c.Request().Context(), id, sellerID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"message": "Product renewed successfully",
+		"product": product,
+	})
+}
+
+// UpdateProductVariant changes the price or stock of one variant
+func (h *ProductHandler) UpdateProductVariant(c echo.Context) error {
+	var input usecase.UpdateVariantInput
+	if err := c.Bind(&input); err != nil {
+		return response.Error(c, err)
+	}
+
+	sellerID := c.Get("uid").(string)
+
+	product, err := h.productUseCase.UpdateProductVariant(c.Request().Context(), c.Param("id"), c.Param("variantId"), sellerID, input)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, product)
+}
+
 func (h *ProductHandler) MigrateProductsBumpedAt(c echo.Context) error {
 
 	if err := h.productUseCase.MigrateProductsBumpedAt(c.Request().Context()); err != nil {
@@ -360,6 +409,17 @@ func (h *ProductHandler) MigrateProductsBumpedAt(c echo.Context) error {
 	})
 }
 
+// GetAttributeSchemaReport lists listings whose attributes no longer fit
+// their game's schema; filter with ?game_title_id=
+func (h *ProductHandler) GetAttributeSchemaReport(c echo.Context) error {
+	report, err := h.productUseCase.AttributeSchemaReport(c.Request().Context(), c.QueryParam("game_title_id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, report)
+}
+
 func (h *ProductHandler) ValidateCredentials(c echo.Context) error {
 	var req validateCredentialsRequest
 	if err := c.Bind(&req); err != nil {
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type PromotionHandler struct {
+	promotionUseCase *usecase.PromotionUseCase
+}
+
+func NewPromotionHandler(promotionUseCase *usecase.PromotionUseCase) *PromotionHandler {
+	return &PromotionHandler{
+		promotionUseCase: promotionUseCase,
+	}
+}
+
+func (h *PromotionHandler) ListPackages(c echo.Context) error {
+	return response.Success(c, usecase.PromotionCatalog)
+}
+
+type purchasePromotionRequest struct {
+	PackageID string `json:"package_id" validate:"required"`
+}
+
+// PurchasePromotion buys a catalog package for the product with the seller's
+// wallet balance
+func (h *PromotionHandler) PurchasePromotion(c echo.Context) error {
+	var req purchasePromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	sellerID := c.Get("uid").(string)
+
+	promotion, err := h.promotionUseCase.PurchasePromotion(c.Request().Context(), sellerID, c.Param("id"), req.PackageID)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Created(c, promotion)
+}
+
+func (h *PromotionHandler) ListMyPromotions(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+	pagination := utils.GetPaginationParams(c)
+
+	promotions, pageInfo, err := h.promotionUseCase.ListPromotions(c.Request().Context(), sellerID, pagination)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, promotions, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
+}
+
+func (h *PromotionHandler) GetPromotionReport(c echo.Context) error {
+	sellerID := c.Get("uid").(string)
+
+	report, err := h.promotionUseCase.GetPromotionReport(c.Request().Context(), sellerID, c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, report)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/response"
+)
+
+type RecommendationHandler struct {
+	recommendationUseCase *usecase.RecommendationUseCase
+}
+
+func NewRecommendationHandler(recommendationUseCase *usecase.RecommendationUseCase) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationUseCase: recommendationUseCase,
+	}
+}
+
+// GetSimilarProducts returns listings most like the given one
+func (h *RecommendationHandler) GetSimilarProducts(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	products, err := h.recommendationUseCase.GetSimilarProducts(c.Request().Context(), c.Param("id"), limit)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, products)
+}
+
+// GetAlsoViewedProducts returns listings that buyers interested in the given
+// one also viewed
+func (h *RecommendationHandler) GetAlsoViewedProducts(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	products, err := h.recommendationUseCase.GetAlsoViewedProducts(c.Request().Context(), c.Param("id"), limit)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, products)
+}
+
+// GetRecommendedProducts returns listings picked for the signed-in user
+func (h *RecommendationHandler) GetRecommendedProducts(c echo.Context) error {
+	userID := c.Get("uid").(string)
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	products, err := h.recommendationUseCase.GetRecommendedProducts(c.Request().Context(), userID, limit)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, products)
+}
@@ -8,6 +8,7 @@ import (
 	"pasargamex/internal/usecase"
 	"pasargamex/pkg/errors"
 	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
 )
 
 type ReviewHandler struct {
@@ -95,20 +96,22 @@ func (h *ReviewHandler) GetReviews(c echo.Context) error {
 		}
 	}
 
-	reviews, total, err := h.reviewUseCase.ListReviews(
+	pagination := utils.NewPaginationParams(page, limit)
+	pagination.Cursor = c.QueryParam("cursor")
+
+	reviews, pageInfo, err := h.reviewUseCase.ListReviews(
 		c.Request().Context(),
 		userID,
 		reviewType,
 		rating,
-		page,
-		limit,
+		pagination,
 	)
 
 	if err != nil {
 		return response.Error(c, err)
 	}
 
-	return response.Paginated(c, reviews, total, page, limit)
+	return response.Paginated(c, reviews, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
 }
 
 type reportReviewRequest struct {
@@ -226,19 +229,21 @@ func (h *ReviewHandler) GetProductReviews(c echo.Context) error {
 		}
 	}
 
-	reviews, total, err := h.reviewUseCase.GetProductReviews(
+	pagination := utils.NewPaginationParams(page, limit)
+	pagination.Cursor = c.QueryParam("cursor")
+
+	reviews, pageInfo, err := h.reviewUseCase.GetProductReviews(
 		c.Request().Context(),
 		productID,
 		rating,
-		page,
-		limit,
+		pagination,
 	)
 
 	if err != nil {
 		return response.Error(c, err)
 	}
 
-	return response.Paginated(c, reviews, total, page, limit)
+	return response.Paginated(c, reviews, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
 }
 
 func (h *ReviewHandler) GetReviewReports(c echo.Context) error {
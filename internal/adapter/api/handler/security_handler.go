@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/response"
+)
+
+type SecurityHandler struct {
+	securityLogUseCase *usecase.SecurityLogUseCase
+}
+
+func NewSecurityHandler(securityLogUseCase *usecase.SecurityLogUseCase) *SecurityHandler {
+	return &SecurityHandler{
+		securityLogUseCase: securityLogUseCase,
+	}
+}
+
+// GetLinkedAccounts returns the accounts sharing a device or IP with the user
+func (h *SecurityHandler) GetLinkedAccounts(c echo.Context) error {
+	linkage, err := h.securityLogUseCase.GetLinkedAccounts(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, linkage)
+}
+
+// ListSecurityLogs returns the user's recent security events, newest first
+func (h *SecurityHandler) ListSecurityLogs(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	logs, err := h.securityLogUseCase.ListUserEvents(c.Request().Context(), c.Param("id"), limit)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, logs)
+}
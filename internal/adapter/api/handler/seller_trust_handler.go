@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+	"pasargamex/pkg/utils"
+)
+
+type SellerTrustHandler struct {
+	trustUseCase *usecase.SellerTrustUseCase
+}
+
+func NewSellerTrustHandler(trustUseCase *usecase.SellerTrustUseCase) *SellerTrustHandler {
+	return &SellerTrustHandler{
+		trustUseCase: trustUseCase,
+	}
+}
+
+type trustTierOverrideRequest struct {
+	Tier   string `json:"tier" validate:"required"`
+	Reason string `json:"reason" validate:"required"`
+}
+
+// ListProfiles returns seller trust profiles, riskiest first, filtered by ?tier=
+func (h *SellerTrustHandler) ListProfiles(c echo.Context) error {
+	pagination := utils.GetPaginationParams(c)
+
+	profiles, total, err := h.trustUseCase.ListProfiles(
+		c.Request().Context(),
+		c.QueryParam("tier"),
+		pagination.Page,
+		pagination.PageSize,
+	)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Paginated(c, profiles, total, pagination.Page, pagination.PageSize)
+}
+
+func (h *SellerTrustHandler) GetProfile(c echo.Context) error {
+	profile, err := h.trustUseCase.GetProfile(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, profile)
+}
+
+// GetTierPolicies returns what each trust tier changes for a seller
+func (h *SellerTrustHandler) GetTierPolicies(c echo.Context) error {
+	return response.Success(c, usecase.TrustTierPolicies)
+}
+
+func (h *SellerTrustHandler) OverrideTier(c echo.Context) error {
+	var req trustTierOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, err)
+	}
+
+	adminID := c.Get("uid").(string)
+
+	profile, err := h.trustUseCase.OverrideTier(c.Request().Context(), adminID, c.Param("id"), req.Tier, req.Reason)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, profile)
+}
+
+func (h *SellerTrustHandler) ClearOverride(c echo.Context) error {
+	adminID := c.Get("uid").(string)
+
+	profile, err := h.trustUseCase.ClearOverride(c.Request().Context(), adminID, c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, profile)
+}
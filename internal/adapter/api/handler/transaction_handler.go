@@ -25,6 +25,7 @@ func SetupTransactionHandler(transactionUseCase *usecase.TransactionUseCase) {
 
 type createTransactionRequest struct {
 	ProductID      string `json:"product_id" validate:"required"`
+	VariantID      string `json:"variant_id,omitempty"`
 	DeliveryMethod string `json:"delivery_method" validate:"required,oneof=instant middleman"`
 	Notes          string `json:"notes,omitempty"`
 }
@@ -43,6 +44,7 @@ func (h *TransactionHandler) CreateTransaction(c echo.Context) error {
 
 	transaction, err := h.transactionUseCase.CreateTransaction(c.Request().Context(), userID, usecase.CreateTransactionInput{
 		ProductID:      req.ProductID,
+		VariantID:      req.VariantID,
 		DeliveryMethod: req.DeliveryMethod,
 		Notes:          req.Notes,
 	})
@@ -95,20 +97,19 @@ func (h *TransactionHandler) ListTransactions(c echo.Context) error {
 
 	userID := c.Get("uid").(string)
 
-	transactions, total, err := h.transactionUseCase.ListTransactions(
+	transactions, pageInfo, err := h.transactionUseCase.ListTransactions(
 		c.Request().Context(),
 		userID,
 		role,
 		status,
-		pagination.Page,
-		pagination.PageSize,
+		pagination,
 	)
 
 	if err != nil {
 		return response.Error(c, err)
 	}
 
-	return response.Paginated(c, transactions, total, pagination.Page, pagination.PageSize)
+	return response.Paginated(c, transactions, pageInfo.Total, pagination.Page, pagination.PageSize, pageInfo.NextCursor)
 }
 
 type processPaymentRequest struct {
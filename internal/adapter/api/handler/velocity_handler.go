@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/response"
+)
+
+type VelocityHandler struct {
+	velocityUseCase *usecase.VelocityUseCase
+}
+
+func NewVelocityHandler(velocityUseCase *usecase.VelocityUseCase) *VelocityHandler {
+	return &VelocityHandler{
+		velocityUseCase: velocityUseCase,
+	}
+}
+
+type velocityRuleRequest struct {
+	Name        string  `json:"name" validate:"required"`
+	Description string  `json:"description"`
+	Action      string  `json:"action" validate:"required"`
+	Scope       string  `json:"scope" validate:"required"`
+	Metric      string  `json:"metric" validate:"required"`
+	Limit       float64 `json:"limit" validate:"required"`
+	WindowHours int     `json:"window_hours" validate:"required"`
+	Enabled     bool    `json:"enabled"`
+}
+
+func (r velocityRuleRequest) toInput() usecase.VelocityRuleInput {
+	return usecase.VelocityRuleInput{
+		Name:        r.Name,
+		Description: r.Description,
+		Action:      r.Action,
+		Scope:       r.Scope,
+		Metric:      r.Metric,
+		Limit:       r.Limit,
+		WindowHours: r.WindowHours,
+		Enabled:     r.Enabled,
+	}
+}
+
+func (h *VelocityHandler) ListRules(c echo.Context) error {
+	rules, err := h.velocityUseCase.ListRules(c.Request().Context())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	actions := []string{}
+	for action := range usecase.VelocityActions {
+		actions = append(actions, action)
+	}
+
+	return response.Success(c, map[string]interface{}{
+		"rules":   rules,
+		"actions": actions,
+	})
+}
+
+func (h *VelocityHandler) CreateRule(c echo.Context) error {
+	var req velocityRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	rule, err := h.velocityUseCase.CreateRule(c.Request().Context(), adminID, req.toInput())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Created(c, rule)
+}
+
+func (h *VelocityHandler) UpdateRule(c echo.Context) error {
+	id := c.Param("id")
+
+	var req velocityRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	adminID := c.Get("uid").(string)
+
+	rule, err := h.velocityUseCase.UpdateRule(c.Request().Context(), adminID, id, req.toInput())
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, rule)
+}
+
+func (h *VelocityHandler) DeleteRule(c echo.Context) error {
+	id := c.Param("id")
+	adminID := c.Get("uid").(string)
+
+	if err := h.velocityUseCase.DeleteRule(c.Request().Context(), adminID, id); err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, map[string]string{
+		"message": "Velocity rule deleted successfully",
+	})
+}
+
+// GetUserUsage shows how close a user is to each velocity limit
+func (h *VelocityHandler) GetUserUsage(c echo.Context) error {
+	usage, err := h.velocityUseCase.GetUserUsage(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, usage)
+}
@@ -139,8 +139,9 @@ func (h *WalletHandler) GetWalletTransactions(c echo.Context) error {
 	}
 
 	pagination := &utils.Pagination{
-		Page:  page,
-		Limit: limit,
+		Page:   page,
+		Limit:  limit,
+		Cursor: c.QueryParam("cursor"),
 	}
 
 	transactions, err := h.walletUseCase.GetWalletTransactions(c.Request().Context(), userID, pagination)
@@ -149,7 +150,7 @@ func (h *WalletHandler) GetWalletTransactions(c echo.Context) error {
 		return response.Error(c, err)
 	}
 
-	return response.Success(c, transactions)
+	return response.Paginated(c, transactions, pagination.Total, page, limit, pagination.NextCursor)
 }
 
 // Payment Methods
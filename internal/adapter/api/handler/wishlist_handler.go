@@ -72,6 +72,32 @@ func (h *WishlistHandler) GetUserWishlist(c echo.Context) error {
 	return response.Paginated(c, items, total, pagination.Page, pagination.PageSize)
 }
 
+type setTargetPriceRequest struct {
+	TargetPrice float64 `json:"target_price" validate:"gte=0"`
+}
+
+// SetTargetPrice sets the price drop alert target of a wishlist item; 0 clears it
+func (h *WishlistHandler) SetTargetPrice(c echo.Context) error {
+	userID := c.Get("uid").(string)
+	productID := c.Param("productId")
+
+	var req setTargetPriceRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Invalid request body", err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, errors.BadRequest("Validation failed", err))
+	}
+
+	item, err := h.wishlistUseCase.SetTargetPrice(c.Request().Context(), userID, productID, req.TargetPrice)
+	if err != nil {
+		return response.Error(c, err)
+	}
+
+	return response.Success(c, item)
+}
+
 func (h *WishlistHandler) CheckWishlistStatus(c echo.Context) error {
 	userID := c.Get("uid").(string)
 	productID := c.Param("productId")
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"pasargamex/pkg/utils"
+)
+
+const maxDeviceIDLength = 128
+
+// ClientInfo puts the caller's IP, user agent and device ID on the request
+// context so use cases can write security logs
+func ClientInfo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			deviceID := strings.TrimSpace(c.Request().Header.Get("X-Device-ID"))
+			if len(deviceID) > maxDeviceIDLength {
+				deviceID = deviceID[:maxDeviceIDLength]
+			}
+
+			ctx := utils.WithClientInfo(c.Request().Context(), utils.ClientInfo{
+				IPAddress: c.RealIP(),
+				UserAgent: c.Request().UserAgent(),
+				DeviceID:  deviceID,
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JobTriggerMiddleware admits external schedulers presenting the shared job
+// trigger token in the X-Job-Token header
+type JobTriggerMiddleware struct {
+	token string
+}
+
+func NewJobTriggerMiddleware(token string) *JobTriggerMiddleware {
+	return &JobTriggerMiddleware{
+		token: token,
+	}
+}
+
+func (m *JobTriggerMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.token == "" {
+			return echo.NewHTTPError(http.StatusNotFound, "Job triggers are disabled")
+		}
+
+		provided := c.Request().Header.Get("X-Job-Token")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(m.token)) != 1 {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid job token")
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupAutoReleasePolicyRoutes(e *echo.Echo, policyHandler *handler.AutoReleasePolicyHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/auto-release-policies")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", policyHandler.ListPolicies)
+	admin.POST("", policyHandler.CreatePolicy)
+	admin.GET("/:id", policyHandler.GetPolicy)
+	admin.PUT("/:id", policyHandler.UpdatePolicy)
+	admin.DELETE("/:id", policyHandler.DeletePolicy)
+}
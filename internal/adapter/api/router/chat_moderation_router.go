@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupChatModerationRoutes(e *echo.Echo, chatModerationHandler *handler.ChatModerationHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/chat-offenders")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", chatModerationHandler.ListOffenders)
+	admin.GET("/:id", chatModerationHandler.GetOffender)
+	admin.POST("/:id/resolve", chatModerationHandler.ResolveOffender)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupCredentialRoutes(e *echo.Echo, credentialHandler *handler.CredentialHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/credentials")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.POST("/reencrypt", credentialHandler.ReencryptCredentials)
+	admin.POST("/rotate-key", credentialHandler.RotateKey)
+}
@@ -14,4 +14,8 @@ func SetupEscrowRoutes(e *echo.Echo, escrowHandler *handler.EscrowHandler, authM
 	escrowGroup.POST("/deliver-credentials", escrowHandler.DeliverCredentials, authMiddleware.Authenticate)
 	escrowGroup.POST("/confirm-credentials", escrowHandler.ConfirmCredentials, authMiddleware.Authenticate)
 	escrowGroup.GET("/transactions/:id/credentials", escrowHandler.GetTransactionCredentials, authMiddleware.Authenticate)
+	escrowGroup.GET("/transactions/:id/credentials/access-log", escrowHandler.GetCredentialAccessLog, authMiddleware.Authenticate)
+	escrowGroup.POST("/transactions/:id/verify", escrowHandler.VerifyDelivery, authMiddleware.Authenticate)
+	escrowGroup.GET("/transactions/:id/approvals", escrowHandler.GetTransactionApprovals, authMiddleware.Authenticate)
+	escrowGroup.POST("/transactions/:id/extend-auto-release", escrowHandler.ExtendAutoRelease, authMiddleware.Authenticate)
 }
\ No newline at end of file
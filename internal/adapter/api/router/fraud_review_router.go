@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupFraudReviewRoutes(e *echo.Echo, fraudReviewHandler *handler.FraudReviewHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/fraud-reviews")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", fraudReviewHandler.ListReviewQueue)
+	admin.POST("/:id/approve", fraudReviewHandler.ApproveTransaction)
+	admin.POST("/:id/reject", fraudReviewHandler.RejectTransaction)
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupFraudRuleRoutes(e *echo.Echo, fraudRuleHandler *handler.FraudRuleHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/fraud-rules")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", fraudRuleHandler.ListRules)
+	admin.POST("", fraudRuleHandler.CreateRule)
+	admin.POST("/dry-run", fraudRuleHandler.DryRun)
+	admin.PUT("/:id", fraudRuleHandler.UpdateRule)
+	admin.DELETE("/:id", fraudRuleHandler.DeleteRule)
+}
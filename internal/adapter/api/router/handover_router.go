@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupHandoverRoutes(e *echo.Echo, handoverHandler *handler.HandoverHandler, authMiddleware *middleware.AuthMiddleware) {
+	handoverGroup := e.Group("/v1/transactions/:id/handover")
+	handoverGroup.Use(authMiddleware.Authenticate)
+
+	handoverGroup.GET("", handoverHandler.GetChecklist)
+	handoverGroup.POST("/steps/:key/complete", handoverHandler.CompleteStep)
+	handoverGroup.POST("/steps/:key/confirm", handoverHandler.ConfirmStep)
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupImageMatchRoutes(e *echo.Echo, imageMatchHandler *handler.ImageMatchHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/image-matches")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", imageMatchHandler.ListImageMatches)
+	admin.GET("/products/:productId", imageMatchHandler.CompareProductImages)
+
+	e.GET("/v1/admin/listing-reviews/:id/image-matches", imageMatchHandler.CompareReviewImages, authMiddleware.Authenticate, adminMiddleware.AdminOnly)
+}
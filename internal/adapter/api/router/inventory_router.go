@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupInventoryRoutes(e *echo.Echo, inventoryHandler *handler.InventoryHandler, authMiddleware *middleware.AuthMiddleware) {
+	inventory := e.Group("/v1/my-products/:id/inventory")
+	inventory.Use(authMiddleware.Authenticate)
+
+	inventory.GET("", inventoryHandler.ListCodes)
+	inventory.POST("", inventoryHandler.UploadCodes)
+	inventory.GET("/summary", inventoryHandler.GetSummary)
+	inventory.DELETE("/:codeId", inventoryHandler.RemoveCode)
+
+	e.POST("/v1/escrow/transactions/:id/report-code", inventoryHandler.ReportCode, authMiddleware.Authenticate)
+}
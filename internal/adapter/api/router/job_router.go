@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupJobRoutes(e *echo.Echo, jobHandler *handler.JobHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware, jobTriggerMiddleware *middleware.JobTriggerMiddleware) {
+	admin := e.Group("/v1/admin/jobs")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", jobHandler.ListJobs)
+	admin.GET("/runs", jobHandler.ListJobRuns)
+	admin.POST("/:name/run", jobHandler.RunJob)
+
+	// Called by an external scheduler (e.g. Cloud Scheduler)
+	trigger := e.Group("/v1/jobs")
+	trigger.Use(jobTriggerMiddleware.Authenticate)
+
+	trigger.POST("/:name/run", jobHandler.RunJob)
+}
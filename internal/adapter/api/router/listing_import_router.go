@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupListingImportRoutes(e *echo.Echo, listingImportHandler *handler.ListingImportHandler, authMiddleware *middleware.AuthMiddleware) {
+	e.GET("/v1/my-products/export", listingImportHandler.ExportCatalog, authMiddleware.Authenticate)
+
+	imports := e.Group("/v1/my-products/imports")
+	imports.Use(authMiddleware.Authenticate)
+
+	imports.POST("", listingImportHandler.PreviewImport)
+	imports.GET("/:jobId", listingImportHandler.GetImportJob)
+	imports.POST("/:jobId/start", listingImportHandler.StartImport)
+}
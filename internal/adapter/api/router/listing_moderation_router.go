@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupListingModerationRoutes(e *echo.Echo, moderationHandler *handler.ListingModerationHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	seller := e.Group("/v1/my-products/:id")
+	seller.Use(authMiddleware.Authenticate)
+
+	seller.GET("/review", moderationHandler.GetMyListingReview)
+	seller.POST("/resubmit", moderationHandler.ResubmitListing)
+
+	admin := e.Group("/v1/admin/listing-reviews")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", moderationHandler.ListReviews)
+	admin.GET("/:id", moderationHandler.GetReview)
+	admin.POST("/:id/approve", moderationHandler.ApproveReview)
+	admin.POST("/:id/reject", moderationHandler.RejectReview)
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupNotificationRoutes(e *echo.Echo, notificationHandler *handler.NotificationHandler, authMiddleware *middleware.AuthMiddleware) {
+	notifications := e.Group("/v1/notifications")
+	notifications.Use(authMiddleware.Authenticate)
+
+	notifications.GET("", notificationHandler.ListNotifications)
+	notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+	notifications.POST("/read-all", notificationHandler.MarkAllRead)
+	notifications.PATCH("/:id/read", notificationHandler.MarkRead)
+}
@@ -15,6 +15,8 @@ func SetupPaymentRoutes(e *echo.Echo, paymentHandler *handler.PaymentHandler, au
 		middleware.PaymentRateLimit(), authMiddleware.Authenticate)
 	paymentGroup.POST("/transactions/instant", paymentHandler.CreateInstantTransaction, 
 		middleware.PaymentRateLimit(), authMiddleware.Authenticate) // Simplified for chat UI
+	paymentGroup.POST("/transactions/:id/pay", paymentHandler.ContinuePayment, 
+		middleware.PaymentRateLimit(), authMiddleware.Authenticate) // After fraud review approval
 	paymentGroup.GET("/transactions/:id/status", paymentHandler.GetPaymentStatus, 
 		middleware.GeneralRateLimit(), authMiddleware.Authenticate)
 
@@ -0,0 +1,10 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+)
+
+func SetupPriceHistoryRoutes(e *echo.Echo, priceHistoryHandler *handler.PriceHistoryHandler) {
+	e.GET("/v1/products/:id/price-history", priceHistoryHandler.GetPriceHistory)
+}
@@ -33,12 +33,15 @@ func SetupProductRouter(e *echo.Echo, authMiddleware *middleware.AuthMiddleware,
 	myProducts.PUT("/:id", productHandler.UpdateProduct)
 	myProducts.DELETE("/:id", productHandler.DeleteProduct)
 	myProducts.POST("/:id/bump", productHandler.BumpProduct)
+	myProducts.POST("/:id/renew", productHandler.RenewProduct)
+	myProducts.PATCH("/:id/variants/:variantId", productHandler.UpdateProductVariant)
 	myProducts.DELETE("/:id/images/:imageId", productHandler.DeleteProductImage)
 
 	admin := e.Group("/v1/admin/products")
 	admin.Use(authMiddleware.Authenticate)
 	admin.Use(adminMiddleware.AdminOnly)
 	admin.POST("/migrate-bumped-at", productHandler.MigrateProductsBumpedAt)
+	admin.GET("/attribute-report", productHandler.GetAttributeSchemaReport)
 
 	admin.POST("/validate-credentials", productHandler.ValidateCredentials)
 }
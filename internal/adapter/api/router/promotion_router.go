@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupPromotionRoutes(e *echo.Echo, promotionHandler *handler.PromotionHandler, authMiddleware *middleware.AuthMiddleware) {
+	e.GET("/v1/promotions/packages", promotionHandler.ListPackages)
+
+	e.POST("/v1/my-products/:id/promotions", promotionHandler.PurchasePromotion, authMiddleware.Authenticate)
+
+	promotions := e.Group("/v1/my-promotions")
+	promotions.Use(authMiddleware.Authenticate)
+
+	promotions.GET("", promotionHandler.ListMyPromotions)
+	promotions.GET("/:id/report", promotionHandler.GetPromotionReport)
+}
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupRecommendationRoutes(e *echo.Echo, recommendationHandler *handler.RecommendationHandler, authMiddleware *middleware.AuthMiddleware) {
+	products := e.Group("/v1/products")
+	products.GET("/:id/similar", recommendationHandler.GetSimilarProducts)
+	products.GET("/:id/also-viewed", recommendationHandler.GetAlsoViewedProducts)
+
+	products.GET("/recommended", recommendationHandler.GetRecommendedProducts, authMiddleware.Authenticate)
+}
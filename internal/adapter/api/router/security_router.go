@@ -0,0 +1,16 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupSecurityRoutes(e *echo.Echo, securityHandler *handler.SecurityHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/users")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("/:id/linked-accounts", securityHandler.GetLinkedAccounts)
+	admin.GET("/:id/security-logs", securityHandler.ListSecurityLogs)
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupSellerTrustRoutes(e *echo.Echo, sellerTrustHandler *handler.SellerTrustHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	admin := e.Group("/v1/admin/seller-trust")
+	admin.Use(authMiddleware.Authenticate)
+	admin.Use(adminMiddleware.AdminOnly)
+
+	admin.GET("", sellerTrustHandler.ListProfiles)
+	admin.GET("/tiers", sellerTrustHandler.GetTierPolicies)
+	admin.GET("/:id", sellerTrustHandler.GetProfile)
+	admin.PUT("/:id/override", sellerTrustHandler.OverrideTier)
+	admin.DELETE("/:id/override", sellerTrustHandler.ClearOverride)
+}
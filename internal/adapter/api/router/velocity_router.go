@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+	"pasargamex/internal/adapter/api/handler"
+	"pasargamex/internal/adapter/api/middleware"
+)
+
+func SetupVelocityRoutes(e *echo.Echo, velocityHandler *handler.VelocityHandler, authMiddleware *middleware.AuthMiddleware, adminMiddleware *middleware.AdminMiddleware) {
+	rules := e.Group("/v1/admin/velocity-rules")
+	rules.Use(authMiddleware.Authenticate)
+	rules.Use(adminMiddleware.AdminOnly)
+
+	rules.GET("", velocityHandler.ListRules)
+	rules.POST("", velocityHandler.CreateRule)
+	rules.PUT("/:id", velocityHandler.UpdateRule)
+	rules.DELETE("/:id", velocityHandler.DeleteRule)
+
+	users := e.Group("/v1/admin/users")
+	users.Use(authMiddleware.Authenticate)
+	users.Use(adminMiddleware.AdminOnly)
+
+	users.GET("/:id/velocity", velocityHandler.GetUserUsage)
+}
@@ -17,5 +17,6 @@ func SetupWishlistRouter(e *echo.Echo, wishlistHandler *handler.WishlistHandler,
 	wishlistGroup.DELETE("/:productId", wishlistHandler.RemoveFromWishlist) // DELETE /v1/wishlist/:productId - Remove from wishlist
 	wishlistGroup.GET("", wishlistHandler.GetUserWishlist)                 // GET /v1/wishlist - Get user's wishlist
 	wishlistGroup.GET("/:productId/status", wishlistHandler.CheckWishlistStatus) // GET /v1/wishlist/:productId/status - Check if in wishlist
+	wishlistGroup.PUT("/:productId/target", wishlistHandler.SetTargetPrice)      // PUT /v1/wishlist/:productId/target - Set price drop target
 	wishlistGroup.GET("/count", wishlistHandler.GetWishlistCount)          // GET /v1/wishlist/count - Get wishlist count
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreAutoReleasePolicyRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreAutoReleasePolicyRepository(client *firestore.Client) repository.AutoReleasePolicyRepository {
+	return &firestoreAutoReleasePolicyRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreAutoReleasePolicyRepository) Create(ctx context.Context, policy *entity.AutoReleasePolicy) error {
+	if policy.ID == "" {
+		policy.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	_, err := r.client.Collection("auto_release_policies").Doc(policy.ID).Set(ctx, policy)
+	if err != nil {
+		return errors.Internal("Failed to create auto-release policy", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreAutoReleasePolicyRepository) GetByID(ctx context.Context, id string) (*entity.AutoReleasePolicy, error) {
+	doc, err := r.client.Collection("auto_release_policies").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Auto-release policy", err)
+		}
+		return nil, errors.Internal("Failed to get auto-release policy", err)
+	}
+
+	var policy entity.AutoReleasePolicy
+	if err := doc.DataTo(&policy); err != nil {
+		return nil, errors.Internal("Failed to parse auto-release policy data", err)
+	}
+
+	return &policy, nil
+}
+
+func (r *firestoreAutoReleasePolicyRepository) Update(ctx context.Context, policy *entity.AutoReleasePolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("auto_release_policies").Doc(policy.ID).Set(ctx, policy)
+	if err != nil {
+		return errors.Internal("Failed to update auto-release policy", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreAutoReleasePolicyRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.Collection("auto_release_policies").Doc(id).Delete(ctx)
+	if err != nil {
+		return errors.Internal("Failed to delete auto-release policy", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreAutoReleasePolicyRepository) List(ctx context.Context, activeOnly bool) ([]*entity.AutoReleasePolicy, error) {
+	query := r.client.Collection("auto_release_policies").Query
+	if activeOnly {
+		query = query.Where("isActive", "==", true)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	policies := []*entity.AutoReleasePolicy{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list auto-release policies", err)
+		}
+
+		var policy entity.AutoReleasePolicy
+		if err := doc.DataTo(&policy); err != nil {
+			return nil, errors.Internal("Failed to parse auto-release policy data", err)
+		}
+
+		policies = append(policies, &policy)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreChatModerationRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreChatModerationRepository(client *firestore.Client) repository.ChatModerationRepository {
+	return &firestoreChatModerationRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreChatModerationRepository) CreateViolation(ctx context.Context, violation *entity.ChatViolation) error {
+	if violation.ID == "" {
+		violation.ID = uuid.New().String()
+	}
+	if violation.CreatedAt.IsZero() {
+		violation.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection("chat_violations").Doc(violation.ID).Set(ctx, violation)
+	if err != nil {
+		return errors.Internal("Failed to create chat violation", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreChatModerationRepository) ListViolationsByUser(ctx context.Context, userID string, since time.Time, limit int) ([]*entity.ChatViolation, error) {
+	// Filter on user only and check the time in memory to avoid a composite index
+	iter := r.client.Collection("chat_violations").
+		Where("userId", "==", userID).
+		Documents(ctx)
+	defer iter.Stop()
+
+	violations := []*entity.ChatViolation{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list chat violations", err)
+		}
+
+		var violation entity.ChatViolation
+		if err := doc.DataTo(&violation); err != nil {
+			log.Printf("Failed to parse chat violation %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		if !violation.CreatedAt.Before(since) {
+			violations = append(violations, &violation)
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].CreatedAt.After(violations[j].CreatedAt)
+	})
+
+	if limit > 0 && len(violations) > limit {
+		violations = violations[:limit]
+	}
+
+	return violations, nil
+}
+
+func (r *firestoreChatModerationRepository) GetOffender(ctx context.Context, userID string) (*entity.ChatOffender, error) {
+	doc, err := r.client.Collection("chat_offenders").Doc(userID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Chat offender", err)
+		}
+		return nil, errors.Internal("Failed to get chat offender", err)
+	}
+
+	var offender entity.ChatOffender
+	if err := doc.DataTo(&offender); err != nil {
+		return nil, errors.Internal("Failed to parse chat offender data", err)
+	}
+
+	return &offender, nil
+}
+
+func (r *firestoreChatModerationRepository) SaveOffender(ctx context.Context, offender *entity.ChatOffender) error {
+	offender.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("chat_offenders").Doc(offender.UserID).Set(ctx, offender)
+	if err != nil {
+		return errors.Internal("Failed to save chat offender", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreChatModerationRepository) ListOffenders(ctx context.Context, status string, limit, offset int) ([]*entity.ChatOffender, int64, error) {
+	query := r.client.Collection("chat_offenders").Query
+	if status != "" {
+		query = query.Where("status", "==", status)
+	}
+
+	allDocs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count chat offenders", err)
+	}
+	total := int64(len(allDocs))
+
+	query = query.OrderBy("lastViolationAt", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	offenders := []*entity.ChatOffender{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to list chat offenders", err)
+		}
+
+		var offender entity.ChatOffender
+		if err := doc.DataTo(&offender); err != nil {
+			log.Printf("Failed to parse chat offender %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		offenders = append(offenders, &offender)
+	}
+
+	return offenders, total, nil
+}
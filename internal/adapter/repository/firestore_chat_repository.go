@@ -15,6 +15,7 @@ import (
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
 	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
 )
 
 type firestoreChatRepository struct {
@@ -86,45 +87,44 @@ func (r *firestoreChatRepository) UpdateMessage(ctx context.Context, chatID stri
 }
 
 func (r *firestoreChatRepository) GetMessagesByChat(ctx context.Context, chatID string, limit, offset int) ([]*entity.Message, int64, error) {
-	query := r.client.Collection("chats").Doc(chatID).Collection("messages").OrderBy("createdAt", firestore.Desc)
-
-	countDocs, err := query.Documents(ctx).GetAll()
+	messages, info, err := r.GetMessagesByChatPage(ctx, chatID, utils.PaginationParams{PageSize: limit, Offset: offset})
 	if err != nil {
-		log.Printf("Firestore error while counting messages for chat %s: %v", chatID, err)
-		return nil, 0, errors.Internal("Failed to count messages for chat", err)
+		return nil, 0, err
 	}
-	total := int64(len(countDocs))
 
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-	if offset > 0 {
-		query = query.Offset(offset)
-	}
+	return messages, info.Total, nil
+}
 
-	iter := query.Documents(ctx)
-	var messages []*entity.Message
+func (r *firestoreChatRepository) GetMessagesByChatPage(ctx context.Context, chatID string, page utils.PaginationParams) ([]*entity.Message, *utils.PageInfo, error) {
+	query := r.client.Collection("chats").Doc(chatID).Collection("messages").Query
 
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			log.Printf("Firestore error while iterating messages for chat %s: %v", chatID, err)
-			return nil, 0, errors.Internal("Failed to iterate messages", err)
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		log.Printf("Firestore error while counting messages for chat %s: %v", chatID, err)
+		return nil, nil, errors.Internal("Failed to count messages for chat", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", page)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
 		}
+		log.Printf("Firestore error while iterating messages for chat %s: %v", chatID, err)
+		return nil, nil, errors.Internal("Failed to iterate messages", err)
+	}
 
+	messages := make([]*entity.Message, 0, len(docs))
+	for _, doc := range docs {
 		var message entity.Message
 		if err := doc.DataTo(&message); err != nil {
 			log.Printf("Error parsing message data for chat %s: %v", chatID, err)
-			return nil, 0, errors.Internal("Failed to parse message data", err)
+			return nil, nil, errors.Internal("Failed to parse message data", err)
 		}
 
 		messages = append(messages, &message)
 	}
 
-	return messages, total, nil
+	return messages, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
 }
 
 func (r *firestoreChatRepository) UpdateMessageReadStatus(ctx context.Context, chatID, messageID string, userID string) error {
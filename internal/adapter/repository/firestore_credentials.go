@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// storedCredentials reads only the credential fields shared by products and
+// transactions
+type storedCredentials struct {
+	Credentials          map[string]interface{}       `firestore:"credentials,omitempty"`
+	EncryptedCredentials *entity.EncryptedCredentials `firestore:"encryptedCredentials,omitempty"`
+}
+
+// resealDocumentCredentials rewrites a document's credential fields from
+// reseal's result in one transaction, so no other field read earlier is
+// written back. Sealed credentials replace any legacy plaintext. Errors from
+// reseal are returned as is, others are reported with the failure message.
+func resealDocumentCredentials(ctx context.Context, client *firestore.Client, docRef *firestore.DocumentRef, reseal repository.CredentialResealFunc, failure string) (bool, error) {
+	var resealErr error
+	changed := false
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		changed, resealErr = false, nil
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current storedCredentials
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		sealed, ok, err := reseal(current.EncryptedCredentials, current.Credentials)
+		if err != nil {
+			resealErr = err
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		changed = true
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "encryptedCredentials", Value: sealed},
+			{Path: "credentials", Value: firestore.Delete},
+			{Path: "updatedAt", Value: time.Now()},
+		})
+	})
+
+	if resealErr != nil {
+		return false, resealErr
+	}
+	if err != nil {
+		return false, errors.Internal(failure, err)
+	}
+
+	return changed, nil
+}
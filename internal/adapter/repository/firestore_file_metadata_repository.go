@@ -163,6 +163,32 @@ func (r *firestoreFileMetadataRepository) GetByUploader(ctx context.Context, use
 	return metadataList, total, nil
 }
 
+func (r *firestoreFileMetadataRepository) ListByHashBands(ctx context.Context, entityType string, bands []string) ([]*entity.FileMetadata, error) {
+	if len(bands) == 0 {
+		return []*entity.FileMetadata{}, nil
+	}
+
+	docs, err := r.client.Collection("file_metadata").
+		Where("entityType", "==", entityType).
+		Where("hashBands", "array-contains-any", bands).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to look up similar images", err)
+	}
+
+	metadataList := make([]*entity.FileMetadata, 0, len(docs))
+	for _, doc := range docs {
+		var metadata entity.FileMetadata
+		if err := doc.DataTo(&metadata); err != nil {
+			logger.Error("Failed to parse file metadata: %v", err)
+			continue
+		}
+		metadataList = append(metadataList, &metadata)
+	}
+
+	return metadataList, nil
+}
+
 func (r *firestoreFileMetadataRepository) Update(ctx context.Context, metadata *entity.FileMetadata) error {
 	metadata.UpdatedAt = time.Now()
 	_, err := r.client.Collection("file_metadata").Doc(metadata.ID).Set(ctx, metadata)
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreFraudRuleRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreFraudRuleRepository(client *firestore.Client) repository.FraudRuleRepository {
+	return &firestoreFraudRuleRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreFraudRuleRepository) Create(ctx context.Context, rule *entity.FraudRule) error {
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	_, err := r.client.Collection("fraud_rules").Doc(rule.ID).Set(ctx, rule)
+	if err != nil {
+		return errors.Internal("Failed to create fraud rule", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreFraudRuleRepository) GetByID(ctx context.Context, id string) (*entity.FraudRule, error) {
+	doc, err := r.client.Collection("fraud_rules").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Fraud rule", err)
+		}
+		return nil, errors.Internal("Failed to get fraud rule", err)
+	}
+
+	var rule entity.FraudRule
+	if err := doc.DataTo(&rule); err != nil {
+		return nil, errors.Internal("Failed to parse fraud rule data", err)
+	}
+
+	return &rule, nil
+}
+
+func (r *firestoreFraudRuleRepository) Update(ctx context.Context, rule *entity.FraudRule) error {
+	rule.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("fraud_rules").Doc(rule.ID).Set(ctx, rule)
+	if err != nil {
+		return errors.Internal("Failed to update fraud rule", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreFraudRuleRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.Collection("fraud_rules").Doc(id).Delete(ctx)
+	if err != nil {
+		return errors.Internal("Failed to delete fraud rule", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreFraudRuleRepository) List(ctx context.Context) ([]*entity.FraudRule, error) {
+	iter := r.client.Collection("fraud_rules").OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	rules := []*entity.FraudRule{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list fraud rules", err)
+		}
+
+		var rule entity.FraudRule
+		if err := doc.DataTo(&rule); err != nil {
+			return nil, errors.Internal("Failed to parse fraud rule data", err)
+		}
+
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+func (r *firestoreFraudRuleRepository) SeedDefaults(ctx context.Context, rules []*entity.FraudRule) (bool, error) {
+	now := time.Now()
+	docs := make(map[string]interface{}, len(rules))
+	for _, rule := range rules {
+		rule.ID = rule.Flag
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+		docs[rule.ID] = rule
+	}
+
+	seeded, err := seedCollectionOnce(ctx, r.client, "fraud_rules", docs)
+	if err != nil {
+		return false, errors.Internal("Failed to seed fraud rules", err)
+	}
+
+	return seeded, nil
+}
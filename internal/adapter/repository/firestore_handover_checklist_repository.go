@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreHandoverChecklistRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreHandoverChecklistRepository(client *firestore.Client) repository.HandoverChecklistRepository {
+	return &firestoreHandoverChecklistRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreHandoverChecklistRepository) Create(ctx context.Context, checklist *entity.HandoverChecklist) error {
+	if checklist.ID == "" {
+		checklist.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	checklist.CreatedAt = now
+	checklist.UpdatedAt = now
+
+	_, err := r.client.Collection("handover_checklists").Doc(checklist.ID).Set(ctx, checklist)
+	if err != nil {
+		return errors.Internal("Failed to create handover checklist", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreHandoverChecklistRepository) GetByTransactionID(ctx context.Context, transactionID string) (*entity.HandoverChecklist, error) {
+	iter := r.client.Collection("handover_checklists").
+		Where("transactionId", "==", transactionID).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err != nil {
+		if err == iterator.Done {
+			return nil, errors.NotFound("Handover checklist", nil)
+		}
+		return nil, errors.Internal("Failed to get handover checklist", err)
+	}
+
+	var checklist entity.HandoverChecklist
+	if err := doc.DataTo(&checklist); err != nil {
+		return nil, errors.Internal("Failed to parse handover checklist data", err)
+	}
+
+	return &checklist, nil
+}
+
+func (r *firestoreHandoverChecklistRepository) Update(ctx context.Context, checklist *entity.HandoverChecklist) error {
+	checklist.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("handover_checklists").Doc(checklist.ID).Set(ctx, checklist)
+	if err != nil {
+		return errors.Internal("Failed to update handover checklist", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreHandoverChecklistRepository) UpdateIfStatus(ctx context.Context, checklist *entity.HandoverChecklist, expected string) (bool, error) {
+	docRef := r.client.Collection("handover_checklists").Doc(checklist.ID)
+	updated := false
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = false
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current entity.HandoverChecklist
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		if current.Status != expected {
+			return nil
+		}
+
+		checklist.UpdatedAt = time.Now()
+		updated = true
+		return tx.Set(docRef, checklist)
+	})
+
+	if err != nil {
+		return false, errors.Internal("Failed to update handover checklist", err)
+	}
+
+	return updated, nil
+}
+
+func (r *firestoreHandoverChecklistRepository) Modify(ctx context.Context, checklistID string, mutate func(checklist *entity.HandoverChecklist) error) (*entity.HandoverChecklist, error) {
+	docRef := r.client.Collection("handover_checklists").Doc(checklistID)
+	var checklist *entity.HandoverChecklist
+	var mutateErr error
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		checklist, mutateErr = nil, nil
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current entity.HandoverChecklist
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		if mutateErr = mutate(&current); mutateErr != nil {
+			return mutateErr
+		}
+
+		current.UpdatedAt = time.Now()
+		checklist = &current
+		return tx.Set(docRef, &current)
+	})
+
+	if mutateErr != nil {
+		return nil, mutateErr
+	}
+	if err != nil {
+		return nil, errors.Internal("Failed to update handover checklist", err)
+	}
+
+	return checklist, nil
+}
+
+func (r *firestoreHandoverChecklistRepository) ListOverdue(ctx context.Context, before time.Time, limit int) ([]*entity.HandoverChecklist, error) {
+	// Filter on status only and check the deadline in memory to avoid a composite index
+	iter := r.client.Collection("handover_checklists").
+		Where("status", "==", "in_progress").
+		Documents(ctx)
+	defer iter.Stop()
+
+	var checklists []*entity.HandoverChecklist
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list handover checklists", err)
+		}
+
+		var checklist entity.HandoverChecklist
+		if err := doc.DataTo(&checklist); err != nil {
+			return nil, errors.Internal("Failed to parse handover checklist data", err)
+		}
+
+		if checklist.DueAt.Before(before) {
+			checklists = append(checklists, &checklist)
+			if limit > 0 && len(checklists) >= limit {
+				break
+			}
+		}
+	}
+
+	return checklists, nil
+}
+
+func (r *firestoreHandoverChecklistRepository) ListByStatus(ctx context.Context, status string, limit int) ([]*entity.HandoverChecklist, error) {
+	query := r.client.Collection("handover_checklists").Where("status", "==", status)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var checklists []*entity.HandoverChecklist
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list handover checklists", err)
+		}
+
+		var checklist entity.HandoverChecklist
+		if err := doc.DataTo(&checklist); err != nil {
+			return nil, errors.Internal("Failed to parse handover checklist data", err)
+		}
+
+		checklists = append(checklists, &checklist)
+	}
+
+	return checklists, nil
+}
+
+func (r *firestoreHandoverChecklistRepository) ListByStatusUpdatedSince(ctx context.Context, status string, since time.Time, after *entity.HandoverChecklist, limit int) ([]*entity.HandoverChecklist, error) {
+	query := r.client.Collection("handover_checklists").
+		Where("status", "==", status).
+		Where("updatedAt", ">=", since).
+		OrderBy("updatedAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+
+	if after != nil {
+		query = query.StartAfter(after.UpdatedAt, after.ID)
+	}
+
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list updated handover checklists", err)
+	}
+
+	checklists := make([]*entity.HandoverChecklist, 0, len(docs))
+	for _, doc := range docs {
+		var checklist entity.HandoverChecklist
+		if err := doc.DataTo(&checklist); err != nil {
+			return nil, errors.Internal("Failed to parse handover checklist data", err)
+		}
+		checklists = append(checklists, &checklist)
+	}
+
+	return checklists, nil
+}
+
+func (r *firestoreHandoverChecklistRepository) CountBySellerAndStatus(ctx context.Context, sellerID, status string) (int, error) {
+	query := r.client.Collection("handover_checklists").
+		Where("sellerId", "==", sellerID).
+		Where("status", "==", status)
+
+	count, err := countQuery(ctx, query)
+	if err != nil {
+		return 0, errors.Internal("Failed to count handover checklists", err)
+	}
+
+	return int(count), nil
+}
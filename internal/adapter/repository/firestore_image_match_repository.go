@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+type firestoreImageMatchRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreImageMatchRepository(client *firestore.Client) repository.ImageMatchRepository {
+	return &firestoreImageMatchRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreImageMatchRepository) Create(ctx context.Context, matches []*entity.ImageMatch) error {
+	now := time.Now()
+
+	for start := 0; start < len(matches); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(matches))
+
+		batch := r.client.Batch()
+		for _, match := range matches[start:end] {
+			if match.ID == "" {
+				match.ID = uuid.New().String()
+			}
+			match.CreatedAt = now
+			batch.Set(r.client.Collection("image_matches").Doc(match.ID), match)
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return errors.Internal("Failed to save image matches", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *firestoreImageMatchRepository) ListByProduct(ctx context.Context, productID string) ([]*entity.ImageMatch, error) {
+	docs, err := r.client.Collection("image_matches").
+		Where("productId", "==", productID).
+		OrderBy("createdAt", firestore.Desc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list image matches", err)
+	}
+
+	return parseImageMatches(docs)
+}
+
+func (r *firestoreImageMatchRepository) ListPage(ctx context.Context, pagination utils.PaginationParams) ([]*entity.ImageMatch, *utils.PageInfo, error) {
+	query := r.client.Collection("image_matches").Query
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Internal("Failed to count image matches", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", pagination)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Internal("Failed to list image matches", err)
+	}
+
+	matches, err := parseImageMatches(docs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return matches, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
+}
+
+func parseImageMatches(docs []*firestore.DocumentSnapshot) ([]*entity.ImageMatch, error) {
+	matches := make([]*entity.ImageMatch, 0, len(docs))
+	for _, doc := range docs {
+		var match entity.ImageMatch
+		if err := doc.DataTo(&match); err != nil {
+			return nil, errors.Internal("Failed to parse image match data", err)
+		}
+		matches = append(matches, &match)
+	}
+	return matches, nil
+}
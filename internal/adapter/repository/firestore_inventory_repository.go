@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+// Firestore caps a batched write at 500 operations
+const maxBatchWrites = 500
+
+type firestoreInventoryRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreInventoryRepository(client *firestore.Client) repository.InventoryRepository {
+	return &firestoreInventoryRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreInventoryRepository) CreateCodes(ctx context.Context, codes []*entity.InventoryCode) error {
+	now := time.Now()
+
+	for start := 0; start < len(codes); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(codes))
+
+		batch := r.client.Batch()
+		for _, code := range codes[start:end] {
+			if code.ID == "" {
+				code.ID = uuid.New().String()
+			}
+			code.CreatedAt = now
+			code.UpdatedAt = now
+			batch.Set(r.client.Collection("inventory_codes").Doc(code.ID), code)
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return errors.Internal("Failed to save inventory codes", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *firestoreInventoryRepository) GetByID(ctx context.Context, id string) (*entity.InventoryCode, error) {
+	doc, err := r.client.Collection("inventory_codes").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Inventory code", err)
+		}
+		return nil, errors.Internal("Failed to get inventory code", err)
+	}
+
+	var code entity.InventoryCode
+	if err := doc.DataTo(&code); err != nil {
+		return nil, errors.Internal("Failed to parse inventory code data", err)
+	}
+
+	return &code, nil
+}
+
+func (r *firestoreInventoryRepository) Update(ctx context.Context, code *entity.InventoryCode) error {
+	code.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("inventory_codes").Doc(code.ID).Set(ctx, code)
+	if err != nil {
+		return errors.Internal("Failed to update inventory code", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreInventoryRepository) ListPage(ctx context.Context, productID, variantID, status string, pagination utils.PaginationParams) ([]*entity.InventoryCode, *utils.PageInfo, error) {
+	query := r.client.Collection("inventory_codes").Where("productId", "==", productID)
+
+	if variantID != "" {
+		query = query.Where("variantId", "==", variantID)
+	}
+	if status != "" {
+		query = query.Where("status", "==", status)
+	}
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Internal("Failed to count inventory codes", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", pagination)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Internal("Failed to list inventory codes", err)
+	}
+
+	inventory := make([]*entity.InventoryCode, 0, len(docs))
+	for _, doc := range docs {
+		var code entity.InventoryCode
+		if err := doc.DataTo(&code); err != nil {
+			return nil, nil, errors.Internal("Failed to parse inventory code data", err)
+		}
+		inventory = append(inventory, &code)
+	}
+
+	return inventory, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
+}
+
+func (r *firestoreInventoryRepository) ClaimCode(ctx context.Context, productID, variantID, transactionID string) (*entity.InventoryCode, error) {
+	collection := r.client.Collection("inventory_codes")
+	var claimed *entity.InventoryCode
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = nil
+
+		// A retried webhook must not hand out a second code
+		delivered, err := tx.Documents(collection.
+			Where("transactionId", "==", transactionID).
+			Where("status", "==", "delivered").
+			Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(delivered) > 0 {
+			var code entity.InventoryCode
+			if err := delivered[0].DataTo(&code); err != nil {
+				return err
+			}
+			claimed = &code
+			return nil
+		}
+
+		// Oldest code first, so codes with an expiry are used before they lapse
+		available, err := tx.Documents(collection.
+			Where("productId", "==", productID).
+			Where("variantId", "==", variantID).
+			Where("status", "==", "available").
+			OrderBy("createdAt", firestore.Asc).
+			Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+		if len(available) == 0 {
+			return nil
+		}
+
+		var code entity.InventoryCode
+		if err := available[0].DataTo(&code); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		code.Status = "delivered"
+		code.TransactionID = transactionID
+		code.DeliveredAt = &now
+		code.UpdatedAt = now
+
+		claimed = &code
+		return tx.Set(available[0].Ref, &code)
+	})
+
+	if err != nil {
+		return nil, errors.Internal("Failed to claim inventory code", err)
+	}
+
+	if claimed == nil {
+		return nil, errors.NotFound("Available inventory code", nil)
+	}
+
+	return claimed, nil
+}
+
+func (r *firestoreInventoryRepository) CountByStatus(ctx context.Context, productID string) (map[string]map[string]int, error) {
+	docs, err := r.client.Collection("inventory_codes").
+		Where("productId", "==", productID).
+		Select("variantId", "status").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to count inventory codes", err)
+	}
+
+	counts := map[string]map[string]int{}
+	for _, doc := range docs {
+		var code entity.InventoryCode
+		if err := doc.DataTo(&code); err != nil {
+			return nil, errors.Internal("Failed to parse inventory code data", err)
+		}
+
+		if counts[code.VariantID] == nil {
+			counts[code.VariantID] = map[string]int{}
+		}
+		counts[code.VariantID][code.Status]++
+	}
+
+	return counts, nil
+}
+
+func (r *firestoreInventoryRepository) Fingerprints(ctx context.Context, productID string) (map[string]bool, error) {
+	docs, err := r.client.Collection("inventory_codes").
+		Where("productId", "==", productID).
+		Select("fingerprint").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to load inventory fingerprints", err)
+	}
+
+	fingerprints := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if fingerprint, err := doc.DataAt("fingerprint"); err == nil {
+			if s, ok := fingerprint.(string); ok {
+				fingerprints[s] = true
+			}
+		}
+	}
+
+	return fingerprints, nil
+}
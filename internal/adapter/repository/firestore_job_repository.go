@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreJobRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreJobRepository(client *firestore.Client) repository.JobRepository {
+	return &firestoreJobRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreJobRepository) AcquireLease(ctx context.Context, jobName, holderID string, ttl time.Duration, dueSince time.Time) (bool, error) {
+	acquired := false
+	docRef := r.client.Collection("job_leases").Doc(jobName)
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		acquired = false
+		now := time.Now()
+
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		if err == nil {
+			var lease entity.JobLease
+			if err := doc.DataTo(&lease); err != nil {
+				return err
+			}
+
+			// A live lease is refused even to its own holder, so a manual
+			// run cannot overlap a scheduled run on the same instance
+			if lease.ExpiresAt.After(now) {
+				return nil
+			}
+
+			if !dueSince.IsZero() && lease.LastStartedAt != nil && !lease.LastStartedAt.Before(dueSince) {
+				return nil
+			}
+		}
+
+		acquired = true
+		return tx.Set(docRef, entity.JobLease{
+			JobName:       jobName,
+			HolderID:      holderID,
+			AcquiredAt:    now,
+			ExpiresAt:     now.Add(ttl),
+			LastStartedAt: &now,
+		})
+	})
+
+	if err != nil {
+		return false, errors.Internal("Failed to acquire job lease", err)
+	}
+
+	return acquired, nil
+}
+
+func (r *firestoreJobRepository) RenewLease(ctx context.Context, jobName, holderID string, ttl time.Duration) (bool, error) {
+	renewed := false
+	docRef := r.client.Collection("job_leases").Doc(jobName)
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		renewed = false
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var lease entity.JobLease
+		if err := doc.DataTo(&lease); err != nil {
+			return err
+		}
+
+		if lease.HolderID != holderID {
+			return nil
+		}
+
+		renewed = true
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "expiresAt", Value: time.Now().Add(ttl)},
+		})
+	})
+
+	if err != nil {
+		return false, errors.Internal("Failed to renew job lease", err)
+	}
+
+	return renewed, nil
+}
+
+func (r *firestoreJobRepository) ReleaseLease(ctx context.Context, jobName, holderID string) error {
+	docRef := r.client.Collection("job_leases").Doc(jobName)
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var lease entity.JobLease
+		if err := doc.DataTo(&lease); err != nil {
+			return err
+		}
+
+		if lease.HolderID != holderID {
+			return nil
+		}
+
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "expiresAt", Value: time.Now()},
+		})
+	})
+
+	if err != nil {
+		return errors.Internal("Failed to release job lease", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreJobRepository) GetLease(ctx context.Context, jobName string) (*entity.JobLease, error) {
+	doc, err := r.client.Collection("job_leases").Doc(jobName).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Job lease", err)
+		}
+		return nil, errors.Internal("Failed to get job lease", err)
+	}
+
+	var lease entity.JobLease
+	if err := doc.DataTo(&lease); err != nil {
+		return nil, errors.Internal("Failed to parse job lease data", err)
+	}
+
+	return &lease, nil
+}
+
+func (r *firestoreJobRepository) CreateRun(ctx context.Context, run *entity.JobRun) error {
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+
+	_, err := r.client.Collection("job_runs").Doc(run.ID).Set(ctx, run)
+	if err != nil {
+		return errors.Internal("Failed to create job run", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreJobRepository) UpdateRun(ctx context.Context, run *entity.JobRun) error {
+	_, err := r.client.Collection("job_runs").Doc(run.ID).Set(ctx, run)
+	if err != nil {
+		return errors.Internal("Failed to update job run", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreJobRepository) ListRuns(ctx context.Context, jobName string, limit, offset int) ([]*entity.JobRun, int64, error) {
+	query := r.client.Collection("job_runs").Query
+	if jobName != "" {
+		query = query.Where("jobName", "==", jobName)
+	}
+
+	allDocs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count job runs", err)
+	}
+	total := int64(len(allDocs))
+
+	query = query.OrderBy("startedAt", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	runs := []*entity.JobRun{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to list job runs", err)
+		}
+
+		var run entity.JobRun
+		if err := doc.DataTo(&run); err != nil {
+			log.Printf("Failed to parse job run %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		runs = append(runs, &run)
+	}
+
+	return runs, total, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreListingImportRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreListingImportRepository(client *firestore.Client) repository.ListingImportRepository {
+	return &firestoreListingImportRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreListingImportRepository) rows(jobID string) *firestore.CollectionRef {
+	return r.client.Collection("import_jobs").Doc(jobID).Collection("rows")
+}
+
+func (r *firestoreListingImportRepository) Create(ctx context.Context, job *entity.ImportJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+
+	_, err := r.client.Collection("import_jobs").Doc(job.ID).Set(ctx, job)
+	if err != nil {
+		return errors.Internal("Failed to create import job", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreListingImportRepository) GetByID(ctx context.Context, id string) (*entity.ImportJob, error) {
+	doc, err := r.client.Collection("import_jobs").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Import job", err)
+		}
+		return nil, errors.Internal("Failed to get import job", err)
+	}
+
+	var job entity.ImportJob
+	if err := doc.DataTo(&job); err != nil {
+		return nil, errors.Internal("Failed to parse import job data", err)
+	}
+
+	return &job, nil
+}
+
+func (r *firestoreListingImportRepository) Update(ctx context.Context, job *entity.ImportJob) error {
+	job.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("import_jobs").Doc(job.ID).Set(ctx, job)
+	if err != nil {
+		return errors.Internal("Failed to update import job", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreListingImportRepository) UpdateIfStatus(ctx context.Context, job *entity.ImportJob, expected string) (bool, error) {
+	docRef := r.client.Collection("import_jobs").Doc(job.ID)
+	updated := false
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = false
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current entity.ImportJob
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		if current.Status != expected {
+			return nil
+		}
+
+		job.UpdatedAt = time.Now()
+		updated = true
+		return tx.Set(docRef, job)
+	})
+
+	if err != nil {
+		return false, errors.Internal("Failed to update import job", err)
+	}
+
+	return updated, nil
+}
+
+func (r *firestoreListingImportRepository) ListByStatus(ctx context.Context, status string, limit int) ([]*entity.ImportJob, error) {
+	query := r.client.Collection("import_jobs").Where("status", "==", status)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list import jobs", err)
+	}
+
+	jobs := make([]*entity.ImportJob, 0, len(docs))
+	for _, doc := range docs {
+		var job entity.ImportJob
+		if err := doc.DataTo(&job); err != nil {
+			return nil, errors.Internal("Failed to parse import job data", err)
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
+
+func (r *firestoreListingImportRepository) SaveRows(ctx context.Context, jobID string, rows []*entity.ImportRow) error {
+	for start := 0; start < len(rows); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(rows))
+
+		batch := r.client.Batch()
+		for _, row := range rows[start:end] {
+			batch.Set(r.rows(jobID).Doc(strconv.Itoa(row.Row)), row)
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return errors.Internal("Failed to save import rows", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *firestoreListingImportRepository) ListRows(ctx context.Context, jobID string) ([]*entity.ImportRow, error) {
+	docs, err := r.rows(jobID).OrderBy("row", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list import rows", err)
+	}
+
+	rows := make([]*entity.ImportRow, 0, len(docs))
+	for _, doc := range docs {
+		var row entity.ImportRow
+		if err := doc.DataTo(&row); err != nil {
+			return nil, errors.Internal("Failed to parse import row data", err)
+		}
+		rows = append(rows, &row)
+	}
+
+	return rows, nil
+}
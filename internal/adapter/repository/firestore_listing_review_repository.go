@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+type firestoreListingReviewRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreListingReviewRepository(client *firestore.Client) repository.ListingReviewRepository {
+	return &firestoreListingReviewRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreListingReviewRepository) Create(ctx context.Context, review *entity.ListingReview) error {
+	if review.ID == "" {
+		review.ID = uuid.New().String()
+	}
+	review.CreatedAt = time.Now()
+	review.UpdatedAt = review.CreatedAt
+
+	_, err := r.client.Collection("listing_reviews").Doc(review.ID).Set(ctx, review)
+	if err != nil {
+		return errors.Internal("Failed to create listing review", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreListingReviewRepository) GetByID(ctx context.Context, id string) (*entity.ListingReview, error) {
+	doc, err := r.client.Collection("listing_reviews").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Listing review", err)
+		}
+		return nil, errors.Internal("Failed to get listing review", err)
+	}
+
+	var review entity.ListingReview
+	if err := doc.DataTo(&review); err != nil {
+		return nil, errors.Internal("Failed to parse listing review data", err)
+	}
+
+	return &review, nil
+}
+
+func (r *firestoreListingReviewRepository) Update(ctx context.Context, review *entity.ListingReview) error {
+	review.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("listing_reviews").Doc(review.ID).Set(ctx, review)
+	if err != nil {
+		return errors.Internal("Failed to update listing review", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreListingReviewRepository) GetLatestByProduct(ctx context.Context, productID string) (*entity.ListingReview, error) {
+	docs, err := r.client.Collection("listing_reviews").
+		Where("productId", "==", productID).
+		OrderBy("createdAt", firestore.Desc).
+		Limit(1).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to get listing review", err)
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.NotFound("Listing review", nil)
+	}
+
+	var review entity.ListingReview
+	if err := docs[0].DataTo(&review); err != nil {
+		return nil, errors.Internal("Failed to parse listing review data", err)
+	}
+
+	return &review, nil
+}
+
+func (r *firestoreListingReviewRepository) ListPage(ctx context.Context, status string, pagination utils.PaginationParams) ([]*entity.ListingReview, *utils.PageInfo, error) {
+	query := r.client.Collection("listing_reviews").Query
+	if status != "" {
+		query = query.Where("status", "==", status)
+	}
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Internal("Failed to count listing reviews", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", pagination)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Internal("Failed to list listing reviews", err)
+	}
+
+	reviews := make([]*entity.ListingReview, 0, len(docs))
+	for _, doc := range docs {
+		var review entity.ListingReview
+		if err := doc.DataTo(&review); err != nil {
+			return nil, nil, errors.Internal("Failed to parse listing review data", err)
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
+}
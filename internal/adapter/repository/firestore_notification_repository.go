@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+type firestoreNotificationRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreNotificationRepository(client *firestore.Client) repository.NotificationRepository {
+	return &firestoreNotificationRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreNotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	if notification.ID == "" {
+		notification.ID = uuid.New().String()
+	}
+	notification.CreatedAt = time.Now()
+
+	_, err := r.client.Collection("notifications").Doc(notification.ID).Set(ctx, notification)
+	if err != nil {
+		return errors.Internal("Failed to create notification", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreNotificationRepository) ListPage(ctx context.Context, userID string, unreadOnly bool, pagination utils.PaginationParams) ([]*entity.Notification, *utils.PageInfo, error) {
+	query := r.client.Collection("notifications").Where("userId", "==", userID)
+	if unreadOnly {
+		query = query.Where("read", "==", false)
+	}
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Internal("Failed to count notifications", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", pagination)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Internal("Failed to list notifications", err)
+	}
+
+	notifications := make([]*entity.Notification, 0, len(docs))
+	for _, doc := range docs {
+		var notification entity.Notification
+		if err := doc.DataTo(&notification); err != nil {
+			return nil, nil, errors.Internal("Failed to parse notification data", err)
+		}
+		notifications = append(notifications, &notification)
+	}
+
+	return notifications, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
+}
+
+func (r *firestoreNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	total, err := countQuery(ctx, r.client.Collection("notifications").
+		Where("userId", "==", userID).
+		Where("read", "==", false))
+	if err != nil {
+		return 0, errors.Internal("Failed to count notifications", err)
+	}
+
+	return total, nil
+}
+
+func (r *firestoreNotificationRepository) MarkRead(ctx context.Context, userID, id string) error {
+	ref := r.client.Collection("notifications").Doc(id)
+
+	doc, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return errors.NotFound("Notification", err)
+		}
+		return errors.Internal("Failed to get notification", err)
+	}
+
+	var notification entity.Notification
+	if err := doc.DataTo(&notification); err != nil {
+		return errors.Internal("Failed to parse notification data", err)
+	}
+	if notification.UserID != userID {
+		return errors.NotFound("Notification", nil)
+	}
+	if notification.Read {
+		return nil
+	}
+
+	_, err = ref.Update(ctx, []firestore.Update{
+		{Path: "read", Value: true},
+		{Path: "readAt", Value: time.Now()},
+	})
+	if err != nil {
+		return errors.Internal("Failed to update notification", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	docs, err := r.client.Collection("notifications").
+		Where("userId", "==", userID).
+		Where("read", "==", false).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return 0, errors.Internal("Failed to get notifications", err)
+	}
+
+	now := time.Now()
+	for start := 0; start < len(docs); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(docs))
+
+		batch := r.client.Batch()
+		for _, doc := range docs[start:end] {
+			batch.Update(doc.Ref, []firestore.Update{
+				{Path: "read", Value: true},
+				{Path: "readAt", Value: now},
+			})
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return 0, errors.Internal("Failed to update notifications", err)
+		}
+	}
+
+	return len(docs), nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"cloud.google.com/go/firestore"
+	firestorepb "cloud.google.com/go/firestore/apiv1/firestorepb"
+
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+// countQuery counts the documents matching a query with a server-side
+// aggregation, billed at one read per thousand matches rather than one per
+// document as with GetAll
+func countQuery(ctx context.Context, query firestore.Query) (int64, error) {
+	result, err := query.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := result["total"].(*firestorepb.Value)
+	if !ok {
+		return 0, nil
+	}
+
+	return value.GetIntegerValue(), nil
+}
+
+// fetchPage runs a query newest first on sortField, with the document ID as
+// tie-breaker, starting after the page's cursor or at its offset. One extra
+// document is read to tell whether a next page exists; the returned cursor
+// is empty on the last page.
+func fetchPage(ctx context.Context, query firestore.Query, sortField string, page utils.PaginationParams) ([]*firestore.DocumentSnapshot, string, error) {
+	query = query.OrderBy(sortField, firestore.Desc).OrderBy(firestore.DocumentID, firestore.Desc)
+
+	if page.Cursor != "" {
+		cursor, err := utils.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", errors.BadRequest("Invalid cursor", err)
+		}
+		query = query.StartAfter(cursor.Key, cursor.ID)
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	if page.PageSize > 0 {
+		query = query.Limit(page.PageSize + 1)
+	}
+
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if page.PageSize <= 0 || len(docs) <= page.PageSize {
+		return docs, "", nil
+	}
+
+	docs = docs[:page.PageSize]
+	last := docs[len(docs)-1]
+
+	key, err := last.DataAt(sortField)
+	if err != nil {
+		return docs, "", nil
+	}
+
+	return docs, utils.EncodeCursor(key, last.Ref.ID), nil
+}
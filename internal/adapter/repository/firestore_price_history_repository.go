@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestorePriceHistoryRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestorePriceHistoryRepository(client *firestore.Client) repository.PriceHistoryRepository {
+	return &firestorePriceHistoryRepository{
+		client: client,
+	}
+}
+
+// history is the price_history subcollection of a product
+func (r *firestorePriceHistoryRepository) history(productID string) *firestore.CollectionRef {
+	return r.client.Collection("products").Doc(productID).Collection("price_history")
+}
+
+func (r *firestorePriceHistoryRepository) Record(ctx context.Context, points []*entity.PricePoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	batch := r.client.Batch()
+	for _, point := range points {
+		if point.ID == "" {
+			point.ID = uuid.New().String()
+		}
+		if point.ChangedAt.IsZero() {
+			point.ChangedAt = time.Now()
+		}
+		batch.Set(r.history(point.ProductID).Doc(point.ID), point)
+	}
+
+	if _, err := batch.Commit(ctx); err != nil {
+		return errors.Internal("Failed to record price history", err)
+	}
+
+	return nil
+}
+
+func (r *firestorePriceHistoryRepository) ListSince(ctx context.Context, productID, variantID string, since time.Time) ([]*entity.PricePoint, error) {
+	docs, err := r.history(productID).
+		Where("variantId", "==", variantID).
+		Where("changedAt", ">=", since).
+		OrderBy("changedAt", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to get price history", err)
+	}
+
+	points := make([]*entity.PricePoint, 0, len(docs))
+	for _, doc := range docs {
+		var point entity.PricePoint
+		if err := doc.DataTo(&point); err != nil {
+			return nil, errors.Internal("Failed to parse price history data", err)
+		}
+		points = append(points, &point)
+	}
+
+	return points, nil
+}
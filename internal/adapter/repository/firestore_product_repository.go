@@ -4,18 +4,17 @@ import (
 	"context"
 	"log"
 	"slices"
-	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/google/uuid"
-	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
 	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
 )
 
 type firestoreProductRepository struct {
@@ -142,7 +141,14 @@ func (r *firestoreProductRepository) List(ctx context.Context, filter map[string
 		})
 	} else {
 
+		// Featured listings hold the top slots, then the most recently bumped
 		slices.SortFunc(allProducts, func(a, b *entity.Product) int {
+			if a.Featured != b.Featured {
+				if a.Featured {
+					return -1
+				}
+				return 1
+			}
 			if a.BumpedAt.After(b.BumpedAt) {
 				return -1
 			} else if a.BumpedAt.Before(b.BumpedAt) {
@@ -179,6 +185,46 @@ func (r *firestoreProductRepository) Update(ctx context.Context, product *entity
 	return nil
 }
 
+func (r *firestoreProductRepository) Modify(ctx context.Context, productID string, mutate func(product *entity.Product) error) (*entity.Product, error) {
+	docRef := r.client.Collection("products").Doc(productID)
+	var product *entity.Product
+	var mutateErr error
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		product, mutateErr = nil, nil
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current entity.Product
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		if mutateErr = mutate(&current); mutateErr != nil {
+			return mutateErr
+		}
+
+		current.UpdatedAt = time.Now()
+		product = &current
+		return tx.Set(docRef, &current)
+	})
+
+	if mutateErr != nil {
+		return nil, mutateErr
+	}
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Product", err)
+		}
+		return nil, errors.Internal("Failed to update product", err)
+	}
+
+	return product, nil
+}
+
 func (r *firestoreProductRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.client.Collection("products").Doc(id).Delete(ctx)
 	if err != nil {
@@ -202,178 +248,130 @@ func (r *firestoreProductRepository) SoftDelete(ctx context.Context, id string)
 	return nil
 }
 
-func (r *firestoreProductRepository) IncrementViews(ctx context.Context, id string) error {
-	_, err := r.client.Collection("products").Doc(id).Update(ctx, []firestore.Update{
-		{Path: "views", Value: firestore.Increment(1)},
-		{Path: "updatedAt", Value: time.Now()},
-	})
+func (r *firestoreProductRepository) ListActiveBumpedBefore(ctx context.Context, before time.Time) ([]*entity.Product, error) {
+	docs, err := r.client.Collection("products").
+		Where("status", "==", "active").
+		Where("bumpedAt", "<", before).
+		Documents(ctx).GetAll()
 	if err != nil {
-		return errors.Internal("Failed to increment product views", err)
+		return nil, errors.Internal("Failed to list stale products", err)
 	}
 
-	return nil
-}
+	products := make([]*entity.Product, 0, len(docs))
+	for _, doc := range docs {
+		var product entity.Product
+		if err := doc.DataTo(&product); err != nil {
+			return nil, errors.Internal("Failed to parse product data", err)
+		}
+		products = append(products, &product)
+	}
 
-func (r *firestoreProductRepository) Search(ctx context.Context, query string, filter map[string]interface{}, limit, offset int) ([]*entity.Product, int64, error) {
-	log.Printf("=== Search repository called with query: '%s' ===", query)
-	log.Printf("Filter: %v", filter)
+	return products, nil
+}
 
-	var minPrice, maxPrice float64
-	if minPriceVal, ok := filter["min_price"]; ok {
-		minPrice = minPriceVal.(float64)
-		log.Printf("Filtering with min_price: %.2f", minPrice)
-		delete(filter, "min_price")
-	}
-	if maxPriceVal, ok := filter["max_price"]; ok {
-		maxPrice = maxPriceVal.(float64)
-		log.Printf("Filtering with max_price: %.2f", maxPrice)
-		delete(filter, "max_price")
+func (r *firestoreProductRepository) ListPageByID(ctx context.Context, afterID string, limit int) ([]*entity.Product, error) {
+	query := r.client.Collection("products").OrderBy(firestore.DocumentID, firestore.Asc)
+	if afterID != "" {
+		query = query.StartAfter(afterID)
 	}
 
-	collection := r.client.Collection("products")
-	docs, err := collection.Documents(ctx).GetAll()
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
 	if err != nil {
-		log.Printf("Error getting products: %v", err)
-		return nil, 0, errors.Internal("Failed to search products", err)
+		return nil, errors.Internal("Failed to list products", err)
 	}
 
-	log.Printf("Found %d raw documents in collection", len(docs))
-
-	var allProducts []*entity.Product
+	products := make([]*entity.Product, 0, len(docs))
 	for _, doc := range docs {
 		var product entity.Product
 		if err := doc.DataTo(&product); err != nil {
-			log.Printf("Error parsing product: %v", err)
-			continue
+			return nil, errors.Internal("Failed to parse product data", err)
 		}
-		product.ID = doc.Ref.ID
-		allProducts = append(allProducts, &product)
+		products = append(products, &product)
 	}
 
-	log.Printf("Successfully parsed %d products", len(allProducts))
-
-	var filteredProducts []*entity.Product
-	searchTerms := strings.ToLower(query)
-
-	for _, product := range allProducts {
-		log.Printf("Checking product %s: Title='%s', Price=%.2f", product.ID, product.Title, product.Price)
-
-		if minPrice > 0 {
-			if product.Price < minPrice {
-				log.Printf("❌ Skipping product %s: price %.2f < min_price %.2f",
-					product.ID, product.Price, minPrice)
-				continue
-			}
-		}
-
-		if maxPrice > 0 {
-			if product.Price > maxPrice {
-				log.Printf("❌ Skipping product %s: price %.2f > max_price %.2f",
-					product.ID, product.Price, maxPrice)
-				continue
-			}
-		}
+	return products, nil
+}
 
-		skipProduct := false
-		for key, value := range filter {
-			if key == "gameTitleId" && value != product.GameTitleID {
-				skipProduct = true
-				break
-			}
-			if key == "type" && value != product.Type {
-				skipProduct = true
-				break
-			}
-			if key == "status" && value != product.Status {
-				skipProduct = true
-				break
-			}
-		}
+func (r *firestoreProductRepository) ListUpdatedSince(ctx context.Context, since time.Time, after *entity.Product, limit int) ([]*entity.Product, error) {
+	query := r.client.Collection("products").
+		Where("updatedAt", ">=", since).
+		OrderBy("updatedAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
 
-		if skipProduct {
-			log.Printf("Skipping product %s due to filter mismatch", product.ID)
-			continue
-		}
+	if after != nil {
+		query = query.StartAfter(after.UpdatedAt, after.ID)
+	}
 
-		titleLower := strings.ToLower(product.Title)
-		descLower := strings.ToLower(product.Description)
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list updated products", err)
+	}
 
-		if strings.Contains(titleLower, searchTerms) || strings.Contains(descLower, searchTerms) {
-			log.Printf("✅ MATCH Product %s matches search terms", product.ID)
-			filteredProducts = append(filteredProducts, product)
-		} else {
-			log.Printf("❌ NO MATCH Product %s does not match search terms", product.ID)
+	products := make([]*entity.Product, 0, len(docs))
+	for _, doc := range docs {
+		var product entity.Product
+		if err := doc.DataTo(&product); err != nil {
+			return nil, errors.Internal("Failed to parse product data", err)
 		}
+		products = append(products, &product)
 	}
 
-	log.Printf("After filtering: %d products match criteria", len(filteredProducts))
+	return products, nil
+}
 
-	if len(filteredProducts) > 0 {
-		slices.SortFunc(filteredProducts, func(a, b *entity.Product) int {
-			if a.BumpedAt.After(b.BumpedAt) {
-				return -1
-			} else if a.BumpedAt.Before(b.BumpedAt) {
-				return 1
-			}
-			return 0
-		})
+func (r *firestoreProductRepository) ResealCredentials(ctx context.Context, productID string, reseal repository.CredentialResealFunc) (bool, error) {
+	return resealDocumentCredentials(ctx, r.client, r.client.Collection("products").Doc(productID), reseal, "Failed to save re-encrypted product credentials")
+}
+
+func (r *firestoreProductRepository) IncrementViews(ctx context.Context, id string) error {
+	_, err := r.client.Collection("products").Doc(id).Update(ctx, []firestore.Update{
+		{Path: "views", Value: firestore.Increment(1)},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	if err != nil {
+		return errors.Internal("Failed to increment product views", err)
 	}
 
-	var paginatedProducts []*entity.Product
-	start := offset
-	end := offset + limit
+	return nil
+}
 
-	if len(filteredProducts) > 0 && start < len(filteredProducts) {
-		if end > len(filteredProducts) {
-			end = len(filteredProducts)
-		}
-		paginatedProducts = filteredProducts[start:end]
-	} else {
-		paginatedProducts = []*entity.Product{}
+func (r *firestoreProductRepository) ListBySellerID(ctx context.Context, sellerID string, status string, limit, offset int) ([]*entity.Product, int64, error) {
+	products, info, err := r.ListBySellerIDPage(ctx, sellerID, status, utils.PaginationParams{PageSize: limit, Offset: offset})
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return paginatedProducts, int64(len(filteredProducts)), nil
+	return products, info.Total, nil
 }
 
-func (r *firestoreProductRepository) ListBySellerID(ctx context.Context, sellerID string, status string, limit, offset int) ([]*entity.Product, int64, error) {
+func (r *firestoreProductRepository) ListBySellerIDPage(ctx context.Context, sellerID string, status string, page utils.PaginationParams) ([]*entity.Product, *utils.PageInfo, error) {
 	query := r.client.Collection("products").Query.Where("sellerId", "==", sellerID).Where("deletedAt", "==", nil)
 
 	if status != "" {
 		query = query.Where("status", "==", status)
 	}
 
-	allDocs, err := query.Documents(ctx).GetAll()
+	total, err := countQuery(ctx, query)
 	if err != nil {
-		return nil, 0, errors.Internal("Failed to count seller products", err)
-	}
-	total := int64(len(allDocs))
-
-	query = query.OrderBy("createdAt", firestore.Desc)
-	if limit > 0 {
-		query = query.Limit(limit)
+		return nil, nil, errors.Internal("Failed to count seller products", err)
 	}
-	if offset > 0 {
-		query = query.Offset(offset)
-	}
-
-	iter := query.Documents(ctx)
-	var products []*entity.Product
 
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, 0, errors.Internal("Failed to iterate seller products", err)
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", page)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
 		}
+		return nil, nil, errors.Internal("Failed to iterate seller products", err)
+	}
 
+	products := make([]*entity.Product, 0, len(docs))
+	for _, doc := range docs {
 		var product entity.Product
 		if err := doc.DataTo(&product); err != nil {
-			return nil, 0, errors.Internal("Failed to parse product data", err)
+			return nil, nil, errors.Internal("Failed to parse product data", err)
 		}
 		products = append(products, &product)
 	}
 
-	return products, total, nil
+	return products, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
 }
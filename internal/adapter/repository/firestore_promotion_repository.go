@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+// statsDateLayout keys product stats by UTC day
+const statsDateLayout = "2006-01-02"
+
+type firestorePromotionRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestorePromotionRepository(client *firestore.Client) repository.PromotionRepository {
+	return &firestorePromotionRepository{
+		client: client,
+	}
+}
+
+func (r *firestorePromotionRepository) Create(ctx context.Context, promotion *entity.Promotion) error {
+	if promotion.ID == "" {
+		promotion.ID = uuid.New().String()
+	}
+	promotion.CreatedAt = time.Now()
+	promotion.UpdatedAt = promotion.CreatedAt
+
+	_, err := r.client.Collection("promotions").Doc(promotion.ID).Set(ctx, promotion)
+	if err != nil {
+		return errors.Internal("Failed to create promotion", err)
+	}
+
+	return nil
+}
+
+func (r *firestorePromotionRepository) GetByID(ctx context.Context, id string) (*entity.Promotion, error) {
+	doc, err := r.client.Collection("promotions").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Promotion", err)
+		}
+		return nil, errors.Internal("Failed to get promotion", err)
+	}
+
+	var promotion entity.Promotion
+	if err := doc.DataTo(&promotion); err != nil {
+		return nil, errors.Internal("Failed to parse promotion data", err)
+	}
+
+	return &promotion, nil
+}
+
+func (r *firestorePromotionRepository) Update(ctx context.Context, promotion *entity.Promotion) error {
+	promotion.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("promotions").Doc(promotion.ID).Set(ctx, promotion)
+	if err != nil {
+		return errors.Internal("Failed to update promotion", err)
+	}
+
+	return nil
+}
+
+func (r *firestorePromotionRepository) ListBySellerPage(ctx context.Context, sellerID string, pagination utils.PaginationParams) ([]*entity.Promotion, *utils.PageInfo, error) {
+	query := r.client.Collection("promotions").Where("sellerId", "==", sellerID)
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Internal("Failed to count promotions", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", pagination)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Internal("Failed to list promotions", err)
+	}
+
+	promotions, err := parsePromotions(docs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return promotions, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
+}
+
+func (r *firestorePromotionRepository) ListActive(ctx context.Context) ([]*entity.Promotion, error) {
+	docs, err := r.client.Collection("promotions").
+		Where("status", "==", "active").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list active promotions", err)
+	}
+
+	return parsePromotions(docs)
+}
+
+func (r *firestorePromotionRepository) ListActiveByProduct(ctx context.Context, productID string) ([]*entity.Promotion, error) {
+	docs, err := r.client.Collection("promotions").
+		Where("productId", "==", productID).
+		Where("status", "==", "active").
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list product promotions", err)
+	}
+
+	return parsePromotions(docs)
+}
+
+func (r *firestorePromotionRepository) CountActiveFeatured(ctx context.Context, gameTitleID string) (int64, error) {
+	total, err := countQuery(ctx, r.client.Collection("promotions").
+		Where("gameTitleId", "==", gameTitleID).
+		Where("type", "==", "featured").
+		Where("status", "==", "active"))
+	if err != nil {
+		return 0, errors.Internal("Failed to count featured promotions", err)
+	}
+
+	return total, nil
+}
+
+// promotionSlotHolds is a promotion_slots document: the promotions holding a
+// slot under its key, by promotion ID, with the time each hold ends
+type promotionSlotHolds struct {
+	Holds map[string]time.Time `firestore:"holds"`
+}
+
+func (r *firestorePromotionRepository) ReserveSlot(ctx context.Context, key, promotionID string, until time.Time, limit int, now time.Time) (bool, error) {
+	docRef := r.client.Collection("promotion_slots").Doc(key)
+	reserved := false
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		reserved = false
+
+		var slots promotionSlotHolds
+		doc, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := doc.DataTo(&slots); err != nil {
+				return err
+			}
+		}
+
+		holds := map[string]time.Time{}
+		for id, endsAt := range slots.Holds {
+			if endsAt.After(now) {
+				holds[id] = endsAt
+			}
+		}
+
+		if _, held := holds[promotionID]; !held && len(holds) >= limit {
+			return nil
+		}
+
+		holds[promotionID] = until
+		reserved = true
+		return tx.Set(docRef, promotionSlotHolds{Holds: holds})
+	})
+	if err != nil {
+		return false, errors.Internal("Failed to reserve promotion slot", err)
+	}
+
+	return reserved, nil
+}
+
+func (r *firestorePromotionRepository) ReleaseSlot(ctx context.Context, key, promotionID string) error {
+	_, err := r.client.Collection("promotion_slots").Doc(key).Update(ctx, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"holds", promotionID}, Value: firestore.Delete},
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		return errors.Internal("Failed to release promotion slot", err)
+	}
+
+	return nil
+}
+
+func parsePromotions(docs []*firestore.DocumentSnapshot) ([]*entity.Promotion, error) {
+	promotions := make([]*entity.Promotion, 0, len(docs))
+	for _, doc := range docs {
+		var promotion entity.Promotion
+		if err := doc.DataTo(&promotion); err != nil {
+			return nil, errors.Internal("Failed to parse promotion data", err)
+		}
+		promotions = append(promotions, &promotion)
+	}
+	return promotions, nil
+}
+
+// statsRef is the counter document of a product for the UTC day of at
+func (r *firestorePromotionRepository) statsRef(productID string, at time.Time) (*firestore.DocumentRef, string) {
+	date := at.UTC().Format(statsDateLayout)
+	return r.client.Collection("product_stats").Doc(productID + "_" + date), date
+}
+
+func (r *firestorePromotionRepository) RecordImpressions(ctx context.Context, productIDs []string, at time.Time) error {
+	for start := 0; start < len(productIDs); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(productIDs))
+
+		batch := r.client.Batch()
+		for _, productID := range productIDs[start:end] {
+			ref, date := r.statsRef(productID, at)
+			batch.Set(ref, map[string]interface{}{
+				"productId":   productID,
+				"date":        date,
+				"impressions": firestore.Increment(1),
+			}, firestore.MergeAll)
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return errors.Internal("Failed to record impressions", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *firestorePromotionRepository) RecordClick(ctx context.Context, productID string, at time.Time) error {
+	ref, date := r.statsRef(productID, at)
+
+	_, err := ref.Set(ctx, map[string]interface{}{
+		"productId": productID,
+		"date":      date,
+		"clicks":    firestore.Increment(1),
+	}, firestore.MergeAll)
+	if err != nil {
+		return errors.Internal("Failed to record click", err)
+	}
+
+	return nil
+}
+
+func (r *firestorePromotionRepository) ListStats(ctx context.Context, productID string, from, to time.Time) ([]*entity.ProductDailyStats, error) {
+	docs, err := r.client.Collection("product_stats").
+		Where("productId", "==", productID).
+		Where("date", ">=", from.UTC().Format(statsDateLayout)).
+		Where("date", "<=", to.UTC().Format(statsDateLayout)).
+		OrderBy("date", firestore.Asc).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to get product stats", err)
+	}
+
+	stats := make([]*entity.ProductDailyStats, 0, len(docs))
+	for _, doc := range docs {
+		var day entity.ProductDailyStats
+		if err := doc.DataTo(&day); err != nil {
+			return nil, errors.Internal("Failed to parse product stats", err)
+		}
+		stats = append(stats, &day)
+	}
+
+	return stats, nil
+}
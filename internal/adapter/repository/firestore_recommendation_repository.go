@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreRecommendationRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreRecommendationRepository(client *firestore.Client) repository.RecommendationRepository {
+	return &firestoreRecommendationRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreRecommendationRepository) RecordView(ctx context.Context, view *entity.ProductView) error {
+	view.ID = view.UserID + "_" + view.ProductID
+
+	_, err := r.client.Collection("product_views").Doc(view.ID).Set(ctx, view)
+	if err != nil {
+		return errors.Internal("Failed to record product view", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreRecommendationRepository) ListViewsSince(ctx context.Context, since time.Time) ([]*entity.ProductView, error) {
+	docs, err := r.client.Collection("product_views").
+		Where("viewedAt", ">=", since).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list product views", err)
+	}
+
+	return parseProductViews(docs)
+}
+
+func (r *firestoreRecommendationRepository) ListViewsByUser(ctx context.Context, userID string, limit int) ([]*entity.ProductView, error) {
+	docs, err := r.client.Collection("product_views").
+		Where("userId", "==", userID).
+		OrderBy("viewedAt", firestore.Desc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list product views", err)
+	}
+
+	return parseProductViews(docs)
+}
+
+func (r *firestoreRecommendationRepository) SaveRecommendations(ctx context.Context, recommendations []*entity.ProductRecommendations) error {
+	for start := 0; start < len(recommendations); start += maxBatchWrites {
+		end := min(start+maxBatchWrites, len(recommendations))
+
+		batch := r.client.Batch()
+		for _, recommendation := range recommendations[start:end] {
+			batch.Set(r.client.Collection("product_recommendations").Doc(recommendation.ProductID), recommendation)
+		}
+
+		if _, err := batch.Commit(ctx); err != nil {
+			return errors.Internal("Failed to save product recommendations", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *firestoreRecommendationRepository) GetRecommendations(ctx context.Context, productID string) (*entity.ProductRecommendations, error) {
+	doc, err := r.client.Collection("product_recommendations").Doc(productID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Product recommendations", err)
+		}
+		return nil, errors.Internal("Failed to get product recommendations", err)
+	}
+
+	var recommendations entity.ProductRecommendations
+	if err := doc.DataTo(&recommendations); err != nil {
+		return nil, errors.Internal("Failed to parse product recommendations data", err)
+	}
+
+	return &recommendations, nil
+}
+
+func parseProductViews(docs []*firestore.DocumentSnapshot) ([]*entity.ProductView, error) {
+	views := make([]*entity.ProductView, 0, len(docs))
+	for _, doc := range docs {
+		var view entity.ProductView
+		if err := doc.DataTo(&view); err != nil {
+			return nil, errors.Internal("Failed to parse product view data", err)
+		}
+		views = append(views, &view)
+	}
+	return views, nil
+}
@@ -12,6 +12,7 @@ import (
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
 	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
 
 	"github.com/google/uuid"
 )
@@ -81,8 +82,43 @@ func (r *firestoreReviewRepository) GetByTransactionID(ctx context.Context, tran
 }
 
 func (r *firestoreReviewRepository) List(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]*entity.Review, int64, error) {
+	reviews, info, err := r.ListPage(ctx, filter, utils.PaginationParams{PageSize: limit, Offset: offset})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, info.Total, nil
+}
+
+func (r *firestoreReviewRepository) ListPage(ctx context.Context, filter map[string]interface{}, page utils.PaginationParams) ([]*entity.Review, *utils.PageInfo, error) {
+	query := r.client.Collection("reviews").Query
+	for key, value := range filter {
+		query = query.Where(key, "==", value)
+	}
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, nil, errors.Internal("Failed to count reviews", err)
+	}
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", page)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
+		}
+		return nil, nil, errors.Internal("Failed to list reviews", err)
+	}
+
+	reviews := make([]*entity.Review, 0, len(docs))
+	for _, doc := range docs {
+		var review entity.Review
+		if err := doc.DataTo(&review); err != nil {
+			return nil, nil, errors.Internal("Failed to parse review data", err)
+		}
+		reviews = append(reviews, &review)
+	}
 
-	return []*entity.Review{}, 0, nil
+	return reviews, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
 }
 
 func (r *firestoreReviewRepository) Update(ctx context.Context, review *entity.Review) error {
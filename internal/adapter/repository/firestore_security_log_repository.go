@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreSecurityLogRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreSecurityLogRepository(client *firestore.Client) repository.SecurityLogRepository {
+	return &firestoreSecurityLogRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreSecurityLogRepository) Create(ctx context.Context, securityLog *entity.SecurityLog) error {
+	if securityLog.ID == "" {
+		securityLog.ID = uuid.New().String()
+	}
+
+	if securityLog.CreatedAt.IsZero() {
+		securityLog.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection("security_logs").Doc(securityLog.ID).Set(ctx, securityLog)
+	if err != nil {
+		return errors.Internal("Failed to create security log", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreSecurityLogRepository) ListByUserID(ctx context.Context, userID string, limit int) ([]*entity.SecurityLog, error) {
+	return r.listWhere(ctx, "userId", userID, limit)
+}
+
+func (r *firestoreSecurityLogRepository) ListByDeviceID(ctx context.Context, deviceID string, limit int) ([]*entity.SecurityLog, error) {
+	return r.listWhere(ctx, "deviceId", deviceID, limit)
+}
+
+func (r *firestoreSecurityLogRepository) ListByIPAddress(ctx context.Context, ipAddress string, limit int) ([]*entity.SecurityLog, error) {
+	return r.listWhere(ctx, "ipAddress", ipAddress, limit)
+}
+
+// listWhere filters on a single field, newest first. Each filtered field
+// needs a composite index with createdAt (see firestore.indexes.json).
+func (r *firestoreSecurityLogRepository) listWhere(ctx context.Context, field, value string, limit int) ([]*entity.SecurityLog, error) {
+	query := r.client.Collection("security_logs").
+		Where(field, "==", value).
+		OrderBy("createdAt", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	logs := []*entity.SecurityLog{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list security logs", err)
+		}
+
+		var securityLog entity.SecurityLog
+		if err := doc.DataTo(&securityLog); err != nil {
+			return nil, errors.Internal("Failed to parse security log data", err)
+		}
+
+		logs = append(logs, &securityLog)
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// seedCollectionOnce writes the default documents, keyed by document ID, the
+// first time it runs against a collection. A marker document records the
+// seed, so defaults an admin later deletes stay deleted, and a collection
+// that already holds documents is only marked. Runs in a transaction, so
+// instances starting together seed at most once.
+func seedCollectionOnce(ctx context.Context, client *firestore.Client, collection string, docs map[string]interface{}) (bool, error) {
+	markerRef := client.Collection("seed_markers").Doc(collection)
+	seeded := false
+
+	err := client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		seeded = false
+
+		_, err := tx.Get(markerRef)
+		if err == nil {
+			return nil
+		}
+		if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		existing, err := tx.Documents(client.Collection(collection).Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+
+		if len(existing) == 0 {
+			for id, doc := range docs {
+				if err := tx.Set(client.Collection(collection).Doc(id), doc); err != nil {
+					return err
+				}
+			}
+			seeded = true
+		}
+
+		return tx.Set(markerRef, map[string]interface{}{
+			"collection": collection,
+			"seeded":     seeded,
+			"markedAt":   time.Now(),
+		})
+	})
+
+	return seeded, err
+}
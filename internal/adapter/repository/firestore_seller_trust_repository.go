@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreSellerTrustRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreSellerTrustRepository(client *firestore.Client) repository.SellerTrustRepository {
+	return &firestoreSellerTrustRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreSellerTrustRepository) GetBySellerID(ctx context.Context, sellerID string) (*entity.SellerTrustProfile, error) {
+	doc, err := r.client.Collection("seller_trust_profiles").Doc(sellerID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Seller trust profile", err)
+		}
+		return nil, errors.Internal("Failed to get seller trust profile", err)
+	}
+
+	var profile entity.SellerTrustProfile
+	if err := doc.DataTo(&profile); err != nil {
+		return nil, errors.Internal("Failed to parse seller trust profile data", err)
+	}
+
+	return &profile, nil
+}
+
+// Save stores the profile under the seller ID, one document per seller
+func (r *firestoreSellerTrustRepository) Save(ctx context.Context, profile *entity.SellerTrustProfile) error {
+	profile.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("seller_trust_profiles").Doc(profile.SellerID).Set(ctx, profile)
+	if err != nil {
+		return errors.Internal("Failed to save seller trust profile", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreSellerTrustRepository) List(ctx context.Context, tier string, limit, offset int) ([]*entity.SellerTrustProfile, int64, error) {
+	query := r.client.Collection("seller_trust_profiles").Query
+	if tier != "" {
+		query = query.Where("tier", "==", tier)
+	}
+
+	allDocs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count seller trust profiles", err)
+	}
+	total := int64(len(allDocs))
+
+	query = query.OrderBy("riskScore", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	profiles := []*entity.SellerTrustProfile{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to list seller trust profiles", err)
+		}
+
+		var profile entity.SellerTrustProfile
+		if err := doc.DataTo(&profile); err != nil {
+			log.Printf("Failed to parse seller trust profile %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		profiles = append(profiles, &profile)
+	}
+
+	return profiles, total, nil
+}
+
+func (r *firestoreSellerTrustRepository) ListComputedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.SellerTrustProfile, error) {
+	docs, err := r.client.Collection("seller_trust_profiles").
+		Where("computedAt", "<", before).
+		OrderBy("computedAt", firestore.Asc).
+		Limit(limit).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list stale seller trust profiles", err)
+	}
+
+	profiles := make([]*entity.SellerTrustProfile, 0, len(docs))
+	for _, doc := range docs {
+		var profile entity.SellerTrustProfile
+		if err := doc.DataTo(&profile); err != nil {
+			log.Printf("Failed to parse seller trust profile %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		profiles = append(profiles, &profile)
+	}
+
+	return profiles, nil
+}
+
+type sellerScoringCheckpoint struct {
+	StartedAt time.Time `firestore:"startedAt"`
+}
+
+func (r *firestoreSellerTrustRepository) GetScoringCheckpoint(ctx context.Context) (*time.Time, error) {
+	doc, err := r.client.Collection("job_checkpoints").Doc("seller_risk_scoring").Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get seller scoring checkpoint", err)
+	}
+
+	var checkpoint sellerScoringCheckpoint
+	if err := doc.DataTo(&checkpoint); err != nil {
+		return nil, errors.Internal("Failed to parse seller scoring checkpoint", err)
+	}
+
+	return &checkpoint.StartedAt, nil
+}
+
+func (r *firestoreSellerTrustRepository) SaveScoringCheckpoint(ctx context.Context, startedAt time.Time) error {
+	_, err := r.client.Collection("job_checkpoints").Doc("seller_risk_scoring").Set(ctx, sellerScoringCheckpoint{StartedAt: startedAt})
+	if err != nil {
+		return errors.Internal("Failed to save seller scoring checkpoint", err)
+	}
+
+	return nil
+}
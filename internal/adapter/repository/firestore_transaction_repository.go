@@ -12,25 +12,33 @@ import (
 	"google.golang.org/grpc/status"
 
 	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
 	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
 )
 
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entity.Transaction) error
 	GetByID(ctx context.Context, id string) (*entity.Transaction, error)
 	Update(ctx context.Context, transaction *entity.Transaction) error
+	// UpdateFields writes only the given fields, keyed by their Firestore names
+	UpdateFields(ctx context.Context, transactionID string, updates map[string]interface{}) error
+	// UpdateFieldsIfUnchanged writes the given fields only if the stored status
+	// and escrow status still equal the expected values and no dispute is open
+	UpdateFieldsIfUnchanged(ctx context.Context, transactionID, expectedStatus, expectedEscrowStatus string, updates map[string]interface{}) (bool, error)
 	List(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]*entity.Transaction, int64, error)
 
 	CreateLog(ctx context.Context, log *entity.TransactionLog) error
 	ListLogsByTransactionID(ctx context.Context, transactionID string) ([]*entity.TransactionLog, error)
 
 	ListByUserID(ctx context.Context, userID string, role string, status string, limit, offset int) ([]*entity.Transaction, int64, error)
+	ListByUserIDPage(ctx context.Context, userID string, role string, status string, page utils.PaginationParams) ([]*entity.Transaction, *utils.PageInfo, error)
 	ListPendingMiddlemanTransactions(ctx context.Context, limit, offset int) ([]*entity.Transaction, int64, error) // Modified to be more generic
 
 	GetTransactionStats(ctx context.Context, userID string, period string) (map[string]interface{}, error)
 	HasCompletedTransaction(ctx context.Context, userID, productID string) (bool, error)
 	GetCompletedTransactionCount(ctx context.Context, productID string) (int, error)
-	GetPendingTransactionCount(ctx context.Context, productID string) (int, error)
+	GetPendingTransactionCount(ctx context.Context, productID, variantID string) (int, error) // variantID "" counts the whole product
 	
 	// Midtrans Integration Methods
 	GetByMidtransOrderID(ctx context.Context, midtransOrderID string) (*entity.Transaction, error)
@@ -39,6 +47,44 @@ type TransactionRepository interface {
 	CreateApproval(ctx context.Context, approval *entity.TransactionApproval) error
 	GetApprovalsByTransactionID(ctx context.Context, transactionID string) ([]*entity.TransactionApproval, error)
 	UpdateApproval(ctx context.Context, approval *entity.TransactionApproval) error
+
+	// Credential Access Audit Methods
+	CreateCredentialAccessLog(ctx context.Context, accessLog *entity.CredentialAccessLog) error
+	ListCredentialAccessLogs(ctx context.Context, transactionID string) ([]*entity.CredentialAccessLog, error)
+	// ReserveCredentialView counts a reveal atomically when fewer than limit
+	// reveals happened within window before now. When refused it returns the
+	// time the next reveal is allowed.
+	ReserveCredentialView(ctx context.Context, transactionID string, now time.Time, limit int, window time.Duration) (bool, time.Time, error)
+	// MarkCredentialsAccessed records the first access and adds the security flags
+	MarkCredentialsAccessed(ctx context.Context, transactionID string, accessedAt time.Time, securityFlags []string) error
+
+	// BackfillCredentialsRedacted sets credentialsRedacted to false on
+	// transactions written before the field existed, so the redaction query
+	// matches them. Runs once; a marker records completion.
+	BackfillCredentialsRedacted(ctx context.Context) (int, error)
+	// ListUnredactedCompletedBefore pages, by completion time, through
+	// transactions completed before cutoff whose credentials are not yet
+	// redacted, starting after the given transaction (nil for the first page)
+	ListUnredactedCompletedBefore(ctx context.Context, cutoff time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error)
+	// MarkCredentialsRedacted removes the stored credentials of a transaction
+	MarkCredentialsRedacted(ctx context.Context, transactionID string, redactedAt time.Time) error
+
+	// ListAutoReleaseDueBefore pages, by auto-release time, through
+	// transactions awaiting auto-release at or before the given time,
+	// starting after the given transaction (nil for the first page)
+	ListAutoReleaseDueBefore(ctx context.Context, before time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error)
+
+	// ListUpdatedSince pages, by update time, through transactions updated at
+	// or after since, starting after the given transaction (nil for the first page)
+	ListUpdatedSince(ctx context.Context, since time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error)
+
+	// ListPageByID pages through every transaction by document ID, starting
+	// after afterID ("" for the first page)
+	ListPageByID(ctx context.Context, afterID string, limit int) ([]*entity.Transaction, error)
+	// ResealCredentials reads the stored credentials and writes back reseal's
+	// result in one transaction, touching no other field. An error from
+	// reseal aborts the write and is returned as is.
+	ResealCredentials(ctx context.Context, transactionID string, reseal repository.CredentialResealFunc) (bool, error)
 }
 
 type firestoreTransactionRepository struct {
@@ -96,6 +142,63 @@ func (r *firestoreTransactionRepository) Update(ctx context.Context, transaction
 	return nil
 }
 
+func (r *firestoreTransactionRepository) UpdateFields(ctx context.Context, transactionID string, updates map[string]interface{}) error {
+	_, err := r.client.Collection("transactions").Doc(transactionID).Update(ctx, transactionFieldUpdates(updates))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return errors.NotFound("Transaction", err)
+		}
+		return errors.Internal("Failed to update transaction", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreTransactionRepository) UpdateFieldsIfUnchanged(ctx context.Context, transactionID, expectedStatus, expectedEscrowStatus string, updates map[string]interface{}) (bool, error) {
+	docRef := r.client.Collection("transactions").Doc(transactionID)
+	updated := false
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = false
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current entity.Transaction
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		if current.Status != expectedStatus || current.EscrowStatus != expectedEscrowStatus || current.IsDisputed {
+			return nil
+		}
+
+		updated = true
+		return tx.Update(docRef, transactionFieldUpdates(updates))
+	})
+
+	if err != nil {
+		return false, errors.Internal("Failed to update transaction", err)
+	}
+
+	return updated, nil
+}
+
+// transactionFieldUpdates converts a field map into Firestore updates,
+// stamping updatedAt
+func transactionFieldUpdates(updates map[string]interface{}) []firestore.Update {
+	fieldUpdates := make([]firestore.Update, 0, len(updates)+1)
+	for path, value := range updates {
+		fieldUpdates = append(fieldUpdates, firestore.Update{Path: path, Value: value})
+	}
+	if _, ok := updates["updatedAt"]; !ok {
+		fieldUpdates = append(fieldUpdates, firestore.Update{Path: "updatedAt", Value: time.Now()})
+	}
+	return fieldUpdates
+}
+
 func (r *firestoreTransactionRepository) List(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]*entity.Transaction, int64, error) {
 	collection := r.client.Collection("transactions")
 	query := collection.OrderBy("createdAt", firestore.Desc)
@@ -119,12 +222,11 @@ func (r *firestoreTransactionRepository) List(ctx context.Context, filter map[st
 		query = query.Where(key, "==", value)
 	}
 
-	countDocs, err := query.Documents(ctx).GetAll()
+	total, err := countQuery(ctx, query)
 	if err != nil {
 		log.Printf("Firestore error while counting transactions with filter %v: %v", filter, err)
 		return nil, 0, errors.Internal("Failed to count transactions", err)
 	}
-	total := int64(len(countDocs))
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -157,6 +259,242 @@ func (r *firestoreTransactionRepository) List(ctx context.Context, filter map[st
 	return transactions, total, nil
 }
 
+func (r *firestoreTransactionRepository) ReserveCredentialView(ctx context.Context, transactionID string, now time.Time, limit int, window time.Duration) (bool, time.Time, error) {
+	docRef := r.client.Collection("transactions").Doc(transactionID)
+	allowed := false
+	var retryAt time.Time
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		allowed, retryAt = false, time.Time{}
+
+		doc, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+
+		var current entity.Transaction
+		if err := doc.DataTo(&current); err != nil {
+			return err
+		}
+
+		recent := []time.Time{}
+		for _, viewedAt := range current.CredentialViewTimes {
+			if viewedAt.After(now.Add(-window)) {
+				recent = append(recent, viewedAt)
+			}
+		}
+
+		if len(recent) >= limit {
+			retryAt = recent[len(recent)-limit].Add(window)
+			return nil
+		}
+
+		allowed = true
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "credentialViewTimes", Value: append(recent, now)},
+			{Path: "credentialsViewCount", Value: current.CredentialsViewCount + 1},
+			{Path: "updatedAt", Value: now},
+		})
+	})
+
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, time.Time{}, errors.NotFound("Transaction", err)
+		}
+		return false, time.Time{}, errors.Internal("Failed to record credential view", err)
+	}
+
+	return allowed, retryAt, nil
+}
+
+func (r *firestoreTransactionRepository) MarkCredentialsAccessed(ctx context.Context, transactionID string, accessedAt time.Time, securityFlags []string) error {
+	updates := []firestore.Update{
+		{Path: "credentialsAccessed", Value: true},
+		{Path: "credentialsAccessedAt", Value: accessedAt},
+		{Path: "updatedAt", Value: time.Now()},
+	}
+	if len(securityFlags) > 0 {
+		flags := make([]interface{}, len(securityFlags))
+		for i, flag := range securityFlags {
+			flags[i] = flag
+		}
+		updates = append(updates, firestore.Update{Path: "securityFlags", Value: firestore.ArrayUnion(flags...)})
+	}
+
+	_, err := r.client.Collection("transactions").Doc(transactionID).Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return errors.NotFound("Transaction", err)
+		}
+		return errors.Internal("Failed to record credential access", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreTransactionRepository) BackfillCredentialsRedacted(ctx context.Context) (int, error) {
+	markerRef := r.client.Collection("migrations").Doc("transactions_credentials_redacted")
+	if _, err := markerRef.Get(ctx); err == nil {
+		return 0, nil
+	} else if status.Code(err) != codes.NotFound {
+		return 0, errors.Internal("Failed to read migration marker", err)
+	}
+
+	updated := 0
+	iter := r.client.Collection("transactions").Select("credentialsRedacted").Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return updated, errors.Internal("Failed to scan transactions for backfill", err)
+		}
+
+		if _, err := doc.DataAt("credentialsRedacted"); err == nil {
+			continue
+		}
+
+		if _, err := doc.Ref.Update(ctx, []firestore.Update{{Path: "credentialsRedacted", Value: false}}); err != nil {
+			return updated, errors.Internal("Failed to backfill credentialsRedacted", err)
+		}
+		updated++
+	}
+
+	if _, err := markerRef.Set(ctx, map[string]interface{}{"updated": updated, "completedAt": time.Now()}); err != nil {
+		return updated, errors.Internal("Failed to write migration marker", err)
+	}
+
+	return updated, nil
+}
+
+func (r *firestoreTransactionRepository) ListUnredactedCompletedBefore(ctx context.Context, cutoff time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error) {
+	query := r.client.Collection("transactions").
+		Where("credentialsRedacted", "==", false).
+		Where("completedAt", "<", cutoff).
+		OrderBy("completedAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+
+	if after != nil && after.CompletedAt != nil {
+		query = query.StartAfter(*after.CompletedAt, after.ID)
+	}
+
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list unredacted transactions", err)
+	}
+
+	transactions := make([]*entity.Transaction, 0, len(docs))
+	for _, doc := range docs {
+		var transaction entity.Transaction
+		if err := doc.DataTo(&transaction); err != nil {
+			return nil, errors.Internal("Failed to parse transaction data", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *firestoreTransactionRepository) ListAutoReleaseDueBefore(ctx context.Context, before time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error) {
+	query := r.client.Collection("transactions").
+		Where("status", "==", "credentials_delivered").
+		Where("autoReleaseAt", "<=", before).
+		OrderBy("autoReleaseAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+
+	if after != nil && after.AutoReleaseAt != nil {
+		query = query.StartAfter(*after.AutoReleaseAt, after.ID)
+	}
+
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list transactions due for auto-release", err)
+	}
+
+	transactions := make([]*entity.Transaction, 0, len(docs))
+	for _, doc := range docs {
+		var transaction entity.Transaction
+		if err := doc.DataTo(&transaction); err != nil {
+			return nil, errors.Internal("Failed to parse transaction data", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *firestoreTransactionRepository) ListUpdatedSince(ctx context.Context, since time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error) {
+	query := r.client.Collection("transactions").
+		Where("updatedAt", ">=", since).
+		OrderBy("updatedAt", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+
+	if after != nil {
+		query = query.StartAfter(after.UpdatedAt, after.ID)
+	}
+
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list updated transactions", err)
+	}
+
+	transactions := make([]*entity.Transaction, 0, len(docs))
+	for _, doc := range docs {
+		var transaction entity.Transaction
+		if err := doc.DataTo(&transaction); err != nil {
+			return nil, errors.Internal("Failed to parse transaction data", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *firestoreTransactionRepository) ListPageByID(ctx context.Context, afterID string, limit int) ([]*entity.Transaction, error) {
+	query := r.client.Collection("transactions").OrderBy(firestore.DocumentID, firestore.Asc)
+	if afterID != "" {
+		query = query.StartAfter(afterID)
+	}
+
+	docs, err := query.Limit(limit).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to list transactions", err)
+	}
+
+	transactions := make([]*entity.Transaction, 0, len(docs))
+	for _, doc := range docs {
+		var transaction entity.Transaction
+		if err := doc.DataTo(&transaction); err != nil {
+			return nil, errors.Internal("Failed to parse transaction data", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *firestoreTransactionRepository) ResealCredentials(ctx context.Context, transactionID string, reseal repository.CredentialResealFunc) (bool, error) {
+	return resealDocumentCredentials(ctx, r.client, r.client.Collection("transactions").Doc(transactionID), reseal, "Failed to save re-encrypted transaction credentials")
+}
+
+func (r *firestoreTransactionRepository) MarkCredentialsRedacted(ctx context.Context, transactionID string, redactedAt time.Time) error {
+	_, err := r.client.Collection("transactions").Doc(transactionID).Update(ctx, []firestore.Update{
+		{Path: "credentials", Value: firestore.Delete},
+		{Path: "encryptedCredentials", Value: firestore.Delete},
+		{Path: "credentialsRedacted", Value: true},
+		{Path: "credentialsRedactedAt", Value: redactedAt},
+		{Path: "updatedAt", Value: redactedAt},
+	})
+	if err != nil {
+		return errors.Internal("Failed to redact transaction credentials", err)
+	}
+
+	return nil
+}
+
 func (r *firestoreTransactionRepository) CreateLog(ctx context.Context, log *entity.TransactionLog) error {
 	if log.ID == "" {
 		log.ID = uuid.New().String()
@@ -200,13 +538,22 @@ func (r *firestoreTransactionRepository) ListLogsByTransactionID(ctx context.Con
 }
 
 func (r *firestoreTransactionRepository) ListByUserID(ctx context.Context, userID string, role string, status string, limit, offset int) ([]*entity.Transaction, int64, error) {
+	transactions, info, err := r.ListByUserIDPage(ctx, userID, role, status, utils.PaginationParams{PageSize: limit, Offset: offset})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, info.Total, nil
+}
+
+func (r *firestoreTransactionRepository) ListByUserIDPage(ctx context.Context, userID string, role string, status string, page utils.PaginationParams) ([]*entity.Transaction, *utils.PageInfo, error) {
 	var field string
 	if role == "buyer" {
 		field = "buyerId"
 	} else if role == "seller" {
 		field = "sellerId"
 	} else {
-		return nil, 0, errors.BadRequest("Invalid role", nil)
+		return nil, nil, errors.BadRequest("Invalid role", nil)
 	}
 
 	query := r.client.Collection("transactions").Where(field, "==", userID)
@@ -215,44 +562,32 @@ func (r *firestoreTransactionRepository) ListByUserID(ctx context.Context, userI
 		query = query.Where("status", "==", status)
 	}
 
-	query = query.OrderBy("createdAt", firestore.Desc)
-
-	countDocs, err := query.Documents(ctx).GetAll()
+	total, err := countQuery(ctx, query)
 	if err != nil {
 		log.Printf("Firestore error while counting transactions for user %s, role %s, status %s: %v", userID, role, status, err)
-		return nil, 0, errors.Internal("Failed to count transactions", err)
+		return nil, nil, errors.Internal("Failed to count transactions", err)
 	}
-	total := int64(len(countDocs))
 
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-	if offset > 0 {
-		query = query.Offset(offset)
-	}
-
-	iter := query.Documents(ctx)
-	var transactions []*entity.Transaction
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			log.Printf("Firestore error while iterating transactions for user %s, role %s, status %s: %v", userID, role, status, err)
-			return nil, 0, errors.Internal("Failed to iterate transactions", err)
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", page)
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, nil, err
 		}
+		log.Printf("Firestore error while iterating transactions for user %s, role %s, status %s: %v", userID, role, status, err)
+		return nil, nil, errors.Internal("Failed to iterate transactions", err)
+	}
 
+	transactions := make([]*entity.Transaction, 0, len(docs))
+	for _, doc := range docs {
 		var transaction entity.Transaction
 		if err := doc.DataTo(&transaction); err != nil {
 			log.Printf("Error parsing transaction data for user %s, role %s, status %s: %v", userID, role, status, err)
-			return nil, 0, errors.Internal("Failed to parse transaction data", err)
+			return nil, nil, errors.Internal("Failed to parse transaction data", err)
 		}
 		transactions = append(transactions, &transaction)
 	}
 
-	return transactions, total, nil
+	return transactions, &utils.PageInfo{Total: total, NextCursor: nextCursor}, nil
 }
 
 // Modified: ListPendingMiddlemanTransactions now fetches based on pending status and filters middlemanStatus in-memory
@@ -389,13 +724,17 @@ func (r *firestoreTransactionRepository) GetCompletedTransactionCount(ctx contex
 	return count, nil
 }
 
-func (r *firestoreTransactionRepository) GetPendingTransactionCount(ctx context.Context, productID string) (int, error) {
-	log.Printf("Getting pending transaction count for product %s", productID)
+func (r *firestoreTransactionRepository) GetPendingTransactionCount(ctx context.Context, productID, variantID string) (int, error) {
+	log.Printf("Getting pending transaction count for product %s variant %q", productID, variantID)
 	
 	query := r.client.Collection("transactions").
 		Where("productId", "==", productID).
 		Where("paymentStatus", "==", "pending").
-		Where("status", "in", []string{"payment_pending", "payment_processing"})
+		Where("status", "in", []string{"payment_pending", "payment_processing", "fraud_review"})
+
+	if variantID != "" {
+		query = query.Where("variantId", "==", variantID)
+	}
 	
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
@@ -494,3 +833,49 @@ func (r *firestoreTransactionRepository) UpdateApproval(ctx context.Context, app
 	log.Printf("Transaction approval updated: %s", approval.ID)
 	return nil
 }
+
+// CreateCredentialAccessLog stores an audit record of a credential reveal
+func (r *firestoreTransactionRepository) CreateCredentialAccessLog(ctx context.Context, accessLog *entity.CredentialAccessLog) error {
+	if accessLog.ID == "" {
+		accessLog.ID = uuid.New().String()
+	}
+
+	if accessLog.AccessedAt.IsZero() {
+		accessLog.AccessedAt = time.Now()
+	}
+
+	_, err := r.client.Collection("credential_access_logs").Doc(accessLog.ID).Set(ctx, accessLog)
+	if err != nil {
+		return errors.Internal("Failed to create credential access log", err)
+	}
+
+	return nil
+}
+
+// ListCredentialAccessLogs retrieves the reveal history of a transaction
+func (r *firestoreTransactionRepository) ListCredentialAccessLogs(ctx context.Context, transactionID string) ([]*entity.CredentialAccessLog, error) {
+	query := r.client.Collection("credential_access_logs").Where("transactionId", "==", transactionID).OrderBy("accessedAt", firestore.Asc)
+	iter := query.Documents(ctx)
+
+	accessLogs := []*entity.CredentialAccessLog{}
+	for {
+		doc, err := iter.Next()
+		if err != nil {
+			if err == iterator.Done {
+				break
+			}
+			return nil, errors.Internal("Failed to get credential access logs", err)
+		}
+
+		var accessLog entity.CredentialAccessLog
+		if err := doc.DataTo(&accessLog); err != nil {
+			log.Printf("Failed to parse credential access log %s: %v", doc.Ref.ID, err)
+			continue
+		}
+
+		accessLog.ID = doc.Ref.ID
+		accessLogs = append(accessLogs, &accessLog)
+	}
+
+	return accessLogs, nil
+}
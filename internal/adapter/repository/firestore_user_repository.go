@@ -132,6 +132,15 @@ func (r *firestoreUserRepository) Update(ctx context.Context, user *entity.User)
 	return nil
 }
 
+func (r *firestoreUserRepository) UpdateTrustTier(ctx context.Context, userID, tier string) error {
+	// Bypasses the Update throttle, which would drop a tier change
+	_, err := r.client.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "trustTier", Value: tier},
+		{Path: "updatedAt", Value: time.Now()},
+	})
+	return err
+}
+
 // cleanupThrottleMap removes old entries to prevent memory leak
 func (r *firestoreUserRepository) cleanupThrottleMap() {
 	r.updateMutex.Lock()
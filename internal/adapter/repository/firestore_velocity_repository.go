@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+type firestoreVelocityRepository struct {
+	client *firestore.Client
+}
+
+func NewFirestoreVelocityRepository(client *firestore.Client) repository.VelocityRepository {
+	return &firestoreVelocityRepository{
+		client: client,
+	}
+}
+
+func (r *firestoreVelocityRepository) CreateRule(ctx context.Context, rule *entity.VelocityRule) error {
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	_, err := r.client.Collection("velocity_rules").Doc(rule.ID).Set(ctx, rule)
+	if err != nil {
+		return errors.Internal("Failed to create velocity rule", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreVelocityRepository) GetRule(ctx context.Context, id string) (*entity.VelocityRule, error) {
+	doc, err := r.client.Collection("velocity_rules").Doc(id).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, errors.NotFound("Velocity rule", err)
+		}
+		return nil, errors.Internal("Failed to get velocity rule", err)
+	}
+
+	var rule entity.VelocityRule
+	if err := doc.DataTo(&rule); err != nil {
+		return nil, errors.Internal("Failed to parse velocity rule data", err)
+	}
+
+	return &rule, nil
+}
+
+func (r *firestoreVelocityRepository) UpdateRule(ctx context.Context, rule *entity.VelocityRule) error {
+	rule.UpdatedAt = time.Now()
+
+	_, err := r.client.Collection("velocity_rules").Doc(rule.ID).Set(ctx, rule)
+	if err != nil {
+		return errors.Internal("Failed to update velocity rule", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreVelocityRepository) DeleteRule(ctx context.Context, id string) error {
+	_, err := r.client.Collection("velocity_rules").Doc(id).Delete(ctx)
+	if err != nil {
+		return errors.Internal("Failed to delete velocity rule", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreVelocityRepository) ListRules(ctx context.Context) ([]*entity.VelocityRule, error) {
+	iter := r.client.Collection("velocity_rules").OrderBy("createdAt", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	rules := []*entity.VelocityRule{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list velocity rules", err)
+		}
+
+		var rule entity.VelocityRule
+		if err := doc.DataTo(&rule); err != nil {
+			return nil, errors.Internal("Failed to parse velocity rule data", err)
+		}
+
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+func (r *firestoreVelocityRepository) SeedDefaults(ctx context.Context, rules []*entity.VelocityRule) (bool, error) {
+	now := time.Now()
+	docs := make(map[string]interface{}, len(rules))
+	for _, rule := range rules {
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+		docs[rule.ID] = rule
+	}
+
+	seeded, err := seedCollectionOnce(ctx, r.client, "velocity_rules", docs)
+	if err != nil {
+		return false, errors.Internal("Failed to seed velocity rules", err)
+	}
+
+	return seeded, nil
+}
+
+func (r *firestoreVelocityRepository) CreateEvent(ctx context.Context, event *entity.VelocityEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection("velocity_events").Doc(event.ID).Set(ctx, event)
+	if err != nil {
+		return errors.Internal("Failed to record velocity event", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreVelocityRepository) ListEvents(ctx context.Context, scope, subjectID, action string, since time.Time) ([]*entity.VelocityEvent, error) {
+	field := "userId"
+	if scope == "device" {
+		field = "deviceId"
+	}
+
+	// Filter on subject and action and check the time in memory to avoid a composite index
+	iter := r.client.Collection("velocity_events").
+		Where(field, "==", subjectID).
+		Where("action", "==", action).
+		Documents(ctx)
+	defer iter.Stop()
+
+	events := []*entity.VelocityEvent{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to list velocity events", err)
+		}
+
+		var event entity.VelocityEvent
+		if err := doc.DataTo(&event); err != nil {
+			return nil, errors.Internal("Failed to parse velocity event data", err)
+		}
+
+		if event.CreatedAt.After(since) {
+			events = append(events, &event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	return events, nil
+}
+
+func (r *firestoreVelocityRepository) Reserve(ctx context.Context, event *entity.VelocityEvent, counters []*entity.VelocityCounter, check func(counters []*entity.VelocityCounter) error) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	refs := r.counterRefs(counters)
+	var checkErr error
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		checkErr = nil
+
+		if err := r.loadCounters(tx, refs, counters, event.CreatedAt); err != nil {
+			return err
+		}
+
+		if checkErr = check(counters); checkErr != nil {
+			return checkErr
+		}
+
+		for i, counter := range counters {
+			counter.Events = append(counter.Events, event)
+			counter.UpdatedAt = event.CreatedAt
+			if err := tx.Set(refs[i], counter); err != nil {
+				return err
+			}
+		}
+
+		return tx.Set(r.client.Collection("velocity_events").Doc(event.ID), event)
+	})
+
+	if checkErr != nil {
+		return checkErr
+	}
+	if err != nil {
+		return errors.Internal("Failed to reserve velocity limits", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreVelocityRepository) Release(ctx context.Context, eventID string, counters []*entity.VelocityCounter) error {
+	refs := r.counterRefs(counters)
+
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := r.loadCounters(tx, refs, counters, time.Now()); err != nil {
+			return err
+		}
+
+		for i, counter := range counters {
+			kept := []*entity.VelocityEvent{}
+			for _, event := range counter.Events {
+				if event.ID != eventID {
+					kept = append(kept, event)
+				}
+			}
+			counter.Events = kept
+			counter.UpdatedAt = time.Now()
+			if err := tx.Set(refs[i], counter); err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(r.client.Collection("velocity_events").Doc(eventID))
+	})
+	if err != nil {
+		return errors.Internal("Failed to release velocity reservation", err)
+	}
+
+	return nil
+}
+
+// counterRefs keys each counter by scope, action, window and a hash of the
+// subject, since device IDs come from a client header
+func (r *firestoreVelocityRepository) counterRefs(counters []*entity.VelocityCounter) []*firestore.DocumentRef {
+	refs := make([]*firestore.DocumentRef, len(counters))
+	for i, counter := range counters {
+		sum := sha256.Sum256([]byte(counter.SubjectID))
+		counter.ID = fmt.Sprintf("%s_%s_%dh_%s", counter.Scope, counter.Action, counter.WindowHours, hex.EncodeToString(sum[:16]))
+		refs[i] = r.client.Collection("velocity_counters").Doc(counter.ID)
+	}
+	return refs
+}
+
+// loadCounters reads the stored events into counters, keeping only those
+// inside each counter's window ending at now
+func (r *firestoreVelocityRepository) loadCounters(tx *firestore.Transaction, refs []*firestore.DocumentRef, counters []*entity.VelocityCounter, now time.Time) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	docs, err := tx.GetAll(refs)
+	if err != nil {
+		return err
+	}
+
+	for i, doc := range docs {
+		counter := counters[i]
+		counter.Events = []*entity.VelocityEvent{}
+		if !doc.Exists() {
+			continue
+		}
+
+		var stored entity.VelocityCounter
+		if err := doc.DataTo(&stored); err != nil {
+			return err
+		}
+
+		since := now.Add(-time.Duration(counter.WindowHours) * time.Hour)
+		for _, event := range stored.Events {
+			if event.CreatedAt.After(since) {
+				counter.Events = append(counter.Events, event)
+			}
+		}
+	}
+
+	return nil
+}
@@ -8,9 +8,12 @@ import (
 
 	"cloud.google.com/go/firestore"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
 	"pasargamex/pkg/utils"
 )
 
@@ -173,62 +176,81 @@ func (r *firestoreWalletTransactionRepository) GetTransactionByID(ctx context.Co
 }
 
 func (r *firestoreWalletTransactionRepository) GetTransactionsByWalletID(ctx context.Context, walletID string, pagination *utils.Pagination) ([]entity.WalletTransaction, error) {
-	query := r.client.Collection("wallet_transactions").Where("walletId", "==", walletID).OrderBy("createdAt", firestore.Desc)
-	
-	if pagination.Page > 1 {
-		query = query.Offset((pagination.Page - 1) * pagination.Limit)
+	query := r.client.Collection("wallet_transactions").Where("walletId", "==", walletID)
+
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		return nil, err
 	}
-	query = query.Limit(pagination.Limit)
+	pagination.Total = total
 
-	iter := query.Documents(ctx)
-	defer iter.Stop()
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", walletPageParams(pagination))
+	if err != nil {
+		return nil, err
+	}
+	pagination.NextCursor = nextCursor
 
-	var transactions []entity.WalletTransaction
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+	return parseWalletTransactions(docs), nil
+}
 
-		var transaction entity.WalletTransaction
-		if err := doc.DataTo(&transaction); err != nil {
-			log.Printf("Error converting document to transaction: %v", err)
-			continue
-		}
+func (r *firestoreWalletTransactionRepository) GetTransactionsByUserID(ctx context.Context, userID string, pagination *utils.Pagination) ([]entity.WalletTransaction, error) {
+	query := r.client.Collection("wallet_transactions").Where("userId", "==", userID)
 
-		transactions = append(transactions, transaction)
+	total, err := countQuery(ctx, query)
+	if err != nil {
+		log.Printf("Error counting wallet transactions for user %s: %v", userID, err)
+	}
+	pagination.Total = total
+
+	docs, nextCursor, err := fetchPage(ctx, query, "createdAt", walletPageParams(pagination))
+	if status.Code(err) == codes.FailedPrecondition {
+		// The userId+createdAt composite index is missing; fall back to the
+		// unordered offset query, which has no cursor
+		log.Printf("Wallet transactions index missing, falling back to offset pagination: %v", err)
+		return r.getTransactionsByUserIDUnordered(ctx, query, pagination), nil
+	}
+	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, err
+		}
+		log.Printf("Error iterating transactions: %v", err)
+		// Return empty slice consistently
+		return []entity.WalletTransaction{}, nil
 	}
+	pagination.NextCursor = nextCursor
 
-	return transactions, nil
+	return parseWalletTransactions(docs), nil
 }
 
-func (r *firestoreWalletTransactionRepository) GetTransactionsByUserID(ctx context.Context, userID string, pagination *utils.Pagination) ([]entity.WalletTransaction, error) {
-	// Simple query without OrderBy to avoid composite index requirement
-	query := r.client.Collection("wallet_transactions").Where("userId", "==", userID)
-	
+func (r *firestoreWalletTransactionRepository) getTransactionsByUserIDUnordered(ctx context.Context, query firestore.Query, pagination *utils.Pagination) []entity.WalletTransaction {
 	if pagination.Page > 1 {
 		query = query.Offset((pagination.Page - 1) * pagination.Limit)
 	}
 	query = query.Limit(pagination.Limit)
 
-	iter := query.Documents(ctx)
-	defer iter.Stop()
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		log.Printf("Error iterating transactions: %v", err)
+		return []entity.WalletTransaction{}
+	}
 
-	var transactions []entity.WalletTransaction
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			log.Printf("Error iterating transactions: %v", err)
-			// Return empty slice consistently
-			return []entity.WalletTransaction{}, nil
-		}
+	return parseWalletTransactions(docs)
+}
+
+func walletPageParams(pagination *utils.Pagination) utils.PaginationParams {
+	return utils.PaginationParams{
+		Page:     pagination.Page,
+		PageSize: pagination.Limit,
+		Offset:   max(pagination.Page-1, 0) * pagination.Limit,
+		Cursor:   pagination.Cursor,
+	}
+}
 
+// parseWalletTransactions skips documents that fail to parse and always
+// returns a non-nil slice
+func parseWalletTransactions(docs []*firestore.DocumentSnapshot) []entity.WalletTransaction {
+	transactions := []entity.WalletTransaction{}
+	for _, doc := range docs {
 		var transaction entity.WalletTransaction
 		if err := doc.DataTo(&transaction); err != nil {
 			log.Printf("Error converting document to transaction: %v", err)
@@ -238,12 +260,7 @@ func (r *firestoreWalletTransactionRepository) GetTransactionsByUserID(ctx conte
 		transactions = append(transactions, transaction)
 	}
 
-	// Always return non-nil slice
-	if transactions == nil {
-		transactions = []entity.WalletTransaction{}
-	}
-
-	return transactions, nil
+	return transactions
 }
 
 func (r *firestoreWalletTransactionRepository) UpdateTransaction(ctx context.Context, transaction *entity.WalletTransaction) error {
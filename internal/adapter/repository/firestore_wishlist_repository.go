@@ -51,10 +51,11 @@ func (r *firestoreWishlistRepository) AddToWishlist(ctx context.Context, userID,
 	// Create wishlist item
 	wishlistID := fmt.Sprintf("%s_%s", userID, productID)
 	wishlistItem := entity.WishlistItem{
-		ID:        wishlistID,
-		UserID:    userID,
-		ProductID: productID,
-		CreatedAt: time.Now(),
+		ID:         wishlistID,
+		UserID:     userID,
+		ProductID:  productID,
+		CreatedAt:  time.Now(),
+		PriceAtAdd: product.Price,
 	}
 
 	// Save to Firestore
@@ -177,11 +178,13 @@ func (r *firestoreWishlistRepository) GetUserWishlist(ctx context.Context, userI
 		// Apply pagination
 		if int(activeCount) > offset && (limit <= 0 || len(wishlistItems) < limit) {
 			wishlistItems = append(wishlistItems, entity.WishlistItemWithProduct{
-				ID:        item.ID,
-				UserID:    item.UserID,
-				ProductID: item.ProductID,
-				Product:   product,
-				CreatedAt: item.CreatedAt,
+				ID:          item.ID,
+				UserID:      item.UserID,
+				ProductID:   item.ProductID,
+				Product:     product,
+				CreatedAt:   item.CreatedAt,
+				PriceAtAdd:  item.PriceAtAdd,
+				TargetPrice: item.TargetPrice,
 			})
 		}
 	}
@@ -266,6 +269,53 @@ func (r *firestoreWishlistRepository) GetWishlistCount(ctx context.Context, user
 	return activeCount, nil
 }
 
+func (r *firestoreWishlistRepository) UpdateWishlistItem(ctx context.Context, item *entity.WishlistItem) error {
+	_, err := r.client.Collection("wishlists").Doc(item.ID).Set(ctx, item)
+	if err != nil {
+		return errors.Internal("Failed to update wishlist item", err)
+	}
+
+	return nil
+}
+
+func (r *firestoreWishlistRepository) ListByProduct(ctx context.Context, productID string) ([]*entity.WishlistItem, error) {
+	docs, err := r.client.Collection("wishlists").Where("productId", "==", productID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to get wishlist items", err)
+	}
+
+	items := make([]*entity.WishlistItem, 0, len(docs))
+	for _, doc := range docs {
+		var item entity.WishlistItem
+		if err := doc.DataTo(&item); err != nil {
+			log.Printf("Error parsing wishlist item %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+func (r *firestoreWishlistRepository) ListCreatedSince(ctx context.Context, since time.Time) ([]*entity.WishlistItem, error) {
+	docs, err := r.client.Collection("wishlists").Where("createdAt", ">=", since).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, errors.Internal("Failed to get wishlist items", err)
+	}
+
+	items := make([]*entity.WishlistItem, 0, len(docs))
+	for _, doc := range docs {
+		var item entity.WishlistItem
+		if err := doc.DataTo(&item); err != nil {
+			log.Printf("Error parsing wishlist item %s: %v", doc.Ref.ID, err)
+			continue
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
 func IsNotFound(err error) bool {
 	// Check if this is a Firestore "not found" error
 	return err != nil && err.Error() == "rpc error: code = NotFound desc = no such entity"
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// AutoReleasePolicy sets the buyer inspection window before escrow is
+// auto-released. Empty match fields act as wildcards; the most specific
+// active policy wins.
+type AutoReleasePolicy struct {
+	ID            string  `json:"id" firestore:"id"`
+	Name          string  `json:"name" firestore:"name"`
+	SecurityLevel string  `json:"security_level,omitempty" firestore:"securityLevel,omitempty"` // low, medium, high
+	GameTitleID   string  `json:"game_title_id,omitempty" firestore:"gameTitleId,omitempty"`
+	ProductType   string  `json:"product_type,omitempty" firestore:"productType,omitempty"`
+	MinAmount     float64 `json:"min_amount" firestore:"minAmount"`
+	MaxAmount     float64 `json:"max_amount" firestore:"maxAmount"` // 0 means no upper bound
+
+	InspectionHours int  `json:"inspection_hours" firestore:"inspectionHours"`
+	ExtensionHours  int  `json:"extension_hours" firestore:"extensionHours"` // one-time buyer extension, 0 disables it
+	WarningHours    int  `json:"warning_hours" firestore:"warningHours"`     // chat warning before release
+	Priority        int  `json:"priority" firestore:"priority"`              // breaks ties between equally specific policies
+	IsActive        bool `json:"is_active" firestore:"isActive"`
+
+	CreatedBy string    `json:"created_by" firestore:"createdBy"`
+	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updatedAt"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+)
+
+// ChatViolation records a chat message that tripped the content scanner
+type ChatViolation struct {
+	ID         string   `json:"id" firestore:"id"`
+	UserID     string   `json:"user_id" firestore:"userId"`
+	ChatID     string   `json:"chat_id" firestore:"chatId"`
+	Action     string   `json:"action" firestore:"action"` // warn, block
+	Categories []string `json:"categories" firestore:"categories"`
+	Matches    []string `json:"matches" firestore:"matches"`
+	Source     string   `json:"source" firestore:"source"` // api, websocket
+
+	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+}
+
+// ChatOffender flags a user with repeated chat violations for admin review.
+// The document ID is the user ID.
+type ChatOffender struct {
+	UserID          string    `json:"user_id" firestore:"userId"`
+	Status          string    `json:"status" firestore:"status"` // pending_review, resolved
+	ViolationCount  int       `json:"violation_count" firestore:"violationCount"`
+	LastViolationAt time.Time `json:"last_violation_at" firestore:"lastViolationAt"`
+	FlaggedAt       time.Time `json:"flagged_at" firestore:"flaggedAt"`
+
+	ReviewedBy  string     `json:"reviewed_by,omitempty" firestore:"reviewedBy,omitempty"`
+	ReviewNotes string     `json:"review_notes,omitempty" firestore:"reviewNotes,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty" firestore:"reviewedAt,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at" firestore:"updatedAt"`
+}
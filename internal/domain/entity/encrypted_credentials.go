@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+)
+
+// EncryptedCredentials holds envelope-encrypted credentials. The data key is
+// wrapped by the key provider identified by KeyID, so the ciphertext can be
+// decrypted after the provider's active key has been rotated.
+type EncryptedCredentials struct {
+	KeyID       string    `json:"key_id" firestore:"keyId"`
+	WrappedKey  []byte    `json:"-" firestore:"wrappedKey"`
+	Nonce       []byte    `json:"-" firestore:"nonce"`
+	Ciphertext  []byte    `json:"-" firestore:"ciphertext"`
+	Algorithm   string    `json:"algorithm" firestore:"algorithm"` // AES-256-GCM
+	EncryptedAt time.Time `json:"encrypted_at" firestore:"encryptedAt"`
+}
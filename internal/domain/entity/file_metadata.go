@@ -17,4 +17,9 @@ type FileMetadata struct {
 	IsPublic   bool      `json:"is_public" firestore:"isPublic"`
 	CreatedAt  time.Time `json:"created_at" firestore:"createdAt"`
 	UpdatedAt  time.Time `json:"updated_at,omitempty" firestore:"updatedAt"`
+
+	// Perceptual hash of product images, used to spot pictures reused from
+	// other listings; HashBands are its pieces for lookups
+	PerceptualHash string   `json:"perceptual_hash,omitempty" firestore:"perceptualHash,omitempty"`
+	HashBands      []string `json:"-" firestore:"hashBands,omitempty"`
 }
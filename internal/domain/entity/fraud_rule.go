@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// FraudRuleCondition compares one checkout fact against a value, for example
+// {"field": "transaction.total_amount", "operator": "gt", "value": 1000000}
+type FraudRuleCondition struct {
+	Field    string      `json:"field" firestore:"field"`
+	Operator string      `json:"operator" firestore:"operator"` // gt, gte, lt, lte, eq, neq, in, not_in, contains_any
+	Value    interface{} `json:"value" firestore:"value"`
+}
+
+// FraudRule adds its weight to the fraud score when all conditions match
+type FraudRule struct {
+	ID          string               `json:"id" firestore:"id"`
+	Flag        string               `json:"flag" firestore:"flag"` // stored in Transaction.SecurityFlags
+	Name        string               `json:"name" firestore:"name"`
+	Description string               `json:"description" firestore:"description"` // shown as the reason
+	Conditions  []FraudRuleCondition `json:"conditions" firestore:"conditions"`
+	Weight      float64              `json:"weight" firestore:"weight"`
+	Enabled     bool                 `json:"enabled" firestore:"enabled"`
+
+	UpdatedBy string    `json:"updated_by,omitempty" firestore:"updatedBy,omitempty"`
+	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updatedAt"`
+}
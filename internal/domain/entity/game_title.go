@@ -20,6 +20,8 @@ type GameTitle struct {
 	Icon        string               `json:"icon,omitempty" firestore:"icon,omitempty"`
 	Banner      string               `json:"banner,omitempty" firestore:"banner,omitempty"`
 	Attributes  []GameTitleAttribute `json:"attributes" firestore:"attributes"`
+	// Account handover checklist template; defaults are used when empty
+	HandoverSteps []HandoverStepTemplate `json:"handover_steps,omitempty" firestore:"handoverSteps,omitempty"`
 	Status      string               `json:"status" firestore:"status"`
 	CreatedAt   time.Time            `json:"created_at" firestore:"createdAt"`
 	UpdatedAt   time.Time            `json:"updated_at" firestore:"updatedAt"`
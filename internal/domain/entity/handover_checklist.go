@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+)
+
+// HandoverStepTemplate is a step configured per game title for account handovers
+type HandoverStepTemplate struct {
+	Key              string `json:"key" firestore:"key"`
+	Title            string `json:"title" firestore:"title"`
+	Description      string `json:"description,omitempty" firestore:"description,omitempty"`
+	Required         bool   `json:"required" firestore:"required"`
+	RequiresEvidence bool   `json:"requires_evidence" firestore:"requiresEvidence"`
+}
+
+type HandoverStep struct {
+	Key              string `json:"key" firestore:"key"`
+	Title            string `json:"title" firestore:"title"`
+	Description      string `json:"description,omitempty" firestore:"description,omitempty"`
+	Required         bool   `json:"required" firestore:"required"`
+	RequiresEvidence bool   `json:"requires_evidence" firestore:"requiresEvidence"`
+
+	// Seller side
+	SellerCompleted   bool       `json:"seller_completed" firestore:"sellerCompleted"`
+	SellerCompletedAt *time.Time `json:"seller_completed_at,omitempty" firestore:"sellerCompletedAt,omitempty"`
+	EvidenceURL       string     `json:"evidence_url,omitempty" firestore:"evidenceUrl,omitempty"`
+	SellerNotes       string     `json:"seller_notes,omitempty" firestore:"sellerNotes,omitempty"`
+
+	// Buyer side
+	BuyerConfirmed   bool       `json:"buyer_confirmed" firestore:"buyerConfirmed"`
+	BuyerConfirmedAt *time.Time `json:"buyer_confirmed_at,omitempty" firestore:"buyerConfirmedAt,omitempty"`
+	BuyerNotes       string     `json:"buyer_notes,omitempty" firestore:"buyerNotes,omitempty"`
+}
+
+type HandoverChecklist struct {
+	ID            string         `json:"id" firestore:"id"`
+	TransactionID string         `json:"transaction_id" firestore:"transactionId"`
+	GameTitleID   string         `json:"game_title_id" firestore:"gameTitleId"`
+	SellerID      string         `json:"seller_id" firestore:"sellerId"`
+	BuyerID       string         `json:"buyer_id" firestore:"buyerId"`
+	Steps         []HandoverStep `json:"steps" firestore:"steps"`
+	Status        string         `json:"status" firestore:"status"` // in_progress, completed, disputed
+	DueAt         time.Time      `json:"due_at" firestore:"dueAt"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty" firestore:"completedAt,omitempty"`
+	CreatedAt     time.Time      `json:"created_at" firestore:"createdAt"`
+	UpdatedAt     time.Time      `json:"updated_at" firestore:"updatedAt"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+// ImageMatch records a product image that closely matches an earlier image
+// of another seller's listing
+type ImageMatch struct {
+	ID               string    `json:"id" firestore:"id"`
+	ProductID        string    `json:"product_id" firestore:"productId"`
+	SellerID         string    `json:"seller_id" firestore:"sellerId"`
+	FileID           string    `json:"file_id" firestore:"fileId"`
+	ImageURL         string    `json:"image_url" firestore:"imageUrl"`
+	MatchedProductID string    `json:"matched_product_id" firestore:"matchedProductId"`
+	MatchedSellerID  string    `json:"matched_seller_id" firestore:"matchedSellerId"`
+	MatchedFileID    string    `json:"matched_file_id" firestore:"matchedFileId"`
+	MatchedImageURL  string    `json:"matched_image_url" firestore:"matchedImageUrl"`
+	Distance         int       `json:"distance" firestore:"distance"` // differing bits of the perceptual hashes
+	ReviewID         string    `json:"review_id,omitempty" firestore:"reviewId,omitempty"`
+	CreatedAt        time.Time `json:"created_at" firestore:"createdAt"`
+}
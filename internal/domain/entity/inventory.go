@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// InventoryCode is one sealed voucher code or credential set in a product's
+// pool, delivered to exactly one paid order
+type InventoryCode struct {
+	ID        string `json:"id" firestore:"id"`
+	ProductID string `json:"product_id" firestore:"productId"`
+	VariantID string `json:"variant_id,omitempty" firestore:"variantId"` // "" for products without variants
+	SellerID  string `json:"seller_id" firestore:"sellerId"`
+	BatchID   string `json:"batch_id" firestore:"batchId"`
+
+	EncryptedCode *EncryptedCredentials `json:"-" firestore:"encryptedCode"`
+	Fingerprint   string                `json:"-" firestore:"fingerprint"` // hash of product ID and code, for duplicate detection
+	Hint          string                `json:"hint" firestore:"hint"`     // masked tail, e.g. "••••7KQ2"
+
+	Status        string     `json:"status" firestore:"status"` // available, delivered, reported, removed
+	TransactionID string     `json:"transaction_id,omitempty" firestore:"transactionId,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty" firestore:"deliveredAt,omitempty"`
+	ReportedAt    *time.Time `json:"reported_at,omitempty" firestore:"reportedAt,omitempty"`
+	ReportReason  string     `json:"report_reason,omitempty" firestore:"reportReason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updatedAt"`
+}
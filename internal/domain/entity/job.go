@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+)
+
+// JobLease grants one instance the right to run a background job until it
+// expires. The holder renews it while the job runs and releases it when done.
+type JobLease struct {
+	JobName    string    `json:"job_name" firestore:"jobName"`
+	HolderID   string    `json:"holder_id" firestore:"holderId"`
+	AcquiredAt time.Time `json:"acquired_at" firestore:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expires_at" firestore:"expiresAt"`
+	// LastStartedAt persists the schedule across restarts
+	LastStartedAt *time.Time `json:"last_started_at,omitempty" firestore:"lastStartedAt,omitempty"`
+}
+
+type JobRun struct {
+	ID             string     `json:"id" firestore:"id"`
+	JobName        string     `json:"job_name" firestore:"jobName"`
+	InstanceID     string     `json:"instance_id" firestore:"instanceId"`
+	Status         string     `json:"status" firestore:"status"` // running, succeeded, failed
+	ItemsProcessed int        `json:"items_processed" firestore:"itemsProcessed"`
+	Errors         []string   `json:"errors,omitempty" firestore:"errors,omitempty"`
+	StartedAt      time.Time  `json:"started_at" firestore:"startedAt"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty" firestore:"finishedAt,omitempty"`
+}
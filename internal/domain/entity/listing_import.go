@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+)
+
+// ImportRowError is a problem with one cell or row of an import sheet.
+// Row numbers match the spreadsheet, with the header on row 1.
+type ImportRowError struct {
+	Row     int    `json:"row" firestore:"row"`
+	Field   string `json:"field,omitempty" firestore:"field,omitempty"`
+	Message string `json:"message" firestore:"message"`
+}
+
+// ImportJob is one bulk listing upload: validated on upload, then created or
+// updated in the background once the seller starts it
+type ImportJob struct {
+	ID          string           `json:"id" firestore:"id"`
+	SellerID    string           `json:"seller_id" firestore:"sellerId"`
+	FileName    string           `json:"file_name" firestore:"fileName"`
+	Format      string           `json:"format" firestore:"format"` // csv, xlsx
+	Status      string           `json:"status" firestore:"status"` // previewed, running, completed, failed
+	TotalRows   int              `json:"total_rows" firestore:"totalRows"`
+	ValidRows   int              `json:"valid_rows" firestore:"validRows"`
+	InvalidRows int              `json:"invalid_rows" firestore:"invalidRows"`
+	Processed   int              `json:"processed" firestore:"processed"`
+	Created     int              `json:"created" firestore:"created"`
+	Updated     int              `json:"updated" firestore:"updated"`
+	Failed      int              `json:"failed" firestore:"failed"`
+	Errors      []ImportRowError `json:"errors,omitempty" firestore:"errors,omitempty"` // rows that failed while running
+	StartedAt   *time.Time       `json:"started_at,omitempty" firestore:"startedAt,omitempty"`
+	FinishedAt  *time.Time       `json:"finished_at,omitempty" firestore:"finishedAt,omitempty"`
+	CreatedAt   time.Time        `json:"created_at" firestore:"createdAt"`
+	UpdatedAt   time.Time        `json:"updated_at" firestore:"updatedAt"`
+}
+
+// ImportRow is a valid row of an import sheet, kept as the raw cell values
+// by column name until the job runs
+type ImportRow struct {
+	Row    int               `json:"row" firestore:"row"`
+	Values map[string]string `json:"values" firestore:"values"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+)
+
+// ListingFinding is something the automated listing checks flagged
+type ListingFinding struct {
+	Check    string `json:"check" firestore:"check"` // banned_word, contact_info, missing_attribute
+	Field    string `json:"field" firestore:"field"`
+	Match    string `json:"match,omitempty" firestore:"match,omitempty"`
+	Message  string `json:"message" firestore:"message"`
+	Blocking bool   `json:"blocking" firestore:"blocking"` // rejects the listing without waiting for an admin
+}
+
+// ListingReview is one round of moderation of a listing, opened when a seller
+// below the trust threshold publishes, edits or resubmits it
+type ListingReview struct {
+	ID          string           `json:"id" firestore:"id"`
+	ProductID   string           `json:"product_id" firestore:"productId"`
+	SellerID    string           `json:"seller_id" firestore:"sellerId"`
+	GameTitleID string           `json:"game_title_id" firestore:"gameTitleId"`
+	Title       string           `json:"title" firestore:"title"`
+	Trigger     string           `json:"trigger" firestore:"trigger"` // created, edited, resubmitted
+	SellerNote  string           `json:"seller_note,omitempty" firestore:"sellerNote,omitempty"`
+	Status      string           `json:"status" firestore:"status"` // pending, approved, rejected, superseded
+	Findings    []ListingFinding `json:"findings,omitempty" firestore:"findings,omitempty"`
+	Reasons     []string         `json:"reasons,omitempty" firestore:"reasons,omitempty"`
+	ReviewNote  string           `json:"review_note,omitempty" firestore:"reviewNote,omitempty"`
+	ReviewerID  string           `json:"reviewer_id,omitempty" firestore:"reviewerId,omitempty"` // "system" for automated rejections
+	ReviewedAt  *time.Time       `json:"reviewed_at,omitempty" firestore:"reviewedAt,omitempty"`
+	CreatedAt   time.Time        `json:"created_at" firestore:"createdAt"`
+	UpdatedAt   time.Time        `json:"updated_at" firestore:"updatedAt"`
+}
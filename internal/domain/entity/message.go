@@ -14,5 +14,7 @@ type Message struct {
 	AttachmentURLs []string `json:"attachment_urls,omitempty" firestore:"attachmentUrls,omitempty"` // New: Multiple attachments support
 	ProductID     string                 `json:"product_id,omitempty" firestore:"productId,omitempty"` // New: ProductID associated with the message
 	ReadBy        []string               `json:"read_by" firestore:"readBy"`
+	ContentWarning string   `json:"content_warning,omitempty" firestore:"contentWarning,omitempty"` // Banner for suspected off-platform solicitation
+	ContentFlags   []string `json:"content_flags,omitempty" firestore:"contentFlags,omitempty"`
 	CreatedAt     time.Time              `json:"created_at" firestore:"createdAt"`
 }
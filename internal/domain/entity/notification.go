@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+)
+
+// Notification is a stored copy of an alert pushed over WebSocket, so users
+// who were offline still see it
+type Notification struct {
+	ID        string                 `json:"id" firestore:"id"`
+	UserID    string                 `json:"user_id" firestore:"userId"`
+	Type      string                 `json:"type" firestore:"type"`
+	Title     string                 `json:"title" firestore:"title"`
+	Message   string                 `json:"message" firestore:"message"`
+	Data      map[string]interface{} `json:"data,omitempty" firestore:"data,omitempty"`
+	Read      bool                   `json:"read" firestore:"read"`
+	ReadAt    *time.Time             `json:"read_at,omitempty" firestore:"readAt,omitempty"`
+	CreatedAt time.Time              `json:"created_at" firestore:"createdAt"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+)
+
+// PricePoint records a price that applied from ChangedAt until the next point.
+// VariantID is empty for the product's listed price, which for products with
+// variants is the cheapest available variant.
+type PricePoint struct {
+	ID            string    `json:"id" firestore:"id"`
+	ProductID     string    `json:"product_id" firestore:"productId"`
+	VariantID     string    `json:"variant_id,omitempty" firestore:"variantId"`
+	Price         float64   `json:"price" firestore:"price"`
+	PreviousPrice float64   `json:"previous_price,omitempty" firestore:"previousPrice"` // 0 for the first point
+	ChangedAt     time.Time `json:"changed_at" firestore:"changedAt"`
+}
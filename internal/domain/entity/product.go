@@ -10,6 +10,22 @@ type ProductImage struct {
 	DisplayOrder int    `json:"display_order" firestore:"displayOrder"`
 }
 
+// ProductVariant is one purchasable option of a product, such as a top-up
+// denomination. Stock 0 means untracked, as on Product.
+type ProductVariant struct {
+	ID         string                 `json:"id" firestore:"id"`
+	Label      string                 `json:"label" firestore:"label"`
+	Price      float64                `json:"price" firestore:"price"`
+	Stock      int                    `json:"stock" firestore:"stock"`
+	SoldCount  int                    `json:"sold_count" firestore:"soldCount"`
+	Attributes map[string]interface{} `json:"attributes,omitempty" firestore:"attributes,omitempty"`
+}
+
+// SoldOut reports whether a tracked variant has no stock left
+func (v *ProductVariant) SoldOut() bool {
+	return v.Stock > 0 && v.SoldCount >= v.Stock
+}
+
 type Product struct {
 	ID          string                 `json:"id" firestore:"id"`
 	GameTitleID string                 `json:"game_title_id" firestore:"gameTitleId"`
@@ -24,14 +40,123 @@ type Product struct {
 	Stock       int                    `json:"stock" firestore:"stock"`
 	SoldCount   int                    `json:"sold_count" firestore:"soldCount"`
 
+	// With variants, Price is the cheapest available variant ("from Rp X"),
+	// PriceMax the most expensive, and Stock/SoldCount their totals
+	Variants []ProductVariant `json:"variants,omitempty" firestore:"variants,omitempty"`
+	PriceMax float64          `json:"price_max,omitempty" firestore:"priceMax,omitempty"`
+
 	DeliveryMethod       string                 `json:"delivery_method" firestore:"deliveryMethod"`
+	// Credentials is legacy plaintext storage, cleared once sealed into EncryptedCredentials
 	Credentials          map[string]interface{} `json:"credentials,omitempty" firestore:"credentials,omitempty"`
+	EncryptedCredentials *EncryptedCredentials  `json:"-" firestore:"encryptedCredentials,omitempty"`
 	CredentialsValidated bool                   `json:"credentials_validated" firestore:"credentialsValidated"`
 
+	// UsesCodeInventory delivers each order a code from the product's pool;
+	// Stock then follows the codes left
+	UsesCodeInventory bool `json:"uses_code_inventory" firestore:"usesCodeInventory"`
+	LowStockThreshold int  `json:"low_stock_threshold,omitempty" firestore:"lowStockThreshold,omitempty"`
+
 	Views     int        `json:"views" firestore:"views"`
 	Featured  bool       `json:"featured" firestore:"featured"`
 	CreatedAt time.Time  `json:"created_at" firestore:"createdAt"`
 	UpdatedAt time.Time  `json:"updated_at" firestore:"updatedAt"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty" firestore:"deletedAt,omitempty"`
 	BumpedAt  time.Time  `json:"bumped_at" firestore:"bumpedAt"`
+
+	// Set by paid promotions; impressions and clicks are only counted while
+	// PromotedUntil is in the future
+	FeaturedUntil *time.Time `json:"featured_until,omitempty" firestore:"featuredUntil,omitempty"`
+	PromotedUntil *time.Time `json:"promoted_until,omitempty" firestore:"promotedUntil,omitempty"`
+
+	// Inactive listings expire; EditedAt tracks seller edits since
+	// UpdatedAt also moves on views and sales
+	EditedAt         *time.Time `json:"edited_at,omitempty" firestore:"editedAt,omitempty"`
+	ExpiryRemindedAt *time.Time `json:"expiry_reminded_at,omitempty" firestore:"expiryRemindedAt,omitempty"`
+	ExpiredAt        *time.Time `json:"expired_at,omitempty" firestore:"expiredAt,omitempty"`
+}
+
+// HasVariants reports whether the product is sold through variants
+func (p *Product) HasVariants() bool {
+	return len(p.Variants) > 0
+}
+
+// Variant returns the variant with the given ID, or nil
+func (p *Product) Variant(id string) *ProductVariant {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i]
+		}
+	}
+	return nil
+}
+
+// HighestPrice is PriceMax for products with variants and Price otherwise
+func (p *Product) HighestPrice() float64 {
+	if p.HasVariants() {
+		return p.PriceMax
+	}
+	return p.Price
+}
+
+// SyncVariantTotals recomputes the product-level price range, stock and sold
+// count from its variants. Sold-out variants only count towards the price
+// range when every variant is sold out.
+func (p *Product) SyncVariantTotals() {
+	if !p.HasVariants() {
+		p.PriceMax = 0
+		return
+	}
+
+	priceFrom, priceMax := -1.0, 0.0
+	for pass := 0; pass < 2 && priceFrom < 0; pass++ {
+		for i := range p.Variants {
+			variant := &p.Variants[i]
+			if pass == 0 && variant.SoldOut() {
+				continue
+			}
+			if priceFrom < 0 || variant.Price < priceFrom {
+				priceFrom = variant.Price
+			}
+			if variant.Price > priceMax {
+				priceMax = variant.Price
+			}
+		}
+	}
+
+	stock, sold := 0, 0
+	for _, variant := range p.Variants {
+		stock += variant.Stock
+		sold += variant.SoldCount
+	}
+
+	p.Price = priceFrom
+	p.PriceMax = priceMax
+	p.Stock = stock
+	p.SoldCount = sold
+}
+
+// HasCredentials reports whether the product carries credentials in either form
+func (p *Product) HasCredentials() bool {
+	return p.EncryptedCredentials != nil || len(p.Credentials) > 0
+}
+
+// IsPromoted reports whether a paid promotion is running at the given time
+func (p *Product) IsPromoted(at time.Time) bool {
+	return p.PromotedUntil != nil && p.PromotedUntil.After(at)
+}
+
+// LastActivity is the latest sign the seller still tends the listing: their
+// last visit, the last bump or the last edit
+func (p *Product) LastActivity(sellerLastSeen time.Time) time.Time {
+	last := p.CreatedAt
+	if p.BumpedAt.After(last) {
+		last = p.BumpedAt
+	}
+	if p.EditedAt != nil && p.EditedAt.After(last) {
+		last = *p.EditedAt
+	}
+	if sellerLastSeen.After(last) {
+		last = sellerLastSeen
+	}
+	return last
 }
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+)
+
+// PromotionPackage is an entry of the promotion catalog
+type PromotionPackage struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Type          string  `json:"type"` // bump, auto_bump, featured
+	Price         float64 `json:"price"`
+	IntervalHours int     `json:"interval_hours,omitempty"` // auto_bump only
+	DurationDays  int     `json:"duration_days,omitempty"`
+}
+
+// Promotion is a package bought for one product, paid from the seller wallet
+type Promotion struct {
+	ID                  string     `json:"id" firestore:"id"`
+	ProductID           string     `json:"product_id" firestore:"productId"`
+	SellerID            string     `json:"seller_id" firestore:"sellerId"`
+	GameTitleID         string     `json:"game_title_id" firestore:"gameTitleId"`
+	PackageID           string     `json:"package_id" firestore:"packageId"`
+	Type                string     `json:"type" firestore:"type"`
+	Price               float64    `json:"price" firestore:"price"`
+	WalletTransactionID string     `json:"wallet_transaction_id" firestore:"walletTransactionId"`
+	IntervalHours       int        `json:"interval_hours,omitempty" firestore:"intervalHours,omitempty"`
+	Status              string     `json:"status" firestore:"status"` // active, completed, cancelled
+	BumpCount           int        `json:"bump_count" firestore:"bumpCount"`
+	NextBumpAt          *time.Time `json:"next_bump_at,omitempty" firestore:"nextBumpAt,omitempty"`
+	StartsAt            time.Time  `json:"starts_at" firestore:"startsAt"`
+	EndsAt              time.Time  `json:"ends_at" firestore:"endsAt"`
+	CreatedAt           time.Time  `json:"created_at" firestore:"createdAt"`
+	UpdatedAt           time.Time  `json:"updated_at" firestore:"updatedAt"`
+}
+
+// ProductDailyStats counts how often a promoted product was listed
+// (impressions) and opened (clicks) on one UTC day
+type ProductDailyStats struct {
+	ProductID   string `json:"product_id" firestore:"productId"`
+	Date        string `json:"date" firestore:"date"` // 2006-01-02
+	Impressions int64  `json:"impressions" firestore:"impressions"`
+	Clicks      int64  `json:"clicks" firestore:"clicks"`
+}
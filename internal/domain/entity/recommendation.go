@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+)
+
+// ProductView records that a signed-in user opened a listing. Repeat views
+// of the same listing overwrite the record, keeping the latest ViewedAt.
+type ProductView struct {
+	ID          string    `json:"id" firestore:"id"`
+	UserID      string    `json:"user_id" firestore:"userId"`
+	ProductID   string    `json:"product_id" firestore:"productId"`
+	GameTitleID string    `json:"game_title_id" firestore:"gameTitleId"`
+	ViewedAt    time.Time `json:"viewed_at" firestore:"viewedAt"`
+}
+
+type RecommendedProduct struct {
+	ProductID string  `json:"product_id" firestore:"productId"`
+	Score     float64 `json:"score" firestore:"score"`
+}
+
+// ProductRecommendations are the related listings of a product, computed
+// offline by the recommendation job and ranked by score
+type ProductRecommendations struct {
+	ProductID   string               `json:"product_id" firestore:"productId"`
+	GameTitleID string               `json:"game_title_id" firestore:"gameTitleId"`
+	Similar     []RecommendedProduct `json:"similar" firestore:"similar"`
+	AlsoViewed  []RecommendedProduct `json:"also_viewed" firestore:"alsoViewed"`
+	ComputedAt  time.Time            `json:"computed_at" firestore:"computedAt"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+)
+
+// SellerRiskSignals are the history inputs behind a seller risk score
+type SellerRiskSignals struct {
+	TotalSales       int     `json:"total_sales" firestore:"totalSales"`
+	CompletedSales   int     `json:"completed_sales" firestore:"completedSales"`
+	Disputes         int     `json:"disputes" firestore:"disputes"`
+	DisputeRate      float64 `json:"dispute_rate" firestore:"disputeRate"`
+	SLABreaches      int     `json:"sla_breaches" firestore:"slaBreaches"`
+	Refunds          int     `json:"refunds" firestore:"refunds"`
+	RefundRatio      float64 `json:"refund_ratio" firestore:"refundRatio"`
+	AccountAgeDays   int     `json:"account_age_days" firestore:"accountAgeDays"`
+	LinkedAccounts   int     `json:"linked_accounts" firestore:"linkedAccounts"`
+	LinkedBuyerSales int     `json:"linked_buyer_sales" firestore:"linkedBuyerSales"` // sales to accounts sharing a device or IP
+}
+
+// SellerTrustProfile holds the nightly risk score of a seller. Tier is the
+// effective tier: the admin override when set, otherwise the computed one.
+type SellerTrustProfile struct {
+	SellerID     string            `json:"seller_id" firestore:"sellerId"`
+	RiskScore    float64           `json:"risk_score" firestore:"riskScore"` // 0 (no risk) to 1
+	ComputedTier string            `json:"computed_tier" firestore:"computedTier"`
+	Tier         string            `json:"tier" firestore:"tier"` // trusted, standard, new, watch, restricted
+	Signals      SellerRiskSignals `json:"signals" firestore:"signals"`
+	Reasons      []string          `json:"reasons,omitempty" firestore:"reasons,omitempty"`
+
+	OverrideTier   string     `json:"override_tier,omitempty" firestore:"overrideTier,omitempty"`
+	OverrideReason string     `json:"override_reason,omitempty" firestore:"overrideReason,omitempty"`
+	OverriddenBy   string     `json:"overridden_by,omitempty" firestore:"overriddenBy,omitempty"`
+	OverriddenAt   *time.Time `json:"overridden_at,omitempty" firestore:"overriddenAt,omitempty"`
+
+	ComputedAt time.Time `json:"computed_at" firestore:"computedAt"`
+	UpdatedAt  time.Time `json:"updated_at" firestore:"updatedAt"`
+}
@@ -7,9 +7,11 @@ import (
 type Transaction struct {
 	ID             string                 `json:"id" firestore:"id"`
 	ProductID      string                 `json:"product_id" firestore:"productId"`
+	VariantID      string                 `json:"variant_id,omitempty" firestore:"variantId,omitempty"`
+	VariantLabel   string                 `json:"variant_label,omitempty" firestore:"variantLabel,omitempty"`
 	SellerID       string                 `json:"seller_id" firestore:"sellerId"`
 	BuyerID        string                 `json:"buyer_id" firestore:"buyerId"`
-	Status         string                 `json:"status" firestore:"status"` // payment_pending, payment_processing, credentials_delivered, completed, disputed, refunded, cancelled
+	Status         string                 `json:"status" firestore:"status"` // payment_pending, payment_processing, fraud_review, credentials_delivered, completed, disputed, refunded, cancelled
 	DeliveryMethod string                 `json:"delivery_method" firestore:"deliveryMethod"`
 	Amount         float64                `json:"amount" firestore:"amount"`
 	Fee            float64                `json:"fee" firestore:"fee"`
@@ -29,7 +31,10 @@ type Transaction struct {
 	SecurityLevel     string                 `json:"security_level,omitempty" firestore:"securityLevel,omitempty"`
 	EscrowStatus      string                 `json:"escrow_status,omitempty" firestore:"escrowStatus,omitempty"` // held, released, refunded
 
-	Credentials map[string]interface{} `json:"-" firestore:"credentials,omitempty"`
+	Credentials map[string]interface{} `json:"-" firestore:"credentials,omitempty"` // Legacy plaintext, cleared once sealed
+	EncryptedCredentials *EncryptedCredentials `json:"-" firestore:"encryptedCredentials,omitempty"`
+	InventoryCodeID      string                `json:"-" firestore:"inventoryCodeId,omitempty"` // code delivered from the product's pool
+	CodeReplacements     int                   `json:"code_replacements,omitempty" firestore:"codeReplacements,omitempty"`
 	
 	// Credential Delivery Fields
 	CredentialsDelivered bool       `json:"credentials_delivered" firestore:"credentialsDelivered"`
@@ -39,6 +44,10 @@ type Transaction struct {
 	
 	// Auto-release timer
 	AutoReleaseAt *time.Time `json:"auto_release_at,omitempty" firestore:"autoReleaseAt,omitempty"`
+	AutoReleasePolicyID string `json:"auto_release_policy_id,omitempty" firestore:"autoReleasePolicyId,omitempty"`
+	AutoReleaseExtended bool `json:"auto_release_extended" firestore:"autoReleaseExtended"`
+	AutoReleaseExtendedAt *time.Time `json:"auto_release_extended_at,omitempty" firestore:"autoReleaseExtendedAt,omitempty"`
+	AutoReleaseWarnedAt *time.Time `json:"auto_release_warned_at,omitempty" firestore:"autoReleaseWarnedAt,omitempty"`
 
 	AdminID         string `json:"admin_id,omitempty" firestore:"adminId,omitempty"`
 	MiddlemanStatus string `json:"middleman_status,omitempty" firestore:"middlemanStatus,omitempty"`
@@ -56,8 +65,21 @@ type Transaction struct {
 	// Security & Monitoring
 	CredentialsAccessed  bool       `json:"credentials_accessed" firestore:"credentialsAccessed"`
 	CredentialsAccessedAt *time.Time `json:"credentials_accessed_at,omitempty" firestore:"credentialsAccessedAt,omitempty"`
+	CredentialsViewCount int        `json:"credentials_view_count" firestore:"credentialsViewCount"`
+	CredentialViewTimes  []time.Time `json:"-" firestore:"credentialViewTimes,omitempty"` // Recent reveals, for the reveal throttle
+	CredentialsRedacted  bool       `json:"credentials_redacted" firestore:"credentialsRedacted"`
+	CredentialsRedactedAt *time.Time `json:"credentials_redacted_at,omitempty" firestore:"credentialsRedactedAt,omitempty"`
 	FraudScore          float64    `json:"fraud_score" firestore:"fraudScore"`
 	SecurityFlags       []string   `json:"security_flags,omitempty" firestore:"securityFlags,omitempty"`
+	FraudRiskLevel      string     `json:"fraud_risk_level,omitempty" firestore:"fraudRiskLevel,omitempty"`
+	FraudReasons        []string   `json:"fraud_reasons,omitempty" firestore:"fraudReasons,omitempty"`
+
+	// Fraud Review Queue
+	FraudReviewStatus   string     `json:"fraud_review_status,omitempty" firestore:"fraudReviewStatus,omitempty"` // pending, approved, rejected
+	FraudHeldStatus     string     `json:"-" firestore:"fraudHeldStatus,omitempty"`                              // status restored on approval
+	FraudReviewNotes    string     `json:"fraud_review_notes,omitempty" firestore:"fraudReviewNotes,omitempty"`
+	FraudReviewedBy     string     `json:"fraud_reviewed_by,omitempty" firestore:"fraudReviewedBy,omitempty"`
+	FraudReviewedAt     *time.Time `json:"fraud_reviewed_at,omitempty" firestore:"fraudReviewedAt,omitempty"`
 	
 	// Refund Management
 	RefundAmount         float64    `json:"refund_amount,omitempty" firestore:"refundAmount,omitempty"`
@@ -84,3 +106,13 @@ type TransactionLog struct {
 	CreatedBy     string    `json:"created_by" firestore:"createdBy"`
 	CreatedAt     time.Time `json:"created_at" firestore:"createdAt"`
 }
+
+// CredentialAccessLog records each time a buyer reveals delivered credentials
+type CredentialAccessLog struct {
+	ID            string    `json:"id" firestore:"id"`
+	TransactionID string    `json:"transaction_id" firestore:"transactionId"`
+	UserID        string    `json:"user_id" firestore:"userId"`
+	IPAddress     string    `json:"ip_address,omitempty" firestore:"ipAddress,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty" firestore:"userAgent,omitempty"`
+	AccessedAt    time.Time `json:"accessed_at" firestore:"accessedAt"`
+}
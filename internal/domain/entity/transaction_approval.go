@@ -102,10 +102,11 @@ type SecurityLog struct {
 	ID            string                 `json:"id" firestore:"id"`
 	TransactionID string                 `json:"transaction_id,omitempty" firestore:"transactionId,omitempty"`
 	UserID        string                 `json:"user_id" firestore:"userId"`
-	EventType     string                 `json:"event_type" firestore:"eventType"`
+	EventType     string                 `json:"event_type" firestore:"eventType"` // login, checkout, withdrawal, credential_reveal
 	RiskScore     int                    `json:"risk_score" firestore:"riskScore"` // 0-100
 	Details       map[string]interface{} `json:"details,omitempty" firestore:"details,omitempty"`
 	IPAddress     string                 `json:"ip_address,omitempty" firestore:"ipAddress,omitempty"`
 	UserAgent     string                 `json:"user_agent,omitempty" firestore:"userAgent,omitempty"`
+	DeviceID      string                 `json:"device_id,omitempty" firestore:"deviceId,omitempty"`
 	CreatedAt     time.Time              `json:"created_at" firestore:"createdAt"`
 }
\ No newline at end of file
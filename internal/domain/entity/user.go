@@ -24,6 +24,7 @@ type User struct {
 	SellerReviewCount int     `json:"seller_review_count,omitempty" firestore:"sellerReviewCount,omitempty"`
 	BuyerRating       float64 `json:"buyer_rating,omitempty" firestore:"buyerRating,omitempty"`
 	BuyerReviewCount  int     `json:"buyer_review_count,omitempty" firestore:"buyerReviewCount,omitempty"`
+	TrustTier         string  `json:"trust_tier,omitempty" firestore:"trustTier,omitempty"` // set by the nightly seller risk job
 
 	// Online presence and profile fields
 	AvatarURL    string    `json:"avatar_url,omitempty" firestore:"avatarURL,omitempty"`
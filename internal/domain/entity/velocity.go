@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+)
+
+// VelocityRule caps how often, or for how much, a user or device may perform
+// a business action within a rolling window
+type VelocityRule struct {
+	ID          string  `json:"id" firestore:"id"`
+	Name        string  `json:"name" firestore:"name"`
+	Description string  `json:"description,omitempty" firestore:"description,omitempty"`
+	Action      string  `json:"action" firestore:"action"` // checkout, withdrawal, add_payment_method, topup
+	Scope       string  `json:"scope" firestore:"scope"`   // user, device
+	Metric      string  `json:"metric" firestore:"metric"` // count, amount
+	Limit       float64 `json:"limit" firestore:"limit"`
+	WindowHours int     `json:"window_hours" firestore:"windowHours"`
+	Enabled     bool    `json:"enabled" firestore:"enabled"`
+
+	UpdatedBy string    `json:"updated_by" firestore:"updatedBy"`
+	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+	UpdatedAt time.Time `json:"updated_at" firestore:"updatedAt"`
+}
+
+// VelocityEvent is one counted action
+type VelocityEvent struct {
+	ID        string    `json:"id" firestore:"id"`
+	Action    string    `json:"action" firestore:"action"`
+	UserID    string    `json:"user_id" firestore:"userId"`
+	DeviceID  string    `json:"device_id,omitempty" firestore:"deviceId,omitempty"`
+	Amount    float64   `json:"amount" firestore:"amount"`
+	Reference string    `json:"reference,omitempty" firestore:"reference,omitempty"`
+	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+}
+
+// VelocityCounter holds one subject's events for an action within one rule
+// window, so a limit check and the event it admits commit together
+type VelocityCounter struct {
+	ID          string           `json:"id" firestore:"id"`
+	Scope       string           `json:"scope" firestore:"scope"`
+	SubjectID   string           `json:"subject_id" firestore:"subjectId"`
+	Action      string           `json:"action" firestore:"action"`
+	WindowHours int              `json:"window_hours" firestore:"windowHours"`
+	Events      []*VelocityEvent `json:"events" firestore:"events"`
+	UpdatedAt   time.Time        `json:"updated_at" firestore:"updatedAt"`
+}
@@ -9,6 +9,12 @@ type WishlistItem struct {
 	UserID    string    `json:"user_id" firestore:"userId"`
 	ProductID string    `json:"product_id" firestore:"productId"`
 	CreatedAt time.Time `json:"created_at" firestore:"createdAt"`
+
+	// Price drop alerts fire below PriceAtAdd or at TargetPrice, once per
+	// new low (LastAlertedPrice)
+	PriceAtAdd       float64 `json:"price_at_add" firestore:"priceAtAdd"`
+	TargetPrice      float64 `json:"target_price,omitempty" firestore:"targetPrice,omitempty"`
+	LastAlertedPrice float64 `json:"last_alerted_price,omitempty" firestore:"lastAlertedPrice,omitempty"`
 }
 
 type WishlistItemWithProduct struct {
@@ -17,4 +23,7 @@ type WishlistItemWithProduct struct {
 	ProductID string    `json:"product_id"`
 	Product   *Product  `json:"product"`
 	CreatedAt time.Time `json:"created_at"`
+
+	PriceAtAdd  float64 `json:"price_at_add"`
+	TargetPrice float64 `json:"target_price,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type AutoReleasePolicyRepository interface {
+	Create(ctx context.Context, policy *entity.AutoReleasePolicy) error
+	GetByID(ctx context.Context, id string) (*entity.AutoReleasePolicy, error)
+	Update(ctx context.Context, policy *entity.AutoReleasePolicy) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, activeOnly bool) ([]*entity.AutoReleasePolicy, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type ChatModerationRepository interface {
+	CreateViolation(ctx context.Context, violation *entity.ChatViolation) error
+	// ListViolationsByUser returns the user's violations, newest first
+	ListViolationsByUser(ctx context.Context, userID string, since time.Time, limit int) ([]*entity.ChatViolation, error)
+
+	GetOffender(ctx context.Context, userID string) (*entity.ChatOffender, error)
+	SaveOffender(ctx context.Context, offender *entity.ChatOffender) error
+	ListOffenders(ctx context.Context, status string, limit, offset int) ([]*entity.ChatOffender, int64, error)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
 )
 
 type ChatRepository interface {
@@ -15,6 +16,7 @@ type ChatRepository interface {
 	// Message methods
 	CreateMessage(ctx context.Context, message *entity.Message) error
 	GetMessagesByChat(ctx context.Context, chatID string, limit, offset int) ([]*entity.Message, int64, error)
+	GetMessagesByChatPage(ctx context.Context, chatID string, page utils.PaginationParams) ([]*entity.Message, *utils.PageInfo, error)
 	UpdateMessageReadStatus(ctx context.Context, chatID, messageID string, userID string) error
 
 	// New methods for advanced chat features
@@ -12,6 +12,9 @@ type FileMetadataRepository interface {
 	GetByObjectName(ctx context.Context, objectName string) (*entity.FileMetadata, error)
 	GetByEntityID(ctx context.Context, entityType, entityID string) ([]*entity.FileMetadata, error)
 	GetByUploader(ctx context.Context, userID string, limit, offset int) ([]*entity.FileMetadata, int64, error)
+	// ListByHashBands returns files of the entity type sharing any of the
+	// perceptual hash bands
+	ListByHashBands(ctx context.Context, entityType string, bands []string) ([]*entity.FileMetadata, error)
 	Update(ctx context.Context, metadata *entity.FileMetadata) error
 	Delete(ctx context.Context, id string) error
 }
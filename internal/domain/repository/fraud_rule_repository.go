@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type FraudRuleRepository interface {
+	Create(ctx context.Context, rule *entity.FraudRule) error
+	GetByID(ctx context.Context, id string) (*entity.FraudRule, error)
+	Update(ctx context.Context, rule *entity.FraudRule) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*entity.FraudRule, error)
+
+	// SeedDefaults stores the default rules, keyed by flag, the first time it
+	// runs against an empty collection and reports whether it wrote them
+	SeedDefaults(ctx context.Context, rules []*entity.FraudRule) (bool, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type HandoverChecklistRepository interface {
+	Create(ctx context.Context, checklist *entity.HandoverChecklist) error
+	GetByTransactionID(ctx context.Context, transactionID string) (*entity.HandoverChecklist, error)
+	Update(ctx context.Context, checklist *entity.HandoverChecklist) error
+	// UpdateIfStatus writes the checklist only if the stored status still equals expected
+	UpdateIfStatus(ctx context.Context, checklist *entity.HandoverChecklist, expected string) (bool, error)
+	// Modify reads the checklist and writes back mutate's changes in one
+	// transaction; an error from mutate aborts the write and is returned as is
+	Modify(ctx context.Context, checklistID string, mutate func(checklist *entity.HandoverChecklist) error) (*entity.HandoverChecklist, error)
+
+	// ListOverdue returns in-progress checklists whose SLA expired before the given time
+	ListOverdue(ctx context.Context, before time.Time, limit int) ([]*entity.HandoverChecklist, error)
+	ListByStatus(ctx context.Context, status string, limit int) ([]*entity.HandoverChecklist, error)
+	// ListByStatusUpdatedSince pages, by update time, through checklists in
+	// the given status updated at or after since, starting after the given
+	// checklist (nil for the first page)
+	ListByStatusUpdatedSince(ctx context.Context, status string, since time.Time, after *entity.HandoverChecklist, limit int) ([]*entity.HandoverChecklist, error)
+	CountBySellerAndStatus(ctx context.Context, sellerID, status string) (int, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
+)
+
+type ImageMatchRepository interface {
+	Create(ctx context.Context, matches []*entity.ImageMatch) error
+	ListByProduct(ctx context.Context, productID string) ([]*entity.ImageMatch, error)
+	ListPage(ctx context.Context, pagination utils.PaginationParams) ([]*entity.ImageMatch, *utils.PageInfo, error)
+}
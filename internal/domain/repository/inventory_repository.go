@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
+)
+
+type InventoryRepository interface {
+	CreateCodes(ctx context.Context, codes []*entity.InventoryCode) error
+	GetByID(ctx context.Context, id string) (*entity.InventoryCode, error)
+	Update(ctx context.Context, code *entity.InventoryCode) error
+	ListPage(ctx context.Context, productID, variantID, status string, pagination utils.PaginationParams) ([]*entity.InventoryCode, *utils.PageInfo, error)
+
+	// ClaimCode atomically marks one available code as delivered to the
+	// transaction. It returns the code already delivered to the transaction
+	// if there is one, and NOT_FOUND when the pool is empty.
+	ClaimCode(ctx context.Context, productID, variantID, transactionID string) (*entity.InventoryCode, error)
+
+	// CountByStatus counts a product's codes per variant ID and status
+	CountByStatus(ctx context.Context, productID string) (map[string]map[string]int, error)
+	// Fingerprints returns the fingerprints of every code uploaded for the product
+	Fingerprints(ctx context.Context, productID string) (map[string]bool, error)
+}
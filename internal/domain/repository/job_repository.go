@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type JobRepository interface {
+	// AcquireLease atomically takes the job lease if it is free or expired,
+	// whoever held it, and the job has not started since dueSince. A zero
+	// dueSince ignores the last start.
+	AcquireLease(ctx context.Context, jobName, holderID string, ttl time.Duration, dueSince time.Time) (bool, error)
+	// RenewLease extends a lease still held by holderID
+	RenewLease(ctx context.Context, jobName, holderID string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, jobName, holderID string) error
+	GetLease(ctx context.Context, jobName string) (*entity.JobLease, error)
+
+	CreateRun(ctx context.Context, run *entity.JobRun) error
+	UpdateRun(ctx context.Context, run *entity.JobRun) error
+	ListRuns(ctx context.Context, jobName string, limit, offset int) ([]*entity.JobRun, int64, error)
+}
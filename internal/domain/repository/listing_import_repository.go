@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type ListingImportRepository interface {
+	Create(ctx context.Context, job *entity.ImportJob) error
+	GetByID(ctx context.Context, id string) (*entity.ImportJob, error)
+	Update(ctx context.Context, job *entity.ImportJob) error
+	// UpdateIfStatus writes the job only if the stored status still equals expected
+	UpdateIfStatus(ctx context.Context, job *entity.ImportJob, expected string) (bool, error)
+	ListByStatus(ctx context.Context, status string, limit int) ([]*entity.ImportJob, error)
+	SaveRows(ctx context.Context, jobID string, rows []*entity.ImportRow) error
+	// ListRows returns the rows of a job in sheet order
+	ListRows(ctx context.Context, jobID string) ([]*entity.ImportRow, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
+)
+
+type ListingReviewRepository interface {
+	Create(ctx context.Context, review *entity.ListingReview) error
+	GetByID(ctx context.Context, id string) (*entity.ListingReview, error)
+	Update(ctx context.Context, review *entity.ListingReview) error
+	// GetLatestByProduct returns NOT_FOUND for products never reviewed
+	GetLatestByProduct(ctx context.Context, productID string) (*entity.ListingReview, error)
+	ListPage(ctx context.Context, status string, pagination utils.PaginationParams) ([]*entity.ListingReview, *utils.PageInfo, error)
+}
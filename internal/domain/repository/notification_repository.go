@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *entity.Notification) error
+	ListPage(ctx context.Context, userID string, unreadOnly bool, pagination utils.PaginationParams) ([]*entity.Notification, *utils.PageInfo, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead returns NOT_FOUND for notifications of other users
+	MarkRead(ctx context.Context, userID, id string) error
+	MarkAllRead(ctx context.Context, userID string) (int, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type PriceHistoryRepository interface {
+	Record(ctx context.Context, points []*entity.PricePoint) error
+	// ListSince returns the product's points for one variant ("" for the
+	// listed price) changed at or after since, oldest first
+	ListSince(ctx context.Context, productID, variantID string, since time.Time) ([]*entity.PricePoint, error)
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
 )
 
 type ProductRepository interface {
@@ -11,9 +13,29 @@ type ProductRepository interface {
 	GetByID(ctx context.Context, id string) (*entity.Product, error)
 	List(ctx context.Context, filter map[string]interface{}, sort string, limit, offset int) ([]*entity.Product, int64, error)
 	Update(ctx context.Context, product *entity.Product) error
+	// Modify reads the product and writes back mutate's changes in one
+	// transaction; an error from mutate aborts the write and is returned as is
+	Modify(ctx context.Context, productID string, mutate func(product *entity.Product) error) (*entity.Product, error)
 	Delete(ctx context.Context, id string) error
 	SoftDelete(ctx context.Context, id string) error
 	IncrementViews(ctx context.Context, id string) error
 	ListBySellerID(ctx context.Context, sellerID string, status string, limit, offset int) ([]*entity.Product, int64, error)
-	Search(ctx context.Context, query string, filter map[string]interface{}, limit, offset int) ([]*entity.Product, int64, error)
+	ListBySellerIDPage(ctx context.Context, sellerID string, status string, page utils.PaginationParams) ([]*entity.Product, *utils.PageInfo, error)
+	// ListActiveBumpedBefore returns active products not bumped since the
+	// given time, the candidates for expiry
+	ListActiveBumpedBefore(ctx context.Context, before time.Time) ([]*entity.Product, error)
+	// ListPageByID pages through every product by document ID, starting
+	// after afterID ("" for the first page)
+	ListPageByID(ctx context.Context, afterID string, limit int) ([]*entity.Product, error)
+	// ListUpdatedSince pages, by update time, through products updated at or
+	// after since, starting after the given product (nil for the first page)
+	ListUpdatedSince(ctx context.Context, since time.Time, after *entity.Product, limit int) ([]*entity.Product, error)
+	// ResealCredentials reads the stored credentials and writes back reseal's
+	// result in one transaction, touching no other field. An error from
+	// reseal aborts the write and is returned as is.
+	ResealCredentials(ctx context.Context, productID string, reseal CredentialResealFunc) (bool, error)
 }
+
+// CredentialResealFunc returns the credentials to store for the given sealed
+// and legacy plaintext credentials, and whether they changed
+type CredentialResealFunc func(sealed *entity.EncryptedCredentials, plaintext map[string]interface{}) (*entity.EncryptedCredentials, bool, error)
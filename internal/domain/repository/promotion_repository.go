@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *entity.Promotion) error
+	GetByID(ctx context.Context, id string) (*entity.Promotion, error)
+	Update(ctx context.Context, promotion *entity.Promotion) error
+	ListBySellerPage(ctx context.Context, sellerID string, pagination utils.PaginationParams) ([]*entity.Promotion, *utils.PageInfo, error)
+	ListActive(ctx context.Context) ([]*entity.Promotion, error)
+	ListActiveByProduct(ctx context.Context, productID string) ([]*entity.Promotion, error)
+	CountActiveFeatured(ctx context.Context, gameTitleID string) (int64, error)
+	// ReserveSlot holds one of limit slots under key for the promotion until
+	// the given time, in one transaction that first drops holds ended by now.
+	// It reports whether a slot was free.
+	ReserveSlot(ctx context.Context, key, promotionID string, until time.Time, limit int, now time.Time) (bool, error)
+	ReleaseSlot(ctx context.Context, key, promotionID string) error
+
+	// Impressions and clicks are counted per product and UTC day
+	RecordImpressions(ctx context.Context, productIDs []string, at time.Time) error
+	RecordClick(ctx context.Context, productID string, at time.Time) error
+	ListStats(ctx context.Context, productID string, from, to time.Time) ([]*entity.ProductDailyStats, error)
+}
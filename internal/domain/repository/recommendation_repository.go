@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type RecommendationRepository interface {
+	RecordView(ctx context.Context, view *entity.ProductView) error
+	ListViewsSince(ctx context.Context, since time.Time) ([]*entity.ProductView, error)
+	ListViewsByUser(ctx context.Context, userID string, limit int) ([]*entity.ProductView, error)
+
+	// SaveRecommendations replaces the stored lists of the given products
+	SaveRecommendations(ctx context.Context, recommendations []*entity.ProductRecommendations) error
+	GetRecommendations(ctx context.Context, productID string) (*entity.ProductRecommendations, error)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
 )
 
 type ReviewRepository interface {
@@ -10,6 +11,7 @@ type ReviewRepository interface {
 	GetByID(ctx context.Context, id string) (*entity.Review, error)
 	GetByTransactionID(ctx context.Context, transactionID string) (*entity.Review, error)
 	List(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]*entity.Review, int64, error)
+	ListPage(ctx context.Context, filter map[string]interface{}, page utils.PaginationParams) ([]*entity.Review, *utils.PageInfo, error)
 	Update(ctx context.Context, review *entity.Review) error
 	Delete(ctx context.Context, id string) error
 
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type SecurityLogRepository interface {
+	Create(ctx context.Context, securityLog *entity.SecurityLog) error
+	ListByUserID(ctx context.Context, userID string, limit int) ([]*entity.SecurityLog, error)
+	ListByDeviceID(ctx context.Context, deviceID string, limit int) ([]*entity.SecurityLog, error)
+	ListByIPAddress(ctx context.Context, ipAddress string, limit int) ([]*entity.SecurityLog, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type SellerTrustRepository interface {
+	GetBySellerID(ctx context.Context, sellerID string) (*entity.SellerTrustProfile, error)
+	Save(ctx context.Context, profile *entity.SellerTrustProfile) error
+	// List returns profiles ordered by risk score, optionally filtered by tier
+	List(ctx context.Context, tier string, limit, offset int) ([]*entity.SellerTrustProfile, int64, error)
+	// ListComputedBefore returns up to limit profiles last scored before the given time, oldest first
+	ListComputedBefore(ctx context.Context, before time.Time, limit int) ([]*entity.SellerTrustProfile, error)
+
+	// GetScoringCheckpoint returns when the last completed scoring run
+	// started, or nil before the first run
+	GetScoringCheckpoint(ctx context.Context) (*time.Time, error)
+	SaveScoringCheckpoint(ctx context.Context, startedAt time.Time) error
+}
@@ -2,25 +2,34 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/utils"
 )
 
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *entity.Transaction) error
 	GetByID(ctx context.Context, id string) (*entity.Transaction, error)
 	Update(ctx context.Context, transaction *entity.Transaction) error
+	// UpdateFields writes only the given fields, keyed by their Firestore names
+	UpdateFields(ctx context.Context, transactionID string, updates map[string]interface{}) error
+	// UpdateFieldsIfUnchanged writes the given fields only if the stored status
+	// and escrow status still equal the expected values and no dispute is open
+	UpdateFieldsIfUnchanged(ctx context.Context, transactionID, expectedStatus, expectedEscrowStatus string, updates map[string]interface{}) (bool, error)
 	List(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]*entity.Transaction, int64, error)
 
 	CreateLog(ctx context.Context, log *entity.TransactionLog) error
 	ListLogsByTransactionID(ctx context.Context, transactionID string) ([]*entity.TransactionLog, error)
 
 	ListByUserID(ctx context.Context, userID string, role string, status string, limit, offset int) ([]*entity.Transaction, int64, error)
+	ListByUserIDPage(ctx context.Context, userID string, role string, status string, page utils.PaginationParams) ([]*entity.Transaction, *utils.PageInfo, error)
 	ListPendingMiddlemanTransactions(ctx context.Context, limit, offset int) ([]*entity.Transaction, int64, error)
 
 	GetTransactionStats(ctx context.Context, userID string, period string) (map[string]interface{}, error)
 	HasCompletedTransaction(ctx context.Context, userID, productID string) (bool, error)
 	GetCompletedTransactionCount(ctx context.Context, productID string) (int, error)
-	GetPendingTransactionCount(ctx context.Context, productID string) (int, error)
+	GetPendingTransactionCount(ctx context.Context, productID, variantID string) (int, error) // variantID "" counts the whole product
 	
 	// Midtrans Integration Methods
 	GetByMidtransOrderID(ctx context.Context, midtransOrderID string) (*entity.Transaction, error)
@@ -29,4 +38,42 @@ type TransactionRepository interface {
 	CreateApproval(ctx context.Context, approval *entity.TransactionApproval) error
 	GetApprovalsByTransactionID(ctx context.Context, transactionID string) ([]*entity.TransactionApproval, error)
 	UpdateApproval(ctx context.Context, approval *entity.TransactionApproval) error
+
+	// Credential Access Audit Methods
+	CreateCredentialAccessLog(ctx context.Context, accessLog *entity.CredentialAccessLog) error
+	ListCredentialAccessLogs(ctx context.Context, transactionID string) ([]*entity.CredentialAccessLog, error)
+	// ReserveCredentialView counts a reveal atomically when fewer than limit
+	// reveals happened within window before now. When refused it returns the
+	// time the next reveal is allowed.
+	ReserveCredentialView(ctx context.Context, transactionID string, now time.Time, limit int, window time.Duration) (bool, time.Time, error)
+	// MarkCredentialsAccessed records the first access and adds the security flags
+	MarkCredentialsAccessed(ctx context.Context, transactionID string, accessedAt time.Time, securityFlags []string) error
+
+	// BackfillCredentialsRedacted sets credentialsRedacted to false on
+	// transactions written before the field existed, so the redaction query
+	// matches them. Runs once; a marker records completion.
+	BackfillCredentialsRedacted(ctx context.Context) (int, error)
+	// ListUnredactedCompletedBefore pages, by completion time, through
+	// transactions completed before cutoff whose credentials are not yet
+	// redacted, starting after the given transaction (nil for the first page)
+	ListUnredactedCompletedBefore(ctx context.Context, cutoff time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error)
+	// MarkCredentialsRedacted removes the stored credentials of a transaction
+	MarkCredentialsRedacted(ctx context.Context, transactionID string, redactedAt time.Time) error
+
+	// ListAutoReleaseDueBefore pages, by auto-release time, through
+	// transactions awaiting auto-release at or before the given time,
+	// starting after the given transaction (nil for the first page)
+	ListAutoReleaseDueBefore(ctx context.Context, before time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error)
+
+	// ListUpdatedSince pages, by update time, through transactions updated at
+	// or after since, starting after the given transaction (nil for the first page)
+	ListUpdatedSince(ctx context.Context, since time.Time, after *entity.Transaction, limit int) ([]*entity.Transaction, error)
+
+	// ListPageByID pages through every transaction by document ID, starting
+	// after afterID ("" for the first page)
+	ListPageByID(ctx context.Context, afterID string, limit int) ([]*entity.Transaction, error)
+	// ResealCredentials reads the stored credentials and writes back reseal's
+	// result in one transaction, touching no other field. An error from
+	// reseal aborts the write and is returned as is.
+	ResealCredentials(ctx context.Context, transactionID string, reseal CredentialResealFunc) (bool, error)
 }
@@ -11,6 +11,8 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	Update(ctx context.Context, user *entity.User) error
+	// UpdateTrustTier writes only the user's trust tier
+	UpdateTrustTier(ctx context.Context, userID, tier string) error
 	Delete(ctx context.Context, id string) error
 	FindByField(ctx context.Context, field, value string, limit, offset int) ([]*entity.User, int64, error)
 	GetUserByRole(ctx context.Context, role string, limit int) []*entity.User
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+)
+
+type VelocityRepository interface {
+	CreateRule(ctx context.Context, rule *entity.VelocityRule) error
+	GetRule(ctx context.Context, id string) (*entity.VelocityRule, error)
+	UpdateRule(ctx context.Context, rule *entity.VelocityRule) error
+	DeleteRule(ctx context.Context, id string) error
+	ListRules(ctx context.Context) ([]*entity.VelocityRule, error)
+	// SeedDefaults stores the default rules, keyed by rule ID, the first time
+	// it runs against the rule collection. Reports whether anything was written.
+	SeedDefaults(ctx context.Context, rules []*entity.VelocityRule) (bool, error)
+
+	CreateEvent(ctx context.Context, event *entity.VelocityEvent) error
+	// ListEvents returns events for a user or device since the given time,
+	// oldest first. scope is "user" or "device".
+	ListEvents(ctx context.Context, scope, subjectID, action string, since time.Time) ([]*entity.VelocityEvent, error)
+
+	// Reserve loads the counters named by scope, subject, action and window
+	// in one transaction, drops events outside each window and calls check.
+	// When check returns nil the event is appended to every counter and
+	// stored; an error from check aborts the write and is returned as is.
+	Reserve(ctx context.Context, event *entity.VelocityEvent, counters []*entity.VelocityCounter, check func(counters []*entity.VelocityCounter) error) error
+	// Release removes the event with the given ID from the counters and the event log
+	Release(ctx context.Context, eventID string, counters []*entity.VelocityCounter) error
+}
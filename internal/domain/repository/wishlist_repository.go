@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"pasargamex/internal/domain/entity"
 )
 
@@ -23,4 +25,13 @@ type WishlistRepository interface {
 	
 	// Get wishlist count for user
 	GetWishlistCount(ctx context.Context, userID string) (int64, error)
+
+	// Save alert settings of a wishlist item
+	UpdateWishlistItem(ctx context.Context, item *entity.WishlistItem) error
+
+	// Get every wishlist item for a product, for price drop alerts
+	ListByProduct(ctx context.Context, productID string) ([]*entity.WishlistItem, error)
+
+	// Get wishlist items added since the given time, for recommendations
+	ListCreatedSince(ctx context.Context, since time.Time) ([]*entity.WishlistItem, error)
 }
\ No newline at end of file
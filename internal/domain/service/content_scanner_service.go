@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+)
+
+// Actions a content scan can ask for, from least to most severe
+const (
+	ContentActionAllow = "allow"
+	ContentActionWarn  = "warn"
+	ContentActionBlock = "block"
+)
+
+// ContentFinding is one suspicious match in a message
+type ContentFinding struct {
+	Category string `json:"category" firestore:"category"` // phone_number, bank_account, messaging_link
+	Match    string `json:"match" firestore:"match"`
+}
+
+type ContentScanResult struct {
+	Action   string           `json:"action"`
+	Findings []ContentFinding `json:"findings,omitempty"`
+	// Warning is the text shown to users; set by the caller, not the scanner
+	Warning string `json:"warning,omitempty"`
+}
+
+// Categories returns the distinct finding categories in match order
+func (r *ContentScanResult) Categories() []string {
+	categories := []string{}
+	seen := map[string]bool{}
+	for _, finding := range r.Findings {
+		if !seen[finding.Category] {
+			seen[finding.Category] = true
+			categories = append(categories, finding.Category)
+		}
+	}
+	return categories
+}
+
+// ContentScanner inspects user-written chat content for attempts to move
+// the deal or payment off the platform
+type ContentScanner interface {
+	Scan(ctx context.Context, content string) *ContentScanResult
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+)
+
+// KeyProvider wraps and unwraps per-record data keys. It mirrors the
+// encrypt/decrypt surface of a cloud KMS so a managed key service can replace
+// the local key file used in development and tests.
+type KeyProvider interface {
+	// CurrentKeyID is the key new data keys are wrapped with
+	CurrentKeyID() string
+
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// KeyRotator is implemented by key providers that rotate their own master
+// key. Managed KMS keys rotate through key versions instead.
+type KeyRotator interface {
+	RotateKey() (string, error)
+}
+
+// CredentialVault seals credential maps with envelope encryption
+type CredentialVault interface {
+	Seal(ctx context.Context, credentials map[string]interface{}) (*entity.EncryptedCredentials, error)
+
+	Open(ctx context.Context, sealed *entity.EncryptedCredentials) (map[string]interface{}, error)
+
+	// NeedsRotation reports whether the data key is wrapped with a retired key
+	NeedsRotation(sealed *entity.EncryptedCredentials) bool
+}
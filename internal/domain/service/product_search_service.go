@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"pasargamex/internal/domain/entity"
+)
+
+// ProductSearchQuery narrows a full-text search. Empty fields do not filter.
+type ProductSearchQuery struct {
+	Text        string
+	GameTitleID string
+	Type        string
+	Status      string
+	MinPrice    float64
+	MaxPrice    float64
+	Limit       int // 0 returns every hit
+	Offset      int
+}
+
+// ProductSearchHit is one ranked result; Score blends text relevance with
+// bump recency and featured placement. Attributes are carried so callers can
+// filter and facet on them without loading the product.
+type ProductSearchHit struct {
+	ProductID  string                 `json:"product_id"`
+	Score      float64                `json:"score"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type ProductSearchResult struct {
+	Hits  []ProductSearchHit `json:"hits"`
+	Total int64              `json:"total"`
+}
+
+// ProductSearchIndex keeps a searchable copy of the catalog. Index replaces
+// any previous entry for the product; Rebuild swaps in a fresh index built
+// from the given products.
+type ProductSearchIndex interface {
+	Index(ctx context.Context, product *entity.Product) error
+	Remove(ctx context.Context, productID string) error
+	Rebuild(ctx context.Context, products []*entity.Product) error
+	Search(ctx context.Context, query ProductSearchQuery) (*ProductSearchResult, error)
+}
@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+// CloudKMSClient implements KMSClient with Google Cloud KMS. Key names are
+// full crypto key resource names; KMS picks the primary version on encrypt
+// and the matching version on decrypt, so key version rotation needs no
+// re-encryption.
+type CloudKMSClient struct {
+	keys *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+}
+
+func NewCloudKMSClient(ctx context.Context, opts ...option.ClientOption) (*CloudKMSClient, error) {
+	kmsService, err := cloudkms.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kms client: %v", err)
+	}
+
+	return &CloudKMSClient{
+		keys: kmsService.Projects.Locations.KeyRings.CryptoKeys,
+	}, nil
+}
+
+func (c *CloudKMSClient) Encrypt(ctx context.Context, keyName string, plaintext []byte) ([]byte, error) {
+	resp, err := c.keys.Encrypt(keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(plaintext),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+func (c *CloudKMSClient) Decrypt(ctx context.Context, keyName string, ciphertext []byte) ([]byte, error) {
+	resp, err := c.keys.Decrypt(keyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
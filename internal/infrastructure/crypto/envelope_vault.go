@@ -0,0 +1,126 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/service"
+)
+
+const AlgorithmAES256GCM = "AES-256-GCM"
+
+// EnvelopeVault encrypts each credential map with a fresh data key and stores
+// the data key wrapped by the key provider.
+type EnvelopeVault struct {
+	keyProvider service.KeyProvider
+}
+
+func NewEnvelopeVault(keyProvider service.KeyProvider) service.CredentialVault {
+	return &EnvelopeVault{
+		keyProvider: keyProvider,
+	}
+}
+
+func (v *EnvelopeVault) Seal(ctx context.Context, credentials map[string]interface{}) (*entity.EncryptedCredentials, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credentials: %v", err)
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	nonce, ciphertext, err := sealAESGCM(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := v.keyProvider.CurrentKeyID()
+	wrappedKey, err := v.keyProvider.WrapKey(ctx, keyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %v", err)
+	}
+
+	return &entity.EncryptedCredentials{
+		KeyID:       keyID,
+		WrappedKey:  wrappedKey,
+		Nonce:       nonce,
+		Ciphertext:  ciphertext,
+		Algorithm:   AlgorithmAES256GCM,
+		EncryptedAt: time.Now(),
+	}, nil
+}
+
+func (v *EnvelopeVault) Open(ctx context.Context, sealed *entity.EncryptedCredentials) (map[string]interface{}, error) {
+	if sealed.Algorithm != AlgorithmAES256GCM {
+		return nil, fmt.Errorf("unsupported credential algorithm: %s", sealed.Algorithm)
+	}
+
+	dataKey, err := v.keyProvider.UnwrapKey(ctx, sealed.KeyID, sealed.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+
+	plaintext, err := openAESGCM(dataKey, sealed.Nonce, sealed.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials map[string]interface{}
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials: %v", err)
+	}
+
+	return credentials, nil
+}
+
+func (v *EnvelopeVault) NeedsRotation(sealed *entity.EncryptedCredentials) bool {
+	return sealed.KeyID != v.keyProvider.CurrentKeyID()
+}
+
+func sealAESGCM(key, plaintext []byte) ([]byte, []byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create GCM: %v", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+func openAESGCM(key, nonce, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %v", err)
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %v", err)
+	}
+
+	return plaintext, nil
+}
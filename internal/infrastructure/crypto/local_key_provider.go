@@ -0,0 +1,166 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// LocalFileKeyProvider keeps master keys in a JSON file. It is meant for
+// development and tests; production should use a KMS-backed provider.
+type LocalFileKeyProvider struct {
+	path         string
+	currentKeyID string
+	keys         map[string][]byte
+	mutex        sync.RWMutex
+}
+
+type localKeyFile struct {
+	CurrentKeyID string            `json:"current_key_id"`
+	Keys         map[string]string `json:"keys"` // key ID -> base64 key
+}
+
+// NewLocalFileKeyProvider loads the key file. A missing file is created with
+// a fresh key only when createIfMissing is set; otherwise it is an error, since
+// a new key could not open credentials sealed under the lost one.
+func NewLocalFileKeyProvider(path string, createIfMissing bool) (*LocalFileKeyProvider, error) {
+	provider := &LocalFileKeyProvider{
+		path: path,
+		keys: make(map[string][]byte),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if !createIfMissing {
+			return nil, fmt.Errorf("key file %s not found", path)
+		}
+		if _, err := provider.RotateKey(); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	var file localKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %v", err)
+	}
+
+	for keyID, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %v", keyID, err)
+		}
+		provider.keys[keyID] = key
+	}
+
+	if _, ok := provider.keys[file.CurrentKeyID]; !ok {
+		return nil, fmt.Errorf("current key %s not found in key file", file.CurrentKeyID)
+	}
+	provider.currentKeyID = file.CurrentKeyID
+
+	return provider, nil
+}
+
+func (p *LocalFileKeyProvider) CurrentKeyID() string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.currentKeyID
+}
+
+func (p *LocalFileKeyProvider) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	masterKey, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, ciphertext, err := sealAESGCM(masterKey, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nonce, ciphertext...), nil
+}
+
+func (p *LocalFileKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	masterKey, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nonce is the first 12 bytes (standard GCM nonce size)
+	if len(wrappedKey) < 12 {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+
+	return openAESGCM(masterKey, wrappedKey[:12], wrappedKey[12:])
+}
+
+// RotateKey generates a new master key, makes it current and persists the key
+// file. Older keys are kept so existing ciphertext can still be opened.
+func (p *LocalFileKeyProvider) RotateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("failed to generate master key: %v", err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	keyID := fmt.Sprintf("local-%d", time.Now().UnixNano())
+	p.keys[keyID] = key
+	p.currentKeyID = keyID
+
+	if err := p.save(); err != nil {
+		return "", err
+	}
+
+	return keyID, nil
+}
+
+func (p *LocalFileKeyProvider) key(keyID string) ([]byte, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key: %s", keyID)
+	}
+	return key, nil
+}
+
+func (p *LocalFileKeyProvider) save() error {
+	file := localKeyFile{
+		CurrentKeyID: p.currentKeyID,
+		Keys:         make(map[string]string, len(p.keys)),
+	}
+	for keyID, key := range p.keys {
+		file.Keys[keyID] = base64.StdEncoding.EncodeToString(key)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode key file: %v", err)
+	}
+
+	if dir := filepath.Dir(p.path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create key directory: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(p.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+
+	return nil
+}
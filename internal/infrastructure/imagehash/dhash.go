@@ -0,0 +1,128 @@
+package imagehash
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // registered decoders are the formats that can be hashed
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math/bits"
+	"strconv"
+)
+
+// maxPixels refuses images whose header claims more pixels than any real
+// screenshot, so a tiny file cannot decode into gigabytes
+const maxPixels = 50_000_000
+
+// BandWidths splits a hash into bands for lookups. Two hashes within
+// MatchDistance bits of each other differ in at most that many bands, so
+// they always share at least one band exactly.
+var BandWidths = []int{11, 11, 11, 11, 10, 10}
+
+// MatchDistance is the largest number of differing bits at which two images
+// count as the same picture, re-encoded, resized or lightly edited
+var MatchDistance = len(BandWidths) - 1
+
+// Compute decodes an image and returns its difference hash
+func Compute(r io.Reader) (uint64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("unsupported image: %w", err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return 0, fmt.Errorf("image of %dx%d is too large to hash", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return FromImage(img), nil
+}
+
+// FromImage computes a 64-bit difference hash: the image shrunk to 9x8
+// grey cells, one bit per pair of horizontally adjacent cells set when the
+// left one is brighter. It survives resizing, re-compression and small
+// edits, which a byte hash does not.
+func FromImage(img image.Image) uint64 {
+	const width, height = 9, 8
+	bounds := img.Bounds()
+
+	var cells [height][width]float64
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+			cells[y][x] = averageLuma(img, x0, y0, x1, y1)
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width-1; x++ {
+			hash <<= 1
+			if cells[y][x] > cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// averageLuma averages the brightness of a cell, sampling large cells on a
+// grid of at most 16x16 pixels
+func averageLuma(img image.Image, x0, y0, x1, y1 int) float64 {
+	stepX := max((x1-x0)/16, 1)
+	stepY := max((y1-y0)/16, 1)
+
+	var sum float64
+	var count int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// Distance is the number of bits in which two hashes differ
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Format renders a hash as 16 hex digits
+func Format(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// Parse reads a hash rendered by Format
+func Parse(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// Bands returns the hash's bands labelled with their position, e.g. "2:5f3"
+func Bands(hash uint64) []string {
+	bands := make([]string, len(BandWidths))
+	shift := 64
+	for i, width := range BandWidths {
+		shift -= width
+		value := (hash >> uint(shift)) & (1<<uint(width) - 1)
+		bands[i] = fmt.Sprintf("%d:%x", i, value)
+	}
+	return bands
+}
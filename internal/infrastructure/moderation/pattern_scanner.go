@@ -0,0 +1,135 @@
+package moderation
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"pasargamex/internal/domain/service"
+)
+
+// Finding categories reported by the pattern scanner
+const (
+	CategoryPhoneNumber   = "phone_number"
+	CategoryBankAccount   = "bank_account"
+	CategoryMessagingLink = "messaging_link"
+)
+
+// DefaultCategoryActions warns on bare phone numbers, which are often shared
+// innocently, and blocks payment details and messaging-app links
+var DefaultCategoryActions = map[string]string{
+	CategoryPhoneNumber:   service.ContentActionWarn,
+	CategoryBankAccount:   service.ContentActionBlock,
+	CategoryMessagingLink: service.ContentActionBlock,
+}
+
+var (
+	// Indonesian mobile numbers (08xx / +62 8xx) and other international numbers
+	phoneNumberPattern = regexp.MustCompile(`(?:\+62|\b62|\b0)[\s.-]?8[1-9][0-9](?:[\s.-]?[0-9]){6,9}\b|\+[1-9][0-9]{0,2}(?:[\s.-]?[0-9]){7,12}\b`)
+
+	// A run of 8-16 digits, optionally grouped with spaces or dashes
+	accountNumberPattern = regexp.MustCompile(`\b[0-9](?:[\s-]?[0-9]){7,15}\b`)
+
+	// Words that turn the digit run right after them into payment details,
+	// allowing separators and a few filler words in between ("rek saya: ...")
+	bankKeywordPattern = regexp.MustCompile(`(?i)\b(?:no\.?\s*rek|norek|rek(?:ening)?|bank|transfer|tf|a\.?n\.?|atas\s+nama|bca|bni|bri|mandiri|cimb|permata|btn|bsi|jenius|seabank|dana|ovo|gopay|shopeepay)(?:[\s:.,-]+(?:ke|di|saya|aku|ku|no\.?|nomor|nomer))*[\s:.,-]*$`)
+
+	// Bank names right after a digit run ("1234567890 bca")
+	bankNameAfterPattern = regexp.MustCompile(`(?i)^[\s:.,()-]*(?:a\.?n\.?|atas\s+nama|bca|bni|bri|mandiri|cimb|permata|btn|bsi|jenius|seabank|dana|ovo|gopay|shopeepay)\b`)
+
+	// Amounts are digit runs with a currency or price word before them, a
+	// unit after them, or digits grouped in thousands
+	amountPrefixPattern     = regexp.MustCompile(`(?i)(?:\brp\.?|\bidr|\bharga|\bseharga|\bsenilai|\btotal|\bbayar|\bbudget|\bnego)[\s:=-]*$`)
+	amountSuffixPattern     = regexp.MustCompile(`(?i)^\s*(?:rupiah|idr|rb|ribu|jt|juta|k)\b`)
+	groupedThousandsPattern = regexp.MustCompile(`^[0-9]{1,3}(?:[\s-][0-9]{3})+$`)
+
+	messagingLinkPattern = regexp.MustCompile(`(?i)\b(?:wa\.me|api\.whatsapp\.com|chat\.whatsapp\.com|t\.me|telegram\.(?:me|dog)|line\.me|discord\.gg|discord(?:app)?\.com/invite|m\.me|ig\.me)/?[^\s]*`)
+)
+
+type PatternScanner struct {
+	actions map[string]string
+}
+
+// NewPatternScanner returns a regex based scanner. A nil map uses
+// DefaultCategoryActions.
+func NewPatternScanner(actions map[string]string) service.ContentScanner {
+	if actions == nil {
+		actions = DefaultCategoryActions
+	}
+
+	return &PatternScanner{
+		actions: actions,
+	}
+}
+
+func (s *PatternScanner) Scan(ctx context.Context, content string) *service.ContentScanResult {
+	result := &service.ContentScanResult{Action: service.ContentActionAllow}
+	if strings.TrimSpace(content) == "" {
+		return result
+	}
+
+	for _, match := range messagingLinkPattern.FindAllString(content, -1) {
+		s.add(result, CategoryMessagingLink, match)
+	}
+
+	phones := phoneNumberPattern.FindAllString(content, -1)
+	for _, match := range phones {
+		s.add(result, CategoryPhoneNumber, match)
+	}
+
+	for _, match := range bankAccountNumbers(content) {
+		s.add(result, CategoryBankAccount, match)
+	}
+
+	return result
+}
+
+// bankContextWindow is how many bytes around a digit run are searched for
+// payment keywords and amount markers
+const bankContextWindow = 32
+
+// bankAccountNumbers returns the digit runs presented as payment details: a
+// payment keyword right before or a bank name right after, and not an amount
+func bankAccountNumbers(content string) []string {
+	var numbers []string
+	for _, loc := range accountNumberPattern.FindAllStringIndex(content, -1) {
+		number := content[loc[0]:loc[1]]
+		before := content[max(0, loc[0]-bankContextWindow):loc[0]]
+		after := content[loc[1]:min(len(content), loc[1]+bankContextWindow)]
+
+		if amountPrefixPattern.MatchString(before) || amountSuffixPattern.MatchString(after) || groupedThousandsPattern.MatchString(number) {
+			continue
+		}
+
+		if bankKeywordPattern.MatchString(before) || bankNameAfterPattern.MatchString(after) {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+func (s *PatternScanner) add(result *service.ContentScanResult, category, match string) {
+	action, ok := s.actions[category]
+	if !ok || action == service.ContentActionAllow {
+		return
+	}
+
+	result.Findings = append(result.Findings, service.ContentFinding{
+		Category: category,
+		Match:    strings.TrimSpace(match),
+	})
+
+	if actionSeverity(action) > actionSeverity(result.Action) {
+		result.Action = action
+	}
+}
+
+func actionSeverity(action string) int {
+	switch action {
+	case service.ContentActionBlock:
+		return 2
+	case service.ContentActionWarn:
+		return 1
+	}
+	return 0
+}
@@ -100,6 +100,9 @@ func (rl *RateLimiter) Allow(userID, action string) (bool, time.Duration) {
 			case "typing":
 				// Allow 30 typing events per minute (1 token per 2 seconds)
 				bucket = NewTokenBucket(30, 1, 2*time.Second)
+			case "reveal_credentials":
+				// Allow 5 credential reveals per hour (1 token per 12 minutes)
+				bucket = NewTokenBucket(5, 1, 12*time.Minute)
 			default:
 				// Default rate limit: 20 actions per minute
 				bucket = NewTokenBucket(20, 1, 3*time.Second)
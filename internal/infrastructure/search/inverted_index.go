@@ -0,0 +1,445 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/service"
+)
+
+// Field weights: a word in the title counts three times a word in the description
+const (
+	titleWeight       = 3.0
+	attributeWeight   = 1.5
+	descriptionWeight = 1.0
+)
+
+// How a query word may match an indexed term, and how much the match is worth
+const (
+	exactMatchWeight  = 1.0
+	prefixMatchWeight = 0.8
+	typoMatchWeight   = 0.6
+	maxPrefixTerms    = 50
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Final score blend. Relevance is normalized to 0-1 against the best hit and
+// recency halves every recencyHalfLife since the last bump.
+const (
+	relevanceShare  = 0.75
+	recencyShare    = 0.15
+	featuredShare   = 0.10
+	recencyHalfLife = 7 * 24 * time.Hour
+)
+
+type indexedProduct struct {
+	id          string
+	gameTitleID string
+	productType string
+	status      string
+	price       float64
+	priceMax    float64
+	featured    bool
+	bumpedAt    time.Time
+	attributes  map[string]interface{}
+	length      float64
+	terms       map[string]float64
+}
+
+type indexData struct {
+	docs        map[string]*indexedProduct
+	postings    map[string]map[string]float64 // term -> product ID -> weighted term frequency
+	vocabulary  []string                      // sorted, for prefix lookups
+	totalLength float64
+}
+
+func newIndexData() *indexData {
+	return &indexData{
+		docs:     map[string]*indexedProduct{},
+		postings: map[string]map[string]float64{},
+	}
+}
+
+// InvertedIndex is an in-process ProductSearchIndex. Each API instance holds
+// its own copy, so it is rebuilt from Firestore at startup and periodically.
+type InvertedIndex struct {
+	mu   sync.RWMutex
+	data *indexData
+	now  func() time.Time
+}
+
+func NewInvertedIndex() *InvertedIndex {
+	return &InvertedIndex{
+		data: newIndexData(),
+		now:  time.Now,
+	}
+}
+
+func (idx *InvertedIndex) Index(ctx context.Context, product *entity.Product) error {
+	if product == nil || product.ID == "" {
+		return nil
+	}
+
+	doc := analyzeProduct(product)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.data.remove(product.ID)
+	if product.DeletedAt == nil && product.Status != "deleted" {
+		idx.data.add(doc)
+	}
+
+	return nil
+}
+
+func (idx *InvertedIndex) Remove(ctx context.Context, productID string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.data.remove(productID)
+	return nil
+}
+
+func (idx *InvertedIndex) Rebuild(ctx context.Context, products []*entity.Product) error {
+	data := newIndexData()
+	for _, product := range products {
+		if product == nil || product.ID == "" || product.DeletedAt != nil || product.Status == "deleted" {
+			continue
+		}
+		data.remove(product.ID)
+		data.add(analyzeProduct(product))
+	}
+
+	idx.mu.Lock()
+	idx.data = data
+	idx.mu.Unlock()
+
+	return nil
+}
+
+// Len returns the number of indexed products
+func (idx *InvertedIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.data.docs)
+}
+
+type scoredProduct struct {
+	doc       *indexedProduct
+	relevance float64
+	score     float64
+}
+
+func (idx *InvertedIndex) Search(ctx context.Context, query service.ProductSearchQuery) (*service.ProductSearchResult, error) {
+	result := &service.ProductSearchResult{Hits: []service.ProductSearchHit{}}
+
+	queryTerms := uniqueTerms(Tokenize(query.Text))
+	if len(queryTerms) == 0 {
+		return result, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	data := idx.data
+	if len(data.docs) == 0 {
+		return result, nil
+	}
+
+	avgLength := data.totalLength / float64(len(data.docs))
+
+	relevance := map[string]float64{}
+	matchedTerms := map[string]int{}
+
+	for _, queryTerm := range queryTerms {
+		best := map[string]float64{}
+		for term, matchWeight := range data.expand(queryTerm) {
+			postings := data.postings[term]
+			idf := math.Log(1 + (float64(len(data.docs))-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+
+			for productID, tf := range postings {
+				doc := data.docs[productID]
+				norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*doc.length/avgLength))
+				if score := matchWeight * idf * norm; score > best[productID] {
+					best[productID] = score
+				}
+			}
+		}
+
+		for productID, score := range best {
+			relevance[productID] += score
+			matchedTerms[productID]++
+		}
+	}
+
+	// Every query word should match; allow one miss in longer queries
+	required := len(queryTerms)
+	if required > 2 {
+		required--
+	}
+
+	now := idx.now()
+	matches := []*scoredProduct{}
+	maxRelevance := 0.0
+
+	for productID, score := range relevance {
+		if matchedTerms[productID] < required {
+			continue
+		}
+
+		doc := data.docs[productID]
+		if !doc.matches(query) {
+			continue
+		}
+
+		coverage := float64(matchedTerms[productID]) / float64(len(queryTerms))
+		score *= coverage
+
+		matches = append(matches, &scoredProduct{doc: doc, relevance: score})
+		maxRelevance = math.Max(maxRelevance, score)
+	}
+
+	for _, match := range matches {
+		match.score = relevanceShare*match.relevance/maxRelevance + recencyShare*recencyBoost(match.doc.bumpedAt, now)
+		if match.doc.featured {
+			match.score += featuredShare
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if !matches[i].doc.bumpedAt.Equal(matches[j].doc.bumpedAt) {
+			return matches[i].doc.bumpedAt.After(matches[j].doc.bumpedAt)
+		}
+		return matches[i].doc.id < matches[j].doc.id
+	})
+
+	result.Total = int64(len(matches))
+
+	start := query.Offset
+	if start < 0 {
+		start = 0
+	}
+	end := len(matches)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+
+	for i := start; i < end; i++ {
+		result.Hits = append(result.Hits, service.ProductSearchHit{
+			ProductID:  matches[i].doc.id,
+			Score:      math.Round(matches[i].score*10000) / 10000,
+			Attributes: matches[i].doc.attributes,
+		})
+	}
+
+	return result, nil
+}
+
+// expand returns the indexed terms a query word matches with the weight of
+// each match. Prefix matches serve search-as-you-type; typo matches are only
+// tried when the word has no exact or prefix match.
+func (d *indexData) expand(queryTerm string) map[string]float64 {
+	terms := map[string]float64{}
+
+	if _, ok := d.postings[queryTerm]; ok {
+		terms[queryTerm] = exactMatchWeight
+	}
+
+	if len([]rune(queryTerm)) >= 2 {
+		i := sort.SearchStrings(d.vocabulary, queryTerm)
+		for count := 0; i < len(d.vocabulary) && count < maxPrefixTerms; i++ {
+			term := d.vocabulary[i]
+			if !strings.HasPrefix(term, queryTerm) {
+				break
+			}
+			if term != queryTerm {
+				terms[term] = prefixMatchWeight
+				count++
+			}
+		}
+	}
+
+	if len(terms) > 0 {
+		return terms
+	}
+
+	maxEdits := typoAllowance(queryTerm)
+	if maxEdits == 0 {
+		return terms
+	}
+
+	for _, term := range d.vocabulary {
+		if distance := editDistance(queryTerm, term, maxEdits); distance <= maxEdits {
+			terms[term] = typoMatchWeight / float64(distance)
+		}
+	}
+
+	return terms
+}
+
+// typoAllowance permits no typos in short words, one from four letters and
+// two from eight
+func typoAllowance(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d *indexData) add(doc *indexedProduct) {
+	d.docs[doc.id] = doc
+	d.totalLength += doc.length
+
+	for term, tf := range doc.terms {
+		postings, ok := d.postings[term]
+		if !ok {
+			postings = map[string]float64{}
+			d.postings[term] = postings
+
+			i := sort.SearchStrings(d.vocabulary, term)
+			d.vocabulary = append(d.vocabulary, "")
+			copy(d.vocabulary[i+1:], d.vocabulary[i:])
+			d.vocabulary[i] = term
+		}
+		postings[doc.id] = tf
+	}
+}
+
+func (d *indexData) remove(productID string) {
+	doc, ok := d.docs[productID]
+	if !ok {
+		return
+	}
+
+	delete(d.docs, productID)
+	d.totalLength -= doc.length
+
+	for term := range doc.terms {
+		postings := d.postings[term]
+		delete(postings, productID)
+		if len(postings) == 0 {
+			delete(d.postings, term)
+
+			i := sort.SearchStrings(d.vocabulary, term)
+			if i < len(d.vocabulary) && d.vocabulary[i] == term {
+				d.vocabulary = append(d.vocabulary[:i], d.vocabulary[i+1:]...)
+			}
+		}
+	}
+}
+
+func (doc *indexedProduct) matches(query service.ProductSearchQuery) bool {
+	if query.GameTitleID != "" && doc.gameTitleID != query.GameTitleID {
+		return false
+	}
+	if query.Type != "" && doc.productType != query.Type {
+		return false
+	}
+	if query.Status != "" && doc.status != query.Status {
+		return false
+	}
+	if query.MinPrice > 0 && doc.priceMax < query.MinPrice {
+		return false
+	}
+	if query.MaxPrice > 0 && doc.price > query.MaxPrice {
+		return false
+	}
+	return true
+}
+
+func analyzeProduct(product *entity.Product) *indexedProduct {
+	doc := &indexedProduct{
+		id:          product.ID,
+		gameTitleID: product.GameTitleID,
+		productType: product.Type,
+		status:      product.Status,
+		price:       product.Price,
+		priceMax:    product.HighestPrice(),
+		featured:    product.Featured,
+		bumpedAt:    product.BumpedAt,
+		attributes:  product.Attributes,
+		terms:       map[string]float64{},
+	}
+
+	if doc.bumpedAt.IsZero() {
+		doc.bumpedAt = product.CreatedAt
+	}
+
+	addTerms := func(text string, weight float64) {
+		for _, term := range Tokenize(text) {
+			doc.terms[term] += weight
+			doc.length += weight
+		}
+	}
+
+	addTerms(product.Title, titleWeight)
+	addTerms(product.Description, descriptionWeight)
+
+	// Variant labels such as "86 Diamonds" are searchable like attributes
+	for _, variant := range product.Variants {
+		addTerms(variant.Label, attributeWeight)
+	}
+
+	// Attribute values such as rank, server or hero names are searchable too
+	for _, value := range product.Attributes {
+		switch v := value.(type) {
+		case string:
+			addTerms(v, attributeWeight)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					addTerms(s, attributeWeight)
+				}
+			}
+		case bool:
+			// true/false carries no searchable meaning
+		default:
+			addTerms(fmt.Sprint(v), attributeWeight)
+		}
+	}
+
+	return doc
+}
+
+func recencyBoost(bumpedAt, now time.Time) float64 {
+	if bumpedAt.IsZero() {
+		return 0
+	}
+	age := now.Sub(bumpedAt)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
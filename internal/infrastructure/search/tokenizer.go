@@ -0,0 +1,123 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common Indonesian and English words that carry no meaning in
+// listing titles and descriptions
+var stopWords = map[string]bool{
+	// Indonesian
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "untuk": true,
+	"dengan": true, "atau": true, "ini": true, "itu": true, "ada": true, "juga": true,
+	"sudah": true, "udah": true, "akan": true, "pada": true, "dalam": true, "bisa": true,
+	"tidak": true, "gak": true, "ga": true, "aja": true, "saja": true, "nya": true,
+	"jual": true, "dijual": true, "murah": true, "kak": true, "gan": true,
+	// English
+	"the": true, "and": true, "or": true, "for": true, "with": true, "of": true,
+	"to": true, "in": true, "on": true, "a": true, "an": true, "is": true,
+	"are": true, "sale": true, "cheap": true,
+}
+
+// Tokenize lowercases text, splits it on anything that is not a letter or
+// digit, drops stop words and reduces each word to its stem. Query text and
+// indexed text go through the same path so their stems line up.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		if len([]rune(word)) < 2 && !unicode.IsDigit([]rune(word)[0]) {
+			continue
+		}
+		tokens = append(tokens, Stem(word))
+	}
+
+	return tokens
+}
+
+// Stem strips Indonesian particles and possessives and common English
+// inflections. Prefixes are left alone: Indonesian prefix stripping turns
+// game terms like "diamond" into noise.
+func Stem(word string) string {
+	for _, suffix := range []string{"nya", "lah", "kah", "pun"} {
+		if trimmed, ok := trimSuffix(word, suffix, 4); ok {
+			word = trimmed
+			break
+		}
+	}
+
+	if trimmed, ok := trimSuffix(word, "kan", 4); ok {
+		return trimmed
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ing") && len(word) > 6:
+		return word[:len(word)-3]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3:
+		return word[:len(word)-1]
+	}
+
+	return word
+}
+
+func trimSuffix(word, suffix string, minStem int) (string, bool) {
+	if !strings.HasSuffix(word, suffix) || len(word)-len(suffix) < minStem {
+		return word, false
+	}
+	return word[:len(word)-len(suffix)], true
+}
+
+// editDistance is the optimal string alignment distance between a and b,
+// so a swap of two adjacent letters counts as one edit. It stops early and
+// returns max+1 once the distance is known to exceed max.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > max {
+		return max + 1
+	}
+
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	return prev[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
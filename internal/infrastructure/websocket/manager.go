@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
 
 	"github.com/gorilla/websocket"
 )
@@ -31,6 +32,18 @@ type Manager struct {
 	chatRoomClients map[string]map[string]*Client // chatID -> userID -> *Client
 	mutex           sync.RWMutex
 	userRepo        repository.UserRepository
+	contentScreener ContentScreener
+}
+
+// ContentScreener checks user chat messages before they are broadcast
+type ContentScreener interface {
+	ScreenMessage(ctx context.Context, userID, chatID, content, source string) *service.ContentScanResult
+}
+
+// SetContentScreener plugs in the chat content screener. The manager is
+// created before the use cases, so it is set after construction.
+func (m *Manager) SetContentScreener(screener ContentScreener) {
+	m.contentScreener = screener
 }
 
 // NewManager creates a new WebSocket connection manager
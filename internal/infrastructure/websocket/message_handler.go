@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"log"
 	"time"
+
+	"pasargamex/internal/domain/service"
 )
 
 // WebSocket Message Types
@@ -55,6 +57,8 @@ type MessageData struct {
 	Timestamp      string                 `json:"timestamp"`
 	AttachmentURLs []string               `json:"attachment_urls,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	ContentWarning string                 `json:"content_warning,omitempty"`
+	ContentFlags   []string               `json:"content_flags,omitempty"`
 }
 
 type TypingData struct {
@@ -186,6 +190,16 @@ func (m *Manager) handleSendMessage(client *Client, data interface{}) {
 		return
 	}
 
+	// Screen for off-platform payment solicitation before broadcasting
+	var scan *service.ContentScanResult
+	if m.contentScreener != nil {
+		scan = m.contentScreener.ScreenMessage(context.Background(), client.UserID, sendMsgData.ChatID, sendMsgData.Content, "websocket")
+		if scan.Action == service.ContentActionBlock {
+			m.sendErrorToClient(client, scan.Warning)
+			return
+		}
+	}
+
 	// Get user info from repository
 	user, err := m.userRepo.GetByID(context.Background(), client.UserID)
 	if err != nil {
@@ -212,6 +226,11 @@ func (m *Manager) handleSendMessage(client *Client, data interface{}) {
 		Metadata:       map[string]interface{}{},
 	}
 
+	if scan != nil && scan.Action == service.ContentActionWarn {
+		messageData.ContentWarning = scan.Warning
+		messageData.ContentFlags = scan.Categories()
+	}
+
 	// TODO: Save message to database via message service
 	// err = m.messageService.CreateMessage(ctx, messageData)
 	// if err != nil {
@@ -11,17 +11,20 @@ import (
 )
 
 type AntiFraudUseCase struct {
-	transactionRepo repository.TransactionRepository
-	userRepo        repository.UserRepository
+	transactionRepo    repository.TransactionRepository
+	userRepo           repository.UserRepository
+	securityLogUseCase *SecurityLogUseCase
 }
 
 func NewAntiFraudUseCase(
 	transactionRepo repository.TransactionRepository,
 	userRepo repository.UserRepository,
+	securityLogUseCase *SecurityLogUseCase,
 ) *AntiFraudUseCase {
 	return &AntiFraudUseCase{
-		transactionRepo: transactionRepo,
-		userRepo:        userRepo,
+		transactionRepo:    transactionRepo,
+		userRepo:           userRepo,
+		securityLogUseCase: securityLogUseCase,
 	}
 }
 
@@ -120,7 +123,8 @@ func (uc *AntiFraudUseCase) analyzeUserDisputePattern(ctx context.Context, userI
 	return pattern, nil
 }
 
-// ImplementCredentialAccessLogging logs all credential access with metadata
+// ImplementCredentialAccessLogging logs all credential access with metadata.
+// The view itself is counted by ReserveCredentialView before the reveal.
 func (uc *AntiFraudUseCase) LogCredentialAccess(ctx context.Context, transactionID, userID, ipAddress, userAgent string) error {
 	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
@@ -128,30 +132,49 @@ func (uc *AntiFraudUseCase) LogCredentialAccess(ctx context.Context, transaction
 	}
 
 	now := time.Now()
-	transaction.CredentialsAccessed = true
-	transaction.CredentialsAccessedAt = &now
 
 	// SECURITY: Log credential access for audit
 	log.Printf("CREDENTIAL ACCESS: TransactionID=%s, UserID=%s, IP=%s, UserAgent=%s, Time=%s", 
 		transactionID, userID, ipAddress, userAgent, now.Format(time.RFC3339))
 
+	if err := uc.transactionRepo.CreateCredentialAccessLog(ctx, &entity.CredentialAccessLog{
+		TransactionID: transactionID,
+		UserID:        userID,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+		AccessedAt:    now,
+	}); err != nil {
+		return err
+	}
+
+	uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+		UserID:        userID,
+		EventType:     SecurityEventCredentialReveal,
+		TransactionID: transactionID,
+		Details:       map[string]interface{}{"view_count": transaction.CredentialsViewCount},
+	})
+
+	// Only the first reveal counts as the access time for fraud checks
+	if transaction.CredentialsAccessed {
+		return nil
+	}
+
 	// Check for suspicious access patterns
+	var securityFlags []string
 	if transaction.CredentialsDeliveredAt != nil {
 		accessDelay := now.Sub(*transaction.CredentialsDeliveredAt)
 		if accessDelay > 24*time.Hour {
-			transaction.SecurityFlags = append(transaction.SecurityFlags, "delayed_credential_access")
+			securityFlags = append(securityFlags, "delayed_credential_access")
 			log.Printf("SECURITY ALERT: Delayed credential access (%v) for transaction %s", accessDelay, transactionID)
 		}
 		
 		if accessDelay < 1*time.Minute {
-			transaction.SecurityFlags = append(transaction.SecurityFlags, "immediate_credential_access")
+			securityFlags = append(securityFlags, "immediate_credential_access")
 			// This could be normal, but worth tracking
 		}
 	}
 
-	// TODO: Store access logs in separate audit table for legal compliance
-
-	return uc.transactionRepo.Update(ctx, transaction)
+	return uc.transactionRepo.MarkCredentialsAccessed(ctx, transactionID, now, securityFlags)
 }
 
 // PreventRefundAbuse implements checks before processing refunds
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// Approval steps recorded against a transaction. Each step maps to the
+// approver type listed in Transaction.RequiredApprovals.
+const (
+	ApprovalStepDeliveryConfirmed    = "delivery_confirmed"    // seller
+	ApprovalStepVerificationComplete = "verification_complete" // middleman
+	ApprovalStepItemReceived         = "item_received"         // buyer
+	ApprovalStepSystemDelivery       = "system_delivery"       // system (instant delivery)
+)
+
+// RequiredApprovalsFor returns the approver types that must sign off before
+// escrow is released for a delivery method
+func RequiredApprovalsFor(deliveryMethod string) []string {
+	if deliveryMethod == "instant" {
+		return []string{"system"} // Automatic
+	}
+
+	return []string{"seller", "middleman", "buyer"}
+}
+
+// LegacyRequiredApprovalsFor returns the approvals the /transactions flow can
+// collect. It has no seller delivery step: the middleman receives and
+// verifies the seller's credentials, then the buyer confirms.
+func LegacyRequiredApprovalsFor(deliveryMethod string) []string {
+	if deliveryMethod == "instant" {
+		return RequiredApprovalsFor(deliveryMethod)
+	}
+
+	return []string{"middleman", "buyer"}
+}
+
+type ApprovalUseCase struct {
+	transactionRepo repository.TransactionRepository
+}
+
+func NewApprovalUseCase(transactionRepo repository.TransactionRepository) *ApprovalUseCase {
+	return &ApprovalUseCase{
+		transactionRepo: transactionRepo,
+	}
+}
+
+// ApprovalEvidence carries the request context stored with every approval
+type ApprovalEvidence struct {
+	EvidenceURL string
+	Notes       string
+	IPAddress   string
+	UserAgent   string
+}
+
+type RecordApprovalInput struct {
+	ApproverType string // seller, middleman, buyer, system
+	ApproverID   string
+	ApprovalStep string
+	Approved     bool
+	Evidence     ApprovalEvidence
+	Metadata     map[string]interface{}
+}
+
+// RecordApproval stores an approval record and, when approved, marks the
+// approver type as completed on the transaction.
+func (uc *ApprovalUseCase) RecordApproval(ctx context.Context, transaction *entity.Transaction, input RecordApprovalInput) (*entity.TransactionApproval, error) {
+	now := time.Now()
+
+	approval := &entity.TransactionApproval{
+		TransactionID: transaction.ID,
+		ApproverType:  input.ApproverType,
+		ApproverID:    input.ApproverID,
+		ApprovalStep:  input.ApprovalStep,
+		Status:        "rejected",
+		EvidenceURL:   input.Evidence.EvidenceURL,
+		Notes:         input.Evidence.Notes,
+		IPAddress:     input.Evidence.IPAddress,
+		UserAgent:     input.Evidence.UserAgent,
+		Metadata:      input.Metadata,
+	}
+
+	if input.Approved {
+		approval.Status = "approved"
+		approval.ApprovedAt = &now
+	}
+
+	if err := uc.transactionRepo.CreateApproval(ctx, approval); err != nil {
+		return nil, err
+	}
+
+	if input.Approved && !containsString(transaction.CompletedApprovals, input.ApproverType) {
+		transaction.CompletedApprovals = append(transaction.CompletedApprovals, input.ApproverType)
+	}
+
+	log.Printf("Approval recorded for transaction %s: type=%s, step=%s, status=%s",
+		transaction.ID, approval.ApproverType, approval.ApprovalStep, approval.Status)
+
+	return approval, nil
+}
+
+// MissingApprovals returns the required approver types that do not yet have an
+// approved record. The approval records are the source of truth; the
+// CompletedApprovals field on the transaction is kept in sync from them.
+func (uc *ApprovalUseCase) MissingApprovals(ctx context.Context, transaction *entity.Transaction) ([]string, error) {
+	if len(transaction.RequiredApprovals) == 0 {
+		return []string{}, nil
+	}
+
+	approvals, err := uc.transactionRepo.GetApprovalsByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	approved := make(map[string]bool)
+	for _, approval := range approvals {
+		if approval.Status == "approved" {
+			approved[approval.ApproverType] = true
+		}
+	}
+
+	completed := []string{}
+	missing := []string{}
+	for _, required := range transaction.RequiredApprovals {
+		if approved[required] {
+			completed = append(completed, required)
+		} else {
+			missing = append(missing, required)
+		}
+	}
+	transaction.CompletedApprovals = completed
+
+	return missing, nil
+}
+
+// EnsureReleasable blocks escrow release until every required approval exists
+func (uc *ApprovalUseCase) EnsureReleasable(ctx context.Context, transaction *entity.Transaction) error {
+	missing, err := uc.MissingApprovals(ctx, transaction)
+	if err != nil {
+		return errors.Internal("Failed to check transaction approvals", err)
+	}
+
+	if len(missing) > 0 {
+		return errors.BadRequest("Escrow release requires approvals from: "+strings.Join(missing, ", "), nil)
+	}
+
+	return nil
+}
+
+// GetApprovalTrail returns the approval history of a transaction
+func (uc *ApprovalUseCase) GetApprovalTrail(ctx context.Context, transactionID string) ([]*entity.TransactionApproval, error) {
+	approvals, err := uc.transactionRepo.GetApprovalsByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if approvals == nil {
+		approvals = []*entity.TransactionApproval{}
+	}
+
+	return approvals, nil
+}
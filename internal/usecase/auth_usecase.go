@@ -10,14 +10,16 @@ import (
 )
 
 type AuthUseCase struct {
-	userRepo     repository.UserRepository
-	firebaseAuth FirebaseAuthClient
+	userRepo           repository.UserRepository
+	firebaseAuth       FirebaseAuthClient
+	securityLogUseCase *SecurityLogUseCase
 }
 
-func NewAuthUseCase(userRepo repository.UserRepository, firebaseAuth FirebaseAuthClient) *AuthUseCase {
+func NewAuthUseCase(userRepo repository.UserRepository, firebaseAuth FirebaseAuthClient, securityLogUseCase *SecurityLogUseCase) *AuthUseCase {
 	return &AuthUseCase{
-		userRepo:     userRepo,
-		firebaseAuth: firebaseAuth,
+		userRepo:           userRepo,
+		firebaseAuth:       firebaseAuth,
+		securityLogUseCase: securityLogUseCase,
 	}
 }
 
@@ -62,6 +64,12 @@ func (uc *AuthUseCase) Login(ctx context.Context, email, password string) (*Auth
 		// log.Printf("Failed to update user last seen: %v", err)
 	}
 
+	uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+		UserID:    user.ID,
+		EventType: SecurityEventLogin,
+		Details:   map[string]interface{}{"provider": "email"},
+	})
+
 	return &AuthResult{
 		User:         user,
 		Token:        token,
@@ -117,6 +125,13 @@ func (uc *AuthUseCase) Register(ctx context.Context, input RegisterInput) (*Auth
 		return nil, errors.Internal("Failed to generate authentication token", err)
 	}
 
+	// Registration signs the user in, so it is recorded as their first login
+	uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+		UserID:    user.ID,
+		EventType: SecurityEventLogin,
+		Details:   map[string]interface{}{"provider": "email", "registration": true},
+	})
+
 	return &AuthResult{
 		User:         user,
 		Token:        token,
@@ -189,6 +204,12 @@ func (uc *AuthUseCase) CreateOrUpdateUserFromFirebaseOAuth(ctx context.Context,
 		if err != nil {
 			return nil, errors.Internal("Failed to generate token", err)
 		}
+
+		uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+			UserID:    uid,
+			EventType: SecurityEventLogin,
+			Details:   map[string]interface{}{"provider": existingUser.Provider},
+		})
 		
 		return &AuthResult{
 			User:  existingUser,
@@ -227,6 +248,12 @@ func (uc *AuthUseCase) CreateOrUpdateUserFromFirebaseOAuth(ctx context.Context,
 	if err != nil {
 		return nil, errors.Internal("Failed to generate token", err)
 	}
+
+	uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+		UserID:    uid,
+		EventType: SecurityEventLogin,
+		Details:   map[string]interface{}{"provider": provider, "registration": true},
+	})
 	
 	return &AuthResult{
 		User:  user,
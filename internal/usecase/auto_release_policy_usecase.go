@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// DefaultAutoReleasePolicy applies when no admin policy matches a transaction
+var DefaultAutoReleasePolicy = entity.AutoReleasePolicy{
+	ID:              "default",
+	Name:            "Default",
+	InspectionHours: 24,
+	ExtensionHours:  24,
+	WarningHours:    2,
+	IsActive:        true,
+}
+
+const maxInspectionHours = 30 * 24
+
+type AutoReleasePolicyUseCase struct {
+	policyRepo  repository.AutoReleasePolicyRepository
+	productRepo repository.ProductRepository
+}
+
+func NewAutoReleasePolicyUseCase(policyRepo repository.AutoReleasePolicyRepository, productRepo repository.ProductRepository) *AutoReleasePolicyUseCase {
+	return &AutoReleasePolicyUseCase{
+		policyRepo:  policyRepo,
+		productRepo: productRepo,
+	}
+}
+
+type AutoReleasePolicyInput struct {
+	Name            string
+	SecurityLevel   string
+	GameTitleID     string
+	ProductType     string
+	MinAmount       float64
+	MaxAmount       float64
+	InspectionHours int
+	ExtensionHours  int
+	WarningHours    int
+	Priority        int
+	IsActive        bool
+}
+
+func (uc *AutoReleasePolicyUseCase) CreatePolicy(ctx context.Context, adminID string, input AutoReleasePolicyInput) (*entity.AutoReleasePolicy, error) {
+	if err := validateAutoReleasePolicyInput(input); err != nil {
+		return nil, err
+	}
+
+	policy := &entity.AutoReleasePolicy{CreatedBy: adminID}
+	applyAutoReleasePolicyInput(policy, input)
+
+	if err := uc.policyRepo.Create(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Auto-release policy %s created by %s", policy.ID, adminID)
+	return policy, nil
+}
+
+func (uc *AutoReleasePolicyUseCase) UpdatePolicy(ctx context.Context, id string, input AutoReleasePolicyInput) (*entity.AutoReleasePolicy, error) {
+	policy, err := uc.policyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateAutoReleasePolicyInput(input); err != nil {
+		return nil, err
+	}
+
+	applyAutoReleasePolicyInput(policy, input)
+
+	if err := uc.policyRepo.Update(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (uc *AutoReleasePolicyUseCase) DeletePolicy(ctx context.Context, id string) error {
+	if _, err := uc.policyRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	return uc.policyRepo.Delete(ctx, id)
+}
+
+func (uc *AutoReleasePolicyUseCase) GetPolicy(ctx context.Context, id string) (*entity.AutoReleasePolicy, error) {
+	return uc.policyRepo.GetByID(ctx, id)
+}
+
+func (uc *AutoReleasePolicyUseCase) ListPolicies(ctx context.Context) ([]*entity.AutoReleasePolicy, error) {
+	return uc.policyRepo.List(ctx, false)
+}
+
+// ResolvePolicy picks the most specific active policy for a transaction,
+// falling back to DefaultAutoReleasePolicy
+func (uc *AutoReleasePolicyUseCase) ResolvePolicy(ctx context.Context, transaction *entity.Transaction) (*entity.AutoReleasePolicy, error) {
+	policies, err := uc.policyRepo.List(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	gameTitleID, productType := "", ""
+	if product, err := uc.productRepo.GetByID(ctx, transaction.ProductID); err == nil {
+		gameTitleID, productType = product.GameTitleID, product.Type
+	} else {
+		log.Printf("Failed to load product %s for auto-release policy: %v", transaction.ProductID, err)
+	}
+
+	candidates := []*entity.AutoReleasePolicy{}
+	for _, policy := range policies {
+		if policyMatches(policy, transaction, gameTitleID, productType) {
+			candidates = append(candidates, policy)
+		}
+	}
+
+	if len(candidates) == 0 {
+		policy := DefaultAutoReleasePolicy
+		return &policy, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := policySpecificity(candidates[i]), policySpecificity(candidates[j])
+		if si != sj {
+			return si > sj
+		}
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
+	return candidates[0], nil
+}
+
+// PolicyForTransaction returns the policy recorded on the transaction at
+// delivery time, or the default when it has since been deleted
+func (uc *AutoReleasePolicyUseCase) PolicyForTransaction(ctx context.Context, transaction *entity.Transaction) *entity.AutoReleasePolicy {
+	if transaction.AutoReleasePolicyID != "" && transaction.AutoReleasePolicyID != DefaultAutoReleasePolicy.ID {
+		if policy, err := uc.policyRepo.GetByID(ctx, transaction.AutoReleasePolicyID); err == nil {
+			return policy
+		}
+	}
+
+	policy := DefaultAutoReleasePolicy
+	return &policy
+}
+
+// MaxWarningHours returns the longest warning window of the default policy
+// and every stored policy, active or not, since transactions keep the policy
+// they were delivered under
+func (uc *AutoReleasePolicyUseCase) MaxWarningHours(ctx context.Context) (int, error) {
+	policies, err := uc.policyRepo.List(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+
+	hours := DefaultAutoReleasePolicy.WarningHours
+	for _, policy := range policies {
+		hours = max(hours, policy.WarningHours)
+	}
+
+	return hours, nil
+}
+
+// InspectionWindow returns the time between delivery and auto-release
+func InspectionWindow(policy *entity.AutoReleasePolicy) time.Duration {
+	return time.Duration(policy.InspectionHours) * time.Hour
+}
+
+func policyMatches(policy *entity.AutoReleasePolicy, transaction *entity.Transaction, gameTitleID, productType string) bool {
+	if policy.SecurityLevel != "" && policy.SecurityLevel != transaction.SecurityLevel {
+		return false
+	}
+	if policy.GameTitleID != "" && policy.GameTitleID != gameTitleID {
+		return false
+	}
+	if policy.ProductType != "" && policy.ProductType != productType {
+		return false
+	}
+	if transaction.Amount < policy.MinAmount {
+		return false
+	}
+	if policy.MaxAmount > 0 && transaction.Amount > policy.MaxAmount {
+		return false
+	}
+	return true
+}
+
+func policySpecificity(policy *entity.AutoReleasePolicy) int {
+	score := 0
+	if policy.SecurityLevel != "" {
+		score++
+	}
+	if policy.GameTitleID != "" {
+		score++
+	}
+	if policy.ProductType != "" {
+		score++
+	}
+	if policy.MinAmount > 0 || policy.MaxAmount > 0 {
+		score++
+	}
+	return score
+}
+
+func validateAutoReleasePolicyInput(input AutoReleasePolicyInput) error {
+	if input.Name == "" {
+		return errors.BadRequest("Policy name is required", nil)
+	}
+
+	switch input.SecurityLevel {
+	case "", "low", "medium", "high":
+	default:
+		return errors.BadRequest("Security level must be low, medium or high", nil)
+	}
+
+	if input.InspectionHours <= 0 || input.InspectionHours > maxInspectionHours {
+		return errors.BadRequest("Inspection hours must be between 1 and 720", nil)
+	}
+
+	if input.ExtensionHours < 0 || input.ExtensionHours > maxInspectionHours {
+		return errors.BadRequest("Extension hours must be between 0 and 720", nil)
+	}
+
+	if input.WarningHours < 0 || input.WarningHours >= input.InspectionHours {
+		return errors.BadRequest("Warning hours must be shorter than the inspection window", nil)
+	}
+
+	if input.MinAmount < 0 || input.MaxAmount < 0 || (input.MaxAmount > 0 && input.MaxAmount < input.MinAmount) {
+		return errors.BadRequest("Invalid amount band", nil)
+	}
+
+	return nil
+}
+
+func applyAutoReleasePolicyInput(policy *entity.AutoReleasePolicy, input AutoReleasePolicyInput) {
+	policy.Name = input.Name
+	policy.SecurityLevel = input.SecurityLevel
+	policy.GameTitleID = input.GameTitleID
+	policy.ProductType = input.ProductType
+	policy.MinAmount = input.MinAmount
+	policy.MaxAmount = input.MaxAmount
+	policy.InspectionHours = input.InspectionHours
+	policy.ExtensionHours = input.ExtensionHours
+	policy.WarningHours = input.WarningHours
+	policy.Priority = input.Priority
+	policy.IsActive = input.IsActive
+}
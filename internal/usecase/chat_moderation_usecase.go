@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
+	"pasargamex/pkg/errors"
+)
+
+// ContentWarningBanner is shown with chat messages that look like an attempt
+// to move the deal off the platform
+const ContentWarningBanner = "⚠️ Be careful: this message may ask you to contact or pay the other party outside PasargameX. Payments made outside the platform are not protected by escrow."
+
+// ContentBlockedMessage is returned to senders whose message was blocked
+const ContentBlockedMessage = "Message blocked: sharing bank details or messaging-app links is not allowed. Please keep payments on PasargameX."
+
+const (
+	ChatOffenderPendingReview = "pending_review"
+	ChatOffenderResolved      = "resolved"
+)
+
+const (
+	// Users with this many violations inside the window are flagged for review
+	chatOffenderThreshold = 3
+	chatOffenderWindow    = 7 * 24 * time.Hour
+)
+
+type ChatModerationUseCase struct {
+	scanner        service.ContentScanner
+	moderationRepo repository.ChatModerationRepository
+}
+
+func NewChatModerationUseCase(scanner service.ContentScanner, moderationRepo repository.ChatModerationRepository) *ChatModerationUseCase {
+	return &ChatModerationUseCase{
+		scanner:        scanner,
+		moderationRepo: moderationRepo,
+	}
+}
+
+// ScreenMessage scans a user's chat message, records a violation when it
+// warns or blocks, and flags repeat offenders. Recording failures never
+// block the message; the scan result is always returned.
+func (uc *ChatModerationUseCase) ScreenMessage(ctx context.Context, userID, chatID, content, source string) *service.ContentScanResult {
+	result := uc.scanner.Scan(ctx, content)
+	switch result.Action {
+	case service.ContentActionAllow:
+		return result
+	case service.ContentActionBlock:
+		result.Warning = ContentBlockedMessage
+	default:
+		result.Warning = ContentWarningBanner
+	}
+
+	matches := make([]string, len(result.Findings))
+	for i, finding := range result.Findings {
+		matches[i] = finding.Match
+	}
+
+	violation := &entity.ChatViolation{
+		UserID:     userID,
+		ChatID:     chatID,
+		Action:     result.Action,
+		Categories: result.Categories(),
+		Matches:    matches,
+		Source:     source,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := uc.moderationRepo.CreateViolation(ctx, violation); err != nil {
+		log.Printf("Failed to record chat violation for user %s: %v", userID, err)
+		return result
+	}
+
+	log.Printf("SECURITY: Chat message from %s in chat %s %sed: %v", userID, chatID, result.Action, violation.Categories)
+
+	if err := uc.flagRepeatOffender(ctx, userID, violation.CreatedAt); err != nil {
+		log.Printf("Failed to update chat offender %s: %v", userID, err)
+	}
+
+	return result
+}
+
+func (uc *ChatModerationUseCase) flagRepeatOffender(ctx context.Context, userID string, now time.Time) error {
+	recent, err := uc.moderationRepo.ListViolationsByUser(ctx, userID, now.Add(-chatOffenderWindow), 0)
+	if err != nil {
+		return err
+	}
+
+	offender, err := uc.moderationRepo.GetOffender(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, "NOT_FOUND") {
+			return err
+		}
+		if len(recent) < chatOffenderThreshold {
+			return nil
+		}
+		// Count the violations that led to the first flag
+		offender = &entity.ChatOffender{UserID: userID, ViolationCount: len(recent) - 1}
+	}
+
+	offender.ViolationCount++
+	offender.LastViolationAt = now
+
+	// Resolved offenders are flagged again once they cross the threshold anew
+	if offender.Status != ChatOffenderPendingReview && len(recent) >= chatOffenderThreshold &&
+		(offender.ReviewedAt == nil || countViolationsAfter(recent, *offender.ReviewedAt) >= chatOffenderThreshold) {
+		offender.Status = ChatOffenderPendingReview
+		offender.FlaggedAt = now
+		log.Printf("SECURITY: User %s flagged for review after %d chat violations in %v", userID, len(recent), chatOffenderWindow)
+	}
+
+	return uc.moderationRepo.SaveOffender(ctx, offender)
+}
+
+func countViolationsAfter(violations []*entity.ChatViolation, after time.Time) int {
+	count := 0
+	for _, violation := range violations {
+		if violation.CreatedAt.After(after) {
+			count++
+		}
+	}
+	return count
+}
+
+func (uc *ChatModerationUseCase) ListOffenders(ctx context.Context, status string, page, limit int) ([]*entity.ChatOffender, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return uc.moderationRepo.ListOffenders(ctx, status, limit, (page-1)*limit)
+}
+
+type ChatOffenderDetail struct {
+	*entity.ChatOffender
+	RecentViolations []*entity.ChatViolation `json:"recent_violations"`
+}
+
+// GetOffender returns an offender with their violations from the last 30 days
+func (uc *ChatModerationUseCase) GetOffender(ctx context.Context, userID string) (*ChatOffenderDetail, error) {
+	offender, err := uc.moderationRepo.GetOffender(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	violations, err := uc.moderationRepo.ListViolationsByUser(ctx, userID, time.Now().Add(-30*24*time.Hour), 100)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChatOffenderDetail{
+		ChatOffender:     offender,
+		RecentViolations: violations,
+	}, nil
+}
+
+// ResolveOffender closes the review of a flagged user
+func (uc *ChatModerationUseCase) ResolveOffender(ctx context.Context, adminID, userID, notes string) (*entity.ChatOffender, error) {
+	offender, err := uc.moderationRepo.GetOffender(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offender.Status != ChatOffenderPendingReview {
+		return nil, errors.BadRequest("Chat offender is not pending review", nil)
+	}
+
+	now := time.Now()
+	offender.Status = ChatOffenderResolved
+	offender.ReviewedBy = adminID
+	offender.ReviewNotes = notes
+	offender.ReviewedAt = &now
+
+	if err := uc.moderationRepo.SaveOffender(ctx, offender); err != nil {
+		return nil, err
+	}
+
+	return offender, nil
+}
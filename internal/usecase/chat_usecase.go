@@ -11,17 +11,21 @@ import (
 
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
 	"pasargamex/internal/infrastructure/ratelimit"
 	ws "pasargamex/internal/infrastructure/websocket"
 	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
 )
 
 type ChatUseCase struct {
-	chatRepo    repository.ChatRepository
-	userRepo    repository.UserRepository
-	productRepo repository.ProductRepository
-	wsManager   *ws.Manager
-	rateLimiter *ratelimit.RateLimiter
+	chatRepo          repository.ChatRepository
+	userRepo          repository.UserRepository
+	productRepo       repository.ProductRepository
+	wsManager         *ws.Manager
+	rateLimiter       *ratelimit.RateLimiter
+	moderationUseCase *ChatModerationUseCase
+	priceWatch        *PriceWatchUseCase
 }
 
 func NewChatUseCase(
@@ -29,16 +33,20 @@ func NewChatUseCase(
 	userRepo repository.UserRepository,
 	productRepo repository.ProductRepository,
 	wsManager *ws.Manager,
+	moderationUseCase *ChatModerationUseCase,
+	priceWatch *PriceWatchUseCase,
 ) *ChatUseCase {
 	rateLimiter := ratelimit.NewRateLimiter()
 	rateLimiter.StartCleanupRoutine() // Start cleanup routine
 
 	return &ChatUseCase{
-		chatRepo:    chatRepo,
-		userRepo:    userRepo,
-		productRepo: productRepo,
-		wsManager:   wsManager,
-		rateLimiter: rateLimiter,
+		chatRepo:          chatRepo,
+		userRepo:          userRepo,
+		productRepo:       productRepo,
+		wsManager:         wsManager,
+		rateLimiter:       rateLimiter,
+		moderationUseCase: moderationUseCase,
+		priceWatch:        priceWatch,
 	}
 }
 
@@ -599,6 +607,12 @@ func (uc *ChatUseCase) SendMessage(ctx context.Context, userID string, input Sen
 		return nil, errors.NotFound("Sender not found", err)
 	}
 
+	// Screen for off-platform payment solicitation
+	scan := uc.moderationUseCase.ScreenMessage(ctx, userID, input.ChatID, input.Content, "api")
+	if scan.Action == service.ContentActionBlock {
+		return nil, errors.BadRequest(scan.Warning, nil)
+	}
+
 	// Determine attachment URLs (use new field if provided, fallback to old field)
 	var attachmentURLs []string
 	if len(input.AttachmentURLs) > 0 {
@@ -621,6 +635,11 @@ func (uc *ChatUseCase) SendMessage(ctx context.Context, userID string, input Sen
 		CreatedAt:      time.Now(),
 	}
 
+	if scan.Action == service.ContentActionWarn {
+		message.ContentWarning = scan.Warning
+		message.ContentFlags = scan.Categories()
+	}
+
 	if err := uc.chatRepo.CreateMessage(ctx, message); err != nil {
 		log.Printf("SendMessage Error: Failed to create message for chat %s: %v", input.ChatID, err)
 		return nil, err
@@ -820,6 +839,7 @@ func (uc *ChatUseCase) AcceptOffer(ctx context.Context, chatID, messageID, userI
 				log.Printf("DEBUG: Updating product price from %.0f to %.0f IDR", originalPrice, newPrice)
 
 				// Update product with negotiated price
+				pricesBefore := SnapshotPrices(product)
 				product.Price = newPrice
 				product.UpdatedAt = time.Now()
 
@@ -828,6 +848,9 @@ func (uc *ChatUseCase) AcceptOffer(ctx context.Context, chatID, messageID, userI
 					// Don't fail the entire operation, just log the warning
 				} else {
 					log.Printf("SUCCESS: Product %s price updated from %.0f to %.0f IDR after offer acceptance", productID, originalPrice, newPrice)
+					if uc.priceWatch != nil {
+						uc.priceWatch.RecordPriceChanges(ctx, pricesBefore, product)
+					}
 				}
 			} else {
 				log.Printf("ERROR: Failed to get product %s: %v", productID, err)
@@ -1021,22 +1044,22 @@ func (uc *ChatUseCase) GetChatsByType(ctx context.Context, userID string, chatTy
 	return chatResponses, int64(len(filteredChats)), nil
 }
 
-func (uc *ChatUseCase) GetChatMessages(ctx context.Context, userID, chatID string, limit, offset int) ([]*MessageResponse, int64, error) {
+func (uc *ChatUseCase) GetChatMessages(ctx context.Context, userID, chatID string, pagination utils.PaginationParams) ([]*MessageResponse, *utils.PageInfo, error) {
 	chat, err := uc.chatRepo.GetByID(ctx, chatID)
 	if err != nil {
 		log.Printf("GetChatMessages Error: Chat %s not found: %v", chatID, err)
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	if !containsString(chat.Participants, userID) {
 		log.Printf("GetChatMessages Error: User %s is not a participant in chat %s", userID, chatID)
-		return nil, 0, errors.Forbidden("User is not a participant in this chat", nil)
+		return nil, nil, errors.Forbidden("User is not a participant in this chat", nil)
 	}
 
-	messages, total, err := uc.chatRepo.GetMessagesByChat(ctx, chatID, limit, offset)
+	messages, pageInfo, err := uc.chatRepo.GetMessagesByChatPage(ctx, chatID, pagination)
 	if err != nil {
 		log.Printf("GetChatMessages Error: Failed to get messages for chat %s: %v", chatID, err)
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	var messageResponses []*MessageResponse
@@ -1054,7 +1077,7 @@ func (uc *ChatUseCase) GetChatMessages(ctx context.Context, userID, chatID strin
 		messageResponses = append(messageResponses, messageResp)
 	}
 
-	return messageResponses, total, nil
+	return messageResponses, pageInfo, nil
 }
 
 func (uc *ChatUseCase) MarkChatAsRead(ctx context.Context, userID, chatID string) error {
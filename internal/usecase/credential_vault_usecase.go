@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
+	"pasargamex/pkg/errors"
+)
+
+// sealCredentials encrypts a credential map. Empty maps yield nil.
+func sealCredentials(ctx context.Context, vault service.CredentialVault, credentials map[string]interface{}) (*entity.EncryptedCredentials, error) {
+	if len(credentials) == 0 {
+		return nil, nil
+	}
+
+	sealed, err := vault.Seal(ctx, credentials)
+	if err != nil {
+		return nil, errors.Internal("Failed to encrypt credentials", err)
+	}
+
+	return sealed, nil
+}
+
+// productSealedCredentials returns the product's credentials in sealed form,
+// sealing legacy plaintext on the fly. Nothing is decrypted.
+func productSealedCredentials(ctx context.Context, vault service.CredentialVault, product *entity.Product) (*entity.EncryptedCredentials, error) {
+	if product.EncryptedCredentials != nil {
+		sealed := *product.EncryptedCredentials
+		return &sealed, nil
+	}
+	return sealCredentials(ctx, vault, product.Credentials)
+}
+
+type CredentialRotationUseCase struct {
+	productRepo     repository.ProductRepository
+	transactionRepo repository.TransactionRepository
+	vault           service.CredentialVault
+	keyProvider     service.KeyProvider
+}
+
+func NewCredentialRotationUseCase(
+	productRepo repository.ProductRepository,
+	transactionRepo repository.TransactionRepository,
+	vault service.CredentialVault,
+	keyProvider service.KeyProvider,
+) *CredentialRotationUseCase {
+	return &CredentialRotationUseCase{
+		productRepo:     productRepo,
+		transactionRepo: transactionRepo,
+		vault:           vault,
+		keyProvider:     keyProvider,
+	}
+}
+
+type CredentialRotationResult struct {
+	KeyID                   string    `json:"key_id,omitempty"`
+	ProductsScanned         int       `json:"products_scanned"`
+	ProductsReencrypted     int       `json:"products_reencrypted"`
+	TransactionsScanned     int       `json:"transactions_scanned"`
+	TransactionsReencrypted int       `json:"transactions_reencrypted"`
+	Failed                  int       `json:"failed"`
+	StartedAt               time.Time `json:"started_at"`
+	FinishedAt              time.Time `json:"finished_at"`
+}
+
+const credentialRotationBatchSize = 100
+
+// ReencryptAll re-seals every credential wrapped with a retired key and
+// migrates remaining plaintext credentials into sealed form. Each document
+// is re-sealed in its own transaction that writes only the credential
+// fields, so concurrent status or escrow changes are never overwritten.
+func (uc *CredentialRotationUseCase) ReencryptAll(ctx context.Context) (*CredentialRotationResult, error) {
+	result := &CredentialRotationResult{StartedAt: time.Now()}
+
+	afterID := ""
+	for {
+		products, err := uc.productRepo.ListPageByID(ctx, afterID, credentialRotationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, product := range products {
+			result.ProductsScanned++
+
+			changed, err := uc.productRepo.ResealCredentials(ctx, product.ID, uc.reseal(ctx))
+			if err != nil {
+				log.Printf("Failed to re-encrypt credentials for product %s: %v", product.ID, err)
+				result.Failed++
+				continue
+			}
+			if changed {
+				result.ProductsReencrypted++
+			}
+		}
+
+		if len(products) < credentialRotationBatchSize {
+			break
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	afterID = ""
+	for {
+		transactions, err := uc.transactionRepo.ListPageByID(ctx, afterID, credentialRotationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			result.TransactionsScanned++
+
+			changed, err := uc.transactionRepo.ResealCredentials(ctx, transaction.ID, uc.reseal(ctx))
+			if err != nil {
+				log.Printf("Failed to re-encrypt credentials for transaction %s: %v", transaction.ID, err)
+				result.Failed++
+				continue
+			}
+			if changed {
+				result.TransactionsReencrypted++
+			}
+		}
+
+		if len(transactions) < credentialRotationBatchSize {
+			break
+		}
+		afterID = transactions[len(transactions)-1].ID
+	}
+
+	result.FinishedAt = time.Now()
+	log.Printf("Credential re-encryption finished: %d products, %d transactions re-encrypted, %d failed",
+		result.ProductsReencrypted, result.TransactionsReencrypted, result.Failed)
+
+	return result, nil
+}
+
+// RotateKey makes a new master key current and re-seals every credential
+// under it. Providers backed by a managed KMS key rotate through key versions
+// in KMS and are rejected here.
+func (uc *CredentialRotationUseCase) RotateKey(ctx context.Context) (*CredentialRotationResult, error) {
+	rotator, ok := uc.keyProvider.(service.KeyRotator)
+	if !ok {
+		return nil, errors.BadRequest("Credential key is managed by KMS; rotate it by creating a new key version", nil)
+	}
+
+	keyID, err := rotator.RotateKey()
+	if err != nil {
+		return nil, errors.Internal("Failed to rotate credential key", err)
+	}
+	log.Printf("Credential master key rotated to %s", keyID)
+
+	result, err := uc.ReencryptAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.KeyID = keyID
+
+	return result, nil
+}
+
+// reseal returns a function giving the credentials sealed under the current
+// key and whether anything changed
+func (uc *CredentialRotationUseCase) reseal(ctx context.Context) repository.CredentialResealFunc {
+	return func(sealed *entity.EncryptedCredentials, plaintext map[string]interface{}) (*entity.EncryptedCredentials, bool, error) {
+		if sealed == nil {
+			if len(plaintext) == 0 {
+				return nil, false, nil
+			}
+			resealed, err := uc.vault.Seal(ctx, plaintext)
+			return resealed, err == nil, err
+		}
+
+		if !uc.vault.NeedsRotation(sealed) {
+			// Drop any plaintext left next to already sealed credentials
+			return sealed, len(plaintext) > 0, nil
+		}
+
+		credentials, err := uc.vault.Open(ctx, sealed)
+		if err != nil {
+			return nil, false, err
+		}
+
+		resealed, err := uc.vault.Seal(ctx, credentials)
+		return resealed, err == nil, err
+	}
+}
+
+// RunRotationJob adapts ReencryptAll to the job scheduler
+func (uc *CredentialRotationUseCase) RunRotationJob(ctx context.Context) (*JobResult, error) {
+	rotation, err := uc.ReencryptAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobResult{ItemsProcessed: rotation.ProductsReencrypted + rotation.TransactionsReencrypted}
+	if rotation.Failed > 0 {
+		result.Errors = append(result.Errors, fmt.Sprintf("%d credentials failed to re-encrypt", rotation.Failed))
+	}
+
+	return result, nil
+}
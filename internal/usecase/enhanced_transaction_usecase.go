@@ -24,7 +24,12 @@ type EnhancedTransactionUseCase struct {
 	paymentGateway  service.PaymentGatewayService
 	chatUseCase     *ChatUseCase
 	walletUseCase   *WalletUseCase
-	wsManager       *websocket.Manager
+	approvalUseCase *ApprovalUseCase
+	credentialVault service.CredentialVault
+	fraudUseCase    *FraudDetectionUseCase
+	velocityUseCase  *VelocityUseCase
+	inventoryUseCase *InventoryUseCase
+	wsManager        *websocket.Manager
 }
 
 func NewEnhancedTransactionUseCase(
@@ -34,6 +39,11 @@ func NewEnhancedTransactionUseCase(
 	paymentGateway service.PaymentGatewayService,
 	chatUseCase *ChatUseCase,
 	walletUseCase *WalletUseCase,
+	approvalUseCase *ApprovalUseCase,
+	credentialVault service.CredentialVault,
+	fraudUseCase *FraudDetectionUseCase,
+	velocityUseCase *VelocityUseCase,
+	inventoryUseCase *InventoryUseCase,
 	wsManager *websocket.Manager,
 ) *EnhancedTransactionUseCase {
 	return &EnhancedTransactionUseCase{
@@ -44,12 +54,18 @@ func NewEnhancedTransactionUseCase(
 		paymentGateway:  paymentGateway,
 		chatUseCase:     chatUseCase,
 		walletUseCase:   walletUseCase,
-		wsManager:       wsManager,
+		approvalUseCase: approvalUseCase,
+		credentialVault: credentialVault,
+		fraudUseCase:    fraudUseCase,
+		velocityUseCase:  velocityUseCase,
+		inventoryUseCase: inventoryUseCase,
+		wsManager:        wsManager,
 	}
 }
 
 type CreateSecureTransactionInput struct {
 	ProductID      string
+	VariantID      string // Required when the product has variants
 	DeliveryMethod string
 	PaymentMethod  string // "midtrans_snap", "midtrans_bank_transfer", "wallet"
 	MiddlemanID    string // Required for middleman delivery
@@ -99,69 +115,25 @@ func (uc *EnhancedTransactionUseCase) CreateSecureTransaction(ctx context.Contex
 		return nil, errors.BadRequest("Seller is not verified", nil)
 	}
 
-	// CRITICAL: Check product stock availability with race condition protection
-	// For single-use products (like game accounts), treat stock as 1 if not set
-	maxStock := product.Stock
-	if maxStock == 0 && len(product.Credentials) > 0 {
-		maxStock = 1 // Single-use product with credentials
+	item, err := resolveCheckoutItem(product, input.VariantID)
+	if err != nil {
+		return nil, err
 	}
-	
-	if maxStock > 0 {
-		// Check if product is already sold out
-		if product.SoldCount >= maxStock {
-			return nil, errors.BadRequest("Product is sold out", nil)
-		}
-		
-		// Additional check: count pending + completed transactions to prevent overselling
-		pendingCount, err := uc.transactionRepo.GetPendingTransactionCount(ctx, input.ProductID)
-		if err != nil {
-			log.Printf("Error checking pending transaction count: %v", err)
-		} else {
-			totalReserved := product.SoldCount + pendingCount
-			if totalReserved >= maxStock {
-				return nil, errors.BadRequest("Product is sold out (reserved)", nil)
-			}
-		}
+
+	// CRITICAL: Check stock of the product or variant, counting pending
+	// transactions as reserved to prevent overselling
+	if err := checkStockAvailable(ctx, uc.transactionRepo, product, item); err != nil {
+		return nil, err
 	}
 
 	if input.DeliveryMethod != "instant" && input.DeliveryMethod != "middleman" {
 		return nil, errors.BadRequest("Invalid delivery method", nil)
 	}
 
-	// FRAUD DETECTION: Analyze transaction for fraud risk
-	fraudUseCase := NewFraudDetectionUseCase(uc.transactionRepo, uc.userRepo)
-	
-	// Create temporary transaction for fraud analysis (after price calculation)
-	fee := uc.feeCalculator.CalculateFee(product.Price, input.PaymentMethod)
-	totalAmount := product.Price + fee
-	
-	tempTransaction := &entity.Transaction{
-		ProductID:   input.ProductID,
-		BuyerID:     buyerID,
-		SellerID:    product.SellerID,
-		TotalAmount: totalAmount,
-	}
-	
-	fraudResult, err := fraudUseCase.AnalyzeTransaction(ctx, tempTransaction, buyer, seller, product)
-	if err != nil {
-		log.Printf("Fraud analysis failed: %v", err)
-		// Continue with transaction but log the error
-	} else {
-		log.Printf("Fraud analysis result: Score=%.2f, Risk=%s, Action=%s", 
-			fraudResult.Score, fraudResult.RiskLevel, fraudResult.Action)
-			
-		// Block high-risk transactions
-		if fraudResult.Action == "block" {
-			return nil, errors.BadRequest("Transaction blocked for security reasons. Please contact support.", nil)
-		}
-		
-		// Flag for review but allow to proceed
-		if fraudResult.Action == "review" {
-			log.Printf("SECURITY: Transaction %s flagged for review: %v", tempTransaction.ID, fraudResult.Reasons)
-		}
-	}
+	fee := uc.feeCalculator.CalculateFee(item.Price, input.PaymentMethod)
+	totalAmount := item.Price + fee
 
-	if input.DeliveryMethod == "instant" && len(product.Credentials) == 0 {
+	if input.DeliveryMethod == "instant" && !product.HasCredentials() && !product.UsesCodeInventory {
 		return nil, errors.BadRequest("Product credentials are not available", nil)
 	}
 
@@ -176,18 +148,18 @@ func (uc *EnhancedTransactionUseCase) CreateSecureTransaction(ctx context.Contex
 	transaction := &entity.Transaction{
 		ID:             transactionID,
 		ProductID:      input.ProductID,
+		VariantID:      item.variantID(),
+		VariantLabel:   item.variantLabel(),
 		SellerID:       product.SellerID,
 		BuyerID:        buyerID,
 		Status:         "payment_pending",
 		DeliveryMethod: input.DeliveryMethod,
-		Amount:         product.Price,
+		Amount:         item.Price,
 		Fee:            fee,
 		TotalAmount:    totalAmount,
 		PaymentMethod:  input.PaymentMethod,
 		PaymentStatus:  "pending",
 		AdminID:        input.MiddlemanID,
-		// Add fraud analysis results if available
-		FraudScore:     0.0,
 		SecurityFlags:  []string{},
 		
 		// Midtrans fields
@@ -203,14 +175,21 @@ func (uc *EnhancedTransactionUseCase) CreateSecureTransaction(ctx context.Contex
 		UpdatedAt: time.Now(),
 	}
 	
-	// Update fraud analysis results before saving
-	if fraudResult != nil {
-		transaction.FraudScore = fraudResult.Score
-		transaction.SecurityFlags = fraudResult.Flags
+	// VELOCITY LIMITS: checkout count and spend per user and device, reserved
+	// against the transaction ID assigned up front
+	if err := uc.velocityUseCase.Reserve(ctx, VelocityActionCheckout, buyerID, transaction.TotalAmount, transactionID); err != nil {
+		return nil, err
+	}
+
+	// FRAUD DETECTION: "block" rejects, "review" holds the transaction for an admin
+	if err := uc.fraudUseCase.ScoreCheckout(ctx, transaction, buyer, seller, product); err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionCheckout, buyerID, transactionID)
+		return nil, err
 	}
 
 	// 5. Save transaction
 	if err := uc.transactionRepo.Create(ctx, transaction); err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionCheckout, buyerID, transactionID)
 		return nil, errors.Internal("Failed to create transaction", err)
 	}
 
@@ -219,32 +198,11 @@ func (uc *EnhancedTransactionUseCase) CreateSecureTransaction(ctx context.Contex
 		Transaction: transaction,
 	}
 
-	if input.PaymentMethod != "wallet" {
-		paymentResp, err := uc.createMidtransPayment(ctx, transaction, product, input.CustomerDetails, input.Embed)
-		if err != nil {
-			log.Printf("Failed to create Midtrans payment: %v", err)
-			// Update transaction status to failed
-			transaction.Status = "payment_failed"
-			transaction.PaymentStatus = "failed"
-			uc.transactionRepo.Update(ctx, transaction)
-			return nil, errors.Internal("Failed to create payment", err)
-		}
-
-		// Update transaction with payment details
-		transaction.MidtransToken = paymentResp.Token
-		transaction.MidtransRedirectURL = paymentResp.RedirectURL
-		transaction.PaymentDetails = map[string]interface{}{
-			"payment_type": paymentResp.PaymentType,
-			"va_numbers":   paymentResp.VaNumbers,
+	// Payment is only created once the transaction clears fraud review
+	if input.PaymentMethod != "wallet" && transaction.FraudReviewStatus != FraudReviewPending {
+		if err := uc.attachMidtransPayment(ctx, transaction, product, input.CustomerDetails, input.Embed, response); err != nil {
+			return nil, err
 		}
-
-		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-			log.Printf("Failed to update transaction with payment details: %v", err)
-		}
-
-		response.PaymentToken = paymentResp.Token
-		response.PaymentURL = paymentResp.RedirectURL
-		response.VirtualAccounts = paymentResp.VaNumbers
 	}
 
 	// 7. Create transaction chat if middleman delivery
@@ -270,6 +228,80 @@ func (uc *EnhancedTransactionUseCase) CreateSecureTransaction(ctx context.Contex
 	return response, nil
 }
 
+// attachMidtransPayment creates the Midtrans payment and stores its details on
+// the transaction and response
+func (uc *EnhancedTransactionUseCase) attachMidtransPayment(ctx context.Context, transaction *entity.Transaction, product *entity.Product, customerDetails service.CustomerDetails, embed bool, response *SecureTransactionResponse) error {
+	paymentResp, err := uc.createMidtransPayment(ctx, transaction, product, customerDetails, embed)
+	if err != nil {
+		log.Printf("Failed to create Midtrans payment: %v", err)
+		// Update transaction status to failed
+		transaction.Status = "payment_failed"
+		transaction.PaymentStatus = "failed"
+		uc.transactionRepo.Update(ctx, transaction)
+		return errors.Internal("Failed to create payment", err)
+	}
+
+	// Update transaction with payment details
+	transaction.MidtransToken = paymentResp.Token
+	transaction.MidtransRedirectURL = paymentResp.RedirectURL
+	transaction.PaymentDetails = map[string]interface{}{
+		"payment_type": paymentResp.PaymentType,
+		"va_numbers":   paymentResp.VaNumbers,
+	}
+
+	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+		log.Printf("Failed to update transaction with payment details: %v", err)
+	}
+
+	response.PaymentToken = paymentResp.Token
+	response.PaymentURL = paymentResp.RedirectURL
+	response.VirtualAccounts = paymentResp.VaNumbers
+	return nil
+}
+
+// ContinuePayment creates the Midtrans payment for a transaction that was held
+// for fraud review and has since been approved
+func (uc *EnhancedTransactionUseCase) ContinuePayment(ctx context.Context, transactionID, buyerID string, customerDetails service.CustomerDetails, embed bool) (*SecureTransactionResponse, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != buyerID {
+		return nil, errors.Forbidden("Only the buyer can pay for this transaction", nil)
+	}
+
+	if err := EnsureFraudCleared(transaction); err != nil {
+		return nil, err
+	}
+
+	if transaction.PaymentMethod == "wallet" {
+		return nil, errors.BadRequest("Wallet transactions are paid from the wallet", nil)
+	}
+
+	if transaction.Status != "payment_pending" || transaction.PaymentStatus != "pending" {
+		return nil, errors.BadRequest("Transaction is not awaiting payment", nil)
+	}
+
+	response := &SecureTransactionResponse{Transaction: transaction}
+	if transaction.MidtransToken != "" {
+		response.PaymentToken = transaction.MidtransToken
+		response.PaymentURL = transaction.MidtransRedirectURL
+		return response, nil
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, transaction.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.attachMidtransPayment(ctx, transaction, product, customerDetails, embed, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 func (uc *EnhancedTransactionUseCase) createMidtransPayment(ctx context.Context, transaction *entity.Transaction, product *entity.Product, customerDetails service.CustomerDetails, embed bool) (*service.PaymentGatewayResponse, error) {
 	// Create payment request
 	paymentReq := service.PaymentGatewayRequest{
@@ -281,9 +313,9 @@ func (uc *EnhancedTransactionUseCase) createMidtransPayment(ctx context.Context,
 		ItemDetails: []service.ItemDetail{
 			{
 				ID:       product.ID,
-				Price:    product.Price,
+				Price:    transaction.Amount,
 				Quantity: 1,
-				Name:     product.Title,
+				Name:     checkoutItemName(product, transaction),
 				Category: "Gaming Product",
 			},
 			{
@@ -314,11 +346,7 @@ func (uc *EnhancedTransactionUseCase) calculateSecurityLevel(amount float64, del
 }
 
 func (uc *EnhancedTransactionUseCase) getRequiredApprovals(deliveryMethod string) []string {
-	if deliveryMethod == "instant" {
-		return []string{"system"} // Automatic
-	}
-
-	return []string{"seller", "middleman", "buyer"}
+	return RequiredApprovalsFor(deliveryMethod)
 }
 
 
@@ -486,7 +514,24 @@ func (uc *EnhancedTransactionUseCase) processInstantDeliveryFromWebhook(ctx cont
 		return
 	}
 	
-	log.Printf("Product retrieved successfully: %s, hasCredentials: %t", product.Title, len(product.Credentials) > 0)
+	log.Printf("Product retrieved successfully: %s, hasCredentials: %t", product.Title, product.HasCredentials())
+
+	// Copy the sealed credentials, or a code from the product's pool; the
+	// buyer decrypts them through the escrow reveal endpoint
+	if product.UsesCodeInventory {
+		if err := uc.inventoryUseCase.DeliverCode(ctx, product, transaction); err != nil {
+			log.Printf("Failed to deliver inventory code for transaction %s: %v", transaction.ID, err)
+			return
+		}
+	} else {
+		sealedCredentials, err := productSealedCredentials(ctx, uc.credentialVault, product)
+		if err != nil {
+			log.Printf("Failed to seal credentials for transaction %s: %v", transaction.ID, err)
+			return
+		}
+		transaction.EncryptedCredentials = sealedCredentials
+		transaction.Credentials = nil
+	}
 
 	// Update transaction status
 	transaction.Status = "credentials_delivered"
@@ -494,28 +539,27 @@ func (uc *EnhancedTransactionUseCase) processInstantDeliveryFromWebhook(ctx cont
 	now := time.Now()
 	transaction.CredentialsDeliveredAt = &now
 
+	// Automated delivery stands in for the seller's approval
+	if _, err := uc.approvalUseCase.RecordApproval(ctx, transaction, RecordApprovalInput{
+		ApproverType: "system",
+		ApproverID:   "system",
+		ApprovalStep: ApprovalStepSystemDelivery,
+		Approved:     true,
+		Evidence: ApprovalEvidence{
+			Notes: "Credentials delivered automatically after payment confirmation",
+		},
+	}); err != nil {
+		log.Printf("Failed to record system approval for transaction %s: %v", transaction.ID, err)
+	}
+
 	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
 		log.Printf("Failed to update transaction after delivery: %v", err)
 		return
 	}
 	
-	// Update product sold count and status
-	product.SoldCount++
-	
-	// Update product status based on stock
-	if product.Stock > 0 && product.SoldCount >= product.Stock {
-		product.Status = "sold_out"
-		log.Printf("Product %s is now sold out (%d/%d)", product.ID, product.SoldCount, product.Stock)
-	} else if product.Stock == 0 && len(product.Credentials) > 0 {
-		// Single-use product with credentials - mark as sold
-		product.Status = "sold"
-		log.Printf("Single-use product %s is now sold", product.ID)
-	}
-	
-	if err := uc.productRepo.Update(ctx, product); err != nil {
-		log.Printf("Failed to update product status and sold count: %v", err)
-		// Don't return error, transaction is still successful
-	}
+	// Update product or variant sold count, stock and status. Failures are
+	// logged; the transaction is still successful
+	uc.inventoryUseCase.RecordSale(ctx, product, transaction.VariantID)
 
 	// Send credentials via chat
 	uc.sendCredentialsMessage(ctx, transaction, product)
@@ -575,12 +619,12 @@ func (uc *EnhancedTransactionUseCase) notifyPaymentFailure(ctx context.Context,
 
 // sendCredentialsMessage sends credentials via chat system
 func (uc *EnhancedTransactionUseCase) sendCredentialsMessage(ctx context.Context, transaction *entity.Transaction, product *entity.Product) {
-	message := fmt.Sprintf("🎉 Payment confirmed! Here are your credentials:\n\n" +
+	message := fmt.Sprintf("🎉 Payment confirmed! Your credentials are ready.\n\n" +
 		"**Product:** %s\n" +
-		"**Credentials:** %s\n\n" +
+		"Open the transaction to securely reveal your credentials.\n\n" +
 		"Transaction ID: %s\n" +
 		"Thank you for your purchase!", 
-		product.Title, product.Credentials, transaction.ID)
+		product.Title, transaction.ID)
 
 	// For instant transactions, try to find or create a direct chat
 	if uc.chatUseCase != nil {
@@ -8,139 +8,313 @@ import (
 
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
 	"pasargamex/pkg/errors"
 )
 
 type EscrowManagerUseCase struct {
-	transactionRepo repository.TransactionRepository
-	walletUseCase   *WalletUseCase
-	chatUseCase     *ChatUseCase
+	transactionRepo  repository.TransactionRepository
+	productRepo      repository.ProductRepository
+	walletUseCase    *WalletUseCase
+	chatUseCase      *ChatUseCase
+	approvalUseCase  *ApprovalUseCase
+	handoverUseCase  *HandoverUseCase
+	credentialVault  service.CredentialVault
+	antiFraudUseCase *AntiFraudUseCase
+	policyUseCase    *AutoReleasePolicyUseCase
+	trustUseCase     *SellerTrustUseCase
 }
 
+// CredentialRetentionWindow is how long credentials stay available after a
+// transaction completes before they are redacted
+const CredentialRetentionWindow = 7 * 24 * time.Hour
+
+const credentialRedactionBatchSize = 100
+
+const autoReleaseBatchSize = 100
+
+// Credential reveals allowed per transaction within the reveal window. The
+// count lives on the transaction, so every instance enforces the same limit.
+const (
+	credentialRevealLimit  = 5
+	credentialRevealWindow = time.Hour
+)
+
 func NewEscrowManagerUseCase(
 	transactionRepo repository.TransactionRepository,
+	productRepo repository.ProductRepository,
 	walletUseCase *WalletUseCase,
 	chatUseCase *ChatUseCase,
+	approvalUseCase *ApprovalUseCase,
+	handoverUseCase *HandoverUseCase,
+	credentialVault service.CredentialVault,
+	antiFraudUseCase *AntiFraudUseCase,
+	policyUseCase *AutoReleasePolicyUseCase,
+	trustUseCase *SellerTrustUseCase,
 ) *EscrowManagerUseCase {
 	return &EscrowManagerUseCase{
-		transactionRepo: transactionRepo,
-		walletUseCase:   walletUseCase,
-		chatUseCase:     chatUseCase,
+		transactionRepo:  transactionRepo,
+		productRepo:      productRepo,
+		walletUseCase:    walletUseCase,
+		chatUseCase:      chatUseCase,
+		approvalUseCase:  approvalUseCase,
+		handoverUseCase:  handoverUseCase,
+		credentialVault:  credentialVault,
+		antiFraudUseCase: antiFraudUseCase,
+		policyUseCase:    policyUseCase,
+		trustUseCase:     trustUseCase,
 	}
 }
 
 // DeliverCredentials - Seller delivers account credentials
-func (uc *EscrowManagerUseCase) DeliverCredentials(ctx context.Context, transactionID, sellerID string, credentials map[string]interface{}) error {
+func (uc *EscrowManagerUseCase) DeliverCredentials(ctx context.Context, transactionID, sellerID string, credentials map[string]interface{}, evidence ApprovalEvidence) (*entity.Transaction, error) {
 	log.Printf("Delivering credentials for transaction: %s", transactionID)
 
 	// Get transaction
 	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Validate seller
 	if transaction.SellerID != sellerID {
-		return errors.Forbidden("Only the seller can deliver credentials", nil)
+		return nil, errors.Forbidden("Only the seller can deliver credentials", nil)
 	}
 
 	// Validate transaction state
 	if transaction.PaymentStatus != "paid" {
-		return errors.BadRequest("Payment must be completed before delivering credentials", nil)
+		return nil, errors.BadRequest("Payment must be completed before delivering credentials", nil)
 	}
 
 	if transaction.CredentialsDelivered {
-		return errors.BadRequest("Credentials already delivered", nil)
+		return nil, errors.BadRequest("Credentials already delivered", nil)
+	}
+
+	sealedCredentials, err := sealCredentials(ctx, uc.credentialVault, credentials)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update transaction with credentials
 	now := time.Now()
-	transaction.Credentials = credentials
+	transaction.EncryptedCredentials = sealedCredentials
+	transaction.Credentials = nil
 	transaction.CredentialsDelivered = true
 	transaction.CredentialsDeliveredAt = &now
 	transaction.Status = "credentials_delivered"
 	
-	// Set auto-release timer (24 hours from delivery)
-	autoReleaseTime := now.Add(24 * time.Hour)
+	// Set auto-release timer from the matching inspection policy, held
+	// longer for sellers in a lower trust tier
+	policy, err := uc.policyUseCase.ResolvePolicy(ctx, transaction)
+	if err != nil {
+		return nil, errors.Internal("Failed to resolve auto-release policy", err)
+	}
+	inspectionWindow := InspectionWindow(policy) + uc.trustUseCase.ExtraHoldFor(ctx, transaction.SellerID)
+	autoReleaseTime := now.Add(inspectionWindow)
 	transaction.AutoReleaseAt = &autoReleaseTime
+	transaction.AutoReleasePolicyID = policy.ID
 	
 	transaction.UpdatedAt = now
 
+	// Account sales get a guided handover checklist. Release is gated on
+	// it, so delivery fails rather than go ahead without one.
+	if _, err := uc.handoverUseCase.StartChecklist(ctx, transaction); err != nil {
+		return nil, errors.Internal("Failed to start handover checklist", err)
+	}
+
+	// Seller delivery is the first step of the approval trail
+	if _, err := uc.approvalUseCase.RecordApproval(ctx, transaction, RecordApprovalInput{
+		ApproverType: "seller",
+		ApproverID:   sellerID,
+		ApprovalStep: ApprovalStepDeliveryConfirmed,
+		Approved:     true,
+		Evidence:     evidence,
+	}); err != nil {
+		return nil, errors.Internal("Failed to record seller approval", err)
+	}
+
 	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-		return errors.Internal("Failed to update transaction", err)
+		return nil, errors.Internal("Failed to update transaction", err)
 	}
 
 	// Send notification to buyer via chat
 	if transaction.MiddlemanChatID != "" {
 		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, 
-			"🎮 Seller has delivered the account credentials. Please check and confirm within 24 hours.", 
+			fmt.Sprintf("🎮 Seller has delivered the account credentials. Please check and confirm within %d hours.", int(inspectionWindow.Hours())),
 			"credentials_delivered", 
 			map[string]interface{}{
 				"transaction_id": transactionID,
 				"auto_release_at": autoReleaseTime.Unix(),
+				"can_extend":      policy.ExtensionHours > 0,
 			})
 	}
 
 	log.Printf("Credentials delivered for transaction: %s", transactionID)
-	return nil
+	return transaction, nil
+}
+
+// VerifyDelivery - Assigned middleman verifies the delivered account
+func (uc *EscrowManagerUseCase) VerifyDelivery(ctx context.Context, transactionID, middlemanID string, isVerified bool, evidence ApprovalEvidence) (*entity.Transaction, error) {
+	log.Printf("Middleman verifying delivery for transaction: %s, verified: %v", transactionID, isVerified)
+
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.DeliveryMethod != "middleman" {
+		return nil, errors.BadRequest("Transaction is not a middleman transaction", nil)
+	}
+
+	if transaction.AdminID != middlemanID {
+		return nil, errors.Forbidden("Only the assigned middleman can verify this delivery", nil)
+	}
+
+	if !transaction.CredentialsDelivered {
+		return nil, errors.BadRequest("Credentials must be delivered first", nil)
+	}
+
+	if transaction.EscrowStatus == "released" {
+		return nil, errors.BadRequest("Escrow has already been released", nil)
+	}
+
+	if _, err := uc.approvalUseCase.RecordApproval(ctx, transaction, RecordApprovalInput{
+		ApproverType: "middleman",
+		ApproverID:   middlemanID,
+		ApprovalStep: ApprovalStepVerificationComplete,
+		Approved:     isVerified,
+		Evidence:     evidence,
+	}); err != nil {
+		return nil, errors.Internal("Failed to record middleman approval", err)
+	}
+
+	now := time.Now()
+	released := false
+
+	if isVerified {
+		if err := uc.transactionRepo.UpdateFields(ctx, transaction.ID, map[string]interface{}{
+			"completedApprovals": transaction.CompletedApprovals,
+			"updatedAt":          now,
+		}); err != nil {
+			return nil, err
+		}
+
+		released, err = uc.releaseIfApproved(ctx, transaction, "completed", now)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if err := uc.markDisputed(ctx, transaction, fmt.Sprintf("Middleman rejected delivery: %s", evidence.Notes), now); err != nil {
+			return nil, err
+		}
+	}
+
+	if released {
+		uc.completeRelease(ctx, transaction, "✅ All approvals received. Funds released to seller. Transaction completed!", "transaction_completed")
+	} else if transaction.MiddlemanChatID != "" {
+		message := "🛡️ Middleman verified the delivered account. Waiting for remaining approvals."
+		systemType := "middleman_verified"
+		if !isVerified {
+			message = "⚠️ Middleman rejected the delivered account. Admin review required."
+			systemType = "middleman_rejected"
+		}
+		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, systemType,
+			map[string]interface{}{
+				"transaction_id": transactionID,
+				"notes":          evidence.Notes,
+			})
+	}
+
+	return transaction, nil
 }
 
 // ConfirmCredentials - Buyer confirms credentials are working
-func (uc *EscrowManagerUseCase) ConfirmCredentials(ctx context.Context, transactionID, buyerID string, isWorking bool, notes string) error {
+func (uc *EscrowManagerUseCase) ConfirmCredentials(ctx context.Context, transactionID, buyerID string, isWorking bool, notes string, evidence ApprovalEvidence) (*entity.Transaction, error) {
 	log.Printf("Buyer confirming credentials for transaction: %s, working: %v", transactionID, isWorking)
 
 	// Get transaction
 	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Validate buyer
 	if transaction.BuyerID != buyerID {
-		return errors.Forbidden("Only the buyer can confirm credentials", nil)
+		return nil, errors.Forbidden("Only the buyer can confirm credentials", nil)
 	}
 
 	// Validate transaction state
 	if !transaction.CredentialsDelivered {
-		return errors.BadRequest("Credentials must be delivered first", nil)
+		return nil, errors.BadRequest("Credentials must be delivered first", nil)
+	}
+
+	if transaction.EscrowStatus == "released" {
+		return nil, errors.BadRequest("Escrow has already been released", nil)
+	}
+
+	if isWorking {
+		if err := uc.handoverUseCase.EnsureComplete(ctx, transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	if evidence.Notes == "" {
+		evidence.Notes = notes
+	}
+
+	if _, err := uc.approvalUseCase.RecordApproval(ctx, transaction, RecordApprovalInput{
+		ApproverType: "buyer",
+		ApproverID:   buyerID,
+		ApprovalStep: ApprovalStepItemReceived,
+		Approved:     isWorking,
+		Evidence:     evidence,
+	}); err != nil {
+		return nil, errors.Internal("Failed to record buyer approval", err)
 	}
 
 	now := time.Now()
+	released := false
 
 	if isWorking {
-		// Credentials work - release funds immediately
+		if err := uc.transactionRepo.UpdateFields(ctx, transaction.ID, map[string]interface{}{
+			"buyerConfirmedCredentials": true,
+			"buyerConfirmedAt":          now,
+			"completedApprovals":        transaction.CompletedApprovals,
+			"updatedAt":                 now,
+		}); err != nil {
+			return nil, err
+		}
 		transaction.BuyerConfirmedCredentials = true
 		transaction.BuyerConfirmedAt = &now
-		transaction.Status = "completed"
-		transaction.CompletedAt = &now
-		transaction.EscrowStatus = "released"
 
-		// Release funds to seller
-		if err := uc.releaseFundsToSeller(ctx, transaction); err != nil {
-			log.Printf("Warning: Failed to release funds to seller: %v", err)
+		// Funds are only released once every required party has approved
+		released, err = uc.releaseIfApproved(ctx, transaction, "completed", now)
+		if err != nil {
+			return nil, err
 		}
+	} else {
+		// Credentials don't work - dispute
+		if err := uc.markDisputed(ctx, transaction, fmt.Sprintf("Buyer dispute: %s", notes), now); err != nil {
+			return nil, err
+		}
+	}
 
-		// Notify via chat
-		if transaction.MiddlemanChatID != "" {
-			uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, 
-				"✅ Buyer confirmed credentials are working. Funds released to seller. Transaction completed!", 
-				"transaction_completed", 
+	if released {
+		uc.completeRelease(ctx, transaction, "✅ Buyer confirmed credentials are working. Funds released to seller. Transaction completed!", "transaction_completed")
+	} else if transaction.MiddlemanChatID != "" {
+		if isWorking {
+			uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID,
+				"👍 Buyer confirmed credentials are working. Waiting for remaining approvals before releasing funds.",
+				"buyer_confirmed",
 				map[string]interface{}{
-					"transaction_id": transactionID,
-					"completed_at": now.Unix(),
+					"transaction_id":      transactionID,
+					"completed_approvals": transaction.CompletedApprovals,
 				})
-		}
-
-	} else {
-		// Credentials don't work - dispute
-		transaction.Status = "disputed"
-		transaction.Notes = fmt.Sprintf("Buyer dispute: %s", notes)
-
-		// Notify admin/middleman
-		if transaction.MiddlemanChatID != "" {
-			uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, 
-				"⚠️ Buyer reported credentials not working. Admin review required.", 
-				"credentials_disputed", 
+		} else {
+			// Notify admin/middleman
+			uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID,
+				"⚠️ Buyer reported credentials not working. Admin review required.",
+				"credentials_disputed",
 				map[string]interface{}{
 					"transaction_id": transactionID,
 					"dispute_reason": notes,
@@ -148,74 +322,499 @@ func (uc *EscrowManagerUseCase) ConfirmCredentials(ctx context.Context, transact
 		}
 	}
 
+	log.Printf("Credentials confirmation processed for transaction: %s", transactionID)
+	return transaction, nil
+}
+
+// ExtendAutoRelease - Buyer pushes the auto-release back once, by the
+// extension allowed in the transaction's policy
+func (uc *EscrowManagerUseCase) ExtendAutoRelease(ctx context.Context, transactionID, buyerID, reason string) (*entity.Transaction, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != buyerID {
+		return nil, errors.Forbidden("Only the buyer can extend the inspection window", nil)
+	}
+
+	if transaction.Status != "credentials_delivered" || transaction.AutoReleaseAt == nil {
+		return nil, errors.BadRequest("Transaction is not awaiting auto-release", nil)
+	}
+
+	if transaction.EscrowStatus == "released" {
+		return nil, errors.BadRequest("Escrow has already been released", nil)
+	}
+
+	if transaction.AutoReleaseExtended {
+		return nil, errors.BadRequest("The inspection window can only be extended once", nil)
+	}
+
+	policy := uc.policyUseCase.PolicyForTransaction(ctx, transaction)
+	if policy.ExtensionHours <= 0 {
+		return nil, errors.BadRequest("This transaction does not allow an extension", nil)
+	}
+
+	now := time.Now()
+	if now.After(*transaction.AutoReleaseAt) {
+		return nil, errors.BadRequest("The inspection window has already ended", nil)
+	}
+
+	// Only extend a transaction the auto-release job has not claimed meanwhile
+	autoReleaseTime := transaction.AutoReleaseAt.Add(time.Duration(policy.ExtensionHours) * time.Hour)
+	updated, err := uc.transactionRepo.UpdateFieldsIfUnchanged(ctx, transaction.ID, transaction.Status, transaction.EscrowStatus, map[string]interface{}{
+		"autoReleaseAt":         autoReleaseTime,
+		"autoReleaseExtended":   true,
+		"autoReleaseExtendedAt": now,
+		"autoReleaseWarnedAt":   nil,
+		"updatedAt":             now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.Conflict("Transaction changed before the inspection window could be extended")
+	}
+
+	transaction.AutoReleaseAt = &autoReleaseTime
+	transaction.AutoReleaseExtended = true
+	transaction.AutoReleaseExtendedAt = &now
+	transaction.AutoReleaseWarnedAt = nil
 	transaction.UpdatedAt = now
 
-	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-		return errors.Internal("Failed to update transaction", err)
+	if err := uc.transactionRepo.CreateLog(ctx, &entity.TransactionLog{
+		TransactionID: transaction.ID,
+		Status:        transaction.Status,
+		Notes:         fmt.Sprintf("Buyer extended inspection window by %d hours: %s", policy.ExtensionHours, reason),
+		CreatedBy:     buyerID,
+		CreatedAt:     now,
+	}); err != nil {
+		log.Printf("Failed to create extension log for transaction %s: %v", transaction.ID, err)
 	}
 
-	log.Printf("Credentials confirmation processed for transaction: %s", transactionID)
+	if transaction.MiddlemanChatID != "" {
+		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID,
+			fmt.Sprintf("⏳ Buyer extended the inspection window by %d hours. Funds will be auto-released on %s.",
+				policy.ExtensionHours, autoReleaseTime.Format("02 Jan 2006 15:04 MST")),
+			"auto_release_extended",
+			map[string]interface{}{
+				"transaction_id":  transaction.ID,
+				"auto_release_at": autoReleaseTime.Unix(),
+				"reason":          reason,
+			})
+	}
+
+	return transaction, nil
+}
+
+// RevealCredentials decrypts delivered credentials for the buyer. This is the
+// only path that opens sealed credentials; every reveal is rate limited and
+// written to the credential access audit log.
+func (uc *EscrowManagerUseCase) RevealCredentials(ctx context.Context, transactionID, buyerID, ipAddress, userAgent string) (map[string]interface{}, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != buyerID {
+		return nil, errors.Forbidden("Only the buyer can view credentials", nil)
+	}
+
+	if !transaction.CredentialsDelivered {
+		return nil, errors.BadRequest("Credentials have not been delivered yet", nil)
+	}
+
+	if transaction.CredentialsRedacted {
+		return nil, errors.BadRequest("Credentials were removed after the retention period", nil)
+	}
+
+	var credentials map[string]interface{}
+	if transaction.EncryptedCredentials != nil {
+		credentials, err = uc.credentialVault.Open(ctx, transaction.EncryptedCredentials)
+		if err != nil {
+			return nil, errors.Internal("Failed to decrypt credentials", err)
+		}
+	} else if len(transaction.Credentials) > 0 {
+		// Legacy plaintext not yet migrated by the rotation job
+		credentials = transaction.Credentials
+	} else {
+		return nil, errors.NotFound("Credentials", nil)
+	}
+
+	now := time.Now()
+	allowed, retryAt, err := uc.transactionRepo.ReserveCredentialView(ctx, transactionID, now, credentialRevealLimit, credentialRevealWindow)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		log.Printf("Credential reveal rate limit exceeded for user %s on transaction %s", buyerID, transactionID)
+		return nil, errors.TooManyRequests("Too many credential reveals. Please wait before trying again", retryAt.Sub(now))
+	}
+
+	// Never hand out credentials without an audit record
+	if err := uc.antiFraudUseCase.LogCredentialAccess(ctx, transactionID, buyerID, ipAddress, userAgent); err != nil {
+		return nil, errors.Internal("Failed to record credential access", err)
+	}
+
+	log.Printf("Credentials revealed for transaction %s", transactionID)
+	return credentials, nil
+}
+
+type CredentialAccessSummary struct {
+	TransactionID   string      `json:"transaction_id"`
+	ViewCount       int         `json:"view_count"`
+	FirstViewedAt   *time.Time  `json:"first_viewed_at,omitempty"`
+	LastViewedAt    *time.Time  `json:"last_viewed_at,omitempty"`
+	ViewedAt        []time.Time `json:"viewed_at"`
+	Redacted        bool        `json:"redacted"`
+	RedactedAt      *time.Time  `json:"redacted_at,omitempty"`
+}
+
+// GetCredentialAccessSummary shows transaction parties when and how often the
+// buyer revealed the credentials. IPs and user agents stay in the audit log.
+func (uc *EscrowManagerUseCase) GetCredentialAccessSummary(ctx context.Context, transactionID, userID string) (*CredentialAccessSummary, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != userID && transaction.SellerID != userID && transaction.AdminID != userID {
+		return nil, errors.Forbidden("You don't have permission to view this transaction", nil)
+	}
+
+	accessLogs, err := uc.transactionRepo.ListCredentialAccessLogs(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CredentialAccessSummary{
+		TransactionID: transactionID,
+		ViewCount:     len(accessLogs),
+		ViewedAt:      make([]time.Time, len(accessLogs)),
+		Redacted:      transaction.CredentialsRedacted,
+		RedactedAt:    transaction.CredentialsRedactedAt,
+	}
+
+	for i, accessLog := range accessLogs {
+		summary.ViewedAt[i] = accessLog.AccessedAt
+	}
+
+	if len(accessLogs) > 0 {
+		summary.FirstViewedAt = &accessLogs[0].AccessedAt
+		summary.LastViewedAt = &accessLogs[len(accessLogs)-1].AccessedAt
+	}
+
+	return summary, nil
+}
+
+// GetApprovals returns the approval trail for a transaction party
+func (uc *EscrowManagerUseCase) GetApprovals(ctx context.Context, transactionID, userID string) ([]*entity.TransactionApproval, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != userID && transaction.SellerID != userID && transaction.AdminID != userID {
+		return nil, errors.Forbidden("You don't have permission to view this transaction", nil)
+	}
+
+	return uc.approvalUseCase.GetApprovalTrail(ctx, transactionID)
+}
+
+// releaseIfApproved marks the escrow as released when every required approval
+// is present and any account handover checklist is complete. The release is
+// claimed atomically; a lost claim returns a CONFLICT error.
+func (uc *EscrowManagerUseCase) releaseIfApproved(ctx context.Context, transaction *entity.Transaction, completedStatus string, now time.Time) (bool, error) {
+	missing, err := uc.approvalUseCase.MissingApprovals(ctx, transaction)
+	if err != nil {
+		return false, errors.Internal("Failed to check transaction approvals", err)
+	}
+
+	if len(missing) > 0 {
+		log.Printf("Escrow release for transaction %s blocked, missing approvals: %v", transaction.ID, missing)
+		return false, nil
+	}
+
+	if transaction.IsDisputed {
+		log.Printf("Escrow release for transaction %s blocked by an open dispute", transaction.ID)
+		return false, nil
+	}
+
+	if err := uc.handoverUseCase.EnsureComplete(ctx, transaction); err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			log.Printf("Escrow release for transaction %s blocked: %v", transaction.ID, err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Claim the release with a compare-and-set on the status, escrow status
+	// and dispute flag so concurrent requests, job instances or a dispute
+	// opened meanwhile cannot lead to a payout
+	claimed, err := uc.transactionRepo.UpdateFieldsIfUnchanged(ctx, transaction.ID, transaction.Status, transaction.EscrowStatus, map[string]interface{}{
+		"status":       completedStatus,
+		"escrowStatus": "released",
+		"completedAt":  now,
+		"updatedAt":    now,
+	})
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		return false, errors.Conflict("Transaction changed before the escrow could be released")
+	}
+
+	transaction.Status = completedStatus
+	transaction.EscrowStatus = "released"
+	transaction.CompletedAt = &now
+	transaction.UpdatedAt = now
+
+	return true, nil
+}
+
+// markDisputed moves the transaction to disputed with a compare-and-set, so a
+// release claimed meanwhile is never overwritten
+func (uc *EscrowManagerUseCase) markDisputed(ctx context.Context, transaction *entity.Transaction, notes string, now time.Time) error {
+	updated, err := uc.transactionRepo.UpdateFieldsIfUnchanged(ctx, transaction.ID, transaction.Status, transaction.EscrowStatus, map[string]interface{}{
+		"status":             "disputed",
+		"notes":              notes,
+		"completedApprovals": transaction.CompletedApprovals,
+		"updatedAt":          now,
+	})
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.Conflict("Transaction changed before the dispute could be recorded")
+	}
+
+	transaction.Status = "disputed"
+	transaction.Notes = notes
+	transaction.UpdatedAt = now
 	return nil
 }
 
+// completeRelease pays out the seller and announces the release in chat
+func (uc *EscrowManagerUseCase) completeRelease(ctx context.Context, transaction *entity.Transaction, message, systemType string) {
+	if err := uc.releaseFundsToSeller(ctx, transaction); err != nil {
+		log.Printf("Warning: Failed to release funds to seller for transaction %s: %v", transaction.ID, err)
+	}
+
+	if transaction.MiddlemanChatID != "" {
+		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, systemType,
+			map[string]interface{}{
+				"transaction_id": transaction.ID,
+				"completed_at":   transaction.CompletedAt.Unix(),
+			})
+	}
+}
+
 // ProcessAutoRelease - Background job to auto-release funds after timer expires
-func (uc *EscrowManagerUseCase) ProcessAutoRelease(ctx context.Context) error {
+func (uc *EscrowManagerUseCase) ProcessAutoRelease(ctx context.Context) (*JobResult, error) {
 	log.Printf("Processing auto-release for expired transactions")
 
-	// Get transactions ready for auto-release
-	filter := map[string]interface{}{
-		"status":               "credentials_delivered",
-		"credentials_delivered": true,
-		"buyer_confirmed_credentials": false,
-		"escrow_status":        "held",
+	// Scan, oldest deadline first, every transaction due now or entering its
+	// warning window, so held transactions never hide older due ones
+	warningHours, err := uc.policyUseCase.MaxWarningHours(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	transactions, _, err := uc.transactionRepo.List(ctx, filter, 100, 0)
+	now := time.Now()
+	horizon := now.Add(time.Duration(warningHours) * time.Hour)
+	result := &JobResult{}
+
+	var last *entity.Transaction
+	for {
+		transactions, err := uc.transactionRepo.ListAutoReleaseDueBefore(ctx, horizon, last, autoReleaseBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			uc.autoReleaseTransaction(ctx, transaction, now, result)
+		}
+
+		if len(transactions) < autoReleaseBatchSize {
+			break
+		}
+		last = transactions[len(transactions)-1]
+	}
+
+	log.Printf("Auto-release processed: %d transactions released", result.ItemsProcessed)
+	return result, nil
+}
+
+// autoReleaseTransaction warns before, or releases after, the end of one
+// transaction's inspection window
+func (uc *EscrowManagerUseCase) autoReleaseTransaction(ctx context.Context, transaction *entity.Transaction, now time.Time, result *JobResult) {
+	if transaction.EscrowStatus == "released" || transaction.AutoReleaseAt == nil {
+		return
+	}
+
+	// Warn both parties shortly before the inspection window ends
+	if !now.After(*transaction.AutoReleaseAt) {
+		uc.warnBeforeAutoRelease(ctx, transaction, now, result)
+		return
+	}
+
+	// The inspection window expiring stands in for the buyer's approval.
+	// Seller and middleman approvals are still required.
+	if !transaction.BuyerConfirmedCredentials {
+		if _, err := uc.approvalUseCase.RecordApproval(ctx, transaction, RecordApprovalInput{
+			ApproverType: "buyer",
+			ApproverID:   "system",
+			ApprovalStep: ApprovalStepItemReceived,
+			Approved:     true,
+			Evidence: ApprovalEvidence{
+				Notes: "Auto-approved after inspection window expired without dispute",
+			},
+		}); err != nil {
+			result.Fail("Failed to record auto-release approval for transaction %s: %v", transaction.ID, err)
+			return
+		}
+
+		if err := uc.transactionRepo.UpdateFields(ctx, transaction.ID, map[string]interface{}{
+			"buyerConfirmedCredentials": true,
+			"buyerConfirmedAt":          now,
+		}); err != nil {
+			result.Fail("Failed to record buyer confirmation for transaction %s: %v", transaction.ID, err)
+			return
+		}
+		transaction.BuyerConfirmedCredentials = true
+		transaction.BuyerConfirmedAt = &now
+	}
+
+	released, err := uc.releaseIfApproved(ctx, transaction, "auto_completed", now)
 	if err != nil {
-		return err
+		// A CONFLICT means it was released, disputed or otherwise changed concurrently
+		if !errors.Is(err, "CONFLICT") {
+			result.Fail("Failed to auto-release transaction %s: %v", transaction.ID, err)
+		}
+		return
 	}
 
-	now := time.Now()
-	releasedCount := 0
+	if !released {
+		return
+	}
 
-	for _, transaction := range transactions {
-		// Check if auto-release time has passed
-		if transaction.AutoReleaseAt != nil && now.After(*transaction.AutoReleaseAt) {
-			log.Printf("Auto-releasing funds for transaction: %s", transaction.ID)
+	log.Printf("Auto-released funds for transaction: %s", transaction.ID)
+	uc.completeRelease(ctx, transaction, "⏰ Auto-release: inspection window ended without buyer dispute. Funds released to seller.", "auto_released")
+	result.ItemsProcessed++
+}
+
+// warnBeforeAutoRelease sends a one-time chat warning once the transaction
+// enters its policy's warning window
+func (uc *EscrowManagerUseCase) warnBeforeAutoRelease(ctx context.Context, transaction *entity.Transaction, now time.Time, result *JobResult) {
+	if transaction.AutoReleaseWarnedAt != nil || transaction.MiddlemanChatID == "" {
+		return
+	}
+
+	policy := uc.policyUseCase.PolicyForTransaction(ctx, transaction)
+	warnAt := transaction.AutoReleaseAt.Add(-time.Duration(policy.WarningHours) * time.Hour)
+	if policy.WarningHours <= 0 || now.Before(warnAt) {
+		return
+	}
+
+	if err := uc.transactionRepo.UpdateFields(ctx, transaction.ID, map[string]interface{}{
+		"autoReleaseWarnedAt": now,
+	}); err != nil {
+		result.Fail("Failed to record auto-release warning for transaction %s: %v", transaction.ID, err)
+		return
+	}
+	transaction.AutoReleaseWarnedAt = &now
 
-			// Release funds
-			transaction.Status = "auto_completed"
-			transaction.CompletedAt = &now
-			transaction.EscrowStatus = "released"
-			transaction.UpdatedAt = now
+	message := fmt.Sprintf("⏰ Funds will be auto-released to the seller on %s unless the buyer reports a problem.",
+		transaction.AutoReleaseAt.Format("02 Jan 2006 15:04 MST"))
+	if policy.ExtensionHours > 0 && !transaction.AutoReleaseExtended {
+		message += fmt.Sprintf(" The buyer can extend the inspection window once by %d hours.", policy.ExtensionHours)
+	}
 
-			if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
-				log.Printf("Failed to auto-release transaction %s: %v", transaction.ID, err)
+	uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, "auto_release_warning",
+		map[string]interface{}{
+			"transaction_id":  transaction.ID,
+			"auto_release_at": transaction.AutoReleaseAt.Unix(),
+			"can_extend":      policy.ExtensionHours > 0 && !transaction.AutoReleaseExtended,
+		})
+}
+
+// RedactExpiredCredentials removes credentials from completed transactions
+// once the retention window has passed. Sold single-use products lose their
+// credentials as well.
+func (uc *EscrowManagerUseCase) RedactExpiredCredentials(ctx context.Context) (*JobResult, error) {
+	log.Printf("Redacting credentials past the retention window")
+
+	// Transactions written before credentialsRedacted existed lack the field
+	// the redaction query filters on
+	backfilled, err := uc.transactionRepo.BackfillCredentialsRedacted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if backfilled > 0 {
+		log.Printf("Backfilled credentialsRedacted on %d transactions", backfilled)
+	}
+
+	cutoff := time.Now().Add(-CredentialRetentionWindow)
+	result := &JobResult{}
+
+	var last *entity.Transaction
+	for {
+		transactions, err := uc.transactionRepo.ListUnredactedCompletedBefore(ctx, cutoff, last, credentialRedactionBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			if transaction.Status != "completed" && transaction.Status != "auto_completed" {
 				continue
 			}
 
-			// Release funds to seller
-			if err := uc.releaseFundsToSeller(ctx, transaction); err != nil {
-				log.Printf("Warning: Failed to release funds to seller for transaction %s: %v", transaction.ID, err)
+			if transaction.EncryptedCredentials == nil && len(transaction.Credentials) == 0 {
+				continue
 			}
 
-			// Notify via chat
-			if transaction.MiddlemanChatID != "" {
-				uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, 
-					"⏰ Auto-release: 24 hours passed without buyer dispute. Funds released to seller.", 
-					"auto_released", 
-					map[string]interface{}{
-						"transaction_id": transaction.ID,
-						"auto_released_at": now.Unix(),
-					})
+			if err := uc.transactionRepo.MarkCredentialsRedacted(ctx, transaction.ID, time.Now()); err != nil {
+				result.Fail("Failed to redact credentials for transaction %s: %v", transaction.ID, err)
+				continue
 			}
 
-			releasedCount++
+			uc.redactProductCredentials(ctx, transaction.ProductID)
+			result.ItemsProcessed++
 		}
+
+		if len(transactions) < credentialRedactionBatchSize {
+			break
+		}
+		last = transactions[len(transactions)-1]
 	}
 
-	log.Printf("Auto-release processed: %d transactions released", releasedCount)
-	return nil
+	log.Printf("Credential redaction processed: %d transactions redacted", result.ItemsProcessed)
+	return result, nil
+}
+
+func (uc *EscrowManagerUseCase) redactProductCredentials(ctx context.Context, productID string) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		log.Printf("Failed to load product %s for credential redaction: %v", productID, err)
+		return
+	}
+
+	// Products still on sale keep their credentials for future buyers
+	if product.Status != "sold" && product.Status != "sold_out" {
+		return
+	}
+
+	if !product.HasCredentials() {
+		return
+	}
+
+	product.EncryptedCredentials = nil
+	product.Credentials = nil
+	product.UpdatedAt = time.Now()
+
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		log.Printf("Failed to redact credentials for product %s: %v", productID, err)
+	}
 }
 
 // releaseFundsToSeller transfers funds from escrow to seller wallet
@@ -228,24 +827,3 @@ func (uc *EscrowManagerUseCase) releaseFundsToSeller(ctx context.Context, transa
 	}
 	return nil
 }
-
-// StartAutoReleaseJob - Start background job for auto-release
-func (uc *EscrowManagerUseCase) StartAutoReleaseJob(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Minute) // Check every 10 minutes
-	
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				if err := uc.ProcessAutoRelease(ctx); err != nil {
-					log.Printf("Auto-release job error: %v", err)
-				}
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			}
-		}
-	}()
-	
-	log.Printf("Auto-release job started (checking every 10 minutes)")
-}
\ No newline at end of file
@@ -3,25 +3,41 @@ package usecase
 import (
 	"context"
 	"log"
-	"strings"
 	"time"
 
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// Fraud review states stored on Transaction.FraudReviewStatus
+const (
+	FraudReviewPending  = "pending"
+	FraudReviewApproved = "approved"
+	FraudReviewRejected = "rejected"
 )
 
 type FraudDetectionUseCase struct {
-	transactionRepo repository.TransactionRepository
-	userRepo        repository.UserRepository
+	transactionRepo    repository.TransactionRepository
+	userRepo           repository.UserRepository
+	chatUseCase        *ChatUseCase
+	ruleUseCase        *FraudRuleUseCase
+	securityLogUseCase *SecurityLogUseCase
 }
 
 func NewFraudDetectionUseCase(
 	transactionRepo repository.TransactionRepository,
 	userRepo repository.UserRepository,
+	chatUseCase *ChatUseCase,
+	ruleUseCase *FraudRuleUseCase,
+	securityLogUseCase *SecurityLogUseCase,
 ) *FraudDetectionUseCase {
 	return &FraudDetectionUseCase{
-		transactionRepo: transactionRepo,
-		userRepo:        userRepo,
+		transactionRepo:    transactionRepo,
+		userRepo:           userRepo,
+		chatUseCase:        chatUseCase,
+		ruleUseCase:        ruleUseCase,
+		securityLogUseCase: securityLogUseCase,
 	}
 }
 
@@ -34,89 +50,165 @@ type FraudAnalysisResult struct {
 	ReviewBy   string   `json:"review_by"`   // auto, human, admin
 }
 
+// AnalyzeTransaction scores a checkout against the admin-managed fraud rules
 func (uc *FraudDetectionUseCase) AnalyzeTransaction(ctx context.Context, transaction *entity.Transaction, buyer *entity.User, seller *entity.User, product *entity.Product) (*FraudAnalysisResult, error) {
-	result := &FraudAnalysisResult{
-		Score:     0.0,
-		RiskLevel: "low",
-		Flags:     []string{},
-		Reasons:   []string{},
-		Action:    "allow",
-		ReviewBy:  "auto",
+	rules, err := uc.ruleUseCase.ActiveRules(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// 1. NEW USER RISK
-	if time.Since(buyer.CreatedAt) < 24*time.Hour {
-		result.Score += 0.2
-		result.Flags = append(result.Flags, "new_buyer")
-		result.Reasons = append(result.Reasons, "Buyer account created less than 24 hours ago")
+	facts := uc.ruleUseCase.BuildFacts(ctx, transaction, buyer, seller, product, time.Now())
+	result := EvaluateFraudRules(rules, facts)
+
+	log.Printf("Fraud analysis for transaction %s: Score=%.2f, Risk=%s, Action=%s", 
+		transaction.ID, result.Score, result.RiskLevel, result.Action)
+
+	return result, nil
+}
+
+// ScoreCheckout analyzes a new transaction and stores the result on it.
+// "block" rejects the checkout; "review" holds the transaction in the fraud
+// review queue until an admin decides. A checkout that cannot be scored is
+// held for review as well.
+func (uc *FraudDetectionUseCase) ScoreCheckout(ctx context.Context, transaction *entity.Transaction, buyer, seller *entity.User, product *entity.Product) error {
+	result, err := uc.AnalyzeTransaction(ctx, transaction, buyer, seller, product)
+	if err != nil {
+		// Scoring problems must not take checkout down, nor let it through unchecked
+		log.Printf("SECURITY: Fraud analysis failed for buyer %s, holding checkout for review: %v", transaction.BuyerID, err)
+		transaction.SecurityFlags = append(transaction.SecurityFlags, "fraud_scoring_failed")
+		transaction.FraudReasons = []string{"Fraud scoring was unavailable at checkout"}
+		holdForFraudReview(transaction)
+		return nil
 	}
 
-	if time.Since(seller.CreatedAt) < 7*24*time.Hour {
-		result.Score += 0.15
-		result.Flags = append(result.Flags, "new_seller")
-		result.Reasons = append(result.Reasons, "Seller account created less than 7 days ago")
+	transaction.FraudScore = result.Score
+	transaction.SecurityFlags = result.Flags
+	transaction.FraudRiskLevel = result.RiskLevel
+	transaction.FraudReasons = result.Reasons
+
+	uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+		UserID:        transaction.BuyerID,
+		EventType:     SecurityEventCheckout,
+		TransactionID: transaction.ID,
+		RiskScore:     int(result.Score * 100),
+		Details: map[string]interface{}{
+			"product_id":   transaction.ProductID,
+			"seller_id":    transaction.SellerID,
+			"total_amount": transaction.TotalAmount,
+			"action":       result.Action,
+			"flags":        result.Flags,
+		},
+	})
+
+	switch result.Action {
+	case "block":
+		log.Printf("SECURITY: Checkout blocked for buyer %s on product %s: %v", transaction.BuyerID, transaction.ProductID, result.Reasons)
+		return errors.BadRequest("Transaction blocked for security reasons. Please contact support.", nil)
+	case "review":
+		holdForFraudReview(transaction)
+		log.Printf("SECURITY: Checkout by buyer %s held for fraud review: %v", transaction.BuyerID, result.Reasons)
 	}
 
-	// 2. HIGH VALUE TRANSACTION
-	if transaction.TotalAmount > 1000000 { // > 1M IDR
-		result.Score += 0.3
-		result.Flags = append(result.Flags, "high_value")
-		result.Reasons = append(result.Reasons, "High value transaction")
+	return nil
+}
+
+// holdForFraudReview parks a transaction in the fraud review queue,
+// remembering the status to restore on approval
+func holdForFraudReview(transaction *entity.Transaction) {
+	transaction.FraudReviewStatus = FraudReviewPending
+	transaction.FraudHeldStatus = transaction.Status
+	transaction.Status = "fraud_review"
+}
+
+// EnsureFraudCleared stops payment and delivery while a transaction is held
+// for fraud review or after it was rejected
+func EnsureFraudCleared(transaction *entity.Transaction) error {
+	switch transaction.FraudReviewStatus {
+	case FraudReviewPending:
+		return errors.BadRequest("Transaction is awaiting security review", nil)
+	case FraudReviewRejected:
+		return errors.BadRequest("Transaction was rejected by security review", nil)
 	}
+	return nil
+}
 
-	// 3. RAPID TRANSACTIONS (Rate limiting check)
-	buyerRecentCount, err := uc.getUserRecentTransactionCount(ctx, buyer.ID, 1*time.Hour)
-	if err == nil && buyerRecentCount > 5 {
-		result.Score += 0.4
-		result.Flags = append(result.Flags, "rapid_transactions")
-		result.Reasons = append(result.Reasons, "Multiple transactions in short time")
+// ListReviewQueue returns transactions waiting for a fraud decision
+func (uc *FraudDetectionUseCase) ListReviewQueue(ctx context.Context, page, limit int) ([]*entity.Transaction, int64, error) {
+	offset := (page - 1) * limit
+	filter := map[string]interface{}{
+		"fraudReviewStatus": FraudReviewPending,
 	}
 
-	// 4. SELLER REPUTATION
-	if seller.SellerReviewCount < 5 {
-		result.Score += 0.1
-		result.Flags = append(result.Flags, "low_seller_reviews")
-		result.Reasons = append(result.Reasons, "Seller has few reviews")
+	return uc.transactionRepo.List(ctx, filter, limit, offset)
+}
+
+// ReviewTransaction records an admin decision. Approval restores the status the
+// transaction was held in; rejection cancels it.
+func (uc *FraudDetectionUseCase) ReviewTransaction(ctx context.Context, adminID, transactionID string, approve bool, notes string) (*entity.Transaction, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
 	}
 
-	if seller.SellerRating < 4.0 {
-		result.Score += 0.2
-		result.Flags = append(result.Flags, "low_seller_rating")
-		result.Reasons = append(result.Reasons, "Seller has low rating")
+	if transaction.FraudReviewStatus != FraudReviewPending {
+		return nil, errors.BadRequest("Transaction is not awaiting fraud review", nil)
 	}
 
-	// 5. PRODUCT RISK FACTORS
-	gameTitle := strings.ToLower(product.Title)
-	if strings.Contains(gameTitle, "valorant") || strings.Contains(gameTitle, "csgo") || strings.Contains(gameTitle, "pubg") {
-		result.Score += 0.1
-		result.Flags = append(result.Flags, "high_risk_game")
-		result.Reasons = append(result.Reasons, "High-risk game category")
+	now := time.Now()
+	transaction.FraudReviewedBy = adminID
+	transaction.FraudReviewedAt = &now
+	transaction.FraudReviewNotes = notes
+	transaction.UpdatedAt = now
+
+	logNotes := "Fraud review approved"
+	message := "✅ Security review passed. You can continue with payment."
+	if approve {
+		transaction.FraudReviewStatus = FraudReviewApproved
+		transaction.Status = transaction.FraudHeldStatus
+		if transaction.Status == "" {
+			transaction.Status = "pending"
+		}
+	} else {
+		transaction.FraudReviewStatus = FraudReviewRejected
+		transaction.Status = "cancelled"
+		transaction.CancellationReason = "Rejected by security review"
+		transaction.CancelledAt = &now
+		logNotes = "Fraud review rejected"
+		message = "⛔ This transaction was cancelled after security review."
 	}
+	transaction.FraudHeldStatus = ""
 
-	// 6. ACCOUNT VERIFICATION
-	// Note: User entity doesn't have IsEmailVerified field, using Status instead
-	if buyer.Status != "active" {
-		result.Score += 0.15
-		result.Flags = append(result.Flags, "inactive_buyer")
-		result.Reasons = append(result.Reasons, "Buyer account not active")
+	if notes != "" {
+		logNotes += ": " + notes
 	}
 
-	if seller.VerificationStatus != "verified" {
-		result.Score += 0.25
-		result.Flags = append(result.Flags, "unverified_seller")
-		result.Reasons = append(result.Reasons, "Seller not fully verified")
+	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+		return nil, errors.Internal("Failed to update transaction", err)
 	}
 
-	// 7. CALCULATE RISK LEVEL AND ACTION
-	uc.calculateRiskLevelAndAction(result)
+	if err := uc.transactionRepo.CreateLog(ctx, &entity.TransactionLog{
+		TransactionID: transaction.ID,
+		Status:        transaction.Status,
+		Notes:         logNotes,
+		CreatedBy:     adminID,
+		CreatedAt:     now,
+	}); err != nil {
+		log.Printf("Failed to create fraud review log for transaction %s: %v", transaction.ID, err)
+	}
 
-	log.Printf("Fraud analysis for transaction %s: Score=%.2f, Risk=%s, Action=%s", 
-		transaction.ID, result.Score, result.RiskLevel, result.Action)
+	if transaction.MiddlemanChatID != "" && uc.chatUseCase != nil {
+		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, "fraud_review_decided",
+			map[string]interface{}{
+				"transaction_id": transaction.ID,
+				"approved":       approve,
+			})
+	}
 
-	return result, nil
+	log.Printf("Fraud review for transaction %s decided by %s: approved=%v", transaction.ID, adminID, approve)
+	return transaction, nil
 }
 
-func (uc *FraudDetectionUseCase) calculateRiskLevelAndAction(result *FraudAnalysisResult) {
+func applyRiskLevelAndAction(result *FraudAnalysisResult) {
 	if result.Score >= 0.8 {
 		result.RiskLevel = "critical"
 		result.Action = "block"
@@ -140,13 +232,6 @@ func (uc *FraudDetectionUseCase) calculateRiskLevelAndAction(result *FraudAnalys
 	}
 }
 
-func (uc *FraudDetectionUseCase) getUserRecentTransactionCount(ctx context.Context, userID string, duration time.Duration) (int, error) {
-	// This would query transactions created within the duration
-	// Implementation would depend on your transaction repository
-	// For now, return 0 to avoid compilation error
-	return 0, nil
-}
-
 // Post-transaction monitoring
 func (uc *FraudDetectionUseCase) MonitorCredentialAccess(ctx context.Context, transactionID string, accessIP string) error {
 	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
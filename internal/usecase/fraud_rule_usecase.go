@@ -0,0 +1,630 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// FraudRuleFields lists the checkout facts rules can match on and their type
+var FraudRuleFields = map[string]string{
+	"buyer.account_age_hours":      "number",
+	"buyer.status":                 "string",
+	"buyer.recent_transactions_1h": "number",
+	"buyer.linked_accounts":        "number",
+	"buyer.device_linked_accounts": "number",
+	"buyer.linked_to_seller":       "bool",
+	"seller.linked_accounts":       "number",
+	"seller.account_age_hours":     "number",
+	"seller.review_count":          "number",
+	"seller.rating":                "number",
+	"seller.verification_status":   "string",
+	"product.title":                "string",
+	"product.type":                 "string",
+	"product.game_title_id":        "string",
+	"product.price":                "number",
+	"transaction.total_amount":     "number",
+	"transaction.delivery_method":  "string",
+	"transaction.payment_method":   "string",
+}
+
+// FraudRuleOperators lists the supported operators and the field types they apply to
+var FraudRuleOperators = map[string]string{
+	"gt":           "number",
+	"gte":          "number",
+	"lt":           "number",
+	"lte":          "number",
+	"eq":           "any",
+	"neq":          "any",
+	"in":           "string",
+	"not_in":       "string",
+	"contains_any": "string",
+}
+
+// DefaultFraudRules seed an empty rule collection at startup with the checks
+// that used to be hardcoded in AnalyzeTransaction
+var DefaultFraudRules = []entity.FraudRule{
+	{Flag: "new_buyer", Name: "New buyer", Description: "Buyer account created less than 24 hours ago", Weight: 0.2, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "buyer.account_age_hours", Operator: "lt", Value: 24.0}}},
+	{Flag: "new_seller", Name: "New seller", Description: "Seller account created less than 7 days ago", Weight: 0.15, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "seller.account_age_hours", Operator: "lt", Value: 168.0}}},
+	{Flag: "high_value", Name: "High value", Description: "High value transaction", Weight: 0.3, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "transaction.total_amount", Operator: "gt", Value: 1000000.0}}},
+	{Flag: "rapid_transactions", Name: "Rapid transactions", Description: "Multiple transactions in short time", Weight: 0.4, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "buyer.recent_transactions_1h", Operator: "gt", Value: 5.0}}},
+	{Flag: "low_seller_reviews", Name: "Few seller reviews", Description: "Seller has few reviews", Weight: 0.1, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "seller.review_count", Operator: "lt", Value: 5.0}}},
+	{Flag: "low_seller_rating", Name: "Low seller rating", Description: "Seller has low rating", Weight: 0.2, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "seller.rating", Operator: "lt", Value: 4.0}}},
+	{Flag: "high_risk_game", Name: "High-risk game", Description: "High-risk game category", Weight: 0.1, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "product.title", Operator: "contains_any", Value: []interface{}{"valorant", "csgo", "pubg"}}}},
+	{Flag: "inactive_buyer", Name: "Inactive buyer", Description: "Buyer account not active", Weight: 0.15, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "buyer.status", Operator: "neq", Value: "active"}}},
+	{Flag: "unverified_seller", Name: "Unverified seller", Description: "Seller not fully verified", Weight: 0.25, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "seller.verification_status", Operator: "neq", Value: "verified"}}},
+	{Flag: "buyer_linked_to_seller", Name: "Buyer linked to seller", Description: "Buyer and seller share a device or IP address", Weight: 0.4, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "buyer.linked_to_seller", Operator: "eq", Value: true}}},
+	{Flag: "shared_device_accounts", Name: "Shared device", Description: "Buyer's device is used by several other accounts", Weight: 0.25, Enabled: true,
+		Conditions: []entity.FraudRuleCondition{{Field: "buyer.device_linked_accounts", Operator: "gte", Value: 2.0}}},
+}
+
+const (
+	fraudRuleCacheTTL    = time.Minute
+	maxFraudDryRunSample = 500
+	fraudLinkageTimeout  = 2 * time.Second
+)
+
+type FraudRuleUseCase struct {
+	ruleRepo           repository.FraudRuleRepository
+	transactionRepo    repository.TransactionRepository
+	userRepo           repository.UserRepository
+	productRepo        repository.ProductRepository
+	securityLogUseCase *SecurityLogUseCase
+
+	mu       sync.Mutex
+	cached   []*entity.FraudRule
+	cachedAt time.Time
+}
+
+func NewFraudRuleUseCase(
+	ruleRepo repository.FraudRuleRepository,
+	transactionRepo repository.TransactionRepository,
+	userRepo repository.UserRepository,
+	productRepo repository.ProductRepository,
+	securityLogUseCase *SecurityLogUseCase,
+) *FraudRuleUseCase {
+	return &FraudRuleUseCase{
+		ruleRepo:           ruleRepo,
+		transactionRepo:    transactionRepo,
+		userRepo:           userRepo,
+		productRepo:        productRepo,
+		securityLogUseCase: securityLogUseCase,
+	}
+}
+
+type FraudRuleInput struct {
+	Flag        string
+	Name        string
+	Description string
+	Conditions  []entity.FraudRuleCondition
+	Weight      float64
+	Enabled     bool
+}
+
+// SeedDefaultRules stores DefaultFraudRules once, when the rule collection
+// has never been seeded. Called at startup, never on the read path.
+func (uc *FraudRuleUseCase) SeedDefaultRules(ctx context.Context) error {
+	rules := make([]*entity.FraudRule, 0, len(DefaultFraudRules))
+	for i := range DefaultFraudRules {
+		rule := DefaultFraudRules[i]
+		rule.UpdatedBy = "system"
+		rules = append(rules, &rule)
+	}
+
+	seeded, err := uc.ruleRepo.SeedDefaults(ctx, rules)
+	if err != nil {
+		return err
+	}
+
+	if seeded {
+		uc.invalidateCache()
+		log.Printf("Seeded %d default fraud rules", len(rules))
+	}
+
+	return nil
+}
+
+// ActiveRules returns the stored rules. Rules are cached briefly so checkout
+// does not read Firestore every time.
+func (uc *FraudRuleUseCase) ActiveRules(ctx context.Context) ([]*entity.FraudRule, error) {
+	uc.mu.Lock()
+	if uc.cached != nil && time.Since(uc.cachedAt) < fraudRuleCacheTTL {
+		rules := uc.cached
+		uc.mu.Unlock()
+		return rules, nil
+	}
+	uc.mu.Unlock()
+
+	rules, err := uc.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	uc.cached = rules
+	uc.cachedAt = time.Now()
+	uc.mu.Unlock()
+
+	return rules, nil
+}
+
+func (uc *FraudRuleUseCase) ListRules(ctx context.Context) ([]*entity.FraudRule, error) {
+	return uc.ruleRepo.List(ctx)
+}
+
+func (uc *FraudRuleUseCase) CreateRule(ctx context.Context, adminID string, input FraudRuleInput) (*entity.FraudRule, error) {
+	rule := &entity.FraudRule{}
+	applyFraudRuleInput(rule, input)
+	rule.UpdatedBy = adminID
+
+	if err := ValidateFraudRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateCache()
+	log.Printf("Fraud rule %s (%s) created by %s", rule.ID, rule.Flag, adminID)
+	return rule, nil
+}
+
+func (uc *FraudRuleUseCase) UpdateRule(ctx context.Context, adminID, id string, input FraudRuleInput) (*entity.FraudRule, error) {
+	rule, err := uc.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyFraudRuleInput(rule, input)
+	rule.UpdatedBy = adminID
+
+	if err := ValidateFraudRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateCache()
+	log.Printf("Fraud rule %s (%s) updated by %s", rule.ID, rule.Flag, adminID)
+	return rule, nil
+}
+
+func (uc *FraudRuleUseCase) DeleteRule(ctx context.Context, adminID, id string) error {
+	if _, err := uc.ruleRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.ruleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	uc.invalidateCache()
+	log.Printf("Fraud rule %s deleted by %s", id, adminID)
+	return nil
+}
+
+func (uc *FraudRuleUseCase) invalidateCache() {
+	uc.mu.Lock()
+	uc.cached = nil
+	uc.mu.Unlock()
+}
+
+// FraudFacts are the checkout values rules are evaluated against
+type FraudFacts map[string]interface{}
+
+// BuildFacts collects checkout facts as of the given time. Replays pass the
+// transaction's creation time so account ages match what checkout saw.
+func (uc *FraudRuleUseCase) BuildFacts(ctx context.Context, transaction *entity.Transaction, buyer, seller *entity.User, product *entity.Product, at time.Time) FraudFacts {
+	facts := FraudFacts{
+		"buyer.account_age_hours":      at.Sub(buyer.CreatedAt).Hours(),
+		"buyer.status":                 buyer.Status,
+		"buyer.recent_transactions_1h": float64(uc.recentTransactionCount(ctx, buyer.ID, at, time.Hour)),
+		"seller.account_age_hours":     at.Sub(seller.CreatedAt).Hours(),
+		"seller.review_count":          float64(seller.SellerReviewCount),
+		"seller.rating":                seller.SellerRating,
+		"seller.verification_status":   seller.VerificationStatus,
+		"transaction.total_amount":     transaction.TotalAmount,
+		"transaction.delivery_method":  transaction.DeliveryMethod,
+		"transaction.payment_method":   transaction.PaymentMethod,
+	}
+
+	if product != nil {
+		facts["product.title"] = product.Title
+		facts["product.type"] = product.Type
+		facts["product.game_title_id"] = product.GameTitleID
+		facts["product.price"] = product.Price
+	}
+
+	uc.addLinkageFacts(ctx, facts, buyer.ID, seller.ID)
+
+	return facts
+}
+
+// addLinkageFacts adds device and IP linkage facts. Linkage reflects the
+// security log today, so replays of old checkouts see current links. The
+// lookup is time-boxed so it cannot stall checkout; facts it could not load
+// are left out.
+func (uc *FraudRuleUseCase) addLinkageFacts(ctx context.Context, facts FraudFacts, buyerID, sellerID string) {
+	if uc.securityLogUseCase == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fraudLinkageTimeout)
+	defer cancel()
+
+	buyerLinkage, err := uc.securityLogUseCase.GetLinkedAccounts(ctx, buyerID)
+	if err != nil {
+		log.Printf("Failed to load account linkage for buyer %s: %v", buyerID, err)
+		return
+	}
+	facts["buyer.linked_accounts"] = float64(len(buyerLinkage.LinkedAccounts))
+	facts["buyer.device_linked_accounts"] = float64(buyerLinkage.DeviceLinkedCount())
+	facts["buyer.linked_to_seller"] = buyerLinkage.IsLinkedTo(sellerID)
+
+	sellerLinkage, err := uc.securityLogUseCase.GetLinkedAccounts(ctx, sellerID)
+	if err != nil {
+		log.Printf("Failed to load account linkage for seller %s: %v", sellerID, err)
+		return
+	}
+	facts["seller.linked_accounts"] = float64(len(sellerLinkage.LinkedAccounts))
+}
+
+// recentTransactionCount counts the buyer's transactions created in the window before at
+func (uc *FraudRuleUseCase) recentTransactionCount(ctx context.Context, buyerID string, at time.Time, window time.Duration) int {
+	transactions, _, err := uc.transactionRepo.List(ctx, map[string]interface{}{"buyerId": buyerID}, 50, 0)
+	if err != nil {
+		log.Printf("Failed to count recent transactions for buyer %s: %v", buyerID, err)
+		return 0
+	}
+
+	count := 0
+	for _, transaction := range transactions {
+		if transaction.CreatedAt.Before(at) && at.Sub(transaction.CreatedAt) <= window {
+			count++
+		}
+	}
+
+	return count
+}
+
+// EvaluateFraudRules scores the facts against the enabled rules
+func EvaluateFraudRules(rules []*entity.FraudRule, facts FraudFacts) *FraudAnalysisResult {
+	result := &FraudAnalysisResult{
+		Flags:   []string{},
+		Reasons: []string{},
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled || !fraudRuleMatches(rule, facts) {
+			continue
+		}
+
+		result.Score += rule.Weight
+		result.Flags = append(result.Flags, rule.Flag)
+		result.Reasons = append(result.Reasons, rule.Description)
+	}
+
+	applyRiskLevelAndAction(result)
+	return result
+}
+
+func fraudRuleMatches(rule *entity.FraudRule, facts FraudFacts) bool {
+	if len(rule.Conditions) == 0 {
+		return false
+	}
+
+	for _, condition := range rule.Conditions {
+		fact, ok := facts[condition.Field]
+		if !ok || !fraudConditionMatches(condition, fact) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func fraudConditionMatches(condition entity.FraudRuleCondition, fact interface{}) bool {
+	switch condition.Operator {
+	case "gt", "gte", "lt", "lte":
+		left, ok := toFloat(fact)
+		right, ok2 := toFloat(condition.Value)
+		if !ok || !ok2 {
+			return false
+		}
+		switch condition.Operator {
+		case "gt":
+			return left > right
+		case "gte":
+			return left >= right
+		case "lt":
+			return left < right
+		default:
+			return left <= right
+		}
+	case "eq":
+		return fraudValuesEqual(fact, condition.Value)
+	case "neq":
+		return !fraudValuesEqual(fact, condition.Value)
+	case "in", "not_in":
+		found := false
+		for _, value := range toStringList(condition.Value) {
+			if strings.EqualFold(fmt.Sprint(fact), value) {
+				found = true
+				break
+			}
+		}
+		return found == (condition.Operator == "in")
+	case "contains_any":
+		text := strings.ToLower(fmt.Sprint(fact))
+		for _, value := range toStringList(condition.Value) {
+			if value != "" && strings.Contains(text, strings.ToLower(value)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func fraudValuesEqual(fact, value interface{}) bool {
+	if left, ok := toFloat(fact); ok {
+		if right, ok := toFloat(value); ok {
+			return left == right
+		}
+	}
+	return strings.EqualFold(fmt.Sprint(fact), fmt.Sprint(value))
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func toStringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+// ValidateFraudRule checks fields, operators and value types
+func ValidateFraudRule(rule *entity.FraudRule) error {
+	if rule.Flag == "" || rule.Name == "" {
+		return errors.BadRequest("Rule flag and name are required", nil)
+	}
+
+	if rule.Weight < 0 || rule.Weight > 1 {
+		return errors.BadRequest("Rule weight must be between 0 and 1", nil)
+	}
+
+	if len(rule.Conditions) == 0 {
+		return errors.BadRequest("Rule needs at least one condition", nil)
+	}
+
+	for _, condition := range rule.Conditions {
+		fieldType, ok := FraudRuleFields[condition.Field]
+		if !ok {
+			return errors.BadRequest("Unknown rule field: "+condition.Field, nil)
+		}
+
+		operatorType, ok := FraudRuleOperators[condition.Operator]
+		if !ok {
+			return errors.BadRequest("Unknown rule operator: "+condition.Operator, nil)
+		}
+
+		if operatorType != "any" && operatorType != fieldType {
+			return errors.BadRequest(fmt.Sprintf("Operator %s cannot be used with %s", condition.Operator, condition.Field), nil)
+		}
+
+		if fieldType == "bool" {
+			if _, ok := condition.Value.(bool); !ok {
+				return errors.BadRequest("Boolean fields need a true or false value for "+condition.Field, nil)
+			}
+		}
+
+		switch condition.Operator {
+		case "gt", "gte", "lt", "lte":
+			if _, ok := toFloat(condition.Value); !ok {
+				return errors.BadRequest("Numeric operators need a number value for "+condition.Field, nil)
+			}
+		case "in", "not_in", "contains_any":
+			if len(toStringList(condition.Value)) == 0 {
+				return errors.BadRequest("List operators need a non-empty value for "+condition.Field, nil)
+			}
+		}
+	}
+
+	return nil
+}
+
+func applyFraudRuleInput(rule *entity.FraudRule, input FraudRuleInput) {
+	rule.Flag = input.Flag
+	rule.Name = input.Name
+	rule.Description = input.Description
+	rule.Conditions = input.Conditions
+	rule.Weight = input.Weight
+	rule.Enabled = input.Enabled
+}
+
+// FraudRuleChange is a proposed edit for a dry run. An empty ID adds a new rule.
+type FraudRuleChange struct {
+	ID     string
+	Delete bool
+	Input  FraudRuleInput
+}
+
+type FraudDryRunItem struct {
+	TransactionID  string    `json:"transaction_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	CurrentScore   float64   `json:"current_score"`
+	CurrentAction  string    `json:"current_action"`
+	ProposedScore  float64   `json:"proposed_score"`
+	ProposedAction string    `json:"proposed_action"`
+	ProposedFlags  []string  `json:"proposed_flags"`
+}
+
+type FraudDryRunResult struct {
+	TransactionsEvaluated int                `json:"transactions_evaluated"`
+	Skipped               int                `json:"skipped"`
+	CurrentActions        map[string]int     `json:"current_actions"`
+	ProposedActions       map[string]int     `json:"proposed_actions"`
+	ChangedCount          int                `json:"changed_count"`
+	Changed               []*FraudDryRunItem `json:"changed"`
+}
+
+// DryRun replays recent transactions against the current rules and the rules
+// with the proposed changes applied. Nothing is stored.
+func (uc *FraudRuleUseCase) DryRun(ctx context.Context, changes []FraudRuleChange, sampleSize int) (*FraudDryRunResult, error) {
+	if sampleSize <= 0 || sampleSize > maxFraudDryRunSample {
+		sampleSize = 100
+	}
+
+	current, err := uc.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proposed, err := proposeFraudRules(current, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, _, err := uc.transactionRepo.List(ctx, map[string]interface{}{}, sampleSize, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FraudDryRunResult{
+		CurrentActions:  map[string]int{},
+		ProposedActions: map[string]int{},
+		Changed:         []*FraudDryRunItem{},
+	}
+
+	users := map[string]*entity.User{}
+	products := map[string]*entity.Product{}
+	loadUser := func(id string) *entity.User {
+		if user, ok := users[id]; ok {
+			return user
+		}
+		user, err := uc.userRepo.GetByID(ctx, id)
+		if err != nil {
+			user = nil
+		}
+		users[id] = user
+		return user
+	}
+
+	for _, transaction := range transactions {
+		buyer, seller := loadUser(transaction.BuyerID), loadUser(transaction.SellerID)
+		if buyer == nil || seller == nil {
+			result.Skipped++
+			continue
+		}
+
+		product, ok := products[transaction.ProductID]
+		if !ok {
+			product, _ = uc.productRepo.GetByID(ctx, transaction.ProductID)
+			products[transaction.ProductID] = product
+		}
+
+		facts := uc.BuildFacts(ctx, transaction, buyer, seller, product, transaction.CreatedAt)
+		before := EvaluateFraudRules(current, facts)
+		after := EvaluateFraudRules(proposed, facts)
+
+		result.TransactionsEvaluated++
+		result.CurrentActions[before.Action]++
+		result.ProposedActions[after.Action]++
+
+		if before.Action != after.Action || before.Score != after.Score {
+			result.ChangedCount++
+			result.Changed = append(result.Changed, &FraudDryRunItem{
+				TransactionID:  transaction.ID,
+				CreatedAt:      transaction.CreatedAt,
+				CurrentScore:   before.Score,
+				CurrentAction:  before.Action,
+				ProposedScore:  after.Score,
+				ProposedAction: after.Action,
+				ProposedFlags:  after.Flags,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func proposeFraudRules(current []*entity.FraudRule, changes []FraudRuleChange) ([]*entity.FraudRule, error) {
+	byID := make(map[string]int, len(current))
+	proposed := make([]*entity.FraudRule, 0, len(current)+len(changes))
+	for _, rule := range current {
+		copied := *rule
+		byID[rule.ID] = len(proposed)
+		proposed = append(proposed, &copied)
+	}
+
+	for _, change := range changes {
+		if change.ID == "" {
+			rule := &entity.FraudRule{}
+			applyFraudRuleInput(rule, change.Input)
+			if err := ValidateFraudRule(rule); err != nil {
+				return nil, err
+			}
+			proposed = append(proposed, rule)
+			continue
+		}
+
+		index, ok := byID[change.ID]
+		if !ok {
+			return nil, errors.NotFound("Fraud rule "+change.ID, nil)
+		}
+
+		if change.Delete {
+			proposed[index].Enabled = false
+			continue
+		}
+
+		applyFraudRuleInput(proposed[index], change.Input)
+		if err := ValidateFraudRule(proposed[index]); err != nil {
+			return nil, err
+		}
+	}
+
+	return proposed, nil
+}
@@ -27,6 +27,8 @@ type CreateGameTitleInput struct {
 	Banner      string
 	Attributes  []GameTitleAttributeInput
 	Status      string
+
+	HandoverSteps []HandoverStepInput
 }
 
 type GameTitleAttributeInput struct {
@@ -37,6 +39,34 @@ type GameTitleAttributeInput struct {
 	Description string
 }
 
+type HandoverStepInput struct {
+	Key              string
+	Title            string
+	Description      string
+	Required         bool
+	RequiresEvidence bool
+}
+
+func buildHandoverSteps(inputs []HandoverStepInput) ([]entity.HandoverStepTemplate, error) {
+	steps := make([]entity.HandoverStepTemplate, len(inputs))
+	seen := make(map[string]bool)
+	for i, step := range inputs {
+		if seen[step.Key] {
+			return nil, errors.BadRequest("Duplicate handover step key: "+step.Key, nil)
+		}
+		seen[step.Key] = true
+
+		steps[i] = entity.HandoverStepTemplate{
+			Key:              step.Key,
+			Title:            step.Title,
+			Description:      step.Description,
+			Required:         step.Required,
+			RequiresEvidence: step.RequiresEvidence,
+		}
+	}
+	return steps, nil
+}
+
 func (uc *GameTitleUseCase) CreateGameTitle(ctx context.Context, input CreateGameTitleInput) (*entity.GameTitle, error) {
 
 	slug := strings.ToLower(strings.ReplaceAll(input.Name, " ", "-"))
@@ -57,6 +87,11 @@ func (uc *GameTitleUseCase) CreateGameTitle(ctx context.Context, input CreateGam
 		}
 	}
 
+	handoverSteps, err := buildHandoverSteps(input.HandoverSteps)
+	if err != nil {
+		return nil, err
+	}
+
 	gameTitle := &entity.GameTitle{
 		Name:        input.Name,
 		Slug:        slug,
@@ -65,6 +100,7 @@ func (uc *GameTitleUseCase) CreateGameTitle(ctx context.Context, input CreateGam
 		Banner:      input.Banner,
 		Attributes:  attributes,
 		Status:      input.Status,
+		HandoverSteps: handoverSteps,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -127,6 +163,12 @@ func (uc *GameTitleUseCase) UpdateGameTitle(ctx context.Context, id string, inpu
 	}
 	gameTitle.Attributes = attributes
 
+	handoverSteps, err := buildHandoverSteps(input.HandoverSteps)
+	if err != nil {
+		return nil, err
+	}
+	gameTitle.HandoverSteps = handoverSteps
+
 	gameTitle.UpdatedAt = time.Now()
 
 	if err := uc.gameTitleRepo.Update(ctx, gameTitle); err != nil {
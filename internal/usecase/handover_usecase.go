@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// HandoverSLA is how long the parties have to finish the handover checklist
+// before a dispute is opened automatically.
+const HandoverSLA = 48 * time.Hour
+
+// DefaultHandoverSteps is used for game titles without their own template
+var DefaultHandoverSteps = []entity.HandoverStepTemplate{
+	{Key: "change_email", Title: "Change account email", Description: "Replace the registered email with the buyer's email", Required: true, RequiresEvidence: true},
+	{Key: "change_password", Title: "Change password", Description: "Set a new password known only to the buyer", Required: true},
+	{Key: "remove_2fa", Title: "Remove two-factor authentication", Description: "Disable authenticator apps and backup codes", Required: true, RequiresEvidence: true},
+	{Key: "unlink_social", Title: "Unlink Google/Facebook/Apple logins", Description: "Remove every third-party login bound to the account", Required: true, RequiresEvidence: true},
+	{Key: "unlink_phone", Title: "Unlink phone number", Description: "Remove the seller's phone number from the account", Required: true},
+}
+
+type HandoverUseCase struct {
+	checklistRepo   repository.HandoverChecklistRepository
+	transactionRepo repository.TransactionRepository
+	productRepo     repository.ProductRepository
+	gameTitleRepo   repository.GameTitleRepository
+	chatUseCase     *ChatUseCase
+}
+
+func NewHandoverUseCase(
+	checklistRepo repository.HandoverChecklistRepository,
+	transactionRepo repository.TransactionRepository,
+	productRepo repository.ProductRepository,
+	gameTitleRepo repository.GameTitleRepository,
+	chatUseCase *ChatUseCase,
+) *HandoverUseCase {
+	return &HandoverUseCase{
+		checklistRepo:   checklistRepo,
+		transactionRepo: transactionRepo,
+		productRepo:     productRepo,
+		gameTitleRepo:   gameTitleRepo,
+		chatUseCase:     chatUseCase,
+	}
+}
+
+type CompleteHandoverStepInput struct {
+	EvidenceURL string
+	Notes       string
+}
+
+// StartChecklist creates the handover checklist for an account sale. It returns
+// nil for products that are not accounts and the existing checklist if one was
+// already started.
+func (uc *HandoverUseCase) StartChecklist(ctx context.Context, transaction *entity.Transaction) (*entity.HandoverChecklist, error) {
+	existing, err := uc.checklistRepo.GetByTransactionID(ctx, transaction.ID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, "NOT_FOUND") {
+		return nil, err
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, transaction.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type != "account" {
+		return nil, nil
+	}
+
+	templates := DefaultHandoverSteps
+	gameTitle, err := uc.gameTitleRepo.GetByID(ctx, product.GameTitleID)
+	if err != nil {
+		log.Printf("Failed to load game title %s for handover template, using defaults: %v", product.GameTitleID, err)
+	} else if len(gameTitle.HandoverSteps) > 0 {
+		templates = gameTitle.HandoverSteps
+	}
+
+	steps := make([]entity.HandoverStep, len(templates))
+	for i, template := range templates {
+		steps[i] = entity.HandoverStep{
+			Key:              template.Key,
+			Title:            template.Title,
+			Description:      template.Description,
+			Required:         template.Required,
+			RequiresEvidence: template.RequiresEvidence,
+		}
+	}
+
+	checklist := &entity.HandoverChecklist{
+		TransactionID: transaction.ID,
+		GameTitleID:   product.GameTitleID,
+		SellerID:      transaction.SellerID,
+		BuyerID:       transaction.BuyerID,
+		Steps:         steps,
+		Status:        "in_progress",
+		DueAt:         time.Now().Add(HandoverSLA),
+	}
+
+	if err := uc.checklistRepo.Create(ctx, checklist); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Handover checklist created for transaction %s with %d steps", transaction.ID, len(steps))
+	return checklist, nil
+}
+
+// GetChecklist returns the handover checklist to a transaction party
+func (uc *HandoverUseCase) GetChecklist(ctx context.Context, transactionID, userID string) (*entity.HandoverChecklist, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != userID && transaction.SellerID != userID && transaction.AdminID != userID {
+		return nil, errors.Forbidden("You don't have permission to view this transaction", nil)
+	}
+
+	return uc.checklistRepo.GetByTransactionID(ctx, transactionID)
+}
+
+// CompleteStep - Seller ticks a handover step, optionally with screenshot evidence
+func (uc *HandoverUseCase) CompleteStep(ctx context.Context, transactionID, sellerID, stepKey string, input CompleteHandoverStepInput) (*entity.HandoverChecklist, error) {
+	checklist, err := uc.checklistRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if checklist.SellerID != sellerID {
+		return nil, errors.Forbidden("Only the seller can complete handover steps", nil)
+	}
+
+	// Steps are updated in a transaction so a concurrent buyer confirmation
+	// is not overwritten
+	var step entity.HandoverStep
+	checklist, err = uc.checklistRepo.Modify(ctx, checklist.ID, func(checklist *entity.HandoverChecklist) error {
+		if checklist.Status != "in_progress" {
+			return errors.BadRequest("Handover checklist is no longer in progress", nil)
+		}
+
+		current := findHandoverStep(checklist, stepKey)
+		if current == nil {
+			return errors.NotFound("Handover step", nil)
+		}
+
+		if current.RequiresEvidence && input.EvidenceURL == "" {
+			return errors.BadRequest("Screenshot evidence is required for this step", nil)
+		}
+
+		now := time.Now()
+		current.SellerCompleted = true
+		current.SellerCompletedAt = &now
+		current.EvidenceURL = input.EvidenceURL
+		current.SellerNotes = input.Notes
+
+		// Re-ticking a step invalidates the buyer's earlier confirmation
+		current.BuyerConfirmed = false
+		current.BuyerConfirmedAt = nil
+
+		step = *current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyParties(ctx, transactionID, fmt.Sprintf("📋 Seller completed handover step: %s. Please verify and confirm.", step.Title),
+		"handover_step_completed", map[string]interface{}{
+			"transaction_id": transactionID,
+			"step":           step.Key,
+			"evidence_url":   step.EvidenceURL,
+		})
+
+	return checklist, nil
+}
+
+// ConfirmStep - Buyer confirms a step the seller has completed
+func (uc *HandoverUseCase) ConfirmStep(ctx context.Context, transactionID, buyerID, stepKey, notes string) (*entity.HandoverChecklist, error) {
+	checklist, err := uc.checklistRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if checklist.BuyerID != buyerID {
+		return nil, errors.Forbidden("Only the buyer can confirm handover steps", nil)
+	}
+
+	checklist, err = uc.checklistRepo.Modify(ctx, checklist.ID, func(checklist *entity.HandoverChecklist) error {
+		if checklist.Status != "in_progress" {
+			return errors.BadRequest("Handover checklist is no longer in progress", nil)
+		}
+
+		step := findHandoverStep(checklist, stepKey)
+		if step == nil {
+			return errors.NotFound("Handover step", nil)
+		}
+
+		if !step.SellerCompleted {
+			return errors.BadRequest("Seller has not completed this step yet", nil)
+		}
+
+		now := time.Now()
+		step.BuyerConfirmed = true
+		step.BuyerConfirmedAt = &now
+		step.BuyerNotes = notes
+
+		if len(pendingHandoverSteps(checklist)) == 0 {
+			checklist.Status = "completed"
+			checklist.CompletedAt = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if checklist.Status == "completed" {
+		uc.notifyParties(ctx, transactionID, "✅ Account handover checklist completed by both parties.",
+			"handover_completed", map[string]interface{}{
+				"transaction_id": transactionID,
+			})
+	}
+
+	return checklist, nil
+}
+
+// EnsureComplete blocks escrow release while an account handover is unfinished.
+// Account sales always need a completed checklist; other products have none.
+func (uc *HandoverUseCase) EnsureComplete(ctx context.Context, transaction *entity.Transaction) error {
+	checklist, err := uc.checklistRepo.GetByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		if !errors.Is(err, "NOT_FOUND") {
+			return err
+		}
+
+		product, err := uc.productRepo.GetByID(ctx, transaction.ProductID)
+		if err != nil {
+			return err
+		}
+		if product.Type == "account" {
+			return errors.BadRequest("Account handover checklist has not been started", nil)
+		}
+		return nil
+	}
+
+	if checklist.Status == "completed" {
+		return nil
+	}
+
+	pending := pendingHandoverSteps(checklist)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return errors.BadRequest("Account handover is incomplete. Pending steps: "+strings.Join(pending, ", "), nil)
+}
+
+// ProcessOverdueChecklists opens a dispute for every handover that missed its SLA
+func (uc *HandoverUseCase) ProcessOverdueChecklists(ctx context.Context) (*JobResult, error) {
+	log.Printf("Processing overdue handover checklists")
+
+	checklists, err := uc.checklistRepo.ListOverdue(ctx, time.Now(), 100)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobResult{}
+
+	for _, checklist := range checklists {
+		transaction, err := uc.transactionRepo.GetByID(ctx, checklist.TransactionID)
+		if err != nil {
+			result.Fail("Failed to load transaction %s for overdue handover: %v", checklist.TransactionID, err)
+			continue
+		}
+
+		if transaction.EscrowStatus == "released" || transaction.IsDisputed {
+			continue
+		}
+
+		// Claim the checklist first so only one job instance opens the dispute
+		checklist.Status = "disputed"
+		claimed, err := uc.checklistRepo.UpdateIfStatus(ctx, checklist, "in_progress")
+		if err != nil {
+			result.Fail("Failed to claim handover checklist %s: %v", checklist.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		pending := pendingHandoverSteps(checklist)
+		reason := "Account handover not completed within SLA. Pending steps: " + strings.Join(pending, ", ")
+
+		// Open the dispute only if the transaction was not released or
+		// disputed since it was read
+		now := time.Now()
+		opened, err := uc.transactionRepo.UpdateFieldsIfUnchanged(ctx, transaction.ID, transaction.Status, transaction.EscrowStatus, map[string]interface{}{
+			"status":           "disputed",
+			"isDisputed":       true,
+			"disputeStatus":    "open",
+			"disputeCreatedAt": now,
+			"notes":            reason,
+			"updatedAt":        now,
+		})
+		if err != nil || !opened {
+			if err != nil {
+				result.Fail("Failed to open dispute for transaction %s: %v", transaction.ID, err)
+			}
+
+			// Release the claim so the next run retries
+			checklist.Status = "in_progress"
+			if err := uc.checklistRepo.Update(ctx, checklist); err != nil {
+				log.Printf("Failed to reset handover checklist %s: %v", checklist.ID, err)
+			}
+			continue
+		}
+		transaction.Status = "disputed"
+		transaction.IsDisputed = true
+		transaction.DisputeStatus = "open"
+		transaction.DisputeCreatedAt = &now
+		transaction.Notes = reason
+		transaction.UpdatedAt = now
+
+		if err := uc.transactionRepo.CreateLog(ctx, &entity.TransactionLog{
+			TransactionID: transaction.ID,
+			Status:        "disputed",
+			Notes:         reason,
+			CreatedBy:     "system",
+			CreatedAt:     now,
+		}); err != nil {
+			log.Printf("Failed to create dispute log for transaction %s: %v", transaction.ID, err)
+		}
+
+		if transaction.MiddlemanChatID != "" {
+			uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID,
+				"⚠️ Account handover was not completed in time. A dispute has been opened for admin review.",
+				"handover_disputed",
+				map[string]interface{}{
+					"transaction_id": transaction.ID,
+					"pending_steps":  pending,
+				})
+		}
+
+		result.ItemsProcessed++
+	}
+
+	log.Printf("Overdue handovers processed: %d disputes opened", result.ItemsProcessed)
+	return result, nil
+}
+
+func (uc *HandoverUseCase) notifyParties(ctx context.Context, transactionID, message, systemType string, metadata map[string]interface{}) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil || transaction.MiddlemanChatID == "" {
+		return
+	}
+
+	uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, systemType, metadata)
+}
+
+func findHandoverStep(checklist *entity.HandoverChecklist, key string) *entity.HandoverStep {
+	for i := range checklist.Steps {
+		if checklist.Steps[i].Key == key {
+			return &checklist.Steps[i]
+		}
+	}
+	return nil
+}
+
+// pendingHandoverSteps lists required steps not yet confirmed by the buyer
+func pendingHandoverSteps(checklist *entity.HandoverChecklist) []string {
+	pending := []string{}
+	for _, step := range checklist.Steps {
+		if step.Required && !(step.SellerCompleted && step.BuyerConfirmed) {
+			pending = append(pending, step.Key)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/infrastructure/imagehash"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+// productImageEntityType is the FileMetadata entity type of product images
+const productImageEntityType = "product"
+
+type ImageMatchUseCase struct {
+	fileMetadataRepo repository.FileMetadataRepository
+	imageMatchRepo   repository.ImageMatchRepository
+	productRepo      repository.ProductRepository
+	moderation       *ListingModerationUseCase
+}
+
+func NewImageMatchUseCase(
+	fileMetadataRepo repository.FileMetadataRepository,
+	imageMatchRepo repository.ImageMatchRepository,
+	productRepo repository.ProductRepository,
+	moderation *ListingModerationUseCase,
+) *ImageMatchUseCase {
+	return &ImageMatchUseCase{
+		fileMetadataRepo: fileMetadataRepo,
+		imageMatchRepo:   imageMatchRepo,
+		productRepo:      productRepo,
+		moderation:       moderation,
+	}
+}
+
+// HashImage stores the perceptual hash of the image on its metadata. Formats
+// without a decoder (WebP, AVIF) are left unhashed and never match.
+func HashImage(metadata *entity.FileMetadata, content io.Reader) {
+	hash, err := imagehash.Compute(content)
+	if err != nil {
+		log.Printf("Image %s (%s) not hashed: %v", metadata.ID, metadata.FileType, err)
+		return
+	}
+
+	metadata.PerceptualHash = imagehash.Format(hash)
+	metadata.HashBands = imagehash.Bands(hash)
+}
+
+// HashStoredImage hashes an image whose metadata is already saved
+func (uc *ImageMatchUseCase) HashStoredImage(ctx context.Context, fileID string, content io.Reader) (*entity.FileMetadata, error) {
+	metadata, err := uc.fileMetadataRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	HashImage(metadata, content)
+	if metadata.PerceptualHash == "" {
+		return metadata, nil
+	}
+
+	if err := uc.fileMetadataRepo.Update(ctx, metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// FindImageMatches compares a listing's new images with the images of other
+// sellers' listings. Only earlier uploads count, so the original listing is
+// never the one flagged.
+func (uc *ImageMatchUseCase) FindImageMatches(ctx context.Context, product *entity.Product, files []*entity.FileMetadata) ([]*entity.ImageMatch, error) {
+	matches := []*entity.ImageMatch{}
+	products := map[string]*entity.Product{}
+
+	for _, file := range files {
+		hash, err := imagehash.Parse(file.PerceptualHash)
+		if file.PerceptualHash == "" || err != nil {
+			continue
+		}
+
+		candidates, err := uc.fileMetadataRepo.ListByHashBands(ctx, productImageEntityType, file.HashBands)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.EntityID == product.ID || candidate.UploadedBy == product.SellerID || !candidate.CreatedAt.Before(file.CreatedAt) {
+				continue
+			}
+
+			candidateHash, err := imagehash.Parse(candidate.PerceptualHash)
+			if err != nil {
+				continue
+			}
+			distance := imagehash.Distance(hash, candidateHash)
+			if distance > imagehash.MatchDistance {
+				continue
+			}
+
+			matched, ok := products[candidate.EntityID]
+			if !ok {
+				matched, err = uc.productRepo.GetByID(ctx, candidate.EntityID)
+				if err != nil {
+					matched = nil
+				}
+				products[candidate.EntityID] = matched
+			}
+			if matched == nil || matched.DeletedAt != nil || matched.SellerID == product.SellerID {
+				continue
+			}
+
+			matches = append(matches, &entity.ImageMatch{
+				ProductID:        product.ID,
+				SellerID:         product.SellerID,
+				FileID:           file.ID,
+				ImageURL:         file.URL,
+				MatchedProductID: matched.ID,
+				MatchedSellerID:  matched.SellerID,
+				MatchedFileID:    candidate.ID,
+				MatchedImageURL:  candidate.URL,
+				Distance:         distance,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	return matches, nil
+}
+
+// CheckProductImages looks for reused images among files just added to a
+// listing and holds the listing for review when any are found
+func (uc *ImageMatchUseCase) CheckProductImages(ctx context.Context, productID string, files []*entity.FileMetadata) error {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	matches, err := uc.FindImageMatches(ctx, product, files)
+	if err != nil || len(matches) == 0 {
+		return err
+	}
+
+	findings := []entity.ListingFinding{}
+	flagged := map[string]bool{}
+	for _, match := range matches {
+		key := match.FileID + "/" + match.MatchedProductID
+		if flagged[key] {
+			continue
+		}
+		flagged[key] = true
+
+		findings = append(findings, entity.ListingFinding{
+			Check:   ListingCheckDuplicateImage,
+			Field:   "images",
+			Match:   match.MatchedProductID,
+			Message: fmt.Sprintf("An image closely matches one of another seller's listing (%d bits apart)", match.Distance),
+		})
+	}
+
+	if uc.moderation != nil {
+		review, err := uc.moderation.Flag(ctx, product, ListingReviewTriggerImageMatch, findings)
+		if err != nil {
+			log.Printf("Error flagging product %s for image matches: %v", product.ID, err)
+		} else if review != nil {
+			for _, match := range matches {
+				match.ReviewID = review.ID
+			}
+		}
+	}
+
+	if err := uc.imageMatchRepo.Create(ctx, matches); err != nil {
+		return err
+	}
+
+	log.Printf("Product %s has %d images matching other sellers' listings", product.ID, len(matches))
+	return nil
+}
+
+// CheckProductImagesAsync runs CheckProductImages in the background so
+// uploads are not slowed down by the lookups
+func (uc *ImageMatchUseCase) CheckProductImagesAsync(productID string, files []*entity.FileMetadata) {
+	go func() {
+		if err := uc.CheckProductImages(context.Background(), productID, files); err != nil {
+			log.Printf("Error checking images of product %s: %v", productID, err)
+		}
+	}()
+}
+
+// ImageMatchComparison puts a flagged listing next to the one its images
+// appear to come from
+type ImageMatchComparison struct {
+	Match          *entity.ImageMatch `json:"match"`
+	Product        *entity.Product    `json:"product"`
+	MatchedProduct *entity.Product    `json:"matched_product"`
+}
+
+// CompareImageMatches returns the matches of a listing side by side with
+// the listings they match, for the admin reviewing it
+func (uc *ImageMatchUseCase) CompareImageMatches(ctx context.Context, productID string) ([]*ImageMatchComparison, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := uc.imageMatchRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	products := map[string]*entity.Product{}
+	comparisons := make([]*ImageMatchComparison, 0, len(matches))
+	for _, match := range matches {
+		matched, ok := products[match.MatchedProductID]
+		if !ok {
+			matched, err = uc.productRepo.GetByID(ctx, match.MatchedProductID)
+			if err != nil && !errors.Is(err, "NOT_FOUND") {
+				return nil, err
+			}
+			products[match.MatchedProductID] = matched
+		}
+
+		comparisons = append(comparisons, &ImageMatchComparison{
+			Match:          match,
+			Product:        product,
+			MatchedProduct: matched,
+		})
+	}
+
+	return comparisons, nil
+}
+
+// CompareReviewImageMatches is CompareImageMatches for the listing of a
+// review round
+func (uc *ImageMatchUseCase) CompareReviewImageMatches(ctx context.Context, reviewID string) ([]*ImageMatchComparison, error) {
+	if uc.moderation == nil {
+		return nil, errors.NotFound("Listing review", nil)
+	}
+
+	review, err := uc.moderation.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.CompareImageMatches(ctx, review.ProductID)
+}
+
+func (uc *ImageMatchUseCase) ListImageMatches(ctx context.Context, pagination utils.PaginationParams) ([]*entity.ImageMatch, *utils.PageInfo, error) {
+	return uc.imageMatchRepo.ListPage(ctx, pagination)
+}
@@ -0,0 +1,631 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
+	"pasargamex/internal/infrastructure/websocket"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+const (
+	InventoryStatusAvailable = "available"
+	InventoryStatusDelivered = "delivered"
+	InventoryStatusReported  = "reported"
+	InventoryStatusRemoved   = "removed"
+
+	InventoryFormatLines = "lines"
+	InventoryFormatCSV   = "csv"
+
+	// MaxInventoryUpload caps the codes accepted in one upload
+	MaxInventoryUpload = 5000
+	// DefaultLowStockThreshold applies when the product sets none
+	DefaultLowStockThreshold = 5
+	// MaxCodeReplacements is how many invalid codes a buyer can swap per
+	// order before it has to go through a dispute
+	MaxCodeReplacements = 2
+	// CodeReportWindow is how long after delivery a code can be reported
+	CodeReportWindow = 72 * time.Hour
+)
+
+type InventoryUseCase struct {
+	inventoryRepo   repository.InventoryRepository
+	productRepo     repository.ProductRepository
+	transactionRepo repository.TransactionRepository
+	credentialVault service.CredentialVault
+	wsManager       *websocket.Manager
+}
+
+func NewInventoryUseCase(
+	inventoryRepo repository.InventoryRepository,
+	productRepo repository.ProductRepository,
+	transactionRepo repository.TransactionRepository,
+	credentialVault service.CredentialVault,
+	wsManager *websocket.Manager,
+) *InventoryUseCase {
+	return &InventoryUseCase{
+		inventoryRepo:   inventoryRepo,
+		productRepo:     productRepo,
+		transactionRepo: transactionRepo,
+		credentialVault: credentialVault,
+		wsManager:       wsManager,
+	}
+}
+
+// InventoryEntry is one parsed code or credential set, before sealing
+type InventoryEntry struct {
+	Fields map[string]interface{}
+	Hint   string
+}
+
+// ParseInventoryEntries reads a pasted list (one code per line) or a CSV file
+// whose header row names the fields of each credential set, e.g.
+// "email,password". Blank lines are skipped.
+func ParseInventoryEntries(format, content string) ([]InventoryEntry, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", InventoryFormatLines:
+		entries := []InventoryEntry{}
+		for _, line := range strings.Split(content, "\n") {
+			code := strings.TrimSpace(line)
+			if code == "" {
+				continue
+			}
+			entries = append(entries, InventoryEntry{
+				Fields: map[string]interface{}{"code": code},
+				Hint:   maskCode(code),
+			})
+		}
+		return entries, nil
+
+	case InventoryFormatCSV:
+		return parseInventoryCSV(content)
+
+	default:
+		return nil, errors.BadRequest("format must be lines or csv", nil)
+	}
+}
+
+func parseInventoryCSV(content string) ([]InventoryEntry, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []InventoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, errors.BadRequest("Invalid CSV header", err)
+	}
+
+	seen := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name == "" || seen[name] {
+			return nil, errors.BadRequest(fmt.Sprintf("CSV column %d needs a unique name", i+1), nil)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+
+	entries := []InventoryEntry{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.BadRequest("Invalid CSV row", err)
+		}
+
+		fields := map[string]interface{}{}
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				fields[header[i]] = value
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		entries = append(entries, InventoryEntry{
+			Fields: fields,
+			Hint:   maskCode(strings.TrimSpace(record[0])),
+		})
+	}
+
+	return entries, nil
+}
+
+// maskCode keeps the last four characters so sellers can tell codes apart
+func maskCode(code string) string {
+	runes := []rune(code)
+	if len(runes) < 8 {
+		return "••••"
+	}
+	return "••••" + string(runes[len(runes)-4:])
+}
+
+// inventoryFingerprint identifies a code within a product regardless of field
+// order or case, so re-uploading the same file adds nothing
+func inventoryFingerprint(productID string, fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	hash.Write([]byte(productID))
+	for _, key := range keys {
+		hash.Write([]byte{0})
+		hash.Write([]byte(key + "=" + strings.ToLower(fmt.Sprint(fields[key]))))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type UploadInventoryInput struct {
+	VariantID string `json:"variant_id,omitempty"`
+	Format    string `json:"format"` // lines (default) or csv
+	Content   string `json:"content"`
+}
+
+type InventoryUploadResult struct {
+	BatchID    string `json:"batch_id"`
+	Added      int    `json:"added"`
+	Duplicates int    `json:"duplicates"`
+	Available  int    `json:"available"`
+}
+
+// UploadCodes seals and stores a batch of codes for a product or variant
+func (uc *InventoryUseCase) UploadCodes(ctx context.Context, sellerID, productID string, input UploadInventoryInput) (*InventoryUploadResult, error) {
+	product, err := uc.sellerInventoryProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.HasVariants() {
+		if product.Variant(input.VariantID) == nil {
+			return nil, errors.BadRequest("variant_id must name one of the product's variants", nil)
+		}
+	} else if input.VariantID != "" {
+		return nil, errors.BadRequest("Product has no variants", nil)
+	}
+
+	entries, err := ParseInventoryEntries(input.Format, input.Content)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.BadRequest("No codes found in the upload", nil)
+	}
+	if len(entries) > MaxInventoryUpload {
+		return nil, errors.BadRequest(fmt.Sprintf("Upload at most %d codes at a time", MaxInventoryUpload), nil)
+	}
+
+	existing, err := uc.inventoryRepo.Fingerprints(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InventoryUploadResult{BatchID: uuid.New().String()}
+	codes := make([]*entity.InventoryCode, 0, len(entries))
+
+	for _, entry := range entries {
+		fingerprint := inventoryFingerprint(product.ID, entry.Fields)
+		if existing[fingerprint] {
+			result.Duplicates++
+			continue
+		}
+		existing[fingerprint] = true
+
+		sealed, err := sealCredentials(ctx, uc.credentialVault, entry.Fields)
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, &entity.InventoryCode{
+			ProductID:     product.ID,
+			VariantID:     input.VariantID,
+			SellerID:      sellerID,
+			BatchID:       result.BatchID,
+			EncryptedCode: sealed,
+			Fingerprint:   fingerprint,
+			Hint:          entry.Hint,
+			Status:        InventoryStatusAvailable,
+		})
+	}
+
+	if err := uc.inventoryRepo.CreateCodes(ctx, codes); err != nil {
+		return nil, err
+	}
+	result.Added = len(codes)
+
+	_, available, err := uc.saveStock(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Available = available[input.VariantID]
+
+	log.Printf("Seller %s added %d codes (%d duplicates) to product %s", sellerID, result.Added, result.Duplicates, product.ID)
+	return result, nil
+}
+
+// ListCodes lists a product's codes without their contents
+func (uc *InventoryUseCase) ListCodes(ctx context.Context, sellerID, productID, variantID, status string, pagination utils.PaginationParams) ([]*entity.InventoryCode, *utils.PageInfo, error) {
+	if _, err := uc.sellerInventoryProduct(ctx, sellerID, productID); err != nil {
+		return nil, nil, err
+	}
+
+	return uc.inventoryRepo.ListPage(ctx, productID, variantID, status, pagination)
+}
+
+// RemoveCode withdraws an unsold code from the pool
+func (uc *InventoryUseCase) RemoveCode(ctx context.Context, sellerID, productID, codeID string) error {
+	product, err := uc.sellerInventoryProduct(ctx, sellerID, productID)
+	if err != nil {
+		return err
+	}
+
+	code, err := uc.inventoryRepo.GetByID(ctx, codeID)
+	if err != nil {
+		return err
+	}
+	if code.ProductID != product.ID {
+		return errors.NotFound("Inventory code", nil)
+	}
+	if code.Status != InventoryStatusAvailable {
+		return errors.BadRequest("Only available codes can be removed", nil)
+	}
+
+	code.Status = InventoryStatusRemoved
+	code.EncryptedCode = nil
+	if err := uc.inventoryRepo.Update(ctx, code); err != nil {
+		return err
+	}
+
+	_, _, err = uc.saveStock(ctx, product.ID)
+	return err
+}
+
+type VariantInventory struct {
+	VariantID string `json:"variant_id,omitempty"`
+	Label     string `json:"label,omitempty"`
+	Available int    `json:"available"`
+	Delivered int    `json:"delivered"`
+	Reported  int    `json:"reported"`
+	Removed   int    `json:"removed"`
+	LowStock  bool   `json:"low_stock"`
+}
+
+type InventorySummary struct {
+	ProductID         string              `json:"product_id"`
+	LowStockThreshold int                 `json:"low_stock_threshold"`
+	Variants          []*VariantInventory `json:"variants"`
+}
+
+// Summary counts a product's codes by status for the seller dashboard
+func (uc *InventoryUseCase) Summary(ctx context.Context, sellerID, productID string) (*InventorySummary, error) {
+	product, err := uc.sellerInventoryProduct(ctx, sellerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.inventoryRepo.CountByStatus(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &InventorySummary{ProductID: product.ID, LowStockThreshold: lowStockThreshold(product)}
+
+	add := func(variantID, label string) {
+		count := counts[variantID]
+		summary.Variants = append(summary.Variants, &VariantInventory{
+			VariantID: variantID,
+			Label:     label,
+			Available: count[InventoryStatusAvailable],
+			Delivered: count[InventoryStatusDelivered],
+			Reported:  count[InventoryStatusReported],
+			Removed:   count[InventoryStatusRemoved],
+			LowStock:  count[InventoryStatusAvailable] <= summary.LowStockThreshold,
+		})
+	}
+
+	if product.HasVariants() {
+		for _, variant := range product.Variants {
+			add(variant.ID, variant.Label)
+		}
+	} else {
+		add("", "")
+	}
+
+	return summary, nil
+}
+
+// DeliverCode claims a code from the pool for a paid transaction and puts its
+// sealed contents on the transaction. The caller saves the transaction.
+func (uc *InventoryUseCase) DeliverCode(ctx context.Context, product *entity.Product, transaction *entity.Transaction) error {
+	code, err := uc.inventoryRepo.ClaimCode(ctx, product.ID, transaction.VariantID, transaction.ID)
+	if err != nil {
+		if errors.Is(err, "NOT_FOUND") {
+			uc.notifySeller(product, "inventory_empty", map[string]interface{}{
+				"variant_id":     transaction.VariantID,
+				"transaction_id": transaction.ID,
+			}, fmt.Sprintf("⚠️ %s has a paid order but no codes left. Upload codes to deliver it.", product.Title))
+			return errors.BadRequest("No codes left to deliver", err)
+		}
+		return err
+	}
+
+	sealed := *code.EncryptedCode
+	transaction.EncryptedCredentials = &sealed
+	transaction.Credentials = nil
+	transaction.InventoryCodeID = code.ID
+
+	log.Printf("Inventory code %s delivered to transaction %s", code.ID, transaction.ID)
+	return nil
+}
+
+// RecordSale counts the sale, or re-derives sold count and stock from the
+// pool for inventory products, in one product transaction so concurrent
+// sales are not lost, and alerts the seller when stock runs low
+func (uc *InventoryUseCase) RecordSale(ctx context.Context, product *entity.Product, variantID string) {
+	var available map[string]int
+	updated, err := uc.productRepo.Modify(ctx, product.ID, func(current *entity.Product) error {
+		if !current.UsesCodeInventory {
+			recordProductSale(current, variantID)
+			return nil
+		}
+
+		var err error
+		available, err = uc.syncStock(ctx, current)
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to update product status and sold count: %v", err)
+		return
+	}
+	*product = *updated
+
+	if available != nil {
+		uc.checkLowStock(product, variantID, available[variantID])
+	}
+}
+
+// saveStock re-derives the product's stock from the pool and saves it in one
+// product transaction. It returns the saved product and the available codes
+// per variant ID.
+func (uc *InventoryUseCase) saveStock(ctx context.Context, productID string) (*entity.Product, map[string]int, error) {
+	var available map[string]int
+	product, err := uc.productRepo.Modify(ctx, productID, func(product *entity.Product) error {
+		var err error
+		available, err = uc.syncStock(ctx, product)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, available, nil
+}
+
+// syncStock derives sold count and stock from the pool alone, for the product
+// or each variant: delivered codes are sold and stock is those plus the
+// available codes. It flips the listing between active and sold_out and
+// returns the available codes per variant ID.
+func (uc *InventoryUseCase) syncStock(ctx context.Context, product *entity.Product) (map[string]int, error) {
+	counts, err := uc.inventoryRepo.CountByStatus(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	available := map[string]int{}
+	delivered := map[string]int{}
+	total := 0
+	for variantID, count := range counts {
+		available[variantID] = count[InventoryStatusAvailable]
+		delivered[variantID] = count[InventoryStatusDelivered]
+	}
+
+	if product.HasVariants() {
+		for i := range product.Variants {
+			variant := &product.Variants[i]
+			variant.SoldCount = delivered[variant.ID]
+			variant.Stock = variant.SoldCount + available[variant.ID]
+			total += available[variant.ID]
+		}
+		product.SyncVariantTotals()
+	} else {
+		product.SoldCount = delivered[""]
+		product.Stock = product.SoldCount + available[""]
+		total = available[""]
+	}
+
+	if total == 0 && product.Status == "active" {
+		product.Status = "sold_out"
+	} else if total > 0 && product.Status == "sold_out" {
+		product.Status = "active"
+	}
+	product.UpdatedAt = time.Now()
+
+	return available, nil
+}
+
+func lowStockThreshold(product *entity.Product) int {
+	if product.LowStockThreshold > 0 {
+		return product.LowStockThreshold
+	}
+	return DefaultLowStockThreshold
+}
+
+// checkLowStock alerts when a claim brings the pool down to the threshold and
+// again when it runs out
+func (uc *InventoryUseCase) checkLowStock(product *entity.Product, variantID string, available int) {
+	if available != lowStockThreshold(product) && available != 0 {
+		return
+	}
+
+	label := product.Title
+	if variant := product.Variant(variantID); variant != nil {
+		label += " - " + variant.Label
+	}
+
+	message := fmt.Sprintf("📦 Only %d codes left for %s.", available, label)
+	if available == 0 {
+		message = fmt.Sprintf("📦 %s is out of codes and no longer sells.", label)
+	}
+
+	uc.notifySeller(product, "inventory_low_stock", map[string]interface{}{
+		"variant_id": variantID,
+		"available":  available,
+	}, message)
+}
+
+func (uc *InventoryUseCase) notifySeller(product *entity.Product, notificationType string, data map[string]interface{}, message string) {
+	log.Printf("Inventory alert for seller %s on product %s: %s", product.SellerID, product.ID, message)
+
+	if uc.wsManager == nil {
+		return
+	}
+
+	notification := map[string]interface{}{
+		"type":       notificationType,
+		"product_id": product.ID,
+		"message":    message,
+		"timestamp":  time.Now(),
+	}
+	for key, value := range data {
+		notification[key] = value
+	}
+
+	if notificationJSON, err := json.Marshal(notification); err == nil {
+		uc.wsManager.SendToUser(product.SellerID, notificationJSON)
+	}
+}
+
+// ReportInvalidCode lets the buyer swap a delivered code that does not work
+// for a fresh one from the pool. The reported code is kept for the seller to
+// review; repeated reports on one order go through a dispute instead.
+func (uc *InventoryUseCase) ReportInvalidCode(ctx context.Context, buyerID, transactionID, reason string) (*entity.Transaction, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.BuyerID != buyerID {
+		return nil, errors.Forbidden("Only the buyer can report a code", nil)
+	}
+
+	if transaction.InventoryCodeID == "" {
+		return nil, errors.BadRequest("This order was not delivered from a code inventory", nil)
+	}
+
+	if transaction.IsDisputed || transaction.Status == "refunded" || transaction.Status == "cancelled" {
+		return nil, errors.BadRequest("This order can no longer be changed", nil)
+	}
+
+	deliveredAt := transaction.CredentialsDeliveredAt
+	if deliveredAt == nil {
+		deliveredAt = transaction.PaymentAt
+	}
+	if deliveredAt != nil && time.Since(*deliveredAt) > CodeReportWindow {
+		return nil, errors.BadRequest("Codes can only be reported within 72 hours of delivery. Please open a dispute", nil)
+	}
+
+	if transaction.CodeReplacements >= MaxCodeReplacements {
+		return nil, errors.BadRequest("This order has reached its replacement limit. Please open a dispute", nil)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, transaction.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := uc.inventoryRepo.GetByID(ctx, transaction.InventoryCodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A report whose replacement failed for lack of stock is retried as is
+	if code.Status != InventoryStatusReported {
+		now := time.Now()
+		code.Status = InventoryStatusReported
+		code.ReportedAt = &now
+		code.ReportReason = reason
+		if err := uc.inventoryRepo.Update(ctx, code); err != nil {
+			return nil, err
+		}
+
+		uc.notifySeller(product, "inventory_code_reported", map[string]interface{}{
+			"code_id":        code.ID,
+			"transaction_id": transaction.ID,
+			"reason":         reason,
+		}, fmt.Sprintf("🚩 A buyer reported code %s of %s as invalid.", code.Hint, product.Title))
+	}
+
+	if err := uc.DeliverCode(ctx, product, transaction); err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, errors.BadRequest("No replacement code is available yet. The seller has been notified", err)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	transaction.CodeReplacements++
+	transaction.CredentialsDeliveredAt = &now
+	transaction.UpdatedAt = now
+
+	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := uc.transactionRepo.CreateLog(ctx, &entity.TransactionLog{
+		TransactionID: transaction.ID,
+		Status:        transaction.Status,
+		Notes:         fmt.Sprintf("Code %s reported invalid and replaced", code.Hint),
+		CreatedBy:     buyerID,
+		CreatedAt:     now,
+	}); err != nil {
+		log.Printf("Failed to log code replacement for transaction %s: %v", transaction.ID, err)
+	}
+
+	if updated, available, err := uc.saveStock(ctx, product.ID); err != nil {
+		log.Printf("Failed to sync inventory stock for product %s: %v", product.ID, err)
+	} else {
+		uc.checkLowStock(updated, transaction.VariantID, available[transaction.VariantID])
+	}
+
+	return transaction, nil
+}
+
+func (uc *InventoryUseCase) sellerInventoryProduct(ctx context.Context, sellerID, productID string) (*entity.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to manage this product's inventory", nil)
+	}
+
+	if !product.UsesCodeInventory {
+		return nil, errors.BadRequest("Enable code inventory on the product first", nil)
+	}
+
+	return product, nil
+}
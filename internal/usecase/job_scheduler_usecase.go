@@ -0,0 +1,328 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+const maxJobRunErrors = 50
+
+const (
+	// jobLeaseTTL bounds how long a crashed holder can block a job. The
+	// holder renews the lease every jobLeaseRenewInterval while it runs.
+	jobLeaseTTL           = 2 * time.Minute
+	jobLeaseRenewInterval = 30 * time.Second
+
+	// jobPollInterval caps how often an instance checks whether a job is due.
+	// Due times come from the lease, so restarts do not reset the schedule.
+	jobPollInterval = 5 * time.Minute
+)
+
+// JobResult is reported by a job after each run and stored in the run history
+type JobResult struct {
+	ItemsProcessed int
+	Errors         []string
+}
+
+// Fail logs a per-item failure and keeps it for the run history
+func (r *JobResult) Fail(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	log.Print(message)
+
+	if len(r.Errors) < maxJobRunErrors {
+		r.Errors = append(r.Errors, message)
+	}
+}
+
+// JobFunc processes one batch of work. Implementations must claim each item
+// with a compare-and-set so an overlapping run never handles it twice.
+type JobFunc func(ctx context.Context) (*JobResult, error)
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+
+	// Daily jobs run once a day at dailyAt past midnight in location
+	// instead of every interval
+	daily    bool
+	dailyAt  time.Duration
+	location *time.Location
+}
+
+// dueSince returns the time the job must not have started after to be due
+// at now. Interval jobs get half a poll period of slack so ticks landing just
+// before the due time do not skip a whole period.
+func (job *scheduledJob) dueSince(now time.Time, period time.Duration) time.Time {
+	if job.daily {
+		return LastDailyRun(now, job.dailyAt, job.location)
+	}
+	return now.Add(-(job.interval - period/2))
+}
+
+func (job *scheduledJob) schedule() string {
+	if job.daily {
+		at := time.Date(2000, 1, 1, 0, 0, 0, 0, job.location).Add(job.dailyAt)
+		return "daily at " + at.Format("15:04 MST")
+	}
+	return "every " + job.interval.String()
+}
+
+// LastDailyRun returns the latest time of day at, in loc, that is not after now
+func LastDailyRun(now time.Time, at time.Duration, loc *time.Location) time.Time {
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	scheduled := midnight.Add(at)
+	if scheduled.After(local) {
+		scheduled = midnight.AddDate(0, 0, -1).Add(at)
+	}
+	return scheduled
+}
+
+type JobInfo struct {
+	Name               string           `json:"name"`
+	Interval           string           `json:"interval"`
+	Lease              *entity.JobLease `json:"lease,omitempty"`
+	HeldByThisInstance bool             `json:"held_by_this_instance"`
+}
+
+// JobSchedulerUseCase runs background jobs on every instance, but a Firestore
+// lease makes sure only one instance executes a job per interval. Where
+// instances do not live long enough for in-process tickers, an external
+// scheduler triggers jobs through RunNow instead of Start.
+type JobSchedulerUseCase struct {
+	jobRepo    repository.JobRepository
+	instanceID string
+
+	mu   sync.Mutex
+	jobs map[string]*scheduledJob
+}
+
+func NewJobSchedulerUseCase(jobRepo repository.JobRepository) *JobSchedulerUseCase {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &JobSchedulerUseCase{
+		jobRepo:    jobRepo,
+		instanceID: hostname + "-" + uuid.New().String()[:8],
+		jobs:       make(map[string]*scheduledJob),
+	}
+}
+
+// Register adds a job. Must be called before Start.
+func (uc *JobSchedulerUseCase) Register(name string, interval time.Duration, run JobFunc) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.jobs[name] = &scheduledJob{
+		name:     name,
+		interval: interval,
+		run:      run,
+	}
+}
+
+// RegisterDaily adds a job that runs once a day at the given time past
+// midnight in location. Must be called before Start.
+func (uc *JobSchedulerUseCase) RegisterDaily(name string, at time.Duration, location *time.Location, run JobFunc) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.jobs[name] = &scheduledJob{
+		name:     name,
+		interval: 24 * time.Hour,
+		run:      run,
+		daily:    true,
+		dailyAt:  at,
+		location: location,
+	}
+}
+
+// Start launches a ticker per registered job
+func (uc *JobSchedulerUseCase) Start(ctx context.Context) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for _, job := range uc.jobs {
+		go uc.loop(ctx, job)
+		log.Printf("Job %s scheduled %s on instance %s", job.name, job.schedule(), uc.instanceID)
+	}
+}
+
+func (uc *JobSchedulerUseCase) loop(ctx context.Context, job *scheduledJob) {
+	period := min(job.interval, jobPollInterval)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := uc.runOnce(ctx, job, job.dueSince(time.Now(), period)); err != nil {
+				log.Printf("Failed to acquire lease for job %s: %v", job.name, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunNow executes a job immediately unless another run holds its lease. It
+// is the entry point for external schedulers and manual runs.
+func (uc *JobSchedulerUseCase) RunNow(ctx context.Context, name string) (*entity.JobRun, error) {
+	uc.mu.Lock()
+	job, ok := uc.jobs[name]
+	uc.mu.Unlock()
+	if !ok {
+		return nil, errors.NotFound("Job", nil)
+	}
+
+	run, err := uc.runOnce(ctx, job, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.Conflict("Job is already running")
+	}
+
+	return run, nil
+}
+
+// runOnce executes the job if this instance acquires the lease and the job
+// has not started since dueSince. It returns nil when the job was skipped.
+// The lease is short and renewed while the job runs, so a crashed holder is
+// replaced within jobLeaseTTL.
+func (uc *JobSchedulerUseCase) runOnce(ctx context.Context, job *scheduledJob, dueSince time.Time) (*entity.JobRun, error) {
+	acquired, err := uc.jobRepo.AcquireLease(ctx, job.name, uc.instanceID, jobLeaseTTL, dueSince)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, nil
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go uc.keepLease(jobCtx, cancel, job.name)
+
+	run := &entity.JobRun{
+		JobName:    job.name,
+		InstanceID: uc.instanceID,
+		Status:     "running",
+		StartedAt:  time.Now(),
+	}
+	if err := uc.jobRepo.CreateRun(ctx, run); err != nil {
+		log.Printf("Failed to record run for job %s: %v", job.name, err)
+	}
+
+	result, err := job.run(jobCtx)
+	if result == nil {
+		result = &JobResult{}
+	}
+	cancel()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.ItemsProcessed = result.ItemsProcessed
+	run.Errors = result.Errors
+	run.Status = "succeeded"
+	if err != nil {
+		log.Printf("Job %s failed: %v", job.name, err)
+		run.Status = "failed"
+		run.Errors = append(run.Errors, err.Error())
+	}
+
+	if run.ID != "" {
+		if err := uc.jobRepo.UpdateRun(ctx, run); err != nil {
+			log.Printf("Failed to update run for job %s: %v", job.name, err)
+		}
+	}
+
+	if err := uc.jobRepo.ReleaseLease(ctx, job.name, uc.instanceID); err != nil {
+		log.Printf("Failed to release lease for job %s: %v", job.name, err)
+	}
+
+	return run, nil
+}
+
+// keepLease renews the job lease until ctx ends. Losing the lease, or
+// failing to renew it before it could expire, cancels the job so two
+// instances never run it at once.
+func (uc *JobSchedulerUseCase) keepLease(ctx context.Context, cancel context.CancelFunc, jobName string) {
+	ticker := time.NewTicker(jobLeaseRenewInterval)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			renewed, err := uc.jobRepo.RenewLease(ctx, jobName, uc.instanceID, jobLeaseTTL)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil && renewed {
+				renewedAt = time.Now()
+				continue
+			}
+			if err == nil || time.Since(renewedAt) > jobLeaseTTL-jobLeaseRenewInterval {
+				log.Printf("Lost lease for job %s, stopping run: %v", jobName, err)
+				cancel()
+				return
+			}
+			log.Printf("Failed to renew lease for job %s: %v", jobName, err)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ListJobs returns the registered jobs with their current lease holder
+func (uc *JobSchedulerUseCase) ListJobs(ctx context.Context) ([]*JobInfo, error) {
+	uc.mu.Lock()
+	jobs := make([]*scheduledJob, 0, len(uc.jobs))
+	for _, job := range uc.jobs {
+		jobs = append(jobs, job)
+	}
+	uc.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].name < jobs[j].name })
+
+	infos := make([]*JobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		info := &JobInfo{
+			Name:     job.name,
+			Interval: job.schedule(),
+		}
+
+		lease, err := uc.jobRepo.GetLease(ctx, job.name)
+		if err != nil && !errors.Is(err, "NOT_FOUND") {
+			return nil, err
+		}
+		if lease != nil {
+			info.Lease = lease
+			info.HeldByThisInstance = lease.HolderID == uc.instanceID && lease.ExpiresAt.After(time.Now())
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// ListRuns returns the run history, newest first. An empty job name lists all jobs.
+func (uc *JobSchedulerUseCase) ListRuns(ctx context.Context, jobName string, page, limit int) ([]*entity.JobRun, int64, error) {
+	offset := (page - 1) * limit
+	return uc.jobRepo.ListRuns(ctx, jobName, limit, offset)
+}
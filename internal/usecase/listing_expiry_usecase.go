@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
+)
+
+const (
+	// ProductStatusExpired listings are hidden from browsing and search
+	// until the seller renews them
+	ProductStatusExpired = "expired"
+
+	ListingExpiryNone   = ""
+	ListingExpiryRemind = "remind"
+	ListingExpiryExpire = "expire"
+
+	NotificationTypeListingExpiring = "listing_expiring"
+	NotificationTypeListingExpired  = "listing_expired"
+)
+
+type ListingExpiryUseCase struct {
+	productRepo         repository.ProductRepository
+	userRepo            repository.UserRepository
+	searchIndex         service.ProductSearchIndex
+	notificationUseCase *NotificationUseCase
+	expireAfter         time.Duration
+	remindBefore        time.Duration
+}
+
+// NewListingExpiryUseCase expires listings after expireAfter without seller
+// activity, reminding the seller remindBefore ahead. A zero expireAfter
+// turns expiry off.
+func NewListingExpiryUseCase(
+	productRepo repository.ProductRepository,
+	userRepo repository.UserRepository,
+	searchIndex service.ProductSearchIndex,
+	notificationUseCase *NotificationUseCase,
+	expireAfter time.Duration,
+	remindBefore time.Duration,
+) *ListingExpiryUseCase {
+	return &ListingExpiryUseCase{
+		productRepo:         productRepo,
+		userRepo:            userRepo,
+		searchIndex:         searchIndex,
+		notificationUseCase: notificationUseCase,
+		expireAfter:         expireAfter,
+		remindBefore:        min(max(remindBefore, 0), expireAfter),
+	}
+}
+
+func (uc *ListingExpiryUseCase) Enabled() bool {
+	return uc != nil && uc.expireAfter > 0
+}
+
+// ListingExpiryAction decides what the expiry job does with an active
+// listing. The seller is always reminded first, and the listing expires no
+// sooner than remindBefore after the reminder, so switching expiry on never
+// hides listings without warning. A reminder sent before the seller's latest
+// activity belongs to an earlier period and no longer counts.
+func ListingExpiryAction(product *entity.Product, sellerLastSeen, now time.Time, expireAfter, remindBefore time.Duration) string {
+	if expireAfter <= 0 || product.Status != "active" {
+		return ListingExpiryNone
+	}
+
+	lastActivity := product.LastActivity(sellerLastSeen)
+	inactive := now.Sub(lastActivity)
+	reminded := product.ExpiryRemindedAt != nil && product.ExpiryRemindedAt.After(lastActivity)
+
+	if !reminded {
+		if inactive >= expireAfter-remindBefore {
+			return ListingExpiryRemind
+		}
+		return ListingExpiryNone
+	}
+
+	if inactive >= expireAfter && now.Sub(*product.ExpiryRemindedAt) >= remindBefore {
+		return ListingExpiryExpire
+	}
+	return ListingExpiryNone
+}
+
+// RunExpiryJob reminds sellers of listings about to expire and expires those
+// whose reminder went unanswered
+func (uc *ListingExpiryUseCase) RunExpiryJob(ctx context.Context) (*JobResult, error) {
+	result := &JobResult{}
+	if !uc.Enabled() {
+		return result, nil
+	}
+
+	now := time.Now()
+	// Seller visits and edits only push the expiry further out, so listings
+	// bumped within the reminder window cannot be due
+	candidates, err := uc.productRepo.ListActiveBumpedBefore(ctx, now.Add(-(uc.expireAfter - uc.remindBefore)))
+	if err != nil {
+		return nil, err
+	}
+
+	sellers := map[string]*entity.User{}
+	for _, product := range candidates {
+		seller, ok := sellers[product.SellerID]
+		if !ok {
+			seller, err = uc.userRepo.GetByID(ctx, product.SellerID)
+			if err != nil {
+				result.Fail("Failed to load seller %s of product %s: %v", product.SellerID, product.ID, err)
+				continue
+			}
+			sellers[product.SellerID] = seller
+		}
+
+		switch ListingExpiryAction(product, seller.LastSeen, now, uc.expireAfter, uc.remindBefore) {
+		case ListingExpiryRemind:
+			product.ExpiryRemindedAt = &now
+			if err := uc.productRepo.Update(ctx, product); err != nil {
+				result.Fail("Failed to record expiry reminder of product %s: %v", product.ID, err)
+				continue
+			}
+
+			expiresAt := product.LastActivity(seller.LastSeen).Add(uc.expireAfter)
+			if expiresAt.Before(now.Add(uc.remindBefore)) {
+				expiresAt = now.Add(uc.remindBefore)
+			}
+			uc.notifySeller(ctx, product, NotificationTypeListingExpiring, "Your listing is about to expire",
+				fmt.Sprintf("%s expires on %s. Renew it to keep it listed.", product.Title, expiresAt.Format("2 Jan 2006")), expiresAt)
+
+		case ListingExpiryExpire:
+			product.Status = ProductStatusExpired
+			product.ExpiredAt = &now
+			if err := uc.productRepo.Update(ctx, product); err != nil {
+				result.Fail("Failed to expire product %s: %v", product.ID, err)
+				continue
+			}
+			if uc.searchIndex != nil {
+				if err := uc.searchIndex.Index(ctx, product); err != nil {
+					log.Printf("Failed to index product %s: %v", product.ID, err)
+				}
+			}
+
+			log.Printf("Product %s expired after seller inactivity", product.ID)
+			uc.notifySeller(ctx, product, NotificationTypeListingExpired, "Your listing has expired",
+				fmt.Sprintf("%s is no longer shown to buyers. Renew it to list it again.", product.Title), now)
+
+		default:
+			continue
+		}
+
+		result.ItemsProcessed++
+	}
+
+	return result, nil
+}
+
+func (uc *ListingExpiryUseCase) notifySeller(ctx context.Context, product *entity.Product, notificationType, title, message string, at time.Time) {
+	if uc.notificationUseCase == nil {
+		return
+	}
+
+	_, err := uc.notificationUseCase.Notify(ctx, product.SellerID, notificationType, title, message, map[string]interface{}{
+		"product_id": product.ID,
+		"expires_at": at,
+	})
+	if err != nil {
+		log.Printf("Error notifying seller %s about expiry of product %s: %v", product.SellerID, product.ID, err)
+	}
+}
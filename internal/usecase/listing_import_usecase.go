@@ -0,0 +1,783 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/infrastructure/spreadsheet"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+const (
+	ImportJobStatusPreviewed = "previewed"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusCompleted = "completed"
+	ImportJobStatusFailed    = "failed"
+
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+
+	NotificationTypeImportFinished = "listing_import_finished"
+
+	// MaxImportRows caps the listings in one import sheet
+	MaxImportRows = 1000
+	// ImportPreviewTTL is how long a preview can be started before the sheet
+	// has to be uploaded again, since the listings it updates may have changed
+	ImportPreviewTTL = 24 * time.Hour
+
+	// maxImportJobErrors caps the run errors kept on a job
+	maxImportJobErrors = 200
+	// importJobBatchSize caps the jobs one run of the import job picks up
+	importJobBatchSize = 10
+)
+
+// Import sheet columns. Game attributes go in one column each, named
+// "attr.<attribute name>".
+const (
+	ImportColumnID                = "id"
+	ImportColumnGameTitleID       = "game_title_id"
+	ImportColumnTitle             = "title"
+	ImportColumnDescription       = "description"
+	ImportColumnType              = "type"
+	ImportColumnStatus            = "status"
+	ImportColumnPrice             = "price"
+	ImportColumnDeliveryMethod    = "delivery_method"
+	ImportColumnUsesCodeInventory = "uses_code_inventory"
+	ImportColumnLowStockThreshold = "low_stock_threshold"
+	ImportColumnImageURLs         = "image_urls"
+
+	ImportAttributePrefix = "attr."
+)
+
+// ImportColumns lists the fixed columns in the order they are exported
+var ImportColumns = []string{
+	ImportColumnID,
+	ImportColumnGameTitleID,
+	ImportColumnTitle,
+	ImportColumnDescription,
+	ImportColumnType,
+	ImportColumnStatus,
+	ImportColumnPrice,
+	ImportColumnDeliveryMethod,
+	ImportColumnUsesCodeInventory,
+	ImportColumnLowStockThreshold,
+	ImportColumnImageURLs,
+}
+
+const (
+	// importImageSeparator splits the image_urls column
+	importImageSeparator = "|"
+	// importValueSeparator joins multi-select attribute values on export
+	importValueSeparator = ", "
+)
+
+var (
+	importProductTypes    = []string{"account", "topup", "boosting", "item"}
+	importStatuses        = []string{"draft", "active"}
+	importDeliveryMethods = []string{"instant", "middleman", "both"}
+	importColumnsByName   = map[string]bool{}
+)
+
+func init() {
+	for _, column := range ImportColumns {
+		importColumnsByName[column] = true
+	}
+}
+
+type ListingImportUseCase struct {
+	importRepo          repository.ListingImportRepository
+	productRepo         repository.ProductRepository
+	gameTitleRepo       repository.GameTitleRepository
+	userRepo            repository.UserRepository
+	productUseCase      *ProductUseCase
+	notificationUseCase *NotificationUseCase
+}
+
+func NewListingImportUseCase(
+	importRepo repository.ListingImportRepository,
+	productRepo repository.ProductRepository,
+	gameTitleRepo repository.GameTitleRepository,
+	userRepo repository.UserRepository,
+	productUseCase *ProductUseCase,
+	notificationUseCase *NotificationUseCase,
+) *ListingImportUseCase {
+	return &ListingImportUseCase{
+		importRepo:          importRepo,
+		productRepo:         productRepo,
+		gameTitleRepo:       gameTitleRepo,
+		userRepo:            userRepo,
+		productUseCase:      productUseCase,
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// ImportRowResult is the preview of one sheet row
+type ImportRowResult struct {
+	Row       int                     `json:"row"`
+	Action    string                  `json:"action"` // create, update
+	ProductID string                  `json:"product_id,omitempty"`
+	Title     string                  `json:"title"`
+	Errors    []entity.ImportRowError `json:"errors,omitempty"`
+}
+
+// ImportPreview is the validated sheet; only rows without errors are imported
+type ImportPreview struct {
+	Job  *entity.ImportJob `json:"job"`
+	Rows []ImportRowResult `json:"rows"`
+}
+
+// ParseImportSheet reads the header row and returns the non-blank rows below
+// it, keyed by column name
+func ParseImportSheet(cells [][]string) ([]*entity.ImportRow, error) {
+	if len(cells) == 0 {
+		return nil, errors.BadRequest("The sheet is empty", nil)
+	}
+
+	header := make([]string, len(cells[0]))
+	seen := map[string]bool{}
+	for i, name := range cells[0] {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if name == "" {
+			continue
+		}
+
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, ImportAttributePrefix) {
+			// Attribute names keep their spelling; matching them is case-insensitive
+			name = ImportAttributePrefix + strings.TrimSpace(name[len(ImportAttributePrefix):])
+			if name == ImportAttributePrefix {
+				return nil, errors.BadRequest(fmt.Sprintf("Column %d needs an attribute name after %q", i+1, ImportAttributePrefix), nil)
+			}
+		} else if importColumnsByName[lower] {
+			name = lower
+		} else {
+			return nil, errors.BadRequest(fmt.Sprintf("Unknown column %q; columns are %s and %s<attribute>", name, strings.Join(ImportColumns, ", "), ImportAttributePrefix), nil)
+		}
+
+		if seen[strings.ToLower(name)] {
+			return nil, errors.BadRequest(fmt.Sprintf("Column %q appears more than once", name), nil)
+		}
+		seen[strings.ToLower(name)] = true
+		header[i] = name
+	}
+
+	if !seen[ImportColumnTitle] {
+		return nil, errors.BadRequest("The sheet needs a title column", nil)
+	}
+
+	rows := []*entity.ImportRow{}
+	for i, record := range cells[1:] {
+		values := map[string]string{}
+		for j, value := range record {
+			if j >= len(header) || header[j] == "" {
+				continue
+			}
+			if value = strings.TrimSpace(value); value != "" {
+				values[header[j]] = value
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		if len(rows) == MaxImportRows {
+			return nil, errors.BadRequest(fmt.Sprintf("A sheet can have at most %d listings", MaxImportRows), nil)
+		}
+		rows = append(rows, &entity.ImportRow{Row: i + 2, Values: values})
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.BadRequest("The sheet has no listings below the header", nil)
+	}
+
+	return rows, nil
+}
+
+// BuildImportInput maps a sheet row to product input. It checks what can be
+// checked from the row alone; the game's attribute schema and the listing
+// being updated are checked by the import preview.
+func BuildImportInput(row *entity.ImportRow) (string, CreateProductInput, []ProductImageInput, []entity.ImportRowError) {
+	values := row.Values
+	errs := []entity.ImportRowError{}
+	fail := func(field, message string) {
+		errs = append(errs, entity.ImportRowError{Row: row.Row, Field: field, Message: message})
+	}
+
+	input := CreateProductInput{
+		GameTitleID:    values[ImportColumnGameTitleID],
+		Title:          values[ImportColumnTitle],
+		Description:    values[ImportColumnDescription],
+		Type:           strings.ToLower(values[ImportColumnType]),
+		Status:         strings.ToLower(values[ImportColumnStatus]),
+		DeliveryMethod: strings.ToLower(values[ImportColumnDeliveryMethod]),
+		Attributes:     map[string]interface{}{},
+	}
+	productID := values[ImportColumnID]
+
+	if input.Title == "" {
+		fail(ImportColumnTitle, "Title is required")
+	}
+	if productID == "" && input.GameTitleID == "" {
+		fail(ImportColumnGameTitleID, "Game title is required for new listings")
+	}
+	if !containsString(importProductTypes, input.Type) {
+		fail(ImportColumnType, "Type must be one of: "+strings.Join(importProductTypes, ", "))
+	}
+	if productID == "" && input.Status == "" {
+		input.Status = "active"
+	}
+	if !containsString(importDeliveryMethods, input.DeliveryMethod) {
+		fail(ImportColumnDeliveryMethod, "Delivery method must be one of: "+strings.Join(importDeliveryMethods, ", "))
+	}
+
+	if raw, ok := values[ImportColumnPrice]; ok {
+		price, err := strconv.ParseFloat(raw, 64)
+		if err != nil || price < 0 {
+			fail(ImportColumnPrice, "Price must be a number")
+		}
+		input.Price = price
+	}
+
+	if raw, ok := values[ImportColumnUsesCodeInventory]; ok {
+		switch strings.ToLower(raw) {
+		case "true", "yes", "ya", "1":
+			input.UsesCodeInventory = true
+		case "false", "no", "tidak", "0":
+		default:
+			fail(ImportColumnUsesCodeInventory, "uses_code_inventory must be true or false")
+		}
+	}
+
+	if raw, ok := values[ImportColumnLowStockThreshold]; ok {
+		threshold, err := strconv.Atoi(raw)
+		if err != nil || threshold < 0 {
+			fail(ImportColumnLowStockThreshold, "low_stock_threshold must be a whole number of at least zero")
+		}
+		input.LowStockThreshold = threshold
+	}
+
+	images := []ProductImageInput{}
+	for _, url := range strings.Split(values[ImportColumnImageURLs], importImageSeparator) {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		}
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			fail(ImportColumnImageURLs, fmt.Sprintf("%q is not an image URL", url))
+			continue
+		}
+		images = append(images, ProductImageInput{URL: url, DisplayOrder: len(images)})
+	}
+
+	for column, value := range values {
+		if strings.HasPrefix(column, ImportAttributePrefix) {
+			input.Attributes[column[len(ImportAttributePrefix):]] = value
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return productID, input, images, errs
+}
+
+// CatalogSheet lays out products in the import format: the fixed columns
+// followed by one column per attribute used by any of them
+func CatalogSheet(products []*entity.Product) [][]string {
+	attributeNames := map[string]string{}
+	for _, product := range products {
+		for name := range product.Attributes {
+			if _, ok := attributeNames[strings.ToLower(name)]; !ok {
+				attributeNames[strings.ToLower(name)] = name
+			}
+		}
+	}
+
+	attributes := make([]string, 0, len(attributeNames))
+	for _, name := range attributeNames {
+		attributes = append(attributes, name)
+	}
+	sort.Slice(attributes, func(i, j int) bool { return strings.ToLower(attributes[i]) < strings.ToLower(attributes[j]) })
+
+	header := append([]string{}, ImportColumns...)
+	for _, name := range attributes {
+		header = append(header, ImportAttributePrefix+name)
+	}
+
+	rows := [][]string{header}
+	for _, product := range products {
+		row := []string{
+			product.ID,
+			product.GameTitleID,
+			product.Title,
+			product.Description,
+			product.Type,
+			product.Status,
+			strconv.FormatFloat(product.Price, 'f', -1, 64),
+			product.DeliveryMethod,
+			strconv.FormatBool(product.UsesCodeInventory),
+			strconv.Itoa(product.LowStockThreshold),
+			strings.Join(productImageURLs(product), " "+importImageSeparator+" "),
+		}
+		for _, name := range attributes {
+			value, _ := lookupAttribute(product.Attributes, name)
+			row = append(row, formatImportValue(value))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func formatImportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatImportValue(item)
+		}
+		return strings.Join(items, importValueSeparator)
+	case []string:
+		return strings.Join(v, importValueSeparator)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// productImageURLs returns the product's image URLs in display order
+func productImageURLs(product *entity.Product) []string {
+	images := append([]entity.ProductImage{}, product.Images...)
+	sort.SliceStable(images, func(i, j int) bool { return images[i].DisplayOrder < images[j].DisplayOrder })
+
+	urls := make([]string, len(images))
+	for i, image := range images {
+		urls[i] = image.URL
+	}
+	return urls
+}
+
+// importPlan is a validated row, ready to create or update a listing
+type importPlan struct {
+	ProductID string
+	Input     CreateProductInput
+	Images    []ProductImageInput
+	Existing  *entity.Product
+}
+
+func (p *importPlan) action() string {
+	if p.ProductID == "" {
+		return ImportActionCreate
+	}
+	return ImportActionUpdate
+}
+
+// importContext caches lookups shared by the rows of one sheet
+type importContext struct {
+	seller     *entity.User
+	gameTitles map[string]*entity.GameTitle
+}
+
+func (uc *ListingImportUseCase) gameTitle(ctx context.Context, ic *importContext, id string) *entity.GameTitle {
+	if gameTitle, ok := ic.gameTitles[id]; ok {
+		return gameTitle
+	}
+
+	gameTitle, err := uc.gameTitleRepo.GetByID(ctx, id)
+	if err != nil {
+		gameTitle = nil
+	}
+	ic.gameTitles[id] = gameTitle
+	return gameTitle
+}
+
+// planRow validates a row against the seller, the game's attribute schema
+// and the listing it updates, mirroring the checks of CreateProduct and
+// UpdateProduct so the preview predicts the outcome of the run
+func (uc *ListingImportUseCase) planRow(ctx context.Context, ic *importContext, row *entity.ImportRow) (*importPlan, []entity.ImportRowError) {
+	productID, input, images, errs := BuildImportInput(row)
+	plan := &importPlan{ProductID: productID, Input: input, Images: images}
+	fail := func(field, message string) {
+		errs = append(errs, entity.ImportRowError{Row: row.Row, Field: field, Message: message})
+	}
+
+	hasCredentials := false
+	if productID != "" {
+		product, err := uc.productRepo.GetByID(ctx, productID)
+		if err != nil || product.SellerID != ic.seller.ID || product.DeletedAt != nil {
+			fail(ImportColumnID, "Listing not found in your catalog")
+			return plan, errs
+		}
+		plan.Existing = product
+		hasCredentials = product.HasCredentials()
+
+		if plan.Input.GameTitleID == "" {
+			plan.Input.GameTitleID = product.GameTitleID
+		}
+		// Listings held by the system (sold out, in review) can be edited
+		// without moving them out of that status
+		if plan.Input.Status == "" {
+			plan.Input.Status = product.Status
+		} else if plan.Input.Status != product.Status && !containsString(importStatuses, plan.Input.Status) {
+			fail(ImportColumnStatus, "Status must be one of: "+strings.Join(importStatuses, ", "))
+		}
+		if product.UsesCodeInventory && !plan.Input.UsesCodeInventory {
+			fail(ImportColumnUsesCodeInventory, "Code inventory cannot be turned off once enabled")
+		}
+		if product.HasVariants() {
+			// Variant listings are priced per variant, which the sheet leaves as is
+			plan.Input.Price = product.Price
+		}
+		if sameImageURLs(productImageURLs(product), images) {
+			plan.Images = nil
+		}
+	} else if !containsString(importStatuses, plan.Input.Status) {
+		fail(ImportColumnStatus, "Status must be one of: "+strings.Join(importStatuses, ", "))
+	}
+
+	if plan.Input.Price <= 0 {
+		fail(ImportColumnPrice, "Price must be greater than zero")
+	}
+
+	instant := plan.Input.DeliveryMethod == "instant" || plan.Input.DeliveryMethod == "both"
+	if instant && ic.seller.VerificationStatus != "verified" {
+		fail(ImportColumnDeliveryMethod, "Seller must be verified to use instant delivery")
+	}
+	if instant && !plan.Input.UsesCodeInventory && !hasCredentials {
+		// Credentials are never put in a spreadsheet
+		fail(ImportColumnUsesCodeInventory, "Imported instant delivery listings must use code inventory")
+	}
+	if plan.Input.UsesCodeInventory && !instant {
+		fail(ImportColumnUsesCodeInventory, "Code inventory needs instant delivery")
+	}
+
+	if plan.Input.GameTitleID != "" {
+		gameTitle := uc.gameTitle(ctx, ic, plan.Input.GameTitleID)
+		if gameTitle == nil {
+			fail(ImportColumnGameTitleID, "Unknown game title")
+		} else {
+			_, violations := ValidateProductAttributes(gameTitle.Attributes, plan.Input.Attributes)
+			for _, violation := range violations {
+				fail(ImportAttributePrefix+violation.Field, violation.Message)
+			}
+		}
+	}
+
+	return plan, errs
+}
+
+func sameImageURLs(current []string, images []ProductImageInput) bool {
+	if len(images) == 0 {
+		return true // a blank column keeps the current images
+	}
+	if len(current) != len(images) {
+		return false
+	}
+	for i, image := range images {
+		if current[i] != image.URL {
+			return false
+		}
+	}
+	return true
+}
+
+// PreviewImport validates an uploaded sheet and saves its valid rows as an
+// import job the seller can start
+func (uc *ListingImportUseCase) PreviewImport(ctx context.Context, sellerID, fileName string, data []byte) (*ImportPreview, error) {
+	format := spreadsheet.FormatFromFileName(fileName)
+	if format == "" {
+		return nil, errors.BadRequest("Upload a .csv or .xlsx file", nil)
+	}
+
+	cells, err := spreadsheet.Read(format, data)
+	if err != nil {
+		return nil, errors.BadRequest("Could not read the spreadsheet", err)
+	}
+
+	rows, err := ParseImportSheet(cells)
+	if err != nil {
+		return nil, err
+	}
+
+	seller, err := uc.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		return nil, errors.BadRequest("Invalid seller", err)
+	}
+
+	// New active listings count against the seller's listing cap
+	policy := TrustPolicyFor(EffectiveTrustTier(seller, time.Now()))
+	_, active, err := uc.productRepo.ListBySellerID(ctx, sellerID, "active", 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	remaining := int64(policy.MaxActiveListings) - active
+
+	ic := &importContext{seller: seller, gameTitles: map[string]*entity.GameTitle{}}
+	results := make([]ImportRowResult, 0, len(rows))
+	valid := []*entity.ImportRow{}
+	seenIDs := map[string]int{}
+
+	for _, row := range rows {
+		plan, errs := uc.planRow(ctx, ic, row)
+
+		if plan.ProductID != "" {
+			if first, ok := seenIDs[plan.ProductID]; ok {
+				errs = append(errs, entity.ImportRowError{Row: row.Row, Field: ImportColumnID, Message: fmt.Sprintf("Listing is already updated by row %d", first)})
+			} else {
+				seenIDs[plan.ProductID] = row.Row
+			}
+		}
+
+		activates := plan.Input.Status == "active" && (plan.Existing == nil || plan.Existing.Status != "active")
+		if len(errs) == 0 && activates {
+			if remaining <= 0 {
+				errs = append(errs, entity.ImportRowError{Row: row.Row, Field: ImportColumnStatus, Message: fmt.Sprintf("Your tier allows at most %d active listings; import it as draft", policy.MaxActiveListings)})
+			} else {
+				remaining--
+			}
+		}
+
+		results = append(results, ImportRowResult{
+			Row:       row.Row,
+			Action:    plan.action(),
+			ProductID: plan.ProductID,
+			Title:     plan.Input.Title,
+			Errors:    errs,
+		})
+		if len(errs) == 0 {
+			valid = append(valid, row)
+		}
+	}
+
+	job := &entity.ImportJob{
+		SellerID:    sellerID,
+		FileName:    fileName,
+		Format:      format,
+		Status:      ImportJobStatusPreviewed,
+		TotalRows:   len(rows),
+		ValidRows:   len(valid),
+		InvalidRows: len(rows) - len(valid),
+	}
+
+	if err := uc.importRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	if err := uc.importRepo.SaveRows(ctx, job.ID, valid); err != nil {
+		return nil, err
+	}
+
+	return &ImportPreview{Job: job, Rows: results}, nil
+}
+
+// StartImport queues the valid rows of a previewed job; the listings are
+// created and updated by the next run of RunImportJob
+func (uc *ListingImportUseCase) StartImport(ctx context.Context, sellerID, jobID string) (*entity.ImportJob, error) {
+	job, err := uc.GetImportJob(ctx, sellerID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != ImportJobStatusPreviewed {
+		return nil, errors.BadRequest("Import job has already been started", nil)
+	}
+	if job.ValidRows == 0 {
+		return nil, errors.BadRequest("Import job has no valid rows", nil)
+	}
+	if time.Since(job.CreatedAt) > ImportPreviewTTL {
+		return nil, errors.BadRequest("Import preview has expired; upload the sheet again", nil)
+	}
+
+	now := time.Now()
+	job.Status = ImportJobStatusRunning
+	job.StartedAt = &now
+	claimed, err := uc.importRepo.UpdateIfStatus(ctx, job, ImportJobStatusPreviewed)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.BadRequest("Import job has already been started", nil)
+	}
+
+	return job, nil
+}
+
+// RunImportJob runs the queued import jobs, resuming those a previous run
+// left unfinished from the last row they saved
+func (uc *ListingImportUseCase) RunImportJob(ctx context.Context) (*JobResult, error) {
+	jobs, err := uc.importRepo.ListByStatus(ctx, ImportJobStatusRunning, importJobBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobResult{}
+	for _, job := range jobs {
+		if err := uc.runImport(ctx, job); err != nil {
+			result.Fail("Import job %s stopped after %d rows: %v", job.ID, job.Processed, err)
+			continue
+		}
+		result.ItemsProcessed++
+	}
+
+	return result, nil
+}
+
+func (uc *ListingImportUseCase) GetImportJob(ctx context.Context, sellerID, jobID string) (*entity.ImportJob, error) {
+	job, err := uc.importRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to access this import job", nil)
+	}
+
+	return job, nil
+}
+
+// runImport processes the job's rows after the last one it saved, saving
+// progress after each so a run stopped midway redoes at most one row. It
+// returns an error, leaving the job running, when stopped by ctx.
+func (uc *ListingImportUseCase) runImport(ctx context.Context, job *entity.ImportJob) error {
+	rows, err := uc.importRepo.ListRows(ctx, job.ID)
+	if err != nil {
+		log.Printf("Error loading rows of import job %s: %v", job.ID, err)
+		uc.finishImport(ctx, job, ImportJobStatusFailed)
+		return nil
+	}
+
+	seller, err := uc.userRepo.GetByID(ctx, job.SellerID)
+	if err != nil {
+		log.Printf("Error loading seller of import job %s: %v", job.ID, err)
+		uc.finishImport(ctx, job, ImportJobStatusFailed)
+		return nil
+	}
+
+	ic := &importContext{seller: seller, gameTitles: map[string]*entity.GameTitle{}}
+
+	for _, row := range rows[min(job.Processed, len(rows)):] {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := uc.importRow(ctx, ic, job, row); err != nil {
+			job.Failed++
+			if len(job.Errors) < maxImportJobErrors {
+				job.Errors = append(job.Errors, entity.ImportRowError{Row: row.Row, Message: importErrorMessage(err)})
+			}
+		}
+		job.Processed++
+
+		if job.Processed < len(rows) {
+			if err := uc.importRepo.Update(ctx, job); err != nil {
+				log.Printf("Error saving progress of import job %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	status := ImportJobStatusCompleted
+	if job.Failed == len(rows) {
+		status = ImportJobStatusFailed
+	}
+	uc.finishImport(ctx, job, status)
+	return nil
+}
+
+// importRow re-validates a row, since listings may have changed since the
+// preview, then creates or updates its listing
+func (uc *ListingImportUseCase) importRow(ctx context.Context, ic *importContext, job *entity.ImportJob, row *entity.ImportRow) error {
+	plan, errs := uc.planRow(ctx, ic, row)
+	if len(errs) > 0 {
+		return errors.BadRequest(fmt.Sprintf("%s: %s", errs[0].Field, errs[0].Message), nil)
+	}
+
+	if plan.ProductID == "" {
+		if _, err := uc.productUseCase.CreateProduct(ctx, job.SellerID, plan.Input, plan.Images); err != nil {
+			return err
+		}
+		job.Created++
+		return nil
+	}
+
+	if _, err := uc.productUseCase.UpdateProduct(ctx, plan.ProductID, job.SellerID, plan.Input, plan.Images); err != nil {
+		return err
+	}
+	job.Updated++
+	return nil
+}
+
+func (uc *ListingImportUseCase) finishImport(ctx context.Context, job *entity.ImportJob, status string) {
+	now := time.Now()
+	job.Status = status
+	job.FinishedAt = &now
+
+	if err := uc.importRepo.Update(ctx, job); err != nil {
+		log.Printf("Error finishing import job %s: %v", job.ID, err)
+	}
+
+	log.Printf("Import job %s %s: %d created, %d updated, %d failed", job.ID, status, job.Created, job.Updated, job.Failed)
+
+	if uc.notificationUseCase == nil {
+		return
+	}
+
+	message := fmt.Sprintf("%s: %d listings created, %d updated, %d failed", job.FileName, job.Created, job.Updated, job.Failed)
+	_, err := uc.notificationUseCase.Notify(ctx, job.SellerID, NotificationTypeImportFinished, "Listing import finished", message, map[string]interface{}{
+		"import_job_id": job.ID,
+		"status":        status,
+	})
+	if err != nil {
+		log.Printf("Error notifying seller %s of import job %s: %v", job.SellerID, job.ID, err)
+	}
+}
+
+func importErrorMessage(err error) string {
+	if appErr, ok := err.(*errors.AppError); ok {
+		return appErr.Message
+	}
+	return err.Error()
+}
+
+// ExportCatalog returns the seller's listings as a sheet in the import
+// format, so it can be edited and uploaded again
+func (uc *ListingImportUseCase) ExportCatalog(ctx context.Context, sellerID, format string) ([]byte, error) {
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+	if _, ok := spreadsheet.ContentTypes[format]; !ok {
+		return nil, errors.BadRequest("format must be csv or xlsx", nil)
+	}
+
+	products := []*entity.Product{}
+	pagination := utils.PaginationParams{PageSize: utils.MaxPageSize}
+	for {
+		page, info, err := uc.productRepo.ListBySellerIDPage(ctx, sellerID, "", pagination)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, page...)
+
+		if info.NextCursor == "" || len(page) == 0 {
+			break
+		}
+		pagination.Cursor = info.NextCursor
+	}
+
+	data, err := spreadsheet.Write(format, CatalogSheet(products))
+	if err != nil {
+		return nil, errors.Internal("Failed to build catalog export", err)
+	}
+
+	return data, nil
+}
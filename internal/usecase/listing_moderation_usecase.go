@@ -0,0 +1,405 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+const (
+	ProductStatusPendingReview = "pending_review"
+	ProductStatusRejected      = "rejected"
+
+	ListingReviewPending    = "pending"
+	ListingReviewApproved   = "approved"
+	ListingReviewRejected   = "rejected"
+	ListingReviewSuperseded = "superseded"
+
+	ListingReviewTriggerCreated     = "created"
+	ListingReviewTriggerEdited      = "edited"
+	ListingReviewTriggerResubmitted = "resubmitted"
+	ListingReviewTriggerImageMatch  = "image_match"
+
+	ListingCheckBannedWord       = "banned_word"
+	ListingCheckContactInfo      = "contact_info"
+	ListingCheckMissingAttribute = "missing_attribute"
+	ListingCheckDuplicateImage   = "duplicate_image"
+
+	NotificationTypeListingApproved = "listing_approved"
+	NotificationTypeListingRejected = "listing_rejected"
+
+	// systemReviewerID marks reviews rejected by the automated checks
+	systemReviewerID = "system"
+)
+
+// DefaultBannedListingWords are terms for cheats and stolen goods that may
+// not appear in a listing
+var DefaultBannedListingWords = []string{
+	"aimbot", "wallhack", "cheat", "hack", "exploit", "carding", "stolen", "phishing", "keylogger", "akun curian",
+}
+
+// trustTierOrder ranks tiers from least to most trusted
+var trustTierOrder = []string{TrustTierRestricted, TrustTierWatch, TrustTierNew, TrustTierStandard, TrustTierTrusted}
+
+func trustTierRank(tier string) int {
+	for i, t := range trustTierOrder {
+		if t == tier {
+			return i
+		}
+	}
+	return -1
+}
+
+type ListingModerationUseCase struct {
+	reviewRepo          repository.ListingReviewRepository
+	productRepo         repository.ProductRepository
+	gameTitleRepo       repository.GameTitleRepository
+	contentScanner      service.ContentScanner
+	searchIndex         service.ProductSearchIndex
+	notificationUseCase *NotificationUseCase
+	minTrustTier        string
+	bannedWords         *regexp.Regexp
+}
+
+// NewListingModerationUseCase holds listings of sellers below minTrustTier
+// for review. An empty minTrustTier turns moderation off.
+func NewListingModerationUseCase(
+	reviewRepo repository.ListingReviewRepository,
+	productRepo repository.ProductRepository,
+	gameTitleRepo repository.GameTitleRepository,
+	contentScanner service.ContentScanner,
+	searchIndex service.ProductSearchIndex,
+	notificationUseCase *NotificationUseCase,
+	minTrustTier string,
+) *ListingModerationUseCase {
+	return &ListingModerationUseCase{
+		reviewRepo:          reviewRepo,
+		productRepo:         productRepo,
+		gameTitleRepo:       gameTitleRepo,
+		contentScanner:      contentScanner,
+		searchIndex:         searchIndex,
+		notificationUseCase: notificationUseCase,
+		minTrustTier:        minTrustTier,
+		bannedWords:         BannedWordsPattern(DefaultBannedListingWords),
+	}
+}
+
+// BannedWordsPattern matches any of the words, whole and case-insensitive
+func BannedWordsPattern(words []string) *regexp.Regexp {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// Enabled reports whether any listings are moderated
+func (uc *ListingModerationUseCase) Enabled() bool {
+	return uc != nil && uc.minTrustTier != ""
+}
+
+// RequiresReview reports whether the seller's listings wait for an admin
+// before going live
+func (uc *ListingModerationUseCase) RequiresReview(seller *entity.User) bool {
+	if !uc.Enabled() {
+		return false
+	}
+	return trustTierRank(EffectiveTrustTier(seller, time.Now())) < trustTierRank(uc.minTrustTier)
+}
+
+// RunListingChecks runs the automated checks on a listing: banned words and
+// off-platform contact details in the title and description, and required
+// attributes of the game's schema
+func RunListingChecks(ctx context.Context, scanner service.ContentScanner, bannedWords *regexp.Regexp, product *entity.Product, schema []entity.GameTitleAttribute) []entity.ListingFinding {
+	findings := []entity.ListingFinding{}
+
+	fields := []struct{ name, text string }{
+		{"title", product.Title},
+		{"description", product.Description},
+	}
+
+	for _, field := range fields {
+		if bannedWords != nil {
+			for _, match := range bannedWords.FindAllString(field.text, -1) {
+				findings = append(findings, entity.ListingFinding{
+					Check:    ListingCheckBannedWord,
+					Field:    field.name,
+					Match:    match,
+					Message:  fmt.Sprintf("%q is not allowed in listings", match),
+					Blocking: true,
+				})
+			}
+		}
+
+		if scanner == nil {
+			continue
+		}
+		result := scanner.Scan(ctx, field.text)
+		for _, finding := range result.Findings {
+			findings = append(findings, entity.ListingFinding{
+				Check:    ListingCheckContactInfo,
+				Field:    field.name,
+				Match:    finding.Match,
+				Message:  fmt.Sprintf("Contact details (%s) must stay in the platform chat", strings.ReplaceAll(finding.Category, "_", " ")),
+				Blocking: result.Action == service.ContentActionBlock,
+			})
+		}
+	}
+
+	_, violations := ValidateProductAttributes(schema, product.Attributes)
+	for _, violation := range violations {
+		findings = append(findings, entity.ListingFinding{
+			Check:    ListingCheckMissingAttribute,
+			Field:    "attributes." + violation.Field,
+			Message:  violation.Message,
+			Blocking: true,
+		})
+	}
+
+	return findings
+}
+
+// Submit opens a review round for a listing the caller has put in
+// pending_review, superseding any round still open. Blocking findings reject
+// the listing straight away so the seller can fix it without waiting.
+func (uc *ListingModerationUseCase) Submit(ctx context.Context, product *entity.Product, trigger, sellerNote string) (*entity.ListingReview, error) {
+	if previous, err := uc.reviewRepo.GetLatestByProduct(ctx, product.ID); err == nil && previous.Status == ListingReviewPending {
+		previous.Status = ListingReviewSuperseded
+		if err := uc.reviewRepo.Update(ctx, previous); err != nil {
+			log.Printf("Error superseding listing review %s: %v", previous.ID, err)
+		}
+	}
+
+	var schema []entity.GameTitleAttribute
+	if gameTitle, err := uc.gameTitleRepo.GetByID(ctx, product.GameTitleID); err == nil {
+		schema = gameTitle.Attributes
+	}
+
+	review := &entity.ListingReview{
+		ProductID:   product.ID,
+		SellerID:    product.SellerID,
+		GameTitleID: product.GameTitleID,
+		Title:       product.Title,
+		Trigger:     trigger,
+		SellerNote:  sellerNote,
+		Status:      ListingReviewPending,
+		Findings:    RunListingChecks(ctx, uc.contentScanner, uc.bannedWords, product, schema),
+	}
+
+	reasons := []string{}
+	for _, finding := range review.Findings {
+		if finding.Blocking {
+			reasons = append(reasons, finding.Message)
+		}
+	}
+
+	if len(reasons) > 0 {
+		now := time.Now()
+		review.Status = ListingReviewRejected
+		review.Reasons = reasons
+		review.ReviewerID = systemReviewerID
+		review.ReviewedAt = &now
+	}
+
+	if err := uc.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+
+	if review.Status == ListingReviewRejected {
+		product.Status = ProductStatusRejected
+		if err := uc.productRepo.Update(ctx, product); err != nil {
+			return nil, err
+		}
+		uc.indexProduct(ctx, product)
+		uc.notifySeller(ctx, review, product)
+	}
+
+	log.Printf("Listing %s of seller %s submitted for review (%s): %s with %d findings", product.ID, product.SellerID, trigger, review.Status, len(review.Findings))
+	return review, nil
+}
+
+// Flag holds a listing for admin review because of findings made outside the
+// listing checks, such as images reused from another seller's listing. It
+// applies whatever the seller's trust tier and adds to a round that is
+// already open. Drafts and listings already rejected are left as they are.
+func (uc *ListingModerationUseCase) Flag(ctx context.Context, product *entity.Product, trigger string, findings []entity.ListingFinding) (*entity.ListingReview, error) {
+	if product.Status == "active" {
+		product.Status = ProductStatusPendingReview
+		product.UpdatedAt = time.Now()
+		if err := uc.productRepo.Update(ctx, product); err != nil {
+			return nil, err
+		}
+		uc.indexProduct(ctx, product)
+	} else if product.Status != ProductStatusPendingReview {
+		return nil, nil
+	}
+
+	review, err := uc.reviewRepo.GetLatestByProduct(ctx, product.ID)
+	if err != nil || review.Status != ListingReviewPending {
+		if review, err = uc.Submit(ctx, product, trigger, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	review.Findings = append(review.Findings, findings...)
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Listing %s of seller %s flagged for review (%s) with %d findings", product.ID, product.SellerID, trigger, len(findings))
+	return review, nil
+}
+
+// Resubmit sends a rejected listing back to the queue, typically after the
+// seller fixed what the reasons pointed out. A listing still pending can be
+// resubmitted to add a note or reopen a round that failed to open.
+func (uc *ListingModerationUseCase) Resubmit(ctx context.Context, sellerID, productID, note string) (*entity.ListingReview, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to resubmit this product", nil)
+	}
+
+	if product.Status != ProductStatusRejected && product.Status != ProductStatusPendingReview {
+		return nil, errors.BadRequest("Only rejected or pending listings can be resubmitted", nil)
+	}
+
+	product.Status = ProductStatusPendingReview
+	product.UpdatedAt = time.Now()
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+	uc.indexProduct(ctx, product)
+
+	return uc.Submit(ctx, product, ListingReviewTriggerResubmitted, note)
+}
+
+// GetListingReview returns the latest review round of the seller's listing
+func (uc *ListingModerationUseCase) GetListingReview(ctx context.Context, sellerID, productID string) (*entity.ListingReview, error) {
+	review, err := uc.reviewRepo.GetLatestByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to view this review", nil)
+	}
+
+	return review, nil
+}
+
+func (uc *ListingModerationUseCase) ListReviews(ctx context.Context, status string, pagination utils.PaginationParams) ([]*entity.ListingReview, *utils.PageInfo, error) {
+	return uc.reviewRepo.ListPage(ctx, status, pagination)
+}
+
+func (uc *ListingModerationUseCase) GetReview(ctx context.Context, reviewID string) (*entity.ListingReview, error) {
+	return uc.reviewRepo.GetByID(ctx, reviewID)
+}
+
+// ApproveReview publishes the listing
+func (uc *ListingModerationUseCase) ApproveReview(ctx context.Context, adminID, reviewID, note string) (*entity.ListingReview, error) {
+	return uc.decide(ctx, adminID, reviewID, ListingReviewApproved, nil, note)
+}
+
+// RejectReview keeps the listing unpublished; the seller sees the reasons
+// and can resubmit
+func (uc *ListingModerationUseCase) RejectReview(ctx context.Context, adminID, reviewID string, reasons []string, note string) (*entity.ListingReview, error) {
+	if len(reasons) == 0 {
+		return nil, errors.BadRequest("At least one rejection reason is required", nil)
+	}
+	return uc.decide(ctx, adminID, reviewID, ListingReviewRejected, reasons, note)
+}
+
+func (uc *ListingModerationUseCase) decide(ctx context.Context, adminID, reviewID, decision string, reasons []string, note string) (*entity.ListingReview, error) {
+	review, err := uc.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Status != ListingReviewPending {
+		return nil, errors.BadRequest(fmt.Sprintf("Review is already %s", review.Status), nil)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, review.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Status != ProductStatusPendingReview {
+		return nil, errors.BadRequest("Listing is no longer waiting for review", nil)
+	}
+
+	now := time.Now()
+	review.Status = decision
+	review.Reasons = reasons
+	review.ReviewNote = note
+	review.ReviewerID = adminID
+	review.ReviewedAt = &now
+
+	if decision == ListingReviewApproved {
+		product.Status = "active"
+		product.BumpedAt = now // Listed as new once it goes live
+	} else {
+		product.Status = ProductStatusRejected
+	}
+	product.UpdatedAt = now
+
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+	uc.indexProduct(ctx, product)
+
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+
+	uc.notifySeller(ctx, review, product)
+
+	log.Printf("Admin %s %s listing %s (review %s)", adminID, decision, product.ID, review.ID)
+	return review, nil
+}
+
+func (uc *ListingModerationUseCase) notifySeller(ctx context.Context, review *entity.ListingReview, product *entity.Product) {
+	if uc.notificationUseCase == nil {
+		return
+	}
+
+	notificationType := NotificationTypeListingApproved
+	title := "Listing approved"
+	message := fmt.Sprintf("%s is now live", product.Title)
+	if review.Status == ListingReviewRejected {
+		notificationType = NotificationTypeListingRejected
+		title = "Listing rejected"
+		message = fmt.Sprintf("%s was not approved: %s. Update the listing and resubmit it.", product.Title, strings.Join(review.Reasons, "; "))
+	}
+
+	_, err := uc.notificationUseCase.Notify(ctx, product.SellerID, notificationType, title, message, map[string]interface{}{
+		"product_id": product.ID,
+		"review_id":  review.ID,
+		"reasons":    review.Reasons,
+	})
+	if err != nil {
+		log.Printf("Error notifying seller %s about review %s: %v", product.SellerID, review.ID, err)
+	}
+}
+
+func (uc *ListingModerationUseCase) indexProduct(ctx context.Context, product *entity.Product) {
+	if uc.searchIndex == nil {
+		return
+	}
+	if err := uc.searchIndex.Index(ctx, product); err != nil {
+		log.Printf("Failed to index product %s: %v", product.ID, err)
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/infrastructure/websocket"
+	"pasargamex/pkg/utils"
+)
+
+type NotificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+	wsManager        *websocket.Manager
+}
+
+func NewNotificationUseCase(
+	notificationRepo repository.NotificationRepository,
+	wsManager *websocket.Manager,
+) *NotificationUseCase {
+	return &NotificationUseCase{
+		notificationRepo: notificationRepo,
+		wsManager:        wsManager,
+	}
+}
+
+// Notify stores a notification for the user and pushes it to their open
+// WebSocket connections. The push is best effort; the stored copy is what
+// the notification list shows.
+func (uc *NotificationUseCase) Notify(ctx context.Context, userID, notificationType, title, message string, data map[string]interface{}) (*entity.Notification, error) {
+	notification := &entity.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data:    data,
+	}
+
+	if err := uc.notificationRepo.Create(ctx, notification); err != nil {
+		return nil, err
+	}
+
+	if uc.wsManager != nil {
+		payload := map[string]interface{}{
+			"type":            notificationType,
+			"notification_id": notification.ID,
+			"title":           title,
+			"message":         message,
+			"timestamp":       time.Now(),
+		}
+		for key, value := range data {
+			payload[key] = value
+		}
+
+		if payloadJSON, err := json.Marshal(payload); err == nil {
+			uc.wsManager.SendToUser(userID, payloadJSON)
+		} else {
+			log.Printf("Error encoding notification %s: %v", notification.ID, err)
+		}
+	}
+
+	return notification, nil
+}
+
+func (uc *NotificationUseCase) ListNotifications(ctx context.Context, userID string, unreadOnly bool, pagination utils.PaginationParams) ([]*entity.Notification, *utils.PageInfo, error) {
+	return uc.notificationRepo.ListPage(ctx, userID, unreadOnly, pagination)
+}
+
+func (uc *NotificationUseCase) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return uc.notificationRepo.CountUnread(ctx, userID)
+}
+
+func (uc *NotificationUseCase) MarkRead(ctx context.Context, userID, notificationID string) error {
+	return uc.notificationRepo.MarkRead(ctx, userID, notificationID)
+}
+
+func (uc *NotificationUseCase) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	return uc.notificationRepo.MarkAllRead(ctx, userID)
+}
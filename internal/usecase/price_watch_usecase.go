@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+const (
+	NotificationTypePriceDrop = "price_drop"
+
+	// Why a price drop alerts a wishlist item
+	PriceDropBelowAddedPrice = "below_added_price"
+	PriceDropTargetReached   = "target_reached"
+
+	DefaultPriceHistoryDays = 90
+	MaxPriceHistoryDays     = 365
+)
+
+type PriceWatchUseCase struct {
+	priceHistoryRepo    repository.PriceHistoryRepository
+	productRepo         repository.ProductRepository
+	wishlistRepo        repository.WishlistRepository
+	notificationUseCase *NotificationUseCase
+}
+
+func NewPriceWatchUseCase(
+	priceHistoryRepo repository.PriceHistoryRepository,
+	productRepo repository.ProductRepository,
+	wishlistRepo repository.WishlistRepository,
+	notificationUseCase *NotificationUseCase,
+) *PriceWatchUseCase {
+	return &PriceWatchUseCase{
+		priceHistoryRepo:    priceHistoryRepo,
+		productRepo:         productRepo,
+		wishlistRepo:        wishlistRepo,
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// PriceSnapshot holds a product's prices from before an edit
+type PriceSnapshot struct {
+	Price    float64
+	Variants map[string]float64
+}
+
+func SnapshotPrices(product *entity.Product) PriceSnapshot {
+	snapshot := PriceSnapshot{Price: product.Price, Variants: map[string]float64{}}
+	for _, variant := range product.Variants {
+		snapshot.Variants[variant.ID] = variant.Price
+	}
+	return snapshot
+}
+
+// PriceChanges lists the price points to record after an edit: one for the
+// listed price and one per variant whose price moved. With an empty snapshot,
+// as for a new product, every price is recorded as a first point.
+func PriceChanges(before PriceSnapshot, product *entity.Product, now time.Time) []*entity.PricePoint {
+	points := []*entity.PricePoint{}
+
+	if product.Price > 0 && product.Price != before.Price {
+		points = append(points, &entity.PricePoint{
+			ProductID:     product.ID,
+			Price:         product.Price,
+			PreviousPrice: before.Price,
+			ChangedAt:     now,
+		})
+	}
+
+	for _, variant := range product.Variants {
+		previous := before.Variants[variant.ID]
+		if variant.Price == previous {
+			continue
+		}
+		points = append(points, &entity.PricePoint{
+			ProductID:     product.ID,
+			VariantID:     variant.ID,
+			Price:         variant.Price,
+			PreviousPrice: previous,
+			ChangedAt:     now,
+		})
+	}
+
+	return points
+}
+
+// RecordPriceChanges writes the product's price changes to its history and
+// alerts wishlist watchers when the listed price dropped. Failures are logged
+// only; a price edit never fails because of its history.
+func (uc *PriceWatchUseCase) RecordPriceChanges(ctx context.Context, before PriceSnapshot, product *entity.Product) {
+	points := PriceChanges(before, product, time.Now())
+	if len(points) == 0 {
+		return
+	}
+
+	if err := uc.priceHistoryRepo.Record(ctx, points); err != nil {
+		log.Printf("Error recording price history of product %s: %v", product.ID, err)
+	}
+
+	if before.Price > 0 && product.Price < before.Price && product.Status == "active" {
+		// A popular listing can have many watchers; alert them off the request
+		go uc.notifyPriceDrop(context.Background(), *product, before.Price)
+	}
+}
+
+// PriceDropReason tells whether a listed price alerts a wishlist item, and
+// why. Each item is alerted once per new low, so a price bouncing around the
+// same level does not alert again.
+func PriceDropReason(item *entity.WishlistItem, price float64) string {
+	if price <= 0 {
+		return ""
+	}
+	if item.LastAlertedPrice > 0 && price >= item.LastAlertedPrice {
+		return ""
+	}
+	if item.TargetPrice > 0 && price <= item.TargetPrice {
+		return PriceDropTargetReached
+	}
+	if item.PriceAtAdd > 0 && price < item.PriceAtAdd {
+		return PriceDropBelowAddedPrice
+	}
+	return ""
+}
+
+func (uc *PriceWatchUseCase) notifyPriceDrop(ctx context.Context, product entity.Product, previousPrice float64) {
+	items, err := uc.wishlistRepo.ListByProduct(ctx, product.ID)
+	if err != nil {
+		log.Printf("Error loading wishlist watchers of product %s: %v", product.ID, err)
+		return
+	}
+
+	alerted := 0
+	for _, item := range items {
+		if item.UserID == product.SellerID {
+			continue
+		}
+
+		reason := PriceDropReason(item, product.Price)
+		if reason == "" {
+			continue
+		}
+
+		message := fmt.Sprintf("%s dropped from Rp %.0f to Rp %.0f", product.Title, previousPrice, product.Price)
+		if reason == PriceDropTargetReached {
+			message = fmt.Sprintf("%s is now Rp %.0f, at or below your target of Rp %.0f", product.Title, product.Price, item.TargetPrice)
+		}
+
+		_, err := uc.notificationUseCase.Notify(ctx, item.UserID, NotificationTypePriceDrop, "Price drop on your wishlist", message, map[string]interface{}{
+			"product_id":     product.ID,
+			"product_title":  product.Title,
+			"previous_price": previousPrice,
+			"price":          product.Price,
+			"price_at_add":   item.PriceAtAdd,
+			"target_price":   item.TargetPrice,
+			"reason":         reason,
+		})
+		if err != nil {
+			log.Printf("Error notifying user %s of price drop on product %s: %v", item.UserID, product.ID, err)
+			continue
+		}
+
+		item.LastAlertedPrice = product.Price
+		if err := uc.wishlistRepo.UpdateWishlistItem(ctx, item); err != nil {
+			log.Printf("Error saving price alert on wishlist item %s: %v", item.ID, err)
+		}
+		alerted++
+	}
+
+	log.Printf("Price of product %s dropped from %.0f to %.0f; alerted %d of %d watchers", product.ID, previousPrice, product.Price, alerted, len(items))
+}
+
+// PriceChartPoint is one step of a price chart; the price holds until the
+// next point
+type PriceChartPoint struct {
+	Date  time.Time `json:"date"`
+	Price float64   `json:"price"`
+}
+
+type PriceChart struct {
+	ProductID    string            `json:"product_id"`
+	VariantID    string            `json:"variant_id,omitempty"`
+	Days         int               `json:"days"`
+	CurrentPrice float64           `json:"current_price"`
+	LowestPrice  float64           `json:"lowest_price"`
+	HighestPrice float64           `json:"highest_price"`
+	Points       []PriceChartPoint `json:"points"`
+}
+
+// BuildPriceChart turns recorded changes into a step series covering since
+// to now. It opens with the price in effect at since (or at the first point
+// when the product is newer) and closes with the current price, so the line
+// can be drawn without gaps.
+func BuildPriceChart(points []*entity.PricePoint, current float64, since, now time.Time) PriceChart {
+	series := []PriceChartPoint{}
+
+	if len(points) == 0 || points[0].PreviousPrice > 0 {
+		opening := current
+		if len(points) > 0 {
+			opening = points[0].PreviousPrice
+		}
+		series = append(series, PriceChartPoint{Date: since, Price: opening})
+	}
+	for _, point := range points {
+		series = append(series, PriceChartPoint{Date: point.ChangedAt, Price: point.Price})
+	}
+	series = append(series, PriceChartPoint{Date: now, Price: current})
+
+	chart := PriceChart{
+		CurrentPrice: current,
+		LowestPrice:  series[0].Price,
+		HighestPrice: series[0].Price,
+		Points:       series,
+	}
+	for _, point := range series {
+		chart.LowestPrice = min(chart.LowestPrice, point.Price)
+		chart.HighestPrice = max(chart.HighestPrice, point.Price)
+	}
+
+	return chart
+}
+
+// GetPriceHistory returns a chart of the listed price, or of one variant's
+// price, over the last days
+func (uc *PriceWatchUseCase) GetPriceHistory(ctx context.Context, productID, variantID string, days int) (*PriceChart, error) {
+	if days <= 0 {
+		days = DefaultPriceHistoryDays
+	}
+	if days > MaxPriceHistoryDays {
+		return nil, errors.BadRequest(fmt.Sprintf("Price history covers at most %d days", MaxPriceHistoryDays), nil)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.DeletedAt != nil {
+		return nil, errors.NotFound("Product", nil)
+	}
+
+	current := product.Price
+	if variantID != "" {
+		variant := product.Variant(variantID)
+		if variant == nil {
+			return nil, errors.NotFound("Variant not found", nil)
+		}
+		current = variant.Price
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+
+	points, err := uc.priceHistoryRepo.ListSince(ctx, productID, variantID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := BuildPriceChart(points, current, since, now)
+	chart.ProductID = productID
+	chart.VariantID = variantID
+	chart.Days = days
+
+	return &chart, nil
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/errors"
+)
+
+// GameTitleAttribute types and how they filter: numbers by range, lists by
+// contains, everything else by equality
+const (
+	AttributeTypeText        = "text"
+	AttributeTypeNumber      = "number"
+	AttributeTypeSelect      = "select"
+	AttributeTypeMultiSelect = "multiselect"
+	AttributeTypeBoolean     = "boolean"
+)
+
+const (
+	AttributeFilterEquals   = "eq"
+	AttributeFilterRange    = "range"
+	AttributeFilterContains = "contains"
+)
+
+// attributeParamPrefix marks attribute filters in the query string:
+// attr.rank=Mythic, attr.hero_count.min=50, attr.heroes=Fanny
+const attributeParamPrefix = "attr."
+
+type AttributeFilter struct {
+	Name     string   `json:"name"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"` // any value matches
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// AttributeFacet summarizes one game attribute over the filtered listings.
+// Counts ignore the attribute's own filter so other options stay selectable.
+type AttributeFacet struct {
+	Name   string       `json:"name"`
+	Type   string       `json:"type"`
+	Values []FacetValue `json:"values,omitempty"`
+	Min    *float64     `json:"min,omitempty"`
+	Max    *float64     `json:"max,omitempty"`
+	Count  int          `json:"count"` // listings that have the attribute set
+}
+
+// attributeFilterKind maps a schema type onto the filter it supports
+func attributeFilterKind(attrType string) string {
+	switch strings.ToLower(attrType) {
+	case AttributeTypeNumber, "integer", "int", "float":
+		return AttributeFilterRange
+	case AttributeTypeMultiSelect, "multi_select", "list", "array", "tags":
+		return AttributeFilterContains
+	default:
+		return AttributeFilterEquals
+	}
+}
+
+// ParseAttributeFilters reads attr.* query parameters against the game's
+// attribute schema. Unknown attributes and malformed numbers are rejected.
+func ParseAttributeFilters(params map[string][]string, schema []entity.GameTitleAttribute) ([]AttributeFilter, error) {
+	byName := map[string]entity.GameTitleAttribute{}
+	for _, attr := range schema {
+		byName[strings.ToLower(attr.Name)] = attr
+	}
+
+	filters := map[string]*AttributeFilter{}
+	order := []string{}
+
+	for key, values := range params {
+		if !strings.HasPrefix(key, attributeParamPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, attributeParamPrefix)
+		bound := ""
+		if strings.HasSuffix(name, ".min") || strings.HasSuffix(name, ".max") {
+			bound = name[len(name)-3:]
+			name = name[:len(name)-4]
+		}
+
+		attr, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, errors.BadRequest(fmt.Sprintf("Unknown attribute filter: %s", name), nil)
+		}
+
+		filter, ok := filters[attr.Name]
+		if !ok {
+			filter = &AttributeFilter{Name: attr.Name, Operator: attributeFilterKind(attr.Type)}
+			filters[attr.Name] = filter
+			order = append(order, attr.Name)
+		}
+
+		if filter.Operator == AttributeFilterRange {
+			if bound == "" {
+				// attr.level=30 is shorthand for an exact numeric match
+				bound = "eq"
+			}
+			for _, raw := range values {
+				value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+				if err != nil {
+					return nil, errors.BadRequest(fmt.Sprintf("Attribute %s must be a number", attr.Name), err)
+				}
+				if bound == "min" || bound == "eq" {
+					filter.Min = &value
+				}
+				if bound == "max" || bound == "eq" {
+					value := value
+					filter.Max = &value
+				}
+			}
+			continue
+		}
+
+		if bound != "" {
+			return nil, errors.BadRequest(fmt.Sprintf("Attribute %s does not support ranges", attr.Name), nil)
+		}
+
+		for _, raw := range values {
+			for _, value := range strings.Split(raw, ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					filter.Values = append(filter.Values, value)
+				}
+			}
+		}
+	}
+
+	sort.Strings(order)
+	result := make([]AttributeFilter, 0, len(order))
+	for _, name := range order {
+		result = append(result, *filters[name])
+	}
+
+	return result, nil
+}
+
+// HasAttributeParams reports whether the query string carries attr.* filters
+func HasAttributeParams(params map[string][]string) bool {
+	for key := range params {
+		if strings.HasPrefix(key, attributeParamPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesAttributeFilters applies every filter except the one named skip
+func MatchesAttributeFilters(attributes map[string]interface{}, filters []AttributeFilter, skip string) bool {
+	for _, filter := range filters {
+		if filter.Name == skip {
+			continue
+		}
+		if !filter.Matches(attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f AttributeFilter) Matches(attributes map[string]interface{}) bool {
+	value, ok := lookupAttribute(attributes, f.Name)
+	if !ok {
+		return false
+	}
+
+	switch f.Operator {
+	case AttributeFilterRange:
+		number, ok := attributeNumber(value)
+		if !ok {
+			return false
+		}
+		if f.Min != nil && number < *f.Min {
+			return false
+		}
+		if f.Max != nil && number > *f.Max {
+			return false
+		}
+		return true
+
+	case AttributeFilterContains:
+		items := attributeStrings(value)
+		for _, want := range f.Values {
+			for _, item := range items {
+				if strings.EqualFold(item, want) {
+					return true
+				}
+			}
+		}
+		return len(f.Values) == 0
+
+	default:
+		for _, want := range f.Values {
+			for _, item := range attributeStrings(value) {
+				if strings.EqualFold(item, want) {
+					return true
+				}
+			}
+		}
+		return len(f.Values) == 0
+	}
+}
+
+// BuildAttributeFacets counts attribute values across the listings. Each
+// facet is computed over the listings matching all the other filters.
+func BuildAttributeFacets(schema []entity.GameTitleAttribute, products []map[string]interface{}, filters []AttributeFilter) []*AttributeFacet {
+	facets := make([]*AttributeFacet, 0, len(schema))
+
+	for _, attr := range schema {
+		facet := &AttributeFacet{Name: attr.Name, Type: attr.Type}
+		kind := attributeFilterKind(attr.Type)
+
+		counts := map[string]int{}
+		labels := map[string]string{}
+		for _, option := range attr.Options {
+			counts[strings.ToLower(option)] = 0
+			labels[strings.ToLower(option)] = option
+		}
+
+		for _, attributes := range products {
+			if !MatchesAttributeFilters(attributes, filters, attr.Name) {
+				continue
+			}
+
+			value, ok := lookupAttribute(attributes, attr.Name)
+			if !ok {
+				continue
+			}
+
+			if kind == AttributeFilterRange {
+				number, ok := attributeNumber(value)
+				if !ok {
+					continue
+				}
+				facet.Count++
+				if facet.Min == nil || number < *facet.Min {
+					facet.Min = &number
+				}
+				if facet.Max == nil || number > *facet.Max {
+					n := number
+					facet.Max = &n
+				}
+				continue
+			}
+
+			items := attributeStrings(value)
+			if len(items) == 0 {
+				continue
+			}
+			facet.Count++
+
+			seen := map[string]bool{}
+			for _, item := range items {
+				key := strings.ToLower(item)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				counts[key]++
+				if _, ok := labels[key]; !ok {
+					labels[key] = item
+				}
+			}
+		}
+
+		if kind != AttributeFilterRange {
+			for key, count := range counts {
+				facet.Values = append(facet.Values, FacetValue{Value: labels[key], Count: count})
+			}
+			sort.Slice(facet.Values, func(i, j int) bool {
+				if facet.Values[i].Count != facet.Values[j].Count {
+					return facet.Values[i].Count > facet.Values[j].Count
+				}
+				return facet.Values[i].Value < facet.Values[j].Value
+			})
+		}
+
+		facets = append(facets, facet)
+	}
+
+	return facets
+}
+
+// lookupAttribute finds an attribute by schema name, ignoring case
+func lookupAttribute(attributes map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := attributes[name]; ok && value != nil {
+		return value, true
+	}
+	for key, value := range attributes {
+		if strings.EqualFold(key, name) && value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func attributeNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil && !math.IsNaN(number)
+	default:
+		return 0, false
+	}
+}
+
+func attributeStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				items = append(items, fmt.Sprint(item))
+			}
+		}
+		return items
+	case []string:
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/pkg/errors"
+)
+
+// AttributeViolation is a per-field problem with a product's attributes
+type AttributeViolation struct {
+	Field   string      `json:"field"`
+	Message string      `json:"message"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// ValidateProductAttributes checks attributes against the game's schema and
+// returns them coerced to their declared types: numbers from numeric
+// strings, booleans from "true"/"ya"/"1", select values matched to their
+// option's spelling. Unknown fields are rejected so typos don't slip in.
+func ValidateProductAttributes(schema []entity.GameTitleAttribute, attributes map[string]interface{}) (map[string]interface{}, []AttributeViolation) {
+	coerced := map[string]interface{}{}
+	violations := []AttributeViolation{}
+	known := map[string]bool{}
+
+	for _, attr := range schema {
+		known[strings.ToLower(attr.Name)] = true
+
+		value, ok := lookupAttribute(attributes, attr.Name)
+		if ok {
+			if s, isString := value.(string); isString && strings.TrimSpace(s) == "" {
+				ok = false
+			}
+		}
+
+		if !ok {
+			if attr.Required {
+				violations = append(violations, AttributeViolation{Field: attr.Name, Message: fmt.Sprintf("%s is required", attr.Name)})
+			}
+			continue
+		}
+
+		result, message := coerceAttribute(attr, value)
+		if message != "" {
+			violations = append(violations, AttributeViolation{Field: attr.Name, Message: message, Value: value})
+			continue
+		}
+		coerced[attr.Name] = result
+	}
+
+	for key, value := range attributes {
+		if !known[strings.ToLower(key)] {
+			violations = append(violations, AttributeViolation{Field: key, Message: fmt.Sprintf("%s is not an attribute of this game", key), Value: value})
+		}
+	}
+
+	sortViolations(violations)
+	return coerced, violations
+}
+
+func coerceAttribute(attr entity.GameTitleAttribute, value interface{}) (interface{}, string) {
+	switch strings.ToLower(attr.Type) {
+	case AttributeTypeNumber, "integer", "int", "float":
+		number, ok := attributeNumber(value)
+		if !ok || math.IsInf(number, 0) {
+			return nil, fmt.Sprintf("%s must be a number", attr.Name)
+		}
+		return number, ""
+
+	case AttributeTypeBoolean, "bool":
+		switch v := value.(type) {
+		case bool:
+			return v, ""
+		case string:
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "true", "yes", "ya", "1":
+				return true, ""
+			case "false", "no", "tidak", "0":
+				return false, ""
+			}
+		case float64:
+			if v == 0 || v == 1 {
+				return v == 1, ""
+			}
+		}
+		return nil, fmt.Sprintf("%s must be true or false", attr.Name)
+
+	case AttributeTypeMultiSelect, "multi_select", "list", "array", "tags":
+		items := []string{}
+		if s, ok := value.(string); ok {
+			for _, item := range strings.Split(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		} else {
+			items = attributeStrings(value)
+		}
+
+		result := []interface{}{}
+		for _, item := range items {
+			option, ok := matchOption(attr.Options, item)
+			if !ok {
+				return nil, fmt.Sprintf("%s contains %q, which is not one of: %s", attr.Name, item, strings.Join(attr.Options, ", "))
+			}
+			result = append(result, option)
+		}
+		return result, ""
+
+	default:
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = strings.TrimSpace(v)
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			text = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Sprintf("%s must be a single value", attr.Name)
+		}
+
+		option, ok := matchOption(attr.Options, text)
+		if !ok {
+			return nil, fmt.Sprintf("%s must be one of: %s", attr.Name, strings.Join(attr.Options, ", "))
+		}
+		return option, ""
+	}
+}
+
+// matchOption finds value among options ignoring case and returns the
+// option's own spelling. Any value matches when there are no options.
+func matchOption(options []string, value string) (string, bool) {
+	if len(options) == 0 {
+		return value, true
+	}
+	for _, option := range options {
+		if strings.EqualFold(option, strings.TrimSpace(value)) {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+func sortViolations(violations []AttributeViolation) {
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+}
+
+// validateAttributesFor validates attributes for a listing and returns a
+// BAD_REQUEST carrying the per-field violations when they don't fit
+func validateAttributesFor(gameTitle *entity.GameTitle, attributes map[string]interface{}) (map[string]interface{}, error) {
+	coerced, violations := ValidateProductAttributes(gameTitle.Attributes, attributes)
+	if len(violations) > 0 {
+		return nil, errors.BadRequest(fmt.Sprintf("Invalid attributes for %s", gameTitle.Name), nil).
+			WithDetails(map[string]interface{}{"fields": violations})
+	}
+	return coerced, nil
+}
+
+// ProductSchemaViolation lists what is wrong with one existing listing
+type ProductSchemaViolation struct {
+	ProductID   string               `json:"product_id"`
+	Title       string               `json:"title"`
+	SellerID    string               `json:"seller_id"`
+	Status      string               `json:"status"`
+	GameTitleID string               `json:"game_title_id"`
+	Violations  []AttributeViolation `json:"violations"`
+}
+
+type AttributeSchemaReport struct {
+	GameTitleID      string                    `json:"game_title_id,omitempty"`
+	ProductsChecked  int                       `json:"products_checked"`
+	ProductsInvalid  int                       `json:"products_invalid"`
+	InvalidProducts  []*ProductSchemaViolation `json:"invalid_products"`
+	MissingGameTitle []string                  `json:"missing_game_title,omitempty"` // product IDs whose game no longer exists
+}
+
+// AttributeSchemaReport re-validates existing listings against their game's
+// current schema, for use after an admin edits a game's attributes. An empty
+// gameTitleID checks every game.
+func (uc *ProductUseCase) AttributeSchemaReport(ctx context.Context, gameTitleID string) (*AttributeSchemaReport, error) {
+	filter := map[string]interface{}{}
+	if gameTitleID != "" {
+		if _, err := uc.gameTitleRepo.GetByID(ctx, gameTitleID); err != nil {
+			return nil, err
+		}
+		filter["gameTitleId"] = gameTitleID
+	}
+
+	products, _, err := uc.productRepo.List(ctx, filter, "", math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AttributeSchemaReport{
+		GameTitleID:     gameTitleID,
+		InvalidProducts: []*ProductSchemaViolation{},
+	}
+	gameTitles := map[string]*entity.GameTitle{}
+
+	for _, product := range products {
+		if product.DeletedAt != nil || product.Status == "deleted" {
+			continue
+		}
+		report.ProductsChecked++
+
+		gameTitle, ok := gameTitles[product.GameTitleID]
+		if !ok {
+			gameTitle, err = uc.gameTitleRepo.GetByID(ctx, product.GameTitleID)
+			if err != nil && !errors.Is(err, "NOT_FOUND") {
+				return nil, err
+			}
+			gameTitles[product.GameTitleID] = gameTitle
+		}
+
+		if gameTitle == nil {
+			report.MissingGameTitle = append(report.MissingGameTitle, product.ID)
+			continue
+		}
+
+		_, violations := ValidateProductAttributes(gameTitle.Attributes, product.Attributes)
+		if len(violations) == 0 {
+			continue
+		}
+
+		report.InvalidProducts = append(report.InvalidProducts, &ProductSchemaViolation{
+			ProductID:   product.ID,
+			Title:       product.Title,
+			SellerID:    product.SellerID,
+			Status:      product.Status,
+			GameTitleID: product.GameTitleID,
+			Violations:  violations,
+		})
+	}
+
+	report.ProductsInvalid = len(report.InvalidProducts)
+	return report, nil
+}
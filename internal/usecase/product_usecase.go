@@ -2,12 +2,21 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
 	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+const (
+	searchIndexSyncBatchSize = 100
+	searchIndexSyncOverlap   = time.Minute
 )
 
 type ProductUseCase struct {
@@ -15,6 +24,13 @@ type ProductUseCase struct {
 	gameTitleRepo   repository.GameTitleRepository
 	userRepo        repository.UserRepository
 	transactionRepo repository.TransactionRepository
+	credentialVault service.CredentialVault
+	searchIndex     service.ProductSearchIndex
+	priceWatch      *PriceWatchUseCase
+	promotionRepo   repository.PromotionRepository
+	moderation      *ListingModerationUseCase
+	// Views of signed-in users feed the recommendation job
+	recommendationRepo repository.RecommendationRepository
 }
 
 func NewProductUseCase(
@@ -22,13 +38,25 @@ func NewProductUseCase(
 	gameTitleRepo repository.GameTitleRepository,
 	userRepo repository.UserRepository,
 	transactionRepo repository.TransactionRepository,
-
+	credentialVault service.CredentialVault,
+	searchIndex service.ProductSearchIndex,
+	priceWatch *PriceWatchUseCase,
+	promotionRepo repository.PromotionRepository,
+	moderation *ListingModerationUseCase,
+	recommendationRepo repository.RecommendationRepository,
 ) *ProductUseCase {
 	return &ProductUseCase{
 		productRepo:     productRepo,
 		gameTitleRepo:   gameTitleRepo,
 		userRepo:        userRepo,
 		transactionRepo: transactionRepo,
+		credentialVault: credentialVault,
+		searchIndex:     searchIndex,
+		priceWatch:      priceWatch,
+		promotionRepo:   promotionRepo,
+		moderation:      moderation,
+
+		recommendationRepo: recommendationRepo,
 	}
 }
 
@@ -42,6 +70,13 @@ type CreateProductInput struct {
 	Status         string                 `json:"status"`
 	DeliveryMethod string                 `json:"delivery_method"`
 	Credentials    map[string]interface{} `json:"credentials,omitempty"`
+	// Variants replace Price and Stock when set. On update, omitting the
+	// field keeps the current variants and an empty list removes them.
+	Variants []ProductVariantInput `json:"variants,omitempty"`
+	// UsesCodeInventory delivers codes uploaded to the product's pool instead
+	// of Credentials, with stock following the codes left
+	UsesCodeInventory bool `json:"uses_code_inventory"`
+	LowStockThreshold int  `json:"low_stock_threshold,omitempty"`
 }
 
 type ProductImageInput struct {
@@ -61,6 +96,12 @@ func (uc *ProductUseCase) CreateProduct(ctx context.Context, sellerID string, in
 		return nil, errors.BadRequest("Invalid seller", err)
 	}
 
+	if input.Status == "" || input.Status == "active" {
+		if err := uc.checkListingLimit(ctx, seller); err != nil {
+			return nil, err
+		}
+	}
+
 	if (input.DeliveryMethod == "instant" || input.DeliveryMethod == "both") && seller.VerificationStatus != "verified" {
 		return nil, errors.BadRequest("Seller must be verified to use instant delivery", nil)
 	}
@@ -69,10 +110,19 @@ func (uc *ProductUseCase) CreateProduct(ctx context.Context, sellerID string, in
 		return nil, errors.BadRequest("Invalid delivery method", nil)
 	}
 
-	if (input.DeliveryMethod == "instant" || input.DeliveryMethod == "both") && len(input.Credentials) == 0 {
+	if err := validateCodeInventory(input); err != nil {
+		return nil, err
+	}
+
+	if (input.DeliveryMethod == "instant" || input.DeliveryMethod == "both") && len(input.Credentials) == 0 && !input.UsesCodeInventory {
 		return nil, errors.BadRequest("Credentials are required for instant delivery", nil)
 	}
 
+	attributes, err := validateAttributesFor(gameTitle, input.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	productImages := make([]entity.ProductImage, len(images))
 	for i, img := range images {
 		productImages[i] = entity.ProductImage{
@@ -82,6 +132,11 @@ func (uc *ProductUseCase) CreateProduct(ctx context.Context, sellerID string, in
 		}
 	}
 
+	sealedCredentials, err := sealCredentials(ctx, uc.credentialVault, input.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
 	product := &entity.Product{
 		GameTitleID:          gameTitle.ID,
 		SellerID:             sellerID,
@@ -89,26 +144,69 @@ func (uc *ProductUseCase) CreateProduct(ctx context.Context, sellerID string, in
 		Description:          input.Description,
 		Price:                input.Price,
 		Type:                 input.Type,
-		Attributes:           input.Attributes,
+		Attributes:           attributes,
 		Status:               input.Status,
 		Images:               productImages,
 		Views:                0,
 		Featured:             false,
 		DeliveryMethod:       input.DeliveryMethod,
-		Credentials:          input.Credentials,
+		EncryptedCredentials: sealedCredentials,
 		CredentialsValidated: false,
+		UsesCodeInventory:    input.UsesCodeInventory,
+		LowStockThreshold:    input.LowStockThreshold,
 		CreatedAt:            time.Now(),
 		UpdatedAt:            time.Now(),
 		BumpedAt:             time.Now(),
 	}
 
+	if len(input.Variants) > 0 {
+		if err := applyVariants(product, gameTitle, input.Variants); err != nil {
+			return nil, err
+		}
+		if product.UsesCodeInventory {
+			// Stock comes from uploaded codes, of which there are none yet
+			keepInventoryStock(product, nil)
+		}
+	} else if product.Price <= 0 {
+		return nil, errors.BadRequest("Price must be greater than zero", nil)
+	}
+
+	holdForReview := (product.Status == "" || product.Status == "active") && uc.moderation.RequiresReview(seller)
+	if holdForReview {
+		product.Status = ProductStatusPendingReview
+	}
+
 	if err := uc.productRepo.Create(ctx, product); err != nil {
 		return nil, err
 	}
 
+	uc.indexProduct(ctx, product)
+	uc.recordPriceChanges(ctx, PriceSnapshot{}, product)
+
+	if holdForReview {
+		uc.submitForReview(ctx, product, ListingReviewTriggerCreated)
+	}
+
 	return product, nil
 }
 
+// checkListingLimit enforces the active listing cap of the seller's trust tier
+func (uc *ProductUseCase) checkListingLimit(ctx context.Context, seller *entity.User) error {
+	tier := EffectiveTrustTier(seller, time.Now())
+	policy := TrustPolicyFor(tier)
+
+	_, active, err := uc.productRepo.ListBySellerID(ctx, seller.ID, "active", 1, 0)
+	if err != nil {
+		return err
+	}
+
+	if active >= int64(policy.MaxActiveListings) {
+		return errors.BadRequest(fmt.Sprintf("Sellers in the %s tier can have at most %d active listings", tier, policy.MaxActiveListings), nil)
+	}
+
+	return nil
+}
+
 func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id string, sellerID string, input CreateProductInput, images []ProductImageInput) (*entity.Product, error) {
 
 	product, err := uc.productRepo.GetByID(ctx, id)
@@ -120,12 +218,20 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id string, sellerID
 		return nil, errors.Forbidden("You don't have permission to update this product", nil)
 	}
 
-	if input.GameTitleID != product.GameTitleID {
-		_, err = uc.gameTitleRepo.GetByID(ctx, input.GameTitleID)
-		if err != nil {
-			return nil, errors.BadRequest("Invalid game title", err)
-		}
-		product.GameTitleID = input.GameTitleID
+	if input.GameTitleID == "" {
+		input.GameTitleID = product.GameTitleID
+	}
+
+	// Attributes are re-validated on every update since the schema may have changed
+	gameTitle, err := uc.gameTitleRepo.GetByID(ctx, input.GameTitleID)
+	if err != nil {
+		return nil, errors.BadRequest("Invalid game title", err)
+	}
+	product.GameTitleID = input.GameTitleID
+
+	attributes, err := validateAttributesFor(gameTitle, input.Attributes)
+	if err != nil {
+		return nil, err
 	}
 
 	if input.DeliveryMethod == "instant" || input.DeliveryMethod == "both" {
@@ -143,22 +249,95 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id string, sellerID
 		return nil, errors.BadRequest("Invalid delivery method", nil)
 	}
 
+	if err := validateCodeInventory(input); err != nil {
+		return nil, err
+	}
+
+	if product.UsesCodeInventory && !input.UsesCodeInventory {
+		return nil, errors.BadRequest("Code inventory cannot be turned off once enabled", nil)
+	}
+
 	if (input.DeliveryMethod == "instant" || input.DeliveryMethod == "both") &&
-		len(input.Credentials) == 0 && len(product.Credentials) == 0 {
+		len(input.Credentials) == 0 && !product.HasCredentials() && !input.UsesCodeInventory {
 		return nil, errors.BadRequest("Credentials are required for instant delivery", nil)
 	}
 
+	if input.Status == "active" && product.Status != "active" {
+		seller, err := uc.userRepo.GetByID(ctx, sellerID)
+		if err != nil {
+			return nil, errors.BadRequest("Invalid seller", err)
+		}
+
+		if err := uc.checkListingLimit(ctx, seller); err != nil {
+			return nil, err
+		}
+	}
+
+	holdForReview := false
+	if input.Status == "active" && uc.moderation.Enabled() {
+		seller, err := uc.userRepo.GetByID(ctx, sellerID)
+		if err != nil {
+			return nil, errors.BadRequest("Invalid seller", err)
+		}
+		holdForReview = uc.moderation.RequiresReview(seller)
+	}
+
+	reviewTrigger := ListingReviewTriggerEdited
+	if product.Status == ProductStatusRejected {
+		reviewTrigger = ListingReviewTriggerResubmitted
+	}
+
+	pricesBefore := SnapshotPrices(product)
+
 	product.Title = input.Title
 	product.Description = input.Description
 	product.Price = input.Price
 	product.Type = input.Type
-	product.Attributes = input.Attributes
+	product.Attributes = attributes
 	product.Status = input.Status
+	if holdForReview {
+		product.Status = ProductStatusPendingReview
+	}
 	product.DeliveryMethod = input.DeliveryMethod
+	product.LowStockThreshold = input.LowStockThreshold
 	product.UpdatedAt = time.Now()
+	editedAt := product.UpdatedAt
+	product.EditedAt = &editedAt
+	if product.Status != ProductStatusExpired {
+		product.ExpiredAt = nil
+	}
+
+	// Stock of a newly enabled code inventory starts from the sales so far,
+	// with no codes uploaded yet
+	previousVariants := product.Variants
+	if input.UsesCodeInventory && !product.UsesCodeInventory {
+		product.Stock = product.SoldCount
+		previousVariants = nil
+	}
+	product.UsesCodeInventory = input.UsesCodeInventory
+
+	if input.Variants != nil {
+		if err := applyVariants(product, gameTitle, input.Variants); err != nil {
+			return nil, err
+		}
+	}
+	if product.UsesCodeInventory {
+		keepInventoryStock(product, previousVariants)
+	} else {
+		product.SyncVariantTotals()
+	}
+
+	if !product.HasVariants() && product.Price <= 0 {
+		return nil, errors.BadRequest("Price must be greater than zero", nil)
+	}
 
 	if len(input.Credentials) > 0 {
-		product.Credentials = input.Credentials
+		sealedCredentials, err := sealCredentials(ctx, uc.credentialVault, input.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		product.EncryptedCredentials = sealedCredentials
+		product.Credentials = nil
 		product.CredentialsValidated = false
 	}
 
@@ -178,9 +357,24 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id string, sellerID
 		return nil, err
 	}
 
+	uc.indexProduct(ctx, product)
+	uc.recordPriceChanges(ctx, pricesBefore, product)
+
+	if holdForReview {
+		uc.submitForReview(ctx, product, reviewTrigger)
+	}
+
 	return product, nil
 }
 
+// submitForReview queues a listing held in pending_review. When the round
+// cannot be opened the listing stays pending and the seller can resubmit it.
+func (uc *ProductUseCase) submitForReview(ctx context.Context, product *entity.Product, trigger string) {
+	if _, err := uc.moderation.Submit(ctx, product, trigger, ""); err != nil {
+		log.Printf("Error submitting product %s for review: %v", product.ID, err)
+	}
+}
+
 func (uc *ProductUseCase) GetProductByID(ctx context.Context, id string, currentUserID string) (*entity.Product, error) {
 	log.Printf("GetProductByID called with id=%s, currentUserID=%s", id, currentUserID)
 
@@ -191,7 +385,10 @@ func (uc *ProductUseCase) GetProductByID(ctx context.Context, id string, current
 	}
 
 	log.Printf("Product found: id=%s, sellerID=%s, hasCredentials=%v",
-		product.ID, product.SellerID, product.Credentials != nil)
+		product.ID, product.SellerID, product.HasCredentials())
+
+	recordPromotedClick(uc.promotionRepo, product, currentUserID)
+	recordProductView(uc.productRepo, uc.recommendationRepo, product, currentUserID)
 
 	isSeller := currentUserID != "" && product.SellerID == currentUserID
 	log.Printf("Current user is seller: %v", isSeller)
@@ -217,7 +414,7 @@ func (uc *ProductUseCase) GetProductByID(ctx context.Context, id string, current
 	return &productCopy, nil
 }
 
-func (uc *ProductUseCase) ListProducts(ctx context.Context, gameTitleID, productType, status string, minPrice, maxPrice float64, sort string, page, limit int) ([]*entity.Product, int64, error) {
+func (uc *ProductUseCase) ListProducts(ctx context.Context, gameTitleID, productType, status string, minPrice, maxPrice float64, sort string, attributeParams map[string][]string, page, limit int) ([]*entity.Product, int64, []*AttributeFacet, error) {
 
 	filter := make(map[string]interface{})
 
@@ -229,6 +426,11 @@ func (uc *ProductUseCase) ListProducts(ctx context.Context, gameTitleID, product
 		filter["type"] = productType
 	}
 
+	// Expired listings stay hidden until the seller renews them
+	if status == ProductStatusExpired {
+		return []*entity.Product{}, 0, nil, nil
+	}
+
 	if status != "" {
 		filter["status"] = status
 	} else {
@@ -249,7 +451,77 @@ func (uc *ProductUseCase) ListProducts(ctx context.Context, gameTitleID, product
 		offset = 0
 	}
 
-	return uc.productRepo.List(ctx, filter, sort, limit, offset)
+	gameTitle, filters, err := uc.attributeFiltersFor(ctx, gameTitleID, attributeParams)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	if gameTitle == nil {
+		products, total, err := uc.productRepo.List(ctx, filter, sort, limit, offset)
+		if err == nil {
+			recordPromotedImpressions(uc.promotionRepo, products)
+		}
+		return products, total, nil, err
+	}
+
+	// Facets need every listing of the game, so paginate after filtering
+	all, _, err := uc.productRepo.List(ctx, filter, sort, math.MaxInt32, 0)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	attributes := make([]map[string]interface{}, len(all))
+	matched := []*entity.Product{}
+	for i, product := range all {
+		attributes[i] = product.Attributes
+		if MatchesAttributeFilters(product.Attributes, filters, "") {
+			matched = append(matched, product)
+		}
+	}
+
+	facets := BuildAttributeFacets(gameTitle.Attributes, attributes, filters)
+
+	listed := paginateProducts(matched, offset, limit)
+	recordPromotedImpressions(uc.promotionRepo, listed)
+
+	return listed, int64(len(matched)), facets, nil
+}
+
+// attributeFiltersFor parses attr.* filters against the game's schema. The
+// game title is nil when no game is selected, since attributes are per game.
+func (uc *ProductUseCase) attributeFiltersFor(ctx context.Context, gameTitleID string, attributeParams map[string][]string) (*entity.GameTitle, []AttributeFilter, error) {
+	if gameTitleID == "" {
+		if HasAttributeParams(attributeParams) {
+			return nil, nil, errors.BadRequest("game_title_id is required to filter by attributes", nil)
+		}
+		return nil, nil, nil
+	}
+
+	gameTitle, err := uc.gameTitleRepo.GetByID(ctx, gameTitleID)
+	if err != nil {
+		if errors.Is(err, "NOT_FOUND") {
+			return nil, nil, errors.BadRequest("Invalid game title", err)
+		}
+		return nil, nil, err
+	}
+
+	filters, err := ParseAttributeFilters(attributeParams, gameTitle.Attributes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return gameTitle, filters, nil
+}
+
+func paginateProducts(products []*entity.Product, offset, limit int) []*entity.Product {
+	if offset >= len(products) {
+		return []*entity.Product{}
+	}
+	end := offset + limit
+	if limit <= 0 || end > len(products) {
+		end = len(products)
+	}
+	return products[offset:end]
 }
 
 func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string, sellerID string) error {
@@ -263,7 +535,15 @@ func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string, sellerID
 		return errors.Forbidden("You don't have permission to delete this product", nil)
 	}
 
-	return uc.productRepo.SoftDelete(ctx, id)
+	if err := uc.productRepo.SoftDelete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.searchIndex.Remove(ctx, id); err != nil {
+		log.Printf("Failed to remove product %s from search index: %v", id, err)
+	}
+
+	return nil
 }
 
 func (uc *ProductUseCase) DeleteProductImage(ctx context.Context, productID, imageID, sellerID string) (*entity.Product, error) {
@@ -317,14 +597,14 @@ func generateUUID() string {
 	return "img-" + time.Now().Format("20060102150405-999999999")
 }
 
-func (uc *ProductUseCase) ListBySellerID(ctx context.Context, sellerID, status string, limit, offset int) ([]*entity.Product, int64, error) {
+func (uc *ProductUseCase) ListBySellerID(ctx context.Context, sellerID, status string, pagination utils.PaginationParams) ([]*entity.Product, *utils.PageInfo, error) {
 
 	_, err := uc.userRepo.GetByID(ctx, sellerID)
 	if err != nil {
-		return nil, 0, errors.BadRequest("Invalid seller", err)
+		return nil, nil, errors.BadRequest("Invalid seller", err)
 	}
 
-	return uc.productRepo.ListBySellerID(ctx, sellerID, status, limit, offset)
+	return uc.productRepo.ListBySellerIDPage(ctx, sellerID, status, pagination)
 }
 
 func (uc *ProductUseCase) BumpProduct(ctx context.Context, productID string, sellerID string) (*entity.Product, error) {
@@ -349,6 +629,114 @@ func (uc *ProductUseCase) BumpProduct(ctx context.Context, productID string, sel
 		return nil, err
 	}
 
+	uc.indexProduct(ctx, product)
+
+	return product, nil
+}
+
+// RenewProduct puts an expired listing back on the market, or pushes back
+// the expiry of an active one, and bumps it
+func (uc *ProductUseCase) RenewProduct(ctx context.Context, productID string, sellerID string) (*entity.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to renew this product", nil)
+	}
+
+	switch product.Status {
+	case "active":
+	case ProductStatusExpired:
+		seller, err := uc.userRepo.GetByID(ctx, sellerID)
+		if err != nil {
+			return nil, errors.BadRequest("Invalid seller", err)
+		}
+		if err := uc.checkListingLimit(ctx, seller); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.BadRequest("Only active or expired products can be renewed", nil)
+	}
+
+	now := time.Now()
+	product.Status = "active"
+	product.BumpedAt = now
+	product.UpdatedAt = now
+	product.ExpiryRemindedAt = nil
+	product.ExpiredAt = nil
+
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	uc.indexProduct(ctx, product)
+
+	return product, nil
+}
+
+// UpdateVariantInput changes a single variant; nil fields are left as they are
+type UpdateVariantInput struct {
+	Price *float64 `json:"price,omitempty"`
+	Stock *int     `json:"stock,omitempty"`
+}
+
+// UpdateProductVariant restocks or reprices one variant without resending the
+// whole listing. A sold-out listing becomes active again once restocked.
+func (uc *ProductUseCase) UpdateProductVariant(ctx context.Context, productID, variantID, sellerID string, input UpdateVariantInput) (*entity.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to update this product", nil)
+	}
+
+	variant := product.Variant(variantID)
+	if variant == nil {
+		return nil, errors.NotFound("Variant not found", nil)
+	}
+
+	pricesBefore := SnapshotPrices(product)
+
+	if input.Price != nil {
+		if *input.Price <= 0 {
+			return nil, errors.BadRequest("Price must be greater than zero", nil)
+		}
+		variant.Price = *input.Price
+	}
+
+	if input.Stock != nil {
+		if product.UsesCodeInventory {
+			return nil, errors.BadRequest("Stock follows the uploaded codes; upload or remove codes instead", nil)
+		}
+		if *input.Stock < 0 {
+			return nil, errors.BadRequest("Stock cannot be negative", nil)
+		}
+		if *input.Stock > 0 && *input.Stock < variant.SoldCount {
+			return nil, errors.BadRequest(fmt.Sprintf("Stock cannot be below the %d already sold", variant.SoldCount), nil)
+		}
+		variant.Stock = *input.Stock
+	}
+
+	product.SyncVariantTotals()
+
+	if product.Status == "sold_out" && !variant.SoldOut() {
+		product.Status = "active"
+	}
+	product.UpdatedAt = time.Now()
+	editedAt := product.UpdatedAt
+	product.EditedAt = &editedAt
+
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+
+	uc.indexProduct(ctx, product)
+	uc.recordPriceChanges(ctx, pricesBefore, product)
+
 	return product, nil
 }
 
@@ -371,37 +759,221 @@ func (uc *ProductUseCase) MigrateProductsBumpedAt(ctx context.Context) error {
 	return nil
 }
 
-func (uc *ProductUseCase) SearchProducts(ctx context.Context, query string, gameTitleID, productType, status string, minPrice, maxPrice float64, page, limit int) ([]*entity.Product, int64, error) {
+func (uc *ProductUseCase) SearchProducts(ctx context.Context, query string, gameTitleID, productType, status string, minPrice, maxPrice float64, attributeParams map[string][]string, page, limit int) ([]*entity.Product, int64, []*AttributeFacet, error) {
 	log.Printf("SearchProducts usecase called with query: '%s'", query)
 
-	filter := make(map[string]interface{})
+	// Expired listings stay hidden until the seller renews them
+	if status == ProductStatusExpired {
+		return []*entity.Product{}, 0, nil, nil
+	}
 
-	if gameTitleID != "" {
-		filter["gameTitleId"] = gameTitleID
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
 	}
 
-	if productType != "" {
-		filter["type"] = productType
+	searchQuery := service.ProductSearchQuery{
+		Text:        query,
+		GameTitleID: gameTitleID,
+		Type:        productType,
+		Status:      status,
+		MinPrice:    minPrice,
+		MaxPrice:    maxPrice,
+		Limit:       limit,
+		Offset:      offset,
 	}
 
-	if status != "" {
-		filter["status"] = status
+	gameTitle, filters, err := uc.attributeFiltersFor(ctx, gameTitleID, attributeParams)
+	if err != nil {
+		return nil, 0, nil, err
 	}
 
-	if minPrice > 0 {
-		filter["min_price"] = minPrice
+	// With a game selected, rank every hit so attributes can be filtered
+	// and faceted before paginating
+	if gameTitle != nil {
+		searchQuery.Limit = 0
+		searchQuery.Offset = 0
 	}
 
-	if maxPrice > 0 {
-		filter["max_price"] = maxPrice
+	result, err := uc.searchIndex.Search(ctx, searchQuery)
+	if err != nil {
+		return nil, 0, nil, errors.Internal("Failed to search products", err)
 	}
 
-	offset := (page - 1) * limit
-	if offset < 0 {
-		offset = 0
+	hits := result.Hits
+	total := result.Total
+	var facets []*AttributeFacet
+
+	if gameTitle != nil {
+		attributes := make([]map[string]interface{}, len(hits))
+		matched := []service.ProductSearchHit{}
+		for i, hit := range hits {
+			attributes[i] = hit.Attributes
+			if MatchesAttributeFilters(hit.Attributes, filters, "") {
+				matched = append(matched, hit)
+			}
+		}
+
+		facets = BuildAttributeFacets(gameTitle.Attributes, attributes, filters)
+		total = int64(len(matched))
+
+		hits = []service.ProductSearchHit{}
+		if offset < len(matched) {
+			hits = matched[offset:min(offset+limit, len(matched))]
+		}
 	}
 
-	return uc.productRepo.Search(ctx, query, filter, limit, offset)
+	products := make([]*entity.Product, 0, len(hits))
+	for _, hit := range hits {
+		product, err := uc.productRepo.GetByID(ctx, hit.ProductID)
+		if err != nil {
+			if errors.Is(err, "NOT_FOUND") {
+				uc.searchIndex.Remove(ctx, hit.ProductID)
+				continue
+			}
+			return nil, 0, nil, err
+		}
+
+		// Products changed outside this use case (sold, removed) are caught
+		// here and reindexed until the next refresh
+		if !productMatchesSearch(product, searchQuery) {
+			uc.indexProduct(ctx, product)
+			continue
+		}
+
+		products = append(products, product)
+	}
+
+	recordPromotedImpressions(uc.promotionRepo, products)
+
+	return products, total, facets, nil
+}
+
+func productMatchesSearch(product *entity.Product, query service.ProductSearchQuery) bool {
+	if product.DeletedAt != nil || product.Status == "deleted" {
+		return false
+	}
+	// Expired, held and rejected listings are never served from search
+	switch product.Status {
+	case ProductStatusExpired, ProductStatusPendingReview, ProductStatusRejected:
+		return false
+	}
+	if query.GameTitleID != "" && product.GameTitleID != query.GameTitleID {
+		return false
+	}
+	if query.Type != "" && product.Type != query.Type {
+		return false
+	}
+	if query.Status != "" && product.Status != query.Status {
+		return false
+	}
+	if query.MinPrice > 0 && product.HighestPrice() < query.MinPrice {
+		return false
+	}
+	if query.MaxPrice > 0 && product.Price > query.MaxPrice {
+		return false
+	}
+	return true
+}
+
+// indexProduct keeps the search index in step with a write. Failures are
+// logged; the periodic rebuild repairs any drift.
+func (uc *ProductUseCase) indexProduct(ctx context.Context, product *entity.Product) {
+	if err := uc.searchIndex.Index(ctx, product); err != nil {
+		log.Printf("Failed to index product %s: %v", product.ID, err)
+	}
+}
+
+// recordPriceChanges adds the product's price changes to its price history
+func (uc *ProductUseCase) recordPriceChanges(ctx context.Context, before PriceSnapshot, product *entity.Product) {
+	if uc.priceWatch == nil {
+		return
+	}
+	uc.priceWatch.RecordPriceChanges(ctx, before, product)
+}
+
+// RebuildSearchIndex reloads every product into the search index
+func (uc *ProductUseCase) RebuildSearchIndex(ctx context.Context) (int, error) {
+	products, _, err := uc.productRepo.List(ctx, nil, "", math.MaxInt32, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := uc.searchIndex.Rebuild(ctx, products); err != nil {
+		return 0, errors.Internal("Failed to rebuild search index", err)
+	}
+
+	return len(products), nil
+}
+
+// SyncSearchIndex reindexes the products updated at or after since, so
+// changes written through other instances reach this instance's index
+func (uc *ProductUseCase) SyncSearchIndex(ctx context.Context, since time.Time) (int, error) {
+	count := 0
+
+	var last *entity.Product
+	for {
+		products, err := uc.productRepo.ListUpdatedSince(ctx, since, last, searchIndexSyncBatchSize)
+		if err != nil {
+			return count, err
+		}
+
+		for _, product := range products {
+			uc.indexProduct(ctx, product)
+			count++
+		}
+
+		if len(products) < searchIndexSyncBatchSize {
+			break
+		}
+		last = products[len(products)-1]
+	}
+
+	return count, nil
+}
+
+// StartSearchIndexRefresh builds the search index now, pulls in products
+// changed since the previous pass every syncInterval and rebuilds it on
+// every rebuildInterval. Each instance keeps its own index, so this runs on
+// every instance rather than through the leased job scheduler.
+func (uc *ProductUseCase) StartSearchIndexRefresh(ctx context.Context, rebuildInterval, syncInterval time.Duration) {
+	go func() {
+		rebuildTicker := time.NewTicker(rebuildInterval)
+		defer rebuildTicker.Stop()
+		syncTicker := time.NewTicker(syncInterval)
+		defer syncTicker.Stop()
+
+		rebuild := true
+		var syncedAt time.Time
+		for {
+			startedAt := time.Now()
+			if rebuild {
+				count, err := uc.RebuildSearchIndex(ctx)
+				if err != nil {
+					log.Printf("Failed to rebuild product search index: %v", err)
+				} else {
+					log.Printf("Product search index rebuilt with %d products", count)
+					syncedAt = startedAt
+				}
+			} else if !syncedAt.IsZero() {
+				// Overlap the previous pass to allow for clock skew between instances
+				if _, err := uc.SyncSearchIndex(ctx, syncedAt.Add(-searchIndexSyncOverlap)); err != nil {
+					log.Printf("Failed to sync product search index: %v", err)
+				} else {
+					syncedAt = startedAt
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-rebuildTicker.C:
+				rebuild = true
+			case <-syncTicker.C:
+				rebuild = syncedAt.IsZero()
+			}
+		}
+	}()
 }
 
 func (uc *ProductUseCase) ValidateCredentials(ctx context.Context, adminID string, productID string, credentials map[string]interface{}) (bool, error) {
@@ -488,6 +1060,8 @@ func (uc *ProductUseCase) GetSellerProfileWithProducts(ctx context.Context, sell
 		productsByType[product.Type] = append(productsByType[product.Type], product)
 	}
 
+	trustTier := EffectiveTrustTier(seller, time.Now())
+
 	return map[string]interface{}{
 		"seller": map[string]interface{}{
 			"id":                  seller.ID,
@@ -495,6 +1069,8 @@ func (uc *ProductUseCase) GetSellerProfileWithProducts(ctx context.Context, sell
 			"seller_rating":       seller.SellerRating,
 			"review_count":        seller.SellerReviewCount,
 			"verification_status": seller.VerificationStatus,
+			"trust_tier":          trustTier,
+			"badge":               TrustPolicyFor(trustTier).Badge,
 		},
 		"products":         publicProducts,
 		"products_by_type": productsByType,
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+// MaxProductVariants caps how many variants a single listing can carry
+const MaxProductVariants = 50
+
+// ProductVariantInput is a variant as sent by the seller. Existing variants
+// are matched by ID so their sold count survives an update.
+type ProductVariantInput struct {
+	ID         string                 `json:"id,omitempty"`
+	Label      string                 `json:"label"`
+	Price      float64                `json:"price"`
+	Stock      int                    `json:"stock"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// BuildProductVariants validates variant inputs against the game's schema and
+// merges them with the product's current variants. Variant attributes only
+// describe what differs between variants, so required schema fields are not
+// enforced on them.
+func BuildProductVariants(schema []entity.GameTitleAttribute, existing []entity.ProductVariant, inputs []ProductVariantInput) ([]entity.ProductVariant, []AttributeViolation) {
+	violations := []AttributeViolation{}
+	if len(inputs) > MaxProductVariants {
+		violations = append(violations, AttributeViolation{Field: "variants", Message: fmt.Sprintf("A product can have at most %d variants", MaxProductVariants)})
+		return nil, violations
+	}
+
+	optional := make([]entity.GameTitleAttribute, len(schema))
+	for i, attr := range schema {
+		attr.Required = false
+		optional[i] = attr
+	}
+
+	current := map[string]entity.ProductVariant{}
+	for _, variant := range existing {
+		current[variant.ID] = variant
+	}
+
+	variants := make([]entity.ProductVariant, 0, len(inputs))
+	labels := map[string]bool{}
+
+	for i, input := range inputs {
+		field := fmt.Sprintf("variants[%d]", i)
+		label := strings.TrimSpace(input.Label)
+
+		switch {
+		case label == "":
+			violations = append(violations, AttributeViolation{Field: field + ".label", Message: "Label is required"})
+		case labels[strings.ToLower(label)]:
+			violations = append(violations, AttributeViolation{Field: field + ".label", Message: "Label is used by another variant", Value: label})
+		}
+		labels[strings.ToLower(label)] = true
+
+		if input.Price <= 0 {
+			violations = append(violations, AttributeViolation{Field: field + ".price", Message: "Price must be greater than zero", Value: input.Price})
+		}
+		if input.Stock < 0 {
+			violations = append(violations, AttributeViolation{Field: field + ".stock", Message: "Stock cannot be negative", Value: input.Stock})
+		}
+
+		variant := entity.ProductVariant{
+			ID:    input.ID,
+			Label: label,
+			Price: input.Price,
+			Stock: input.Stock,
+		}
+
+		if input.ID != "" {
+			previous, ok := current[input.ID]
+			if !ok {
+				violations = append(violations, AttributeViolation{Field: field + ".id", Message: "Unknown variant", Value: input.ID})
+			}
+			variant.SoldCount = previous.SoldCount
+			if input.Stock > 0 && input.Stock < previous.SoldCount {
+				violations = append(violations, AttributeViolation{Field: field + ".stock", Message: fmt.Sprintf("Stock cannot be below the %d already sold", previous.SoldCount), Value: input.Stock})
+			}
+		} else {
+			variant.ID = uuid.New().String()
+		}
+
+		if len(input.Attributes) > 0 {
+			coerced, attributeViolations := ValidateProductAttributes(optional, input.Attributes)
+			for _, violation := range attributeViolations {
+				violation.Field = field + ".attributes." + violation.Field
+				violations = append(violations, violation)
+			}
+			variant.Attributes = coerced
+		}
+
+		variants = append(variants, variant)
+	}
+
+	sortViolations(violations)
+	return variants, violations
+}
+
+// applyVariants replaces the product's variants and refreshes its totals
+func applyVariants(product *entity.Product, gameTitle *entity.GameTitle, inputs []ProductVariantInput) error {
+	variants, violations := BuildProductVariants(gameTitle.Attributes, product.Variants, inputs)
+	if len(violations) > 0 {
+		return errors.BadRequest("Invalid product variants", nil).
+			WithDetails(map[string]interface{}{"fields": violations})
+	}
+
+	product.Variants = variants
+	product.SyncVariantTotals()
+	return nil
+}
+
+// keepInventoryStock restores the code-derived stock of variants that existed
+// before an update; seller-entered stock does not apply to inventory products
+func keepInventoryStock(product *entity.Product, previous []entity.ProductVariant) {
+	stock := map[string]int{}
+	for _, variant := range previous {
+		stock[variant.ID] = variant.Stock
+	}
+
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if previousStock, ok := stock[variant.ID]; ok {
+			variant.Stock = previousStock
+		} else {
+			variant.Stock = variant.SoldCount
+		}
+	}
+
+	product.SyncVariantTotals()
+}
+
+// validateCodeInventory checks the code inventory settings of a listing
+func validateCodeInventory(input CreateProductInput) error {
+	if input.LowStockThreshold < 0 {
+		return errors.BadRequest("low_stock_threshold cannot be negative", nil)
+	}
+	if input.UsesCodeInventory && input.DeliveryMethod != "instant" && input.DeliveryMethod != "both" {
+		return errors.BadRequest("Code inventory needs instant delivery", nil)
+	}
+	return nil
+}
+
+// checkoutItem is what a buyer is paying for: the product itself or one of
+// its variants
+type checkoutItem struct {
+	Variant   *entity.ProductVariant
+	Price     float64
+	Stock     int
+	SoldCount int
+	Tracked   bool // inventory products are tracked even at zero stock
+}
+
+// resolveCheckoutItem picks the variant being bought. Products with variants
+// must be bought through one; products without variants take no variant ID.
+func resolveCheckoutItem(product *entity.Product, variantID string) (*checkoutItem, error) {
+	if !product.HasVariants() {
+		if variantID != "" {
+			return nil, errors.BadRequest("Product has no variants", nil)
+		}
+
+		stock := product.Stock
+		if stock == 0 && product.HasCredentials() {
+			stock = 1 // Single-use product with credentials
+		}
+		return &checkoutItem{
+			Price:     product.Price,
+			Stock:     stock,
+			SoldCount: product.SoldCount,
+			Tracked:   stock > 0 || product.UsesCodeInventory,
+		}, nil
+	}
+
+	if variantID == "" {
+		return nil, errors.BadRequest("variant_id is required for this product", nil)
+	}
+
+	variant := product.Variant(variantID)
+	if variant == nil {
+		return nil, errors.BadRequest("Variant not found", nil)
+	}
+
+	return &checkoutItem{
+		Variant:   variant,
+		Price:     variant.Price,
+		Stock:     variant.Stock,
+		SoldCount: variant.SoldCount,
+		Tracked:   variant.Stock > 0 || product.UsesCodeInventory,
+	}, nil
+}
+
+func (item *checkoutItem) variantID() string {
+	if item.Variant == nil {
+		return ""
+	}
+	return item.Variant.ID
+}
+
+func (item *checkoutItem) variantLabel() string {
+	if item.Variant == nil {
+		return ""
+	}
+	return item.Variant.Label
+}
+
+// checkoutItemName names what a transaction bought, for payment line items
+func checkoutItemName(product *entity.Product, transaction *entity.Transaction) string {
+	if transaction.VariantLabel == "" {
+		return product.Title
+	}
+	return product.Title + " - " + transaction.VariantLabel
+}
+
+// checkStockAvailable rejects the checkout when the item's tracked stock is
+// taken by completed sales plus transactions still awaiting payment
+func checkStockAvailable(ctx context.Context, transactionRepo repository.TransactionRepository, product *entity.Product, item *checkoutItem) error {
+	if !item.Tracked {
+		return nil
+	}
+
+	if item.SoldCount >= item.Stock {
+		return errors.BadRequest("Product is sold out", nil)
+	}
+
+	pendingCount, err := transactionRepo.GetPendingTransactionCount(ctx, product.ID, item.variantID())
+	if err != nil {
+		log.Printf("Error checking pending transaction count: %v", err)
+		return nil
+	}
+
+	if item.SoldCount+pendingCount >= item.Stock {
+		return errors.BadRequest("Product is sold out (reserved)", nil)
+	}
+
+	return nil
+}
+
+// recordProductSale counts a completed sale against the product or variant
+// and marks the listing sold out once nothing is left
+func recordProductSale(product *entity.Product, variantID string) {
+	if variant := product.Variant(variantID); variant != nil {
+		variant.SoldCount++
+		product.SyncVariantTotals()
+
+		for i := range product.Variants {
+			if !product.Variants[i].SoldOut() {
+				return
+			}
+		}
+		product.Status = "sold_out"
+		log.Printf("Every variant of product %s is sold out", product.ID)
+		return
+	}
+
+	product.SoldCount++
+
+	if product.Stock > 0 && product.SoldCount >= product.Stock {
+		product.Status = "sold_out"
+		log.Printf("Product %s is now sold out (%d/%d)", product.ID, product.SoldCount, product.Stock)
+	} else if product.Stock == 0 && product.HasCredentials() {
+		// Single-use product with credentials - mark as sold
+		product.Status = "sold"
+		log.Printf("Single-use product %s is now sold", product.ID)
+	}
+}
@@ -0,0 +1,462 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+const (
+	PromotionTypeBump     = "bump"
+	PromotionTypeAutoBump = "auto_bump"
+	PromotionTypeFeatured = "featured"
+
+	PromotionStatusActive    = "active"
+	PromotionStatusCompleted = "completed"
+	PromotionStatusCancelled = "cancelled"
+
+	// MaxFeaturedPerGameTitle is how many featured slots each game title has
+	MaxFeaturedPerGameTitle = 6
+	// BumpReportWindow is how long a one-off bump is tracked for its report
+	BumpReportWindow = 24 * time.Hour
+)
+
+// PromotionCatalog lists the packages sellers can buy, priced in IDR
+var PromotionCatalog = []entity.PromotionPackage{
+	{ID: "bump", Name: "Bump to top", Type: PromotionTypeBump, Price: 2000},
+	{ID: "auto_bump_6h_3d", Name: "Auto-bump every 6 hours for 3 days", Type: PromotionTypeAutoBump, Price: 15000, IntervalHours: 6, DurationDays: 3},
+	{ID: "auto_bump_12h_7d", Name: "Auto-bump every 12 hours for 7 days", Type: PromotionTypeAutoBump, Price: 25000, IntervalHours: 12, DurationDays: 7},
+	{ID: "featured_3d", Name: "Featured in its game for 3 days", Type: PromotionTypeFeatured, Price: 30000, DurationDays: 3},
+	{ID: "featured_7d", Name: "Featured in its game for 7 days", Type: PromotionTypeFeatured, Price: 60000, DurationDays: 7},
+}
+
+func PromotionPackageByID(id string) *entity.PromotionPackage {
+	for i := range PromotionCatalog {
+		if PromotionCatalog[i].ID == id {
+			return &PromotionCatalog[i]
+		}
+	}
+	return nil
+}
+
+type PromotionUseCase struct {
+	promotionRepo repository.PromotionRepository
+	productRepo   repository.ProductRepository
+	walletUseCase *WalletUseCase
+	searchIndex   service.ProductSearchIndex
+}
+
+func NewPromotionUseCase(
+	promotionRepo repository.PromotionRepository,
+	productRepo repository.ProductRepository,
+	walletUseCase *WalletUseCase,
+	searchIndex service.ProductSearchIndex,
+) *PromotionUseCase {
+	return &PromotionUseCase{
+		promotionRepo: promotionRepo,
+		productRepo:   productRepo,
+		walletUseCase: walletUseCase,
+		searchIndex:   searchIndex,
+	}
+}
+
+// PurchasePromotion charges the seller's wallet for a catalog package and
+// starts it on the product. The charge is refunded if the promotion cannot
+// be saved.
+func (uc *PromotionUseCase) PurchasePromotion(ctx context.Context, sellerID, productID, packageID string) (*entity.Promotion, error) {
+	pkg := PromotionPackageByID(packageID)
+	if pkg == nil {
+		return nil, errors.BadRequest("Unknown promotion package", nil)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to promote this product", nil)
+	}
+
+	if product.Status != "active" {
+		return nil, errors.BadRequest("Only active products can be promoted", nil)
+	}
+
+	if pkg.Type != PromotionTypeBump {
+		running, err := uc.promotionRepo.ListActiveByProduct(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		for _, promotion := range running {
+			if promotion.Type == pkg.Type {
+				return nil, errors.BadRequest(fmt.Sprintf("Product already has a %s promotion running until %s", pkg.Type, promotion.EndsAt.Format(time.RFC3339)), nil)
+			}
+		}
+	}
+
+	if pkg.Type == PromotionTypeFeatured {
+		featured, err := uc.promotionRepo.CountActiveFeatured(ctx, product.GameTitleID)
+		if err != nil {
+			return nil, err
+		}
+		if featured >= MaxFeaturedPerGameTitle {
+			return nil, errors.BadRequest("All featured slots for this game are taken; try again later", nil)
+		}
+	}
+
+	now := time.Now()
+	promotion := NewPromotion(pkg, product, now)
+
+	// The checks above only give an early answer; the slots reserved here
+	// keep concurrent purchases from overbooking
+	if err := uc.reserveSlots(ctx, promotion, now); err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.walletUseCase.ProcessWalletPayment(ctx, sellerID, pkg.Price, fmt.Sprintf("Promotion: %s (%s)", pkg.Name, product.Title), "promotion:"+promotion.ID)
+	if err != nil {
+		uc.releaseSlots(ctx, promotion, promotionSlots(promotion))
+		return nil, err
+	}
+	promotion.WalletTransactionID = payment.ID
+
+	if err := uc.promotionRepo.Create(ctx, promotion); err != nil {
+		uc.refund(ctx, promotion)
+		return nil, err
+	}
+
+	ApplyPromotion(product, promotion, now)
+
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		promotion.Status = PromotionStatusCancelled
+		if updateErr := uc.promotionRepo.Update(ctx, promotion); updateErr != nil {
+			log.Printf("Error cancelling promotion %s: %v", promotion.ID, updateErr)
+		}
+		uc.refund(ctx, promotion)
+		return nil, err
+	}
+
+	uc.indexProduct(ctx, product)
+
+	log.Printf("Seller %s bought promotion %s (%s) for product %s", sellerID, promotion.ID, pkg.ID, productID)
+	return promotion, nil
+}
+
+func (uc *PromotionUseCase) refund(ctx context.Context, promotion *entity.Promotion) {
+	_, err := uc.walletUseCase.ProcessWalletRefund(ctx, promotion.SellerID, promotion.Price, "Refund for promotion that could not start", "promotion:"+promotion.ID)
+	if err != nil {
+		log.Printf("CRITICAL: Failed to refund promotion %s to seller %s: %v", promotion.ID, promotion.SellerID, err)
+	}
+
+	uc.releaseSlots(ctx, promotion, promotionSlots(promotion))
+}
+
+// promotionSlot caps how many promotions may run at once under a key
+type promotionSlot struct {
+	key   string
+	limit int
+	full  string // rejection when every slot is taken
+}
+
+// promotionSlots lists the slots a promotion holds while it runs: one per
+// product and type, except for one-off bumps, and one of the game's featured
+// slots
+func promotionSlots(promotion *entity.Promotion) []promotionSlot {
+	var slots []promotionSlot
+
+	if promotion.Type != PromotionTypeBump {
+		slots = append(slots, promotionSlot{
+			key:   "product_" + promotion.ProductID + "_" + promotion.Type,
+			limit: 1,
+			full:  fmt.Sprintf("Product already has a %s promotion running", promotion.Type),
+		})
+	}
+	if promotion.Type == PromotionTypeFeatured {
+		slots = append(slots, promotionSlot{
+			key:   "featured_" + promotion.GameTitleID,
+			limit: MaxFeaturedPerGameTitle,
+			full:  "All featured slots for this game are taken; try again later",
+		})
+	}
+
+	return slots
+}
+
+// reserveSlots holds every slot the promotion needs until it ends, giving
+// back those already held when one is taken
+func (uc *PromotionUseCase) reserveSlots(ctx context.Context, promotion *entity.Promotion, now time.Time) error {
+	var reserved []promotionSlot
+
+	for _, slot := range promotionSlots(promotion) {
+		ok, err := uc.promotionRepo.ReserveSlot(ctx, slot.key, promotion.ID, promotion.EndsAt, slot.limit, now)
+		if err == nil && !ok {
+			err = errors.BadRequest(slot.full, nil)
+		}
+		if err != nil {
+			uc.releaseSlots(ctx, promotion, reserved)
+			return err
+		}
+		reserved = append(reserved, slot)
+	}
+
+	return nil
+}
+
+func (uc *PromotionUseCase) releaseSlots(ctx context.Context, promotion *entity.Promotion, slots []promotionSlot) {
+	for _, slot := range slots {
+		if err := uc.promotionRepo.ReleaseSlot(ctx, slot.key, promotion.ID); err != nil {
+			log.Printf("Error releasing promotion slot %s of promotion %s: %v", slot.key, promotion.ID, err)
+		}
+	}
+}
+
+// NewPromotion builds the promotion a package starts at now
+func NewPromotion(pkg *entity.PromotionPackage, product *entity.Product, now time.Time) *entity.Promotion {
+	promotion := &entity.Promotion{
+		ID:            uuid.New().String(),
+		ProductID:     product.ID,
+		SellerID:      product.SellerID,
+		GameTitleID:   product.GameTitleID,
+		PackageID:     pkg.ID,
+		Type:          pkg.Type,
+		Price:         pkg.Price,
+		IntervalHours: pkg.IntervalHours,
+		Status:        PromotionStatusActive,
+		StartsAt:      now,
+		EndsAt:        now.AddDate(0, 0, pkg.DurationDays),
+	}
+
+	if pkg.Type == PromotionTypeBump {
+		promotion.EndsAt = now.Add(BumpReportWindow)
+	}
+	if pkg.Type == PromotionTypeAutoBump {
+		next := now.Add(time.Duration(pkg.IntervalHours) * time.Hour)
+		promotion.NextBumpAt = &next
+	}
+
+	return promotion
+}
+
+// ApplyPromotion puts a newly started promotion into effect on the product
+func ApplyPromotion(product *entity.Product, promotion *entity.Promotion, now time.Time) {
+	switch promotion.Type {
+	case PromotionTypeBump, PromotionTypeAutoBump:
+		product.BumpedAt = now
+		promotion.BumpCount++
+	case PromotionTypeFeatured:
+		featuredUntil := promotion.EndsAt
+		product.Featured = true
+		product.FeaturedUntil = &featuredUntil
+	}
+
+	if product.PromotedUntil == nil || product.PromotedUntil.Before(promotion.EndsAt) {
+		promotedUntil := promotion.EndsAt
+		product.PromotedUntil = &promotedUntil
+	}
+	product.UpdatedAt = now
+}
+
+// AdvancePromotion runs what is due on an active promotion at now: an
+// auto-bump whose time has come, and expiry. Auto-bumps missed while the
+// scheduler was down are skipped rather than run back to back. It reports
+// whether the product changed.
+func AdvancePromotion(promotion *entity.Promotion, product *entity.Product, now time.Time) bool {
+	changed := false
+
+	if promotion.Type == PromotionTypeAutoBump && promotion.NextBumpAt != nil &&
+		!promotion.NextBumpAt.After(now) && now.Before(promotion.EndsAt) {
+		if product.Status == "active" {
+			product.BumpedAt = now
+			promotion.BumpCount++
+			changed = true
+		}
+
+		interval := time.Duration(promotion.IntervalHours) * time.Hour
+		next := *promotion.NextBumpAt
+		for interval > 0 && !next.After(now) {
+			next = next.Add(interval)
+		}
+		promotion.NextBumpAt = &next
+	}
+
+	if !now.Before(promotion.EndsAt) {
+		promotion.Status = PromotionStatusCompleted
+		promotion.NextBumpAt = nil
+
+		if promotion.Type == PromotionTypeFeatured && product.FeaturedUntil != nil && !product.FeaturedUntil.After(now) {
+			product.Featured = false
+			product.FeaturedUntil = nil
+			changed = true
+		}
+		if product.PromotedUntil != nil && !product.PromotedUntil.After(now) {
+			product.PromotedUntil = nil
+			changed = true
+		}
+	}
+
+	if changed {
+		product.UpdatedAt = now
+	}
+	return changed
+}
+
+func promotionDue(promotion *entity.Promotion, now time.Time) bool {
+	if !now.Before(promotion.EndsAt) {
+		return true
+	}
+	return promotion.NextBumpAt != nil && !promotion.NextBumpAt.After(now)
+}
+
+// RunPromotionJob executes due auto-bumps and ends expired promotions,
+// taking featured flags off their products
+func (uc *PromotionUseCase) RunPromotionJob(ctx context.Context) (*JobResult, error) {
+	promotions, err := uc.promotionRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobResult{}
+	for _, promotion := range promotions {
+		now := time.Now()
+		if !promotionDue(promotion, now) {
+			continue
+		}
+
+		product, err := uc.productRepo.GetByID(ctx, promotion.ProductID)
+		if err != nil {
+			if !errors.Is(err, "NOT_FOUND") {
+				result.Fail("Failed to load product %s of promotion %s: %v", promotion.ProductID, promotion.ID, err)
+				continue
+			}
+			// The product is gone, so there is nothing left to promote
+			promotion.Status = PromotionStatusCompleted
+			promotion.NextBumpAt = nil
+			uc.releaseSlots(ctx, promotion, promotionSlots(promotion))
+		} else if AdvancePromotion(promotion, product, now) {
+			if err := uc.productRepo.Update(ctx, product); err != nil {
+				result.Fail("Failed to update product %s of promotion %s: %v", product.ID, promotion.ID, err)
+				continue
+			}
+			uc.indexProduct(ctx, product)
+		}
+
+		if err := uc.promotionRepo.Update(ctx, promotion); err != nil {
+			result.Fail("Failed to update promotion %s: %v", promotion.ID, err)
+			continue
+		}
+
+		result.ItemsProcessed++
+	}
+
+	return result, nil
+}
+
+func (uc *PromotionUseCase) indexProduct(ctx context.Context, product *entity.Product) {
+	if uc.searchIndex == nil {
+		return
+	}
+	if err := uc.searchIndex.Index(ctx, product); err != nil {
+		log.Printf("Failed to index product %s: %v", product.ID, err)
+	}
+}
+
+func (uc *PromotionUseCase) ListPromotions(ctx context.Context, sellerID string, pagination utils.PaginationParams) ([]*entity.Promotion, *utils.PageInfo, error) {
+	return uc.promotionRepo.ListBySellerPage(ctx, sellerID, pagination)
+}
+
+type PromotionReport struct {
+	Promotion        *entity.Promotion           `json:"promotion"`
+	Impressions      int64                       `json:"impressions"`
+	Clicks           int64                       `json:"clicks"`
+	ClickThroughRate float64                     `json:"click_through_rate"` // clicks per impression
+	Daily            []*entity.ProductDailyStats `json:"daily"`
+}
+
+// SummarizePromotionStats totals the daily stats of a promotion. Stats are
+// kept per day, so the first and last day count in full.
+func SummarizePromotionStats(promotion *entity.Promotion, daily []*entity.ProductDailyStats) *PromotionReport {
+	report := &PromotionReport{Promotion: promotion, Daily: daily}
+	for _, day := range daily {
+		report.Impressions += day.Impressions
+		report.Clicks += day.Clicks
+	}
+	if report.Impressions > 0 {
+		report.ClickThroughRate = float64(report.Clicks) / float64(report.Impressions)
+	}
+	return report
+}
+
+// GetPromotionReport returns the impressions and clicks the product got
+// while the promotion ran
+func (uc *PromotionUseCase) GetPromotionReport(ctx context.Context, sellerID, promotionID string) (*PromotionReport, error) {
+	promotion, err := uc.promotionRepo.GetByID(ctx, promotionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if promotion.SellerID != sellerID {
+		return nil, errors.Forbidden("You don't have permission to view this promotion", nil)
+	}
+
+	to := promotion.EndsAt
+	if now := time.Now(); now.Before(to) {
+		to = now
+	}
+
+	daily, err := uc.promotionRepo.ListStats(ctx, promotion.ProductID, promotion.StartsAt, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return SummarizePromotionStats(promotion, daily), nil
+}
+
+// recordPromotedImpressions counts a listing or search page towards the
+// stats of the promoted products on it. Counting runs off the request so
+// browsing is not slowed down by it.
+func recordPromotedImpressions(promotionRepo repository.PromotionRepository, products []*entity.Product) {
+	if promotionRepo == nil {
+		return
+	}
+
+	now := time.Now()
+	productIDs := []string{}
+	for _, product := range products {
+		if product.IsPromoted(now) {
+			productIDs = append(productIDs, product.ID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return
+	}
+
+	go func() {
+		if err := promotionRepo.RecordImpressions(context.Background(), productIDs, now); err != nil {
+			log.Printf("Error recording impressions: %v", err)
+		}
+	}()
+}
+
+// recordPromotedClick counts a visit to a promoted product's page; the
+// seller's own visits are not counted
+func recordPromotedClick(promotionRepo repository.PromotionRepository, product *entity.Product, viewerID string) {
+	now := time.Now()
+	if promotionRepo == nil || !product.IsPromoted(now) || viewerID == product.SellerID {
+		return
+	}
+
+	go func() {
+		if err := promotionRepo.RecordClick(context.Background(), product.ID, now); err != nil {
+			log.Printf("Error recording click on product %s: %v", product.ID, err)
+		}
+	}()
+}
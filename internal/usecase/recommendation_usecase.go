@@ -0,0 +1,564 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+)
+
+const (
+	// Weights of the signals linking a user to a listing; a user's strongest
+	// signal for a listing is the one that counts
+	RecommendationViewWeight     = 1.0
+	RecommendationWishlistWeight = 2.0
+	RecommendationPurchaseWeight = 3.0
+
+	// RecommendationListSize is how many related listings the job keeps per
+	// listing
+	RecommendationListSize = 20
+	// MaxRecommendations is the most listings an endpoint returns
+	MaxRecommendations = 20
+
+	// recommendationWindow bounds the views, wishlists and purchases the job reads
+	recommendationWindow = 90 * 24 * time.Hour
+	// maxBasketSize caps the listings counted per user, since pairs grow
+	// with its square
+	maxBasketSize = 100
+	// maxSimilarityPool caps the listings of one game compared pairwise;
+	// the most recently bumped are kept
+	maxSimilarityPool = 2000
+	// recommendationSeedLimit is how many of a user's recent signals of each
+	// kind seed their personal recommendations
+	recommendationSeedLimit    = 20
+	recommendationPurchasePage = 500
+)
+
+type RecommendationUseCase struct {
+	recommendationRepo repository.RecommendationRepository
+	productRepo        repository.ProductRepository
+	wishlistRepo       repository.WishlistRepository
+	transactionRepo    repository.TransactionRepository
+}
+
+func NewRecommendationUseCase(
+	recommendationRepo repository.RecommendationRepository,
+	productRepo repository.ProductRepository,
+	wishlistRepo repository.WishlistRepository,
+	transactionRepo repository.TransactionRepository,
+) *RecommendationUseCase {
+	return &RecommendationUseCase{
+		recommendationRepo: recommendationRepo,
+		productRepo:        productRepo,
+		wishlistRepo:       wishlistRepo,
+		transactionRepo:    transactionRepo,
+	}
+}
+
+// recordProductView counts a view of a listing and remembers it for signed-in
+// viewers other than the seller
+func recordProductView(productRepo repository.ProductRepository, recommendationRepo repository.RecommendationRepository, product *entity.Product, viewerID string) {
+	if viewerID != "" && viewerID == product.SellerID {
+		return
+	}
+
+	now := time.Now()
+	go func() {
+		ctx := context.Background()
+		if err := productRepo.IncrementViews(ctx, product.ID); err != nil {
+			log.Printf("Error counting view of product %s: %v", product.ID, err)
+		}
+
+		if recommendationRepo == nil || viewerID == "" {
+			return
+		}
+		err := recommendationRepo.RecordView(ctx, &entity.ProductView{
+			UserID:      viewerID,
+			ProductID:   product.ID,
+			GameTitleID: product.GameTitleID,
+			ViewedAt:    now,
+		})
+		if err != nil {
+			log.Printf("Error recording view of product %s: %v", product.ID, err)
+		}
+	}()
+}
+
+// RecommendationBaskets maps each user to the listings they showed interest
+// in, weighted by their strongest signal
+type RecommendationBaskets map[string]map[string]float64
+
+func (b RecommendationBaskets) Add(userID, productID string, weight float64) {
+	if userID == "" || productID == "" {
+		return
+	}
+
+	basket, ok := b[userID]
+	if !ok {
+		basket = map[string]float64{}
+		b[userID] = basket
+	}
+	basket[productID] = max(basket[productID], weight)
+}
+
+// CoOccurrence scores pairs of listings by the users they share: the weaker
+// of each shared user's two signals, summed and divided by the geometric mean
+// of the listings' total weights so popular listings do not top every list
+func CoOccurrence(baskets RecommendationBaskets) map[string]map[string]float64 {
+	totals := map[string]float64{}
+	shared := map[string]map[string]float64{}
+
+	addPair := func(a, b string, weight float64) {
+		related, ok := shared[a]
+		if !ok {
+			related = map[string]float64{}
+			shared[a] = related
+		}
+		related[b] += weight
+	}
+
+	for _, basket := range baskets {
+		items := rankRecommendations(basket, maxBasketSize)
+		for _, item := range items {
+			totals[item.ProductID] += item.Score
+		}
+
+		for i, a := range items {
+			for _, b := range items[i+1:] {
+				weight := min(a.Score, b.Score)
+				addPair(a.ProductID, b.ProductID, weight)
+				addPair(b.ProductID, a.ProductID, weight)
+			}
+		}
+	}
+
+	for a, related := range shared {
+		for b, weight := range related {
+			related[b] = weight / math.Sqrt(totals[a]*totals[b])
+		}
+	}
+
+	return shared
+}
+
+// AttributeSimilarity scores how alike two listings are, from 0 to 1.
+// Listings of different games never match; within a game the same type adds
+// 0.3, closeness in price up to 0.2 and shared attribute values up to 0.5.
+func AttributeSimilarity(a, b *entity.Product) float64 {
+	if a.GameTitleID != b.GameTitleID {
+		return 0
+	}
+
+	score := 0.0
+	if a.Type == b.Type {
+		score += 0.3
+	}
+
+	if a.Price > 0 && b.Price > 0 {
+		score += 0.2 * min(a.Price, b.Price) / max(a.Price, b.Price)
+	} else if a.Price == b.Price {
+		score += 0.2
+	}
+
+	keys := map[string]bool{}
+	matches := 0
+	for key, value := range a.Attributes {
+		keys[strings.ToLower(key)] = true
+		if other, ok := lookupAttribute(b.Attributes, key); ok && value != nil &&
+			strings.EqualFold(fmt.Sprint(value), fmt.Sprint(other)) {
+			matches++
+		}
+	}
+	for key := range b.Attributes {
+		keys[strings.ToLower(key)] = true
+	}
+	if len(keys) > 0 {
+		score += 0.5 * float64(matches) / float64(len(keys))
+	}
+
+	return score
+}
+
+// SimilarProducts ranks, for every listing, the most alike listings of the
+// same game
+func SimilarProducts(products []*entity.Product, size int) map[string][]entity.RecommendedProduct {
+	byGame := map[string][]*entity.Product{}
+	for _, product := range products {
+		byGame[product.GameTitleID] = append(byGame[product.GameTitleID], product)
+	}
+
+	similar := map[string][]entity.RecommendedProduct{}
+	for _, pool := range byGame {
+		if len(pool) > maxSimilarityPool {
+			sort.SliceStable(pool, func(i, j int) bool { return pool[i].BumpedAt.After(pool[j].BumpedAt) })
+			pool = pool[:maxSimilarityPool]
+		}
+
+		for i, a := range pool {
+			scores := map[string]float64{}
+			for j, b := range pool {
+				if i == j {
+					continue
+				}
+				if score := AttributeSimilarity(a, b); score > 0 {
+					scores[b.ID] = score
+				}
+			}
+			similar[a.ID] = rankRecommendations(scores, size)
+		}
+	}
+
+	return similar
+}
+
+// rankRecommendations orders scored listings best first, ties by ID so
+// reruns give the same lists, keeping at most size
+func rankRecommendations(scores map[string]float64, size int) []entity.RecommendedProduct {
+	ranked := make([]entity.RecommendedProduct, 0, len(scores))
+	for productID, score := range scores {
+		ranked = append(ranked, entity.RecommendedProduct{ProductID: productID, Score: score})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ProductID < ranked[j].ProductID
+	})
+
+	if len(ranked) > size {
+		ranked = ranked[:size]
+	}
+	return ranked
+}
+
+// ProductPopularity ranks listings for the fallback lists: views, with each
+// sale counting as much as a purchase signal
+func ProductPopularity(product *entity.Product) float64 {
+	return float64(product.Views)*RecommendationViewWeight + float64(product.SoldCount)*RecommendationPurchaseWeight
+}
+
+// RunRecommendationJob rebuilds the "similar" and "also viewed" lists of
+// every active listing from the last recommendationWindow of signals
+func (uc *RecommendationUseCase) RunRecommendationJob(ctx context.Context) (*JobResult, error) {
+	result := &JobResult{}
+	now := time.Now()
+
+	products, err := uc.activeProducts(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	baskets, err := uc.loadBaskets(ctx, now.Add(-recommendationWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[string]bool, len(products))
+	for _, product := range products {
+		active[product.ID] = true
+	}
+
+	coOccurrence := CoOccurrence(baskets)
+	similar := SimilarProducts(products, RecommendationListSize)
+
+	recommendations := make([]*entity.ProductRecommendations, 0, len(products))
+	for _, product := range products {
+		alsoViewed := map[string]float64{}
+		for productID, score := range coOccurrence[product.ID] {
+			if active[productID] {
+				alsoViewed[productID] = score
+			}
+		}
+
+		productSimilar := similar[product.ID]
+		if productSimilar == nil {
+			productSimilar = []entity.RecommendedProduct{}
+		}
+
+		recommendations = append(recommendations, &entity.ProductRecommendations{
+			ProductID:   product.ID,
+			GameTitleID: product.GameTitleID,
+			Similar:     productSimilar,
+			AlsoViewed:  rankRecommendations(alsoViewed, RecommendationListSize),
+			ComputedAt:  now,
+		})
+	}
+
+	if err := uc.recommendationRepo.SaveRecommendations(ctx, recommendations); err != nil {
+		return nil, err
+	}
+
+	result.ItemsProcessed = len(recommendations)
+	log.Printf("Recommendations rebuilt for %d products from %d users' signals", len(recommendations), len(baskets))
+	return result, nil
+}
+
+func (uc *RecommendationUseCase) loadBaskets(ctx context.Context, since time.Time) (RecommendationBaskets, error) {
+	baskets := RecommendationBaskets{}
+
+	views, err := uc.recommendationRepo.ListViewsSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		baskets.Add(view.UserID, view.ProductID, RecommendationViewWeight)
+	}
+
+	items, err := uc.wishlistRepo.ListCreatedSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		baskets.Add(item.UserID, item.ProductID, RecommendationWishlistWeight)
+	}
+
+	// Transactions come newest first, so paging stops at the window's start
+	filter := map[string]interface{}{"status": "completed"}
+	for offset := 0; ; offset += recommendationPurchasePage {
+		transactions, _, err := uc.transactionRepo.List(ctx, filter, recommendationPurchasePage, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			if transaction.CreatedAt.Before(since) {
+				return baskets, nil
+			}
+			baskets.Add(transaction.BuyerID, transaction.ProductID, RecommendationPurchaseWeight)
+		}
+
+		if len(transactions) < recommendationPurchasePage {
+			return baskets, nil
+		}
+	}
+}
+
+func (uc *RecommendationUseCase) activeProducts(ctx context.Context, filter map[string]interface{}) ([]*entity.Product, error) {
+	filter["status"] = "active"
+	products, _, err := uc.productRepo.List(ctx, filter, "", math.MaxInt32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]*entity.Product, 0, len(products))
+	for _, product := range products {
+		if product.DeletedAt == nil {
+			active = append(active, product)
+		}
+	}
+	return active, nil
+}
+
+// GetSimilarProducts returns the listings most like the given one
+func (uc *RecommendationUseCase) GetSimilarProducts(ctx context.Context, productID string, limit int) ([]*entity.Product, error) {
+	return uc.relatedProducts(ctx, productID, limit, func(recommendations *entity.ProductRecommendations) []entity.RecommendedProduct {
+		return recommendations.Similar
+	})
+}
+
+// GetAlsoViewedProducts returns the listings that users interested in the
+// given one also looked at, wishlisted or bought
+func (uc *RecommendationUseCase) GetAlsoViewedProducts(ctx context.Context, productID string, limit int) ([]*entity.Product, error) {
+	return uc.relatedProducts(ctx, productID, limit, func(recommendations *entity.ProductRecommendations) []entity.RecommendedProduct {
+		return recommendations.AlsoViewed
+	})
+}
+
+// relatedProducts serves one of a listing's precomputed lists, topped up with
+// popular listings of the same game while the list is short or not yet built
+func (uc *RecommendationUseCase) relatedProducts(ctx context.Context, productID string, limit int, list func(*entity.ProductRecommendations) []entity.RecommendedProduct) ([]*entity.Product, error) {
+	limit = recommendationLimit(limit)
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.DeletedAt != nil {
+		return nil, errors.NotFound("Product", nil)
+	}
+
+	var ranked []entity.RecommendedProduct
+	recommendations, err := uc.recommendationRepo.GetRecommendations(ctx, productID)
+	if err == nil {
+		ranked = list(recommendations)
+	} else if !errors.Is(err, "NOT_FOUND") {
+		return nil, err
+	}
+
+	exclude := map[string]bool{product.ID: true}
+	products := uc.resolveRecommendations(ctx, ranked, exclude, "", limit)
+
+	return uc.fillWithPopular(ctx, products, []string{product.GameTitleID}, exclude, "", limit)
+}
+
+// GetRecommendedProducts ranks listings for a user from what they recently
+// viewed, wishlisted and bought: each of those listings lends its similar and
+// also-viewed lists, weighted by the signal. Listings the user already
+// interacted with or sells are left out, and popular listings of the games
+// they look at fill any remaining places.
+func (uc *RecommendationUseCase) GetRecommendedProducts(ctx context.Context, userID string, limit int) ([]*entity.Product, error) {
+	limit = recommendationLimit(limit)
+
+	seeds, games, err := uc.userSeeds(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[string]float64{}
+	for productID, weight := range seeds {
+		recommendations, err := uc.recommendationRepo.GetRecommendations(ctx, productID)
+		if err != nil {
+			if !errors.Is(err, "NOT_FOUND") {
+				log.Printf("Error getting recommendations of product %s: %v", productID, err)
+			}
+			continue
+		}
+
+		for _, related := range recommendations.Similar {
+			scores[related.ProductID] += weight * related.Score
+		}
+		for _, related := range recommendations.AlsoViewed {
+			scores[related.ProductID] += weight * related.Score
+		}
+	}
+
+	exclude := map[string]bool{}
+	for productID := range seeds {
+		exclude[productID] = true
+	}
+
+	products := uc.resolveRecommendations(ctx, rankRecommendations(scores, len(scores)), exclude, userID, limit)
+
+	// Users without history get the most popular listings overall
+	return uc.fillWithPopular(ctx, products, append(games, ""), exclude, userID, limit)
+}
+
+// userSeeds collects the listings a user recently showed interest in with
+// their strongest signal, and the games of those listings, most seen first
+func (uc *RecommendationUseCase) userSeeds(ctx context.Context, userID string) (map[string]float64, []string, error) {
+	seeds := RecommendationBaskets{}
+	gameCounts := map[string]float64{}
+
+	views, err := uc.recommendationRepo.ListViewsByUser(ctx, userID, recommendationSeedLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, view := range views {
+		seeds.Add(userID, view.ProductID, RecommendationViewWeight)
+		gameCounts[view.GameTitleID]++
+	}
+
+	items, _, err := uc.wishlistRepo.GetUserWishlist(ctx, userID, recommendationSeedLimit, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range items {
+		seeds.Add(userID, item.ProductID, RecommendationWishlistWeight)
+		if item.Product != nil {
+			gameCounts[item.Product.GameTitleID]++
+		}
+	}
+
+	transactions, _, err := uc.transactionRepo.ListByUserID(ctx, userID, "buyer", "completed", recommendationSeedLimit, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, transaction := range transactions {
+		seeds.Add(userID, transaction.ProductID, RecommendationPurchaseWeight)
+	}
+
+	delete(gameCounts, "")
+	games := make([]string, 0, len(gameCounts))
+	for _, game := range rankRecommendations(gameCounts, len(gameCounts)) {
+		games = append(games, game.ProductID)
+	}
+
+	return seeds[userID], games, nil
+}
+
+// resolveRecommendations loads ranked listings, skipping excluded ones, those
+// no longer active and those of skipSellerID, and adds the rest to exclude
+func (uc *RecommendationUseCase) resolveRecommendations(ctx context.Context, ranked []entity.RecommendedProduct, exclude map[string]bool, skipSellerID string, limit int) []*entity.Product {
+	products := []*entity.Product{}
+	for _, related := range ranked {
+		if len(products) == limit {
+			break
+		}
+		if exclude[related.ProductID] {
+			continue
+		}
+
+		product, err := uc.productRepo.GetByID(ctx, related.ProductID)
+		if err != nil {
+			if !errors.Is(err, "NOT_FOUND") {
+				log.Printf("Error getting recommended product %s: %v", related.ProductID, err)
+			}
+			continue
+		}
+		if product.Status != "active" || product.DeletedAt != nil || (skipSellerID != "" && product.SellerID == skipSellerID) {
+			continue
+		}
+
+		exclude[product.ID] = true
+		products = append(products, publicProduct(product))
+	}
+	return products
+}
+
+// fillWithPopular tops a list up to limit with the most popular listings of
+// the given games in order; an empty game ID stands for the whole catalog
+func (uc *RecommendationUseCase) fillWithPopular(ctx context.Context, products []*entity.Product, games []string, exclude map[string]bool, skipSellerID string, limit int) ([]*entity.Product, error) {
+	for _, gameTitleID := range games {
+		if len(products) >= limit {
+			break
+		}
+
+		filter := map[string]interface{}{}
+		if gameTitleID != "" {
+			filter["gameTitleId"] = gameTitleID
+		}
+		popular, err := uc.activeProducts(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		sort.SliceStable(popular, func(i, j int) bool {
+			return ProductPopularity(popular[i]) > ProductPopularity(popular[j])
+		})
+
+		for _, product := range popular {
+			if len(products) == limit {
+				break
+			}
+			if exclude[product.ID] || (skipSellerID != "" && product.SellerID == skipSellerID) {
+				continue
+			}
+			exclude[product.ID] = true
+			products = append(products, publicProduct(product))
+		}
+	}
+
+	return products, nil
+}
+
+func recommendationLimit(limit int) int {
+	if limit <= 0 || limit > MaxRecommendations {
+		return MaxRecommendations
+	}
+	return limit
+}
+
+// publicProduct is a copy of a listing without its credentials
+func publicProduct(product *entity.Product) *entity.Product {
+	productCopy := *product
+	productCopy.Credentials = nil
+	return &productCopy
+}
@@ -73,7 +73,7 @@ func (uc *ReviewUseCase) GetReviewByID(ctx context.Context, id string) (*entity.
 	return uc.reviewRepo.GetByID(ctx, id)
 }
 
-func (uc *ReviewUseCase) ListReviews(ctx context.Context, userID, type_ string, rating int, page, limit int) ([]*entity.Review, int64, error) {
+func (uc *ReviewUseCase) ListReviews(ctx context.Context, userID, type_ string, rating int, pagination utils.PaginationParams) ([]*entity.Review, *utils.PageInfo, error) {
 	filter := make(map[string]interface{})
 
 	if userID != "" {
@@ -90,13 +90,11 @@ func (uc *ReviewUseCase) ListReviews(ctx context.Context, userID, type_ string,
 
 	filter["status"] = "active"
 
-	pagination := utils.NewPaginationParams(page, limit)
-
-	return uc.reviewRepo.List(ctx, filter, pagination.PageSize, pagination.Offset)
+	return uc.reviewRepo.ListPage(ctx, filter, pagination)
 }
 
 // GetProductReviews gets all reviews for a specific product
-func (uc *ReviewUseCase) GetProductReviews(ctx context.Context, productID string, rating int, page, limit int) ([]*entity.Review, int64, error) {
+func (uc *ReviewUseCase) GetProductReviews(ctx context.Context, productID string, rating int, pagination utils.PaginationParams) ([]*entity.Review, *utils.PageInfo, error) {
 	filter := make(map[string]interface{})
 	
 	filter["productId"] = productID
@@ -106,9 +104,7 @@ func (uc *ReviewUseCase) GetProductReviews(ctx context.Context, productID string
 		filter["rating"] = rating
 	}
 
-	pagination := utils.NewPaginationParams(page, limit)
-
-	return uc.reviewRepo.List(ctx, filter, pagination.PageSize, pagination.Offset)
+	return uc.reviewRepo.ListPage(ctx, filter, pagination)
 }
 
 func (uc *ReviewUseCase) ReportReview(ctx context.Context, reporterID, reviewID, reason, description string) (*entity.ReviewReport, error) {
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/utils"
+)
+
+// Security event types written to the security log
+const (
+	SecurityEventLogin            = "login"
+	SecurityEventCheckout         = "checkout"
+	SecurityEventWithdrawal       = "withdrawal"
+	SecurityEventCredentialReveal = "credential_reveal"
+)
+
+const (
+	securityLogScanLimit = 200
+	// IPs seen on more accounts than this are treated as shared networks
+	// (internet cafes, mobile carrier NAT) and not used for linking
+	maxAccountsPerLinkedIP = 20
+	// Only the user's most recently used devices and IPs are followed, with
+	// a smaller scan per device or IP, to bound the queries per lookup
+	maxLinkedDevices     = 5
+	maxLinkedIPAddresses = 5
+	linkedScanLimit      = 50
+)
+
+type SecurityLogUseCase struct {
+	securityLogRepo repository.SecurityLogRepository
+}
+
+func NewSecurityLogUseCase(securityLogRepo repository.SecurityLogRepository) *SecurityLogUseCase {
+	return &SecurityLogUseCase{
+		securityLogRepo: securityLogRepo,
+	}
+}
+
+type SecurityEventInput struct {
+	UserID        string
+	EventType     string
+	TransactionID string
+	RiskScore     int
+	Details       map[string]interface{}
+}
+
+// RecordEvent writes a security log using the client info on the context.
+// Failures are logged and never block the calling flow.
+func (uc *SecurityLogUseCase) RecordEvent(ctx context.Context, input SecurityEventInput) {
+	client := utils.ClientInfoFromContext(ctx)
+
+	securityLog := &entity.SecurityLog{
+		TransactionID: input.TransactionID,
+		UserID:        input.UserID,
+		EventType:     input.EventType,
+		RiskScore:     input.RiskScore,
+		Details:       input.Details,
+		IPAddress:     client.IPAddress,
+		UserAgent:     client.UserAgent,
+		DeviceID:      client.DeviceID,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := uc.securityLogRepo.Create(ctx, securityLog); err != nil {
+		log.Printf("Failed to record %s security event for user %s: %v", input.EventType, input.UserID, err)
+	}
+}
+
+func (uc *SecurityLogUseCase) ListUserEvents(ctx context.Context, userID string, limit int) ([]*entity.SecurityLog, error) {
+	if limit <= 0 || limit > securityLogScanLimit {
+		limit = securityLogScanLimit
+	}
+	return uc.securityLogRepo.ListByUserID(ctx, userID, limit)
+}
+
+// LinkedAccount is an edge in the linkage graph from the inspected user
+type LinkedAccount struct {
+	UserID            string    `json:"user_id"`
+	SharedDeviceIDs   []string  `json:"shared_device_ids,omitempty"`
+	SharedIPAddresses []string  `json:"shared_ip_addresses,omitempty"`
+	LastSeenAt        time.Time `json:"last_seen_at"`
+}
+
+type AccountLinkage struct {
+	UserID         string           `json:"user_id"`
+	DeviceIDs      []string         `json:"device_ids"`
+	IPAddresses    []string         `json:"ip_addresses"`
+	SharedNetworks []string         `json:"shared_networks,omitempty"` // IPs ignored for linking
+	LinkedAccounts []*LinkedAccount `json:"linked_accounts"`
+}
+
+// DeviceLinkedCount counts linked accounts that share at least one device
+func (l *AccountLinkage) DeviceLinkedCount() int {
+	count := 0
+	for _, account := range l.LinkedAccounts {
+		if len(account.SharedDeviceIDs) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// IsLinkedTo reports whether the given user shares a device or IP
+func (l *AccountLinkage) IsLinkedTo(userID string) bool {
+	for _, account := range l.LinkedAccounts {
+		if account.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLinkedAccounts builds the accounts that share a device or IP address
+// with the user, based on recent security logs. Only the most recently used
+// devices and IPs are followed; their logs are loaded concurrently.
+func (uc *SecurityLogUseCase) GetLinkedAccounts(ctx context.Context, userID string) (*AccountLinkage, error) {
+	logs, err := uc.securityLogRepo.ListByUserID(ctx, userID, securityLogScanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logs are newest first, so the recent lists keep the latest devices and IPs
+	devices := map[string]bool{}
+	ips := map[string]bool{}
+	var recentDevices, recentIPs []string
+	for _, entry := range logs {
+		if entry.DeviceID != "" && !devices[entry.DeviceID] {
+			devices[entry.DeviceID] = true
+			if len(recentDevices) < maxLinkedDevices {
+				recentDevices = append(recentDevices, entry.DeviceID)
+			}
+		}
+		if entry.IPAddress != "" && !ips[entry.IPAddress] {
+			ips[entry.IPAddress] = true
+			if len(recentIPs) < maxLinkedIPAddresses {
+				recentIPs = append(recentIPs, entry.IPAddress)
+			}
+		}
+	}
+
+	deviceEntries, ipEntries, err := uc.loadLinkedLogs(ctx, recentDevices, recentIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	linkage := &AccountLinkage{
+		UserID:         userID,
+		DeviceIDs:      sortedKeys(devices),
+		IPAddresses:    sortedKeys(ips),
+		LinkedAccounts: []*LinkedAccount{},
+	}
+
+	linked := map[string]*LinkedAccount{}
+	link := func(otherID string, seenAt time.Time) *LinkedAccount {
+		account, ok := linked[otherID]
+		if !ok {
+			account = &LinkedAccount{UserID: otherID}
+			linked[otherID] = account
+		}
+		if seenAt.After(account.LastSeenAt) {
+			account.LastSeenAt = seenAt
+		}
+		return account
+	}
+
+	for i, deviceID := range recentDevices {
+		for _, entry := range deviceEntries[i] {
+			if entry.UserID == userID || entry.UserID == "" {
+				continue
+			}
+			account := link(entry.UserID, entry.CreatedAt)
+			if !containsString(account.SharedDeviceIDs, deviceID) {
+				account.SharedDeviceIDs = append(account.SharedDeviceIDs, deviceID)
+			}
+		}
+	}
+
+	for i, ipAddress := range recentIPs {
+		entries := ipEntries[i]
+
+		accounts := map[string]bool{}
+		for _, entry := range entries {
+			if entry.UserID != "" {
+				accounts[entry.UserID] = true
+			}
+		}
+		if len(accounts) > maxAccountsPerLinkedIP {
+			linkage.SharedNetworks = append(linkage.SharedNetworks, ipAddress)
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.UserID == userID || entry.UserID == "" {
+				continue
+			}
+			account := link(entry.UserID, entry.CreatedAt)
+			if !containsString(account.SharedIPAddresses, ipAddress) {
+				account.SharedIPAddresses = append(account.SharedIPAddresses, ipAddress)
+			}
+		}
+	}
+
+	for _, account := range linked {
+		linkage.LinkedAccounts = append(linkage.LinkedAccounts, account)
+	}
+	sort.Slice(linkage.LinkedAccounts, func(i, j int) bool {
+		return linkage.LinkedAccounts[i].LastSeenAt.After(linkage.LinkedAccounts[j].LastSeenAt)
+	})
+
+	return linkage, nil
+}
+
+// loadLinkedLogs fetches the logs of each device and IP in parallel,
+// returning them in input order
+func (uc *SecurityLogUseCase) loadLinkedLogs(ctx context.Context, deviceIDs, ipAddresses []string) ([][]*entity.SecurityLog, [][]*entity.SecurityLog, error) {
+	deviceEntries := make([][]*entity.SecurityLog, len(deviceIDs))
+	ipEntries := make([][]*entity.SecurityLog, len(ipAddresses))
+	errs := make([]error, len(deviceIDs)+len(ipAddresses))
+
+	var wg sync.WaitGroup
+	for i, deviceID := range deviceIDs {
+		wg.Add(1)
+		go func(i int, deviceID string) {
+			defer wg.Done()
+			deviceEntries[i], errs[i] = uc.securityLogRepo.ListByDeviceID(ctx, deviceID, linkedScanLimit)
+		}(i, deviceID)
+	}
+	for i, ipAddress := range ipAddresses {
+		wg.Add(1)
+		go func(i int, ipAddress string) {
+			defer wg.Done()
+			ipEntries[i], errs[len(deviceIDs)+i] = uc.securityLogRepo.ListByIPAddress(ctx, ipAddress, linkedScanLimit)
+		}(i, ipAddress)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return deviceEntries, ipEntries, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
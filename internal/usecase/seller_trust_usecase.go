@@ -0,0 +1,476 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+// Seller trust tiers, from most to least trusted
+const (
+	TrustTierTrusted    = "trusted"
+	TrustTierStandard   = "standard"
+	TrustTierNew        = "new"
+	TrustTierWatch      = "watch"
+	TrustTierRestricted = "restricted"
+)
+
+// TrustTierPolicy is what a tier changes for the seller
+type TrustTierPolicy struct {
+	ExtraHoldHours    int    `json:"extra_hold_hours"`    // added to the auto-release inspection window
+	MaxActiveListings int    `json:"max_active_listings"` // active products the seller may have at once
+	Badge             string `json:"badge,omitempty"`     // shown on the public seller profile
+}
+
+var TrustTierPolicies = map[string]TrustTierPolicy{
+	TrustTierTrusted:    {ExtraHoldHours: 0, MaxActiveListings: 500, Badge: "trusted_seller"},
+	TrustTierStandard:   {ExtraHoldHours: 0, MaxActiveListings: 100},
+	TrustTierNew:        {ExtraHoldHours: 12, MaxActiveListings: 20, Badge: "new_seller"},
+	TrustTierWatch:      {ExtraHoldHours: 24, MaxActiveListings: 20},
+	TrustTierRestricted: {ExtraHoldHours: 48, MaxActiveListings: 5},
+}
+
+const (
+	sellerTrustScanBatchSize = 100
+	// Profiles older than this are rescored even without new sales, at most
+	// sellerTrustRefreshBatchSize per run
+	sellerTrustRefreshAge       = 7 * 24 * time.Hour
+	sellerTrustRefreshBatchSize = 500
+	newSellerAgeDays            = 30
+	// Rates are computed over at least this many sales so a single early
+	// dispute does not dominate the score
+	minRiskSampleSize = 5
+)
+
+type SellerTrustUseCase struct {
+	trustRepo          repository.SellerTrustRepository
+	userRepo           repository.UserRepository
+	transactionRepo    repository.TransactionRepository
+	checklistRepo      repository.HandoverChecklistRepository
+	securityLogUseCase *SecurityLogUseCase
+}
+
+func NewSellerTrustUseCase(
+	trustRepo repository.SellerTrustRepository,
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	checklistRepo repository.HandoverChecklistRepository,
+	securityLogUseCase *SecurityLogUseCase,
+) *SellerTrustUseCase {
+	return &SellerTrustUseCase{
+		trustRepo:          trustRepo,
+		userRepo:           userRepo,
+		transactionRepo:    transactionRepo,
+		checklistRepo:      checklistRepo,
+		securityLogUseCase: securityLogUseCase,
+	}
+}
+
+// EffectiveTrustTier returns the seller's stored tier. Sellers not scored yet
+// are new during their first month and standard afterwards.
+func EffectiveTrustTier(user *entity.User, now time.Time) string {
+	if user.TrustTier != "" {
+		return user.TrustTier
+	}
+	if now.Sub(user.CreatedAt) < newSellerAgeDays*24*time.Hour {
+		return TrustTierNew
+	}
+	return TrustTierStandard
+}
+
+// TrustPolicyFor returns the policy of a tier, falling back to standard
+func TrustPolicyFor(tier string) TrustTierPolicy {
+	if policy, ok := TrustTierPolicies[tier]; ok {
+		return policy
+	}
+	return TrustTierPolicies[TrustTierStandard]
+}
+
+// ScoreSellerRisk turns the history signals into a 0-1 risk score and the
+// reasons that contributed to it
+func ScoreSellerRisk(signals entity.SellerRiskSignals) (float64, []string) {
+	score := 0.0
+	reasons := []string{}
+
+	if signals.DisputeRate > 0 {
+		score += signals.DisputeRate * 0.35
+		reasons = append(reasons, fmt.Sprintf("Dispute rate %.0f%%", signals.DisputeRate*100))
+	}
+
+	if signals.SLABreaches > 0 {
+		score += math.Min(float64(signals.SLABreaches), 5) / 5 * 0.15
+		reasons = append(reasons, fmt.Sprintf("%d handover SLA breaches", signals.SLABreaches))
+	}
+
+	if signals.RefundRatio > 0 {
+		score += signals.RefundRatio * 0.2
+		reasons = append(reasons, fmt.Sprintf("Refund ratio %.0f%%", signals.RefundRatio*100))
+	}
+
+	switch {
+	case signals.AccountAgeDays < newSellerAgeDays:
+		score += 0.1
+		reasons = append(reasons, "Account younger than 30 days")
+	case signals.AccountAgeDays < 90:
+		score += 0.05
+	}
+
+	if signals.LinkedAccounts >= 3 {
+		score += 0.1
+		reasons = append(reasons, fmt.Sprintf("%d linked accounts", signals.LinkedAccounts))
+	}
+
+	if signals.LinkedBuyerSales > 0 {
+		score += 0.2
+		reasons = append(reasons, fmt.Sprintf("%d sales to linked accounts", signals.LinkedBuyerSales))
+	}
+
+	return math.Min(score, 1.0), reasons
+}
+
+// TrustTierForScore maps a risk score and history to a tier
+func TrustTierForScore(score float64, signals entity.SellerRiskSignals) string {
+	switch {
+	case score >= 0.6:
+		return TrustTierRestricted
+	case score >= 0.35:
+		return TrustTierWatch
+	case signals.AccountAgeDays < newSellerAgeDays || signals.CompletedSales < 3:
+		return TrustTierNew
+	case score < 0.1 && signals.CompletedSales >= 20 && signals.AccountAgeDays >= 90:
+		return TrustTierTrusted
+	default:
+		return TrustTierStandard
+	}
+}
+
+// sellerHistory accumulates one seller's transactions during a scoring run
+type sellerHistory struct {
+	signals  entity.SellerRiskSignals
+	buyerIDs []string
+}
+
+// RunScoringJob rescores the sellers whose transactions or handover
+// checklists changed since the previous run, plus a batch of profiles not
+// scored for sellerTrustRefreshAge so tiers that depend on account age move on
+func (uc *SellerTrustUseCase) RunScoringJob(ctx context.Context) (*JobResult, error) {
+	startedAt := time.Now()
+
+	since := time.Time{}
+	checkpoint, err := uc.trustRepo.GetScoringCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil {
+		since = *checkpoint
+	}
+
+	sellers := map[string]bool{}
+
+	var last *entity.Transaction
+	for {
+		transactions, err := uc.transactionRepo.ListUpdatedSince(ctx, since, last, sellerTrustScanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			if transaction.SellerID != "" {
+				sellers[transaction.SellerID] = true
+			}
+		}
+
+		if len(transactions) < sellerTrustScanBatchSize {
+			break
+		}
+		last = transactions[len(transactions)-1]
+	}
+
+	// Handover checklists are only disputed when the SLA was missed
+	var lastChecklist *entity.HandoverChecklist
+	for {
+		checklists, err := uc.checklistRepo.ListByStatusUpdatedSince(ctx, "disputed", since, lastChecklist, sellerTrustScanBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, checklist := range checklists {
+			sellers[checklist.SellerID] = true
+		}
+
+		if len(checklists) < sellerTrustScanBatchSize {
+			break
+		}
+		lastChecklist = checklists[len(checklists)-1]
+	}
+
+	stale, err := uc.trustRepo.ListComputedBefore(ctx, startedAt.Add(-sellerTrustRefreshAge), sellerTrustRefreshBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range stale {
+		sellers[profile.SellerID] = true
+	}
+
+	result := &JobResult{}
+	failed := false
+
+	for sellerID := range sellers {
+		history, err := uc.loadSellerHistory(ctx, sellerID)
+		if err != nil {
+			result.Fail("Failed to load history of seller %s: %v", sellerID, err)
+			failed = true
+			continue
+		}
+
+		if err := uc.scoreSeller(ctx, sellerID, history, startedAt); err != nil {
+			result.Fail("Failed to score seller %s: %v", sellerID, err)
+			failed = true
+			continue
+		}
+		result.ItemsProcessed++
+	}
+
+	// Keep the previous checkpoint so the next run retries the sellers that
+	// failed, rescoring the others is harmless
+	if failed {
+		log.Printf("Seller risk scoring finished with failures: %d sellers scored", result.ItemsProcessed)
+		return result, nil
+	}
+
+	if err := uc.trustRepo.SaveScoringCheckpoint(ctx, startedAt); err != nil {
+		return result, err
+	}
+
+	log.Printf("Seller risk scoring finished: %d sellers scored", result.ItemsProcessed)
+	return result, nil
+}
+
+// loadSellerHistory accumulates every sale of the seller
+func (uc *SellerTrustUseCase) loadSellerHistory(ctx context.Context, sellerID string) (*sellerHistory, error) {
+	history := &sellerHistory{}
+	page := utils.PaginationParams{PageSize: sellerTrustScanBatchSize}
+
+	for {
+		transactions, pageInfo, err := uc.transactionRepo.ListByUserIDPage(ctx, sellerID, "seller", "", page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			history.signals.TotalSales++
+			history.buyerIDs = append(history.buyerIDs, transaction.BuyerID)
+
+			if transaction.Status == "completed" {
+				history.signals.CompletedSales++
+			}
+			if transaction.IsDisputed {
+				history.signals.Disputes++
+			}
+			if transaction.Status == "refunded" || transaction.PaymentStatus == "refunded" || transaction.EscrowStatus == "refunded" {
+				history.signals.Refunds++
+			}
+		}
+
+		if pageInfo.NextCursor == "" {
+			break
+		}
+		page.Cursor = pageInfo.NextCursor
+	}
+
+	slaBreaches, err := uc.checklistRepo.CountBySellerAndStatus(ctx, sellerID, "disputed")
+	if err != nil {
+		return nil, err
+	}
+	history.signals.SLABreaches = slaBreaches
+
+	return history, nil
+}
+
+func (uc *SellerTrustUseCase) scoreSeller(ctx context.Context, sellerID string, history *sellerHistory, now time.Time) error {
+	seller, err := uc.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		return err
+	}
+
+	signals := history.signals
+	sample := math.Max(float64(signals.TotalSales), minRiskSampleSize)
+	signals.DisputeRate = float64(signals.Disputes) / sample
+	signals.RefundRatio = float64(signals.Refunds) / sample
+	signals.AccountAgeDays = int(now.Sub(seller.CreatedAt).Hours() / 24)
+
+	linkage, err := uc.securityLogUseCase.GetLinkedAccounts(ctx, sellerID)
+	if err != nil {
+		log.Printf("Failed to load account linkage for seller %s: %v", sellerID, err)
+	} else {
+		signals.LinkedAccounts = len(linkage.LinkedAccounts)
+		for _, buyerID := range history.buyerIDs {
+			if linkage.IsLinkedTo(buyerID) {
+				signals.LinkedBuyerSales++
+			}
+		}
+	}
+
+	profile, err := uc.trustRepo.GetBySellerID(ctx, sellerID)
+	if err != nil {
+		if !errors.Is(err, "NOT_FOUND") {
+			return err
+		}
+		profile = &entity.SellerTrustProfile{SellerID: sellerID}
+	}
+
+	profile.Signals = signals
+	profile.RiskScore, profile.Reasons = ScoreSellerRisk(signals)
+	profile.ComputedTier = TrustTierForScore(profile.RiskScore, signals)
+	profile.Tier = profile.ComputedTier
+	if profile.OverrideTier != "" {
+		profile.Tier = profile.OverrideTier
+	}
+	profile.ComputedAt = now
+
+	if err := uc.trustRepo.Save(ctx, profile); err != nil {
+		return err
+	}
+
+	return uc.syncUserTier(ctx, seller, profile.Tier)
+}
+
+// syncUserTier copies the effective tier onto the user for badges and checks
+// that already load the seller
+func (uc *SellerTrustUseCase) syncUserTier(ctx context.Context, seller *entity.User, tier string) error {
+	if seller.TrustTier == tier {
+		return nil
+	}
+
+	if err := uc.userRepo.UpdateTrustTier(ctx, seller.ID, tier); err != nil {
+		return err
+	}
+
+	seller.TrustTier = tier
+	return nil
+}
+
+// GetProfile returns the seller's trust profile. Sellers not scored yet get
+// an unsaved profile with their default tier.
+func (uc *SellerTrustUseCase) GetProfile(ctx context.Context, sellerID string) (*entity.SellerTrustProfile, error) {
+	profile, err := uc.trustRepo.GetBySellerID(ctx, sellerID)
+	if err == nil {
+		return profile, nil
+	}
+	if !errors.Is(err, "NOT_FOUND") {
+		return nil, err
+	}
+
+	seller, err := uc.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		return nil, errors.NotFound("Seller", err)
+	}
+
+	tier := EffectiveTrustTier(seller, time.Now())
+	return &entity.SellerTrustProfile{
+		SellerID:     sellerID,
+		ComputedTier: tier,
+		Tier:         tier,
+	}, nil
+}
+
+func (uc *SellerTrustUseCase) ListProfiles(ctx context.Context, tier string, page, limit int) ([]*entity.SellerTrustProfile, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return uc.trustRepo.List(ctx, tier, limit, (page-1)*limit)
+}
+
+// OverrideTier pins a seller to a tier until the override is cleared
+func (uc *SellerTrustUseCase) OverrideTier(ctx context.Context, adminID, sellerID, tier, reason string) (*entity.SellerTrustProfile, error) {
+	if _, ok := TrustTierPolicies[tier]; !ok {
+		return nil, errors.BadRequest("Invalid trust tier", nil)
+	}
+	if reason == "" {
+		return nil, errors.BadRequest("A reason is required to override a trust tier", nil)
+	}
+
+	profile, err := uc.GetProfile(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profile.OverrideTier = tier
+	profile.OverrideReason = reason
+	profile.OverriddenBy = adminID
+	profile.OverriddenAt = &now
+	profile.Tier = tier
+
+	if err := uc.saveAndSync(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Seller %s trust tier overridden to %s by admin %s: %s", sellerID, tier, adminID, reason)
+	return profile, nil
+}
+
+// ClearOverride returns the seller to the computed tier
+func (uc *SellerTrustUseCase) ClearOverride(ctx context.Context, adminID, sellerID string) (*entity.SellerTrustProfile, error) {
+	profile, err := uc.trustRepo.GetBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.OverrideTier == "" {
+		return nil, errors.BadRequest("Seller has no trust tier override", nil)
+	}
+
+	profile.OverrideTier = ""
+	profile.OverrideReason = ""
+	profile.OverriddenBy = ""
+	profile.OverriddenAt = nil
+	profile.Tier = profile.ComputedTier
+
+	if err := uc.saveAndSync(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Seller %s trust tier override cleared by admin %s", sellerID, adminID)
+	return profile, nil
+}
+
+func (uc *SellerTrustUseCase) saveAndSync(ctx context.Context, profile *entity.SellerTrustProfile) error {
+	if err := uc.trustRepo.Save(ctx, profile); err != nil {
+		return err
+	}
+
+	seller, err := uc.userRepo.GetByID(ctx, profile.SellerID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.syncUserTier(ctx, seller, profile.Tier); err != nil {
+		return errors.Internal("Failed to update seller trust tier", err)
+	}
+
+	return nil
+}
+
+// ExtraHoldFor returns the escrow hold added for the seller's tier
+func (uc *SellerTrustUseCase) ExtraHoldFor(ctx context.Context, sellerID string) time.Duration {
+	seller, err := uc.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		log.Printf("Failed to load seller %s for trust tier: %v", sellerID, err)
+		return 0
+	}
+
+	policy := TrustPolicyFor(EffectiveTrustTier(seller, time.Now()))
+	return time.Duration(policy.ExtraHoldHours) * time.Hour
+}
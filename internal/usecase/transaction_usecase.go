@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+
 	"pasargamex/internal/domain/entity"
 	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/domain/service"
 	"pasargamex/pkg/errors"
 	"pasargamex/pkg/logger"
 	"pasargamex/pkg/utils"
@@ -29,6 +32,12 @@ type TransactionUseCase struct {
 	feeCalculator   FeeCalculator
 	chatUseCase     *ChatUseCase
 	walletUseCase   *WalletUseCase
+	credentialVault service.CredentialVault
+	fraudUseCase    *FraudDetectionUseCase
+	velocityUseCase  *VelocityUseCase
+	inventoryUseCase *InventoryUseCase
+	approvalUseCase  *ApprovalUseCase
+	handoverUseCase  *HandoverUseCase
 }
 
 func NewTransactionUseCase(
@@ -37,6 +46,12 @@ func NewTransactionUseCase(
 	userRepo repository.UserRepository,
 	chatUseCase *ChatUseCase,
 	walletUseCase *WalletUseCase,
+	credentialVault service.CredentialVault,
+	fraudUseCase *FraudDetectionUseCase,
+	velocityUseCase *VelocityUseCase,
+	inventoryUseCase *InventoryUseCase,
+	approvalUseCase *ApprovalUseCase,
+	handoverUseCase *HandoverUseCase,
 ) *TransactionUseCase {
 	return &TransactionUseCase{
 		transactionRepo: transactionRepo,
@@ -45,11 +60,18 @@ func NewTransactionUseCase(
 		feeCalculator:   &defaultFeeCalculator{},
 		chatUseCase:     chatUseCase,
 		walletUseCase:   walletUseCase,
+		credentialVault: credentialVault,
+		fraudUseCase:    fraudUseCase,
+		velocityUseCase:  velocityUseCase,
+		inventoryUseCase: inventoryUseCase,
+		approvalUseCase:  approvalUseCase,
+		handoverUseCase:  handoverUseCase,
 	}
 }
 
 type CreateTransactionInput struct {
 	ProductID      string
+	VariantID      string // Required when the product has variants
 	DeliveryMethod string
 	PaymentMethod  string // "wallet" or "external"
 	Notes          string
@@ -71,6 +93,11 @@ func (uc *TransactionUseCase) CreateTransaction(ctx context.Context, buyerID str
 		return nil, err
 	}
 
+	buyer, err := uc.userRepo.GetByID(ctx, buyerID)
+	if err != nil {
+		return nil, err
+	}
+
 	if product.SellerID == buyerID {
 		return nil, errors.BadRequest("Cannot buy your own product", nil)
 	}
@@ -87,20 +114,31 @@ func (uc *TransactionUseCase) CreateTransaction(ctx context.Context, buyerID str
 		return nil, errors.BadRequest("Invalid delivery method", nil)
 	}
 
-	if input.DeliveryMethod == "instant" && len(product.Credentials) == 0 {
+	if input.DeliveryMethod == "instant" && !product.HasCredentials() && !product.UsesCodeInventory {
 		return nil, errors.BadRequest("Product credentials are not available", nil)
 	}
 
-	fee := uc.feeCalculator.CalculateFee(product.Price, "")
-	totalAmount := product.Price + fee
+	item, err := resolveCheckoutItem(product, input.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStockAvailable(ctx, uc.transactionRepo, product, item); err != nil {
+		return nil, err
+	}
+
+	fee := uc.feeCalculator.CalculateFee(item.Price, "")
+	totalAmount := item.Price + fee
 
 	transaction := &entity.Transaction{
 		ProductID:      input.ProductID,
+		VariantID:      item.variantID(),
+		VariantLabel:   item.variantLabel(),
 		SellerID:       product.SellerID,
 		BuyerID:        buyerID,
 		Status:         "pending", // Initial status is always pending
 		DeliveryMethod: input.DeliveryMethod,
-		Amount:         product.Price,
+		Amount:         item.Price,
 		Fee:            fee,
 		TotalAmount:    totalAmount,
 		PaymentStatus:  "pending", // Payment status also pending initially
@@ -109,19 +147,39 @@ func (uc *TransactionUseCase) CreateTransaction(ctx context.Context, buyerID str
 		Notes:          input.Notes,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
+
+		RequiredApprovals: LegacyRequiredApprovalsFor(input.DeliveryMethod),
 	}
 
-	if input.DeliveryMethod == "instant" {
-		transaction.Credentials = product.Credentials
+	// Inventory codes are only claimed once the order is paid
+	if input.DeliveryMethod == "instant" && !product.UsesCodeInventory {
+		sealedCredentials, err := productSealedCredentials(ctx, uc.credentialVault, product)
+		if err != nil {
+			return nil, err
+		}
+		transaction.EncryptedCredentials = sealedCredentials
+	}
+
+	// The ID is assigned up front so the velocity reservation can reference it
+	transaction.ID = uuid.New().String()
+	if err := uc.velocityUseCase.Reserve(ctx, VelocityActionCheckout, buyerID, transaction.TotalAmount, transaction.ID); err != nil {
+		return nil, err
+	}
+
+	// "block" rejects the checkout, "review" holds it for an admin
+	if err := uc.fraudUseCase.ScoreCheckout(ctx, transaction, buyer, seller, product); err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionCheckout, buyerID, transaction.ID)
+		return nil, err
 	}
 
 	if err := uc.transactionRepo.Create(ctx, transaction); err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionCheckout, buyerID, transaction.ID)
 		return nil, err
 	}
 
 	log := &entity.TransactionLog{
 		TransactionID: transaction.ID,
-		Status:        "pending",
+		Status:        transaction.Status,
 		Notes:         "Transaction created",
 		CreatedBy:     buyerID,
 		CreatedAt:     time.Now(),
@@ -144,7 +202,13 @@ func (uc *TransactionUseCase) GetTransactionByID(ctx context.Context, userID, tr
 		return nil, errors.Forbidden("You don't have permission to view this transaction", nil)
 	}
 
-	return uc.prepareTransactionResponse(transaction, userID), nil
+	approvals, err := uc.transactionRepo.GetApprovalsByTransactionID(ctx, transactionID)
+	if err != nil {
+		logger.Error("Failed to load approvals for transaction %s: %v", transactionID, err)
+		approvals = []*entity.TransactionApproval{}
+	}
+
+	return uc.prepareTransactionResponse(transaction, userID, approvals), nil
 }
 
 // GetTransactionStatus returns lightweight status information
@@ -189,24 +253,22 @@ func (uc *TransactionUseCase) GetTransactionStatus(ctx context.Context, userID,
 	return statusInfo, nil
 }
 
-func (uc *TransactionUseCase) ListTransactions(ctx context.Context, userID, role, status string, page, limit int) ([]interface{}, int64, error) {
+func (uc *TransactionUseCase) ListTransactions(ctx context.Context, userID, role, status string, pagination utils.PaginationParams) ([]interface{}, *utils.PageInfo, error) {
 	if role != "buyer" && role != "seller" {
 		role = "buyer"
 	}
 
-	pagination := utils.NewPaginationParams(page, limit)
-
-	transactions, total, err := uc.transactionRepo.ListByUserID(ctx, userID, role, status, pagination.PageSize, pagination.Offset)
+	transactions, pageInfo, err := uc.transactionRepo.ListByUserIDPage(ctx, userID, role, status, pagination)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	responses := make([]interface{}, len(transactions))
 	for i, transaction := range transactions {
-		responses[i] = uc.prepareTransactionResponse(transaction, userID)
+		responses[i] = uc.prepareTransactionResponse(transaction, userID, nil)
 	}
 
-	return responses, total, nil
+	return responses, pageInfo, nil
 }
 
 // Modified: ProcessPayment now handles wallet payment and middleman transactions
@@ -224,6 +286,10 @@ func (uc *TransactionUseCase) ProcessPayment(ctx context.Context, userID, transa
 		return nil, errors.BadRequest("Payment already processed or refunded", nil)
 	}
 
+	if err := EnsureFraudCleared(transaction); err != nil {
+		return nil, err
+	}
+
 	// Process wallet payment
 	if paymentMethod == "wallet" {
 		if uc.walletUseCase == nil {
@@ -245,10 +311,41 @@ func (uc *TransactionUseCase) ProcessPayment(ctx context.Context, userID, transa
 	now := time.Now()
 	transaction.PaymentAt = &now
 
+	// Instant orders on inventory products get their code from the pool now
+	var inventoryProduct *entity.Product
 	if transaction.DeliveryMethod == "instant" {
-		// For instant delivery, payment means completion
-		transaction.Status = "completed"
-		transaction.CompletedAt = &now
+		product, err := uc.productRepo.GetByID(ctx, transaction.ProductID)
+		if err == nil && product.UsesCodeInventory {
+			if err := uc.inventoryUseCase.DeliverCode(ctx, product, transaction); err != nil {
+				if paymentMethod == "wallet" && uc.walletUseCase != nil {
+					refundDescription := fmt.Sprintf("Refund for undeliverable transaction %s", transaction.ID)
+					uc.walletUseCase.ProcessWalletRefund(ctx, userID, transaction.TotalAmount, refundDescription, transaction.ID)
+				}
+				return nil, err
+			}
+			transaction.CredentialsDelivered = true
+			transaction.CredentialsDeliveredAt = &now
+			inventoryProduct = product
+		}
+	}
+
+	if transaction.DeliveryMethod == "instant" {
+		// For instant delivery, payment means delivery; the transaction
+		// completes once the system approval is recorded
+		if err := uc.recordApproval(ctx, transaction, "system", "system", ApprovalStepSystemDelivery, "Delivered on payment"); err != nil {
+			if paymentMethod == "wallet" && uc.walletUseCase != nil {
+				refundDescription := fmt.Sprintf("Refund for failed transaction %s", transaction.ID)
+				uc.walletUseCase.ProcessWalletRefund(ctx, userID, transaction.TotalAmount, refundDescription, transaction.ID)
+			}
+			return nil, err
+		}
+		released, err := uc.completeIfApproved(ctx, transaction, now)
+		if err != nil {
+			logger.Error("Failed to check approvals of transaction %s: %v", transaction.ID, err)
+		}
+		if !released {
+			transaction.Status = "processing"
+		}
 	} else if transaction.DeliveryMethod == "middleman" {
 		// For middleman, payment by buyer means awaiting middleman confirmation
 		// Status remains "pending" until middleman confirms funds received
@@ -265,6 +362,10 @@ func (uc *TransactionUseCase) ProcessPayment(ctx context.Context, userID, transa
 		return nil, err
 	}
 
+	if inventoryProduct != nil {
+		uc.inventoryUseCase.RecordSale(ctx, inventoryProduct, transaction.VariantID)
+	}
+
 	log := &entity.TransactionLog{
 		TransactionID: transaction.ID,
 		Status:        transaction.Status, // Log the current status (pending)
@@ -406,22 +507,45 @@ func (uc *TransactionUseCase) VerifyAndCompleteMiddleman(ctx context.Context, ad
 		return nil, errors.BadRequest("Transaction is not ready for completion. Funds must be confirmed by middleman.", nil)
 	}
 
-	transaction.Status = "completed"
-	transaction.MiddlemanStatus = "completed"
-	transaction.Credentials = credentials
+	sealedCredentials, err := sealCredentials(ctx, uc.credentialVault, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.EncryptedCredentials = sealedCredentials
+	transaction.Credentials = nil
 
 	now := time.Now()
-	transaction.CompletedAt = &now
 	transaction.UpdatedAt = now
 
+	// The middleman's verification is one approval; funds are only released
+	// once every required party has approved
+	if err := uc.recordApproval(ctx, transaction, "middleman", adminID, ApprovalStepVerificationComplete, "Verified by middleman"); err != nil {
+		return nil, err
+	}
+
+	released, err := uc.completeIfApproved(ctx, transaction, now)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := "Transaction completed by middleman"
+	message := "Transaction completed successfully! Funds released to seller."
+	transaction.MiddlemanStatus = "completed"
+	if !released {
+		notes = "Delivery verified by middleman, awaiting remaining approvals"
+		message = "Middleman verified the delivery. Waiting for remaining approvals before releasing funds."
+		transaction.MiddlemanStatus = "verified"
+	}
+
 	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
 		return nil, err
 	}
 
 	log := &entity.TransactionLog{
 		TransactionID: transaction.ID,
-		Status:        "completed",
-		Notes:         "Transaction completed by middleman",
+		Status:        transaction.Status,
+		Notes:         notes,
 		CreatedBy:     adminID,
 		CreatedAt:     time.Now(),
 	}
@@ -431,7 +555,7 @@ func (uc *TransactionUseCase) VerifyAndCompleteMiddleman(ctx context.Context, ad
 	}
 
 	if transaction.DeliveryMethod == "middleman" && transaction.MiddlemanChatID != "" {
-		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, "Transaction completed successfully! Funds released to seller.", "transaction_completed", map[string]interface{}{"transaction_id": transaction.ID})
+		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, "transaction_completed", map[string]interface{}{"transaction_id": transaction.ID, "released": released})
 	}
 
 	return transaction, nil
@@ -599,6 +723,14 @@ func (uc *TransactionUseCase) ResolveDispute(ctx context.Context, adminID, trans
 		transaction.PaymentStatus = "refunded"
 		transaction.RefundedAt = &now
 	} else {
+		// Resolving for the seller releases funds, so the approval and
+		// handover rules apply
+		if err := uc.approvalUseCase.EnsureReleasable(ctx, transaction); err != nil {
+			return nil, err
+		}
+		if err := uc.handoverUseCase.EnsureComplete(ctx, transaction); err != nil {
+			return nil, err
+		}
 		newStatus = "completed"
 		transaction.Status = newStatus
 		transaction.CompletedAt = &now
@@ -643,18 +775,35 @@ func (uc *TransactionUseCase) ConfirmDelivery(ctx context.Context, buyerID, tran
 		return nil, errors.BadRequest("Transaction is not in processing status", nil)
 	}
 
-	transaction.Status = "completed"
+	// The buyer confirms an account sale only after the handover is done
+	if err := uc.handoverUseCase.EnsureComplete(ctx, transaction); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
-	transaction.CompletedAt = &now
 	transaction.UpdatedAt = now
 
+	if err := uc.recordApproval(ctx, transaction, "buyer", buyerID, ApprovalStepItemReceived, "Delivery confirmed by buyer"); err != nil {
+		return nil, err
+	}
+
+	released, err := uc.completeIfApproved(ctx, transaction, now)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
 		return nil, err
 	}
 
+	message := "Delivery confirmed by buyer. Transaction completed."
+	if !released {
+		message = "Delivery confirmed by buyer. Waiting for remaining approvals before releasing funds."
+	}
+
 	log := &entity.TransactionLog{
 		TransactionID: transaction.ID,
-		Status:        "completed",
+		Status:        transaction.Status,
 		Notes:         "Delivery confirmed by buyer",
 		CreatedBy:     buyerID,
 		CreatedAt:     now,
@@ -665,12 +814,54 @@ func (uc *TransactionUseCase) ConfirmDelivery(ctx context.Context, buyerID, tran
 	}
 
 	if transaction.DeliveryMethod == "middleman" && transaction.MiddlemanChatID != "" {
-		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, "Delivery confirmed by buyer. Transaction completed.", "delivery_confirmed", map[string]interface{}{"transaction_id": transaction.ID})
+		uc.chatUseCase.SendSystemMessage(ctx, transaction.MiddlemanChatID, message, "delivery_confirmed", map[string]interface{}{"transaction_id": transaction.ID, "released": released})
 	}
 
 	return transaction, nil
 }
 
+// recordApproval adds a party's approval to the transaction's approval trail
+func (uc *TransactionUseCase) recordApproval(ctx context.Context, transaction *entity.Transaction, approverType, approverID, step, notes string) error {
+	_, err := uc.approvalUseCase.RecordApproval(ctx, transaction, RecordApprovalInput{
+		ApproverType: approverType,
+		ApproverID:   approverID,
+		ApprovalStep: step,
+		Approved:     true,
+		Evidence:     ApprovalEvidence{Notes: notes},
+	})
+	if err != nil {
+		return errors.Internal("Failed to record "+approverType+" approval", err)
+	}
+	return nil
+}
+
+// completeIfApproved completes the transaction once every required approval
+// is in and any account handover checklist is complete, and reports whether
+// it did. Funds are never released past a missing approval or an unfinished
+// handover, whichever path the release comes from.
+func (uc *TransactionUseCase) completeIfApproved(ctx context.Context, transaction *entity.Transaction, now time.Time) (bool, error) {
+	missing, err := uc.approvalUseCase.MissingApprovals(ctx, transaction)
+	if err != nil {
+		return false, errors.Internal("Failed to check transaction approvals", err)
+	}
+	if len(missing) > 0 {
+		logger.Info("Release of transaction %s waits for approvals: %v", transaction.ID, missing)
+		return false, nil
+	}
+
+	if err := uc.handoverUseCase.EnsureComplete(ctx, transaction); err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			logger.Info("Release of transaction %s waits for the handover: %v", transaction.ID, err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	transaction.Status = "completed"
+	transaction.CompletedAt = &now
+	return true, nil
+}
+
 func (uc *TransactionUseCase) isValidStatusTransition(currentStatus, newStatus, deliveryMethod string, isBuyer, isSeller bool) bool {
 	validTransitions := map[string]map[string]struct {
 		allowed bool
@@ -755,7 +946,7 @@ func (uc *TransactionUseCase) ListPendingMiddlemanTransactions(ctx context.Conte
 	return transactions, total, nil
 }
 
-func (uc *TransactionUseCase) prepareTransactionResponse(transaction *entity.Transaction, userID string) interface{} {
+func (uc *TransactionUseCase) prepareTransactionResponse(transaction *entity.Transaction, userID string, approvals []*entity.TransactionApproval) interface{} {
 	type TransactionResponse struct {
 		ID              string                 `json:"id"`
 		ProductID       string                 `json:"product_id"`
@@ -774,7 +965,19 @@ func (uc *TransactionUseCase) prepareTransactionResponse(transaction *entity.Tra
 		Notes           string                 `json:"notes,omitempty"`
 		MiddlemanChatID string                 `json:"middleman_chat_id,omitempty"` // New: Middleman Chat ID
 
-		Credentials map[string]interface{} `json:"credentials,omitempty"`
+		EscrowStatus       string                        `json:"escrow_status,omitempty"`
+		RequiredApprovals  []string                      `json:"required_approvals,omitempty"`
+		CompletedApprovals []string                      `json:"completed_approvals,omitempty"`
+		Approvals          []*entity.TransactionApproval `json:"approvals,omitempty"` // Only included on detail view
+
+		// Scores and reasons stay admin-only; parties only see the review state
+		FraudReviewStatus string `json:"fraud_review_status,omitempty"`
+
+		// Credentials are only decrypted through the escrow reveal endpoint
+		CredentialsAvailable  bool       `json:"credentials_available"`
+		CredentialsViewCount  int        `json:"credentials_view_count"`
+		CredentialsAccessedAt *time.Time `json:"credentials_accessed_at,omitempty"`
+		CredentialsRedacted   bool       `json:"credentials_redacted"`
 	}
 
 	response := TransactionResponse{
@@ -794,11 +997,22 @@ func (uc *TransactionUseCase) prepareTransactionResponse(transaction *entity.Tra
 		MiddlemanStatus: transaction.MiddlemanStatus,
 		Notes:           transaction.Notes,
 		MiddlemanChatID: transaction.MiddlemanChatID, // Assign new field
+
+		EscrowStatus:       transaction.EscrowStatus,
+		RequiredApprovals:  transaction.RequiredApprovals,
+		CompletedApprovals: transaction.CompletedApprovals,
+		Approvals:          approvals,
+
+		FraudReviewStatus: transaction.FraudReviewStatus,
+
+		CredentialsViewCount:  transaction.CredentialsViewCount,
+		CredentialsAccessedAt: transaction.CredentialsAccessedAt,
+		CredentialsRedacted:   transaction.CredentialsRedacted,
 	}
 
 	if (transaction.SellerID == userID) ||
 		(transaction.BuyerID == userID && transaction.PaymentStatus == "paid") { // Credentials visible to buyer only after payment is "paid" (initiated)
-		response.Credentials = transaction.Credentials
+		response.CredentialsAvailable = transaction.EncryptedCredentials != nil || len(transaction.Credentials) > 0
 	}
 
 	return response
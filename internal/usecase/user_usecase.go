@@ -92,6 +92,12 @@ func (uc *UserUseCase) GetPublicSellerProfile(ctx context.Context, sellerID stri
 	// Add member since information
 	profile["member_since"] = user.CreatedAt.Format("January 2006")
 
+	tier := EffectiveTrustTier(user, time.Now())
+	profile["trust_tier"] = tier
+	if badge := TrustPolicyFor(tier).Badge; badge != "" {
+		profile["badge"] = badge
+	}
+
 	return profile, nil
 }
 
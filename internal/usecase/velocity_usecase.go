@@ -0,0 +1,495 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/pkg/errors"
+	"pasargamex/pkg/utils"
+)
+
+// Business actions velocity rules can limit
+const (
+	VelocityActionCheckout         = "checkout"
+	VelocityActionWithdrawal       = "withdrawal"
+	VelocityActionTopup            = "topup"
+	VelocityActionAddPaymentMethod = "add_payment_method"
+)
+
+var VelocityActions = map[string]bool{
+	VelocityActionCheckout:         true,
+	VelocityActionWithdrawal:       true,
+	VelocityActionTopup:            true,
+	VelocityActionAddPaymentMethod: true,
+}
+
+// DefaultVelocityRules seed an empty rule collection once, keyed by ID
+var DefaultVelocityRules = []entity.VelocityRule{
+	{ID: "checkout_user_count_1h", Name: "Checkouts per hour", Action: VelocityActionCheckout, Scope: "user", Metric: "count", Limit: 10, WindowHours: 1, Enabled: true},
+	{ID: "checkout_device_count_1h", Name: "Checkouts per hour per device", Action: VelocityActionCheckout, Scope: "device", Metric: "count", Limit: 20, WindowHours: 1, Enabled: true},
+	{ID: "checkout_user_amount_24h", Name: "Purchase amount per day", Action: VelocityActionCheckout, Scope: "user", Metric: "amount", Limit: 20000000, WindowHours: 24, Enabled: true},
+	{ID: "withdrawal_user_count_24h", Name: "Withdrawals per day", Action: VelocityActionWithdrawal, Scope: "user", Metric: "count", Limit: 3, WindowHours: 24, Enabled: true},
+	{ID: "topup_user_count_24h", Name: "Top-ups per day", Action: VelocityActionTopup, Scope: "user", Metric: "count", Limit: 10, WindowHours: 24, Enabled: true},
+	{ID: "add_payment_method_user_count_168h", Name: "New payment methods per week", Action: VelocityActionAddPaymentMethod, Scope: "user", Metric: "count", Limit: 3, WindowHours: 168, Enabled: true},
+	{ID: "add_payment_method_device_count_168h", Name: "New payment methods per week per device", Action: VelocityActionAddPaymentMethod, Scope: "device", Metric: "count", Limit: 5, WindowHours: 168, Enabled: true},
+}
+
+const velocityRuleCacheTTL = time.Minute
+
+type VelocityUseCase struct {
+	velocityRepo repository.VelocityRepository
+
+	mu       sync.Mutex
+	cached   []*entity.VelocityRule
+	cachedAt time.Time
+}
+
+func NewVelocityUseCase(velocityRepo repository.VelocityRepository) *VelocityUseCase {
+	return &VelocityUseCase{
+		velocityRepo: velocityRepo,
+	}
+}
+
+type VelocityRuleInput struct {
+	Name        string
+	Description string
+	Action      string
+	Scope       string
+	Metric      string
+	Limit       float64
+	WindowHours int
+	Enabled     bool
+}
+
+// VelocityViolation is returned in the error details when a rule is hit
+type VelocityViolation struct {
+	RuleID            string    `json:"rule_id"`
+	RuleName          string    `json:"rule_name"`
+	Action            string    `json:"action"`
+	Scope             string    `json:"scope"`
+	Metric            string    `json:"metric"`
+	Limit             float64   `json:"limit"`
+	Used              float64   `json:"used"`
+	Requested         float64   `json:"requested"`
+	WindowHours       int       `json:"window_hours"`
+	RetryAfterSeconds int       `json:"retry_after_seconds,omitempty"`
+	RetryAt           time.Time `json:"retry_at,omitempty"`
+}
+
+// EvaluateVelocityRule checks whether one more action of the given amount
+// fits the rule. events must be the subject's events for the rule's action,
+// oldest first. It returns nil when the action is allowed.
+func EvaluateVelocityRule(rule *entity.VelocityRule, events []*entity.VelocityEvent, amount float64, now time.Time) *VelocityViolation {
+	window := time.Duration(rule.WindowHours) * time.Hour
+	start := now.Add(-window)
+
+	inWindow := []*entity.VelocityEvent{}
+	for _, event := range events {
+		if event.CreatedAt.After(start) {
+			inWindow = append(inWindow, event)
+		}
+	}
+
+	requested := 1.0
+	if rule.Metric == "amount" {
+		requested = amount
+	}
+
+	used := 0.0
+	for _, event := range inWindow {
+		used += velocityEventValue(rule, event)
+	}
+
+	if used+requested <= rule.Limit {
+		return nil
+	}
+
+	violation := &VelocityViolation{
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		Action:      rule.Action,
+		Scope:       rule.Scope,
+		Metric:      rule.Metric,
+		Limit:       rule.Limit,
+		Used:        used,
+		Requested:   requested,
+		WindowHours: rule.WindowHours,
+	}
+
+	// A single request above the limit can never succeed, so there is no retry time
+	if requested > rule.Limit {
+		return violation
+	}
+
+	// Retry once enough of the oldest events have left the window
+	remaining := used
+	for _, event := range inWindow {
+		remaining -= velocityEventValue(rule, event)
+		if remaining+requested <= rule.Limit {
+			violation.RetryAt = event.CreatedAt.Add(window)
+			violation.RetryAfterSeconds = int(math.Ceil(violation.RetryAt.Sub(now).Seconds()))
+			break
+		}
+	}
+
+	return violation
+}
+
+func velocityEventValue(rule *entity.VelocityRule, event *entity.VelocityEvent) float64 {
+	if rule.Metric == "amount" {
+		return event.Amount
+	}
+	return 1
+}
+
+// Reserve checks the enabled rules for the action against the user and the
+// device on the request context and, if the action fits, counts it under
+// reference in the same transaction, so concurrent requests cannot both use
+// the last slot. Rules or history that cannot be read reject the action.
+func (uc *VelocityUseCase) Reserve(ctx context.Context, action, userID string, amount float64, reference string) error {
+	rules, counters, ruleCounters, err := uc.countersFor(ctx, action, userID)
+	if err != nil {
+		log.Printf("Failed to load velocity rules, rejecting %s for %s: %v", action, userID, err)
+		return err
+	}
+
+	now := time.Now()
+	event := &entity.VelocityEvent{
+		ID:        velocityEventID(action, reference),
+		Action:    action,
+		UserID:    userID,
+		DeviceID:  utils.ClientInfoFromContext(ctx).DeviceID,
+		Amount:    amount,
+		Reference: reference,
+		CreatedAt: now,
+	}
+
+	var worst *VelocityViolation
+	err = uc.velocityRepo.Reserve(ctx, event, counters, func(counters []*entity.VelocityCounter) error {
+		worst = nil
+		for i, rule := range rules {
+			violation := EvaluateVelocityRule(rule, counters[ruleCounters[i]].Events, amount, now)
+			if violation == nil {
+				continue
+			}
+
+			// Report the rule that blocks the longest; one that never clears wins
+			if worst == nil || violation.RetryAt.IsZero() ||
+				(!worst.RetryAt.IsZero() && violation.RetryAt.After(worst.RetryAt)) {
+				worst = violation
+			}
+		}
+
+		if worst == nil {
+			return nil
+		}
+
+		if worst.RetryAt.IsZero() {
+			return errors.BadRequest(fmt.Sprintf("This request exceeds the limit of %s", worst.RuleName), nil).WithDetails(worst)
+		}
+
+		retryAfter := worst.RetryAt.Sub(now)
+		return errors.TooManyRequests(fmt.Sprintf("Limit reached: %s. Try again after %s", worst.RuleName, worst.RetryAt.Format(time.RFC3339)), retryAfter).
+			WithDetails(worst)
+	})
+
+	if err != nil && worst != nil {
+		log.Printf("SECURITY: Velocity rule %q blocked %s for user %s (used %.0f of %.0f)", worst.RuleName, action, userID, worst.Used, worst.Limit)
+	} else if err != nil {
+		log.Printf("Failed to reserve %s velocity for user %s: %v", action, userID, err)
+	}
+
+	return err
+}
+
+// Release gives back a reservation whose action did not go through.
+// Failures are logged; the event then ages out of the window.
+func (uc *VelocityUseCase) Release(ctx context.Context, action, userID, reference string) {
+	_, counters, _, err := uc.countersFor(ctx, action, userID)
+	if err == nil {
+		err = uc.velocityRepo.Release(ctx, velocityEventID(action, reference), counters)
+	}
+	if err != nil {
+		log.Printf("Failed to release %s velocity reservation %s for user %s: %v", action, reference, userID, err)
+	}
+}
+
+// countersFor returns the enabled rules for the action, the counters they
+// read and, for each rule, the index of its counter. Rules sharing a scope
+// and window share a counter.
+func (uc *VelocityUseCase) countersFor(ctx context.Context, action, userID string) ([]*entity.VelocityRule, []*entity.VelocityCounter, []int, error) {
+	all, err := uc.ActiveRules(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	deviceID := utils.ClientInfoFromContext(ctx).DeviceID
+
+	rules := []*entity.VelocityRule{}
+	counters := []*entity.VelocityCounter{}
+	ruleCounters := []int{}
+	index := map[string]int{}
+
+	for _, rule := range all {
+		if !rule.Enabled || rule.Action != action {
+			continue
+		}
+
+		subjectID := userID
+		if rule.Scope == "device" {
+			if deviceID == "" {
+				continue
+			}
+			subjectID = deviceID
+		}
+
+		key := fmt.Sprintf("%s|%s|%d", rule.Scope, subjectID, rule.WindowHours)
+		i, ok := index[key]
+		if !ok {
+			i = len(counters)
+			index[key] = i
+			counters = append(counters, &entity.VelocityCounter{
+				Scope:       rule.Scope,
+				SubjectID:   subjectID,
+				Action:      action,
+				WindowHours: rule.WindowHours,
+			})
+		}
+
+		rules = append(rules, rule)
+		ruleCounters = append(ruleCounters, i)
+	}
+
+	return rules, counters, ruleCounters, nil
+}
+
+func velocityEventID(action, reference string) string {
+	return action + "_" + reference
+}
+
+// SeedDefaultRules stores DefaultVelocityRules once, when the rule collection
+// has never been seeded. Called at startup, never on the read path.
+func (uc *VelocityUseCase) SeedDefaultRules(ctx context.Context) error {
+	rules := make([]*entity.VelocityRule, 0, len(DefaultVelocityRules))
+	for i := range DefaultVelocityRules {
+		rule := DefaultVelocityRules[i]
+		rule.UpdatedBy = "system"
+		rules = append(rules, &rule)
+	}
+
+	seeded, err := uc.velocityRepo.SeedDefaults(ctx, rules)
+	if err != nil {
+		return err
+	}
+
+	if seeded {
+		uc.invalidateCache()
+		log.Printf("Seeded %d default velocity rules", len(rules))
+	}
+
+	return nil
+}
+
+// ActiveRules returns the stored rules. Rules are cached briefly so limited
+// actions do not read Firestore every time.
+func (uc *VelocityUseCase) ActiveRules(ctx context.Context) ([]*entity.VelocityRule, error) {
+	uc.mu.Lock()
+	if uc.cached != nil && time.Since(uc.cachedAt) < velocityRuleCacheTTL {
+		rules := uc.cached
+		uc.mu.Unlock()
+		return rules, nil
+	}
+	uc.mu.Unlock()
+
+	rules, err := uc.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	uc.cached = rules
+	uc.cachedAt = time.Now()
+	uc.mu.Unlock()
+
+	return rules, nil
+}
+
+func (uc *VelocityUseCase) ListRules(ctx context.Context) ([]*entity.VelocityRule, error) {
+	return uc.velocityRepo.ListRules(ctx)
+}
+
+func (uc *VelocityUseCase) CreateRule(ctx context.Context, adminID string, input VelocityRuleInput) (*entity.VelocityRule, error) {
+	rule := &entity.VelocityRule{}
+	applyVelocityRuleInput(rule, input)
+	rule.UpdatedBy = adminID
+
+	if err := ValidateVelocityRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := uc.velocityRepo.CreateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateCache()
+	log.Printf("Velocity rule %s (%s) created by %s", rule.ID, rule.Name, adminID)
+	return rule, nil
+}
+
+func (uc *VelocityUseCase) UpdateRule(ctx context.Context, adminID, id string, input VelocityRuleInput) (*entity.VelocityRule, error) {
+	rule, err := uc.velocityRepo.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyVelocityRuleInput(rule, input)
+	rule.UpdatedBy = adminID
+
+	if err := ValidateVelocityRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := uc.velocityRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	uc.invalidateCache()
+	log.Printf("Velocity rule %s (%s) updated by %s", rule.ID, rule.Name, adminID)
+	return rule, nil
+}
+
+func (uc *VelocityUseCase) DeleteRule(ctx context.Context, adminID, id string) error {
+	if _, err := uc.velocityRepo.GetRule(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.velocityRepo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+
+	uc.invalidateCache()
+	log.Printf("Velocity rule %s deleted by %s", id, adminID)
+	return nil
+}
+
+func (uc *VelocityUseCase) invalidateCache() {
+	uc.mu.Lock()
+	uc.cached = nil
+	uc.mu.Unlock()
+}
+
+// VelocityUsage is a user's standing against one rule
+type VelocityUsage struct {
+	Rule      *entity.VelocityRule `json:"rule"`
+	SubjectID string               `json:"subject_id"` // user ID or device ID
+	Used      float64              `json:"used"`
+	Remaining float64              `json:"remaining"`
+	Blocked   bool                 `json:"blocked"`
+}
+
+// GetUserUsage reports how much of each enabled rule a user has used. Device
+// rules are reported for every device the user acted from in the window.
+func (uc *VelocityUseCase) GetUserUsage(ctx context.Context, userID string) ([]*VelocityUsage, error) {
+	rules, err := uc.ActiveRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	usage := []*VelocityUsage{}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+
+		since := now.Add(-time.Duration(rule.WindowHours) * time.Hour)
+		userEvents, err := uc.velocityRepo.ListEvents(ctx, "user", userID, rule.Action, since)
+		if err != nil {
+			return nil, err
+		}
+
+		subjects := map[string][]*entity.VelocityEvent{}
+		if rule.Scope == "device" {
+			for _, event := range userEvents {
+				if event.DeviceID == "" || subjects[event.DeviceID] != nil {
+					continue
+				}
+				deviceEvents, err := uc.velocityRepo.ListEvents(ctx, "device", event.DeviceID, rule.Action, since)
+				if err != nil {
+					return nil, err
+				}
+				subjects[event.DeviceID] = deviceEvents
+			}
+		} else {
+			subjects[userID] = userEvents
+		}
+
+		for subjectID, events := range subjects {
+			used := 0.0
+			for _, event := range events {
+				used += velocityEventValue(rule, event)
+			}
+
+			usage = append(usage, &VelocityUsage{
+				Rule:      rule,
+				SubjectID: subjectID,
+				Used:      used,
+				Remaining: math.Max(rule.Limit-used, 0),
+				Blocked:   used >= rule.Limit,
+			})
+		}
+	}
+
+	return usage, nil
+}
+
+// ValidateVelocityRule checks the action, scope, metric and bounds
+func ValidateVelocityRule(rule *entity.VelocityRule) error {
+	if rule.Name == "" {
+		return errors.BadRequest("Rule name is required", nil)
+	}
+
+	if !VelocityActions[rule.Action] {
+		return errors.BadRequest("Unknown velocity action: "+rule.Action, nil)
+	}
+
+	if rule.Scope != "user" && rule.Scope != "device" {
+		return errors.BadRequest("Scope must be user or device", nil)
+	}
+
+	if rule.Metric != "count" && rule.Metric != "amount" {
+		return errors.BadRequest("Metric must be count or amount", nil)
+	}
+
+	if rule.Metric == "amount" && rule.Action == VelocityActionAddPaymentMethod {
+		return errors.BadRequest("Payment method rules can only limit the count", nil)
+	}
+
+	if rule.Limit < 1 {
+		return errors.BadRequest("Limit must be at least 1", nil)
+	}
+
+	if rule.WindowHours < 1 || rule.WindowHours > 720 {
+		return errors.BadRequest("Window must be between 1 and 720 hours", nil)
+	}
+
+	return nil
+}
+
+func applyVelocityRuleInput(rule *entity.VelocityRule, input VelocityRuleInput) {
+	rule.Name = input.Name
+	rule.Description = input.Description
+	rule.Action = input.Action
+	rule.Scope = input.Scope
+	rule.Metric = input.Metric
+	rule.Limit = input.Limit
+	rule.WindowHours = input.WindowHours
+	rule.Enabled = input.Enabled
+}
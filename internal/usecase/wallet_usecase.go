@@ -14,12 +14,14 @@ import (
 )
 
 type WalletUseCase struct {
-	walletRepo          repository.WalletRepository
-	walletTxnRepo       repository.WalletTransactionRepository
-	paymentMethodRepo   repository.PaymentMethodRepository
-	topupRepo           repository.TopupRepository
-	withdrawRepo        repository.WithdrawRepository
-	userRepo            repository.UserRepository
+	walletRepo         repository.WalletRepository
+	walletTxnRepo      repository.WalletTransactionRepository
+	paymentMethodRepo  repository.PaymentMethodRepository
+	topupRepo          repository.TopupRepository
+	withdrawRepo       repository.WithdrawRepository
+	userRepo           repository.UserRepository
+	securityLogUseCase *SecurityLogUseCase
+	velocityUseCase    *VelocityUseCase
 }
 
 func NewWalletUseCase(
@@ -29,14 +31,18 @@ func NewWalletUseCase(
 	topupRepo repository.TopupRepository,
 	withdrawRepo repository.WithdrawRepository,
 	userRepo repository.UserRepository,
+	securityLogUseCase *SecurityLogUseCase,
+	velocityUseCase *VelocityUseCase,
 ) *WalletUseCase {
 	return &WalletUseCase{
-		walletRepo:        walletRepo,
-		walletTxnRepo:     walletTxnRepo,
-		paymentMethodRepo: paymentMethodRepo,
-		topupRepo:         topupRepo,
-		withdrawRepo:      withdrawRepo,
-		userRepo:          userRepo,
+		walletRepo:         walletRepo,
+		walletTxnRepo:      walletTxnRepo,
+		paymentMethodRepo:  paymentMethodRepo,
+		topupRepo:          topupRepo,
+		withdrawRepo:       withdrawRepo,
+		userRepo:           userRepo,
+		securityLogUseCase: securityLogUseCase,
+		velocityUseCase:    velocityUseCase,
 	}
 }
 
@@ -127,6 +133,9 @@ func (uc *WalletUseCase) GetWalletTransactions(ctx context.Context, userID strin
 
 	transactions, err := uc.walletTxnRepo.GetTransactionsByUserID(ctx, userID, pagination)
 	if err != nil {
+		if errors.Is(err, "BAD_REQUEST") {
+			return nil, err
+		}
 		return nil, errors.InternalServer("Failed to get wallet transactions", err)
 	}
 
@@ -141,16 +150,22 @@ func (uc *WalletUseCase) CreatePaymentMethod(ctx context.Context, userID string,
 		return nil, errors.NotFound("User", err)
 	}
 
+	paymentMethodID := uuid.New().String()
+	if err := uc.velocityUseCase.Reserve(ctx, VelocityActionAddPaymentMethod, userID, 0, paymentMethodID); err != nil {
+		return nil, err
+	}
+
 	// If this is set as default, unset other defaults
 	if input.IsDefault {
 		err = uc.paymentMethodRepo.SetDefaultPaymentMethod(ctx, userID, "")
 		if err != nil {
+			uc.velocityUseCase.Release(ctx, VelocityActionAddPaymentMethod, userID, paymentMethodID)
 			return nil, errors.InternalServer("Failed to update default payment method", err)
 		}
 	}
 
 	paymentMethod := &entity.PaymentMethod{
-		ID:            uuid.New().String(),
+		ID:            paymentMethodID,
 		UserID:        userID,
 		Type:          input.Type,
 		Provider:      input.Provider,
@@ -165,6 +180,7 @@ func (uc *WalletUseCase) CreatePaymentMethod(ctx context.Context, userID string,
 
 	err = uc.paymentMethodRepo.CreatePaymentMethod(ctx, paymentMethod)
 	if err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionAddPaymentMethod, userID, paymentMethod.ID)
 		return nil, errors.InternalServer("Failed to create payment method", err)
 	}
 
@@ -258,8 +274,13 @@ func (uc *WalletUseCase) CreateTopupRequest(ctx context.Context, userID string,
 		return nil, errors.Forbidden("Access denied", nil)
 	}
 
+	topupID := uuid.New().String()
+	if err := uc.velocityUseCase.Reserve(ctx, VelocityActionTopup, userID, input.Amount, topupID); err != nil {
+		return nil, err
+	}
+
 	topupRequest := &entity.TopupRequest{
-		ID:              uuid.New().String(),
+		ID:              topupID,
 		UserID:          userID,
 		WalletID:        wallet.ID,
 		Amount:          input.Amount,
@@ -272,6 +293,7 @@ func (uc *WalletUseCase) CreateTopupRequest(ctx context.Context, userID string,
 
 	err = uc.topupRepo.CreateTopupRequest(ctx, topupRequest)
 	if err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionTopup, userID, topupRequest.ID)
 		return nil, errors.InternalServer("Failed to create topup request", err)
 	}
 
@@ -379,12 +401,19 @@ func (uc *WalletUseCase) CreateWithdrawRequest(ctx context.Context, userID strin
 		return nil, errors.Forbidden("Access denied", nil)
 	}
 
+	// Reserved before the request exists; a failed reservation, including one
+	// whose limits cannot be read, rejects the withdrawal
+	withdrawID := uuid.New().String()
+	if err := uc.velocityUseCase.Reserve(ctx, VelocityActionWithdrawal, userID, input.Amount, withdrawID); err != nil {
+		return nil, err
+	}
+
 	// Calculate fee (example: 1% fee)
 	fee := input.Amount * 0.01
 	netAmount := input.Amount - fee
 
 	withdrawRequest := &entity.WithdrawRequest{
-		ID:              uuid.New().String(),
+		ID:              withdrawID,
 		UserID:          userID,
 		WalletID:        wallet.ID,
 		Amount:          input.Amount,
@@ -398,9 +427,20 @@ func (uc *WalletUseCase) CreateWithdrawRequest(ctx context.Context, userID strin
 
 	err = uc.withdrawRepo.CreateWithdrawRequest(ctx, withdrawRequest)
 	if err != nil {
+		uc.velocityUseCase.Release(ctx, VelocityActionWithdrawal, userID, withdrawRequest.ID)
 		return nil, errors.InternalServer("Failed to create withdraw request", err)
 	}
 
+	uc.securityLogUseCase.RecordEvent(ctx, SecurityEventInput{
+		UserID:    userID,
+		EventType: SecurityEventWithdrawal,
+		Details: map[string]interface{}{
+			"withdraw_id":       withdrawRequest.ID,
+			"amount":            withdrawRequest.Amount,
+			"payment_method_id": withdrawRequest.PaymentMethodID,
+		},
+	})
+
 	return withdrawRequest, nil
 }
 
@@ -29,6 +29,8 @@ type WishlistResponse struct {
 	ProductID string                 `json:"product_id"`
 	Product   *WishlistProductInfo   `json:"product"`
 	CreatedAt string                 `json:"created_at"`
+	PriceAtAdd  float64              `json:"price_at_add"`
+	TargetPrice float64              `json:"target_price,omitempty"`
 }
 
 type WishlistProductInfo struct {
@@ -36,6 +38,7 @@ type WishlistProductInfo struct {
 	Title          string                 `json:"title"`
 	Description    string                 `json:"description"`
 	Price          float64                `json:"price"`
+	PriceMax       float64                `json:"price_max,omitempty"` // set when the price is a "from" price across variants
 	Type           string                 `json:"type"`
 	Images         []entity.ProductImage  `json:"images"`
 	Status         string                 `json:"status"`
@@ -73,6 +76,7 @@ func (u *WishlistUseCase) AddToWishlist(ctx context.Context, userID, productID s
 			Title:          product.Title,
 			Description:    product.Description,
 			Price:          product.Price,
+			PriceMax:       product.PriceMax,
 			Type:           product.Type,
 			Images:         product.Images,
 			Status:         product.Status,
@@ -82,7 +86,8 @@ func (u *WishlistUseCase) AddToWishlist(ctx context.Context, userID, productID s
 			Views:          product.Views,
 			SoldCount:      product.SoldCount,
 		},
-		CreatedAt: item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:  item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		PriceAtAdd: item.PriceAtAdd,
 	}, nil
 }
 
@@ -113,6 +118,7 @@ func (u *WishlistUseCase) GetUserWishlist(ctx context.Context, userID string, pa
 				Title:          item.Product.Title,
 				Description:    item.Product.Description,
 				Price:          item.Product.Price,
+				PriceMax:       item.Product.PriceMax,
 				Type:           item.Product.Type,
 				Images:         item.Product.Images,
 				Status:         item.Product.Status,
@@ -122,13 +128,49 @@ func (u *WishlistUseCase) GetUserWishlist(ctx context.Context, userID string, pa
 				Views:          item.Product.Views,
 				SoldCount:      item.Product.SoldCount,
 			},
-			CreatedAt: item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			CreatedAt:   item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			PriceAtAdd:  item.PriceAtAdd,
+			TargetPrice: item.TargetPrice,
 		})
 	}
 	
 	return response, total, nil
 }
 
+// SetTargetPrice sets the price at or below which the user is alerted, on top
+// of the alert for dropping below the price they added the product at. A zero
+// target clears it.
+func (u *WishlistUseCase) SetTargetPrice(ctx context.Context, userID, productID string, targetPrice float64) (*entity.WishlistItem, error) {
+	if targetPrice < 0 {
+		return nil, errors.BadRequest("Target price cannot be negative", nil)
+	}
+
+	item, err := u.wishlistRepo.GetWishlistItem(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetPrice > 0 {
+		product, err := u.productRepo.GetByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		if targetPrice >= product.Price {
+			return nil, errors.BadRequest("Target price must be below the current price", nil)
+		}
+	}
+
+	item.TargetPrice = targetPrice
+	item.LastAlertedPrice = 0 // A new target re-arms the alert
+
+	if err := u.wishlistRepo.UpdateWishlistItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Set target price %.0f on product %s for user %s", targetPrice, productID, userID)
+	return item, nil
+}
+
 func (u *WishlistUseCase) IsInWishlist(ctx context.Context, userID, productID string) (bool, error) {
 	return u.wishlistRepo.IsInWishlist(ctx, userID, productID)
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +21,29 @@ type Config struct {
 	MidtransServerKey   string
 	MidtransClientKey   string
 	MidtransEnvironment string // sandbox or production
+
+	// Credential encryption: "kms" wraps data keys with the Cloud KMS key
+	// CredentialKMSKey, "local" uses the key file (development and tests).
+	// Defaults to local in development and kms everywhere else.
+	CredentialKeyProvider string
+	CredentialKMSKey      string
+	CredentialKeyFile     string
+
+	// Listings of sellers below this trust tier wait for admin review before
+	// going live; empty publishes every listing immediately
+	ListingReviewMinTier string
+
+	// Active listings expire after this many days without seller activity,
+	// with a renewal reminder sent the given number of days before; zero
+	// keeps listings active forever
+	ListingExpiryDays         int
+	ListingExpiryReminderDays int
+
+	// Background jobs run on in-process tickers ("in_process") or only when
+	// an external scheduler calls the job trigger endpoint ("external") with
+	// JobTriggerToken; an empty token disables the endpoint
+	JobScheduler    string
+	JobTriggerToken string
 }
 
 func Load() (*Config, error) {
@@ -39,6 +63,25 @@ func Load() (*Config, error) {
 		MidtransServerKey:   getEnv("MIDTRANS_SERVER_KEY", "SB-Mid-server-your-sandbox-server-key"),
 		MidtransClientKey:   getEnv("MIDTRANS_CLIENT_KEY", "SB-Mid-client-your-sandbox-client-key"),
 		MidtransEnvironment: getEnv("MIDTRANS_ENVIRONMENT", "sandbox"),
+
+		CredentialKeyProvider: getEnv("CREDENTIAL_KEY_PROVIDER", ""),
+		CredentialKMSKey:      getEnv("CREDENTIAL_KMS_KEY", ""),
+		CredentialKeyFile:     getEnv("CREDENTIAL_KEY_FILE", "keys/credential_keys.json"),
+
+		ListingReviewMinTier: getEnv("LISTING_REVIEW_MIN_TIER", ""),
+
+		ListingExpiryDays:         getEnvInt("LISTING_EXPIRY_DAYS", 30),
+		ListingExpiryReminderDays: getEnvInt("LISTING_EXPIRY_REMINDER_DAYS", 3),
+
+		JobScheduler:    getEnv("JOB_SCHEDULER", "in_process"),
+		JobTriggerToken: getEnv("JOB_TRIGGER_TOKEN", ""),
+	}
+
+	if config.CredentialKeyProvider == "" {
+		config.CredentialKeyProvider = "kms"
+		if config.Environment == "development" {
+			config.CredentialKeyProvider = "local"
+		}
 	}
 
 	return config, nil
@@ -50,3 +93,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+	return defaultValue
+}
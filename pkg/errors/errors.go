@@ -3,14 +3,18 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 )
 
 type AppError struct {
-	Code    string
-	Message string
-	Status  int
-	Err     error
+	Code       string
+	Message    string
+	Status     int
+	Err        error
+	Details    interface{}   // optional structured data returned to the client
+	RetryAfter time.Duration // sent as the Retry-After header when set
 }
 
 func (e *AppError) Error() string {
@@ -21,6 +25,12 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// WithDetails attaches structured data to the error response
+func (e *AppError) WithDetails(details interface{}) *AppError {
+	e.Details = details
+	return e
+}
+
 func New(code string, message string, status int, err error) *AppError {
 	return &AppError{
 		Code:    code,
@@ -102,10 +112,19 @@ func InternalServer(message string, err error) *AppError {
 }
 
 func TooManyRequests(message string, waitTime interface{}) *AppError {
-	return &AppError{
+	appErr := &AppError{
 		Code:    "TOO_MANY_REQUESTS",
 		Message: message,
 		Status:  http.StatusTooManyRequests,
 		Err:     nil,
 	}
+
+	if wait, ok := waitTime.(time.Duration); ok && wait > 0 {
+		appErr.RetryAfter = wait
+		appErr.Details = map[string]interface{}{
+			"retry_after_seconds": int(math.Ceil(wait.Seconds())),
+		}
+	}
+
+	return appErr
 }
@@ -2,7 +2,9 @@ package response
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +33,8 @@ type PaginatedResponse struct {
 	Page       int         `json:"page"`
 	PageSize   int         `json:"pageSize"`
 	TotalPages int         `json:"totalPages"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Facets     interface{} `json:"facets,omitempty"`
 }
 
 type MetaInfo struct {
@@ -60,7 +64,35 @@ func Created(c echo.Context, data interface{}) error {
 	})
 }
 
-func Paginated(c echo.Context, items interface{}, total int64, page, pageSize int) error {
+// Paginated writes a page of items. Cursor-paginated listings pass the
+// token for the following page, which is omitted on the last page.
+func Paginated(c echo.Context, items interface{}, total int64, page, pageSize int, nextCursor ...string) error {
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	cursor := ""
+	if len(nextCursor) > 0 {
+		cursor = nextCursor[0]
+	}
+
+	return c.JSON(http.StatusOK, Response{
+		Success:   true,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Data: PaginatedResponse{
+			Items:      items,
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: totalPages,
+			NextCursor: cursor,
+		},
+	})
+}
+
+// PaginatedWithFacets adds filter facet counts alongside the page
+func PaginatedWithFacets(c echo.Context, items interface{}, total int64, page, pageSize int, facets interface{}) error {
 	totalPages := int(total) / pageSize
 	if int(total)%pageSize > 0 {
 		totalPages++
@@ -75,6 +107,7 @@ func Paginated(c echo.Context, items interface{}, total int64, page, pageSize in
 			Page:       page,
 			PageSize:   pageSize,
 			TotalPages: totalPages,
+			Facets:     facets,
 		},
 	})
 }
@@ -116,12 +149,17 @@ func Error(c echo.Context, err error) error {
 	// Handle application errors
 	var appErr *apperrors.AppError
 	if errors.As(err, &appErr) {
+		if appErr.RetryAfter > 0 {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
+
 		return c.JSON(appErr.Status, Response{
 			Success:   false,
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 			Error: &ErrorInfo{
 				Code:    appErr.Code,
 				Message: appErr.Message,
+				Details: appErr.Details,
 			},
 		})
 	}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"context"
+)
+
+type clientInfoKey struct{}
+
+// ClientInfo describes the client behind a request for security logging
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	DeviceID  string // client-provided X-Device-ID header
+}
+
+// WithClientInfo stores the request's client info on the context
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client info stored by WithClientInfo
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Cursor marks a position in a sorted listing: the sort key of the last item
+// returned and its document ID as a tie-breaker. Clients only see the opaque
+// token produced by Encode.
+type Cursor struct {
+	Key interface{} // time.Time, float64 or string
+	ID  string
+}
+
+type cursorPayload struct {
+	Time   *time.Time `json:"t,omitempty"`
+	Number *float64   `json:"n,omitempty"`
+	String *string    `json:"s,omitempty"`
+	ID     string     `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor builds the opaque token for the item with the given sort key and ID
+func EncodeCursor(key interface{}, id string) string {
+	payload := cursorPayload{ID: id}
+
+	switch k := key.(type) {
+	case time.Time:
+		payload.Time = &k
+	case float64:
+		payload.Number = &k
+	case int:
+		n := float64(k)
+		payload.Number = &n
+	case int64:
+		n := float64(k)
+		payload.Number = &n
+	case string:
+		payload.String = &k
+	}
+
+	data, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token from EncodeCursor
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{ID: payload.ID}
+	switch {
+	case payload.Time != nil:
+		cursor.Key = *payload.Time
+	case payload.Number != nil:
+		cursor.Key = *payload.Number
+	case payload.String != nil:
+		cursor.Key = *payload.String
+	default:
+		return nil, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// PageInfo describes the page a listing returned
+type PageInfo struct {
+	Total      int64  // from an aggregation count, so approximate under concurrent writes
+	NextCursor string // empty on the last page
+}
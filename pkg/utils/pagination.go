@@ -6,11 +6,13 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// PaginationParams represents pagination parameters
+// PaginationParams represents pagination parameters. When Cursor is set it
+// takes precedence over Page/Offset.
 type PaginationParams struct {
 	Page     int
 	PageSize int
 	Offset   int
+	Cursor   string
 }
 
 // DefaultPage is the default page number
@@ -27,7 +29,9 @@ func GetPaginationParams(c echo.Context) PaginationParams {
 	page, _ := strconv.Atoi(c.QueryParam("page"))
 	pageSize, _ := strconv.Atoi(c.QueryParam("limit"))
 
-	return NewPaginationParams(page, pageSize)
+	params := NewPaginationParams(page, pageSize)
+	params.Cursor = c.QueryParam("cursor")
+	return params
 }
 
 // NewPaginationParams creates a new pagination parameter set with validation
@@ -59,8 +63,12 @@ func (p PaginationParams) ToMap() map[string]interface{} {
 	}
 }
 
-// Pagination represents pagination configuration for wallet operations
+// Pagination represents pagination configuration for wallet operations.
+// Repositories fill NextCursor and Total after the query.
 type Pagination struct {
-	Page  int
-	Limit int
+	Page       int
+	Limit      int
+	Cursor     string
+	NextCursor string
+	Total      int64
 }
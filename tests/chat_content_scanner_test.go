@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/service"
+	"pasargamex/internal/infrastructure/moderation"
+)
+
+func TestPatternScannerDetectsOffPlatformSolicitation(t *testing.T) {
+	scanner := moderation.NewPatternScanner(nil)
+	ctx := context.Background()
+
+	cases := []struct {
+		content  string
+		action   string
+		category string
+	}{
+		{"chat aku aja di 0812-3456-7890", service.ContentActionWarn, moderation.CategoryPhoneNumber},
+		{"wa +62 812 3456 7890 ya kak", service.ContentActionWarn, moderation.CategoryPhoneNumber},
+		{"transfer ke BCA 1234567890 a.n budi", service.ContentActionBlock, moderation.CategoryBankAccount},
+		{"no rek 123 456 7890 mandiri", service.ContentActionBlock, moderation.CategoryBankAccount},
+		{"rekening saya: 1234567890", service.ContentActionBlock, moderation.CategoryBankAccount},
+		{"kirim ke 1234567890 (bca) ya", service.ContentActionBlock, moderation.CategoryBankAccount},
+		{"langsung aja https://wa.me/6281234567890", service.ContentActionBlock, moderation.CategoryMessagingLink},
+		{"join t.me/jualakunmurah", service.ContentActionBlock, moderation.CategoryMessagingLink},
+	}
+
+	for _, tc := range cases {
+		result := scanner.Scan(ctx, tc.content)
+		assert.Equal(t, tc.action, result.Action, tc.content)
+		assert.Contains(t, result.Categories(), tc.category, tc.content)
+	}
+
+	// Ordinary trade talk passes untouched
+	for _, content := range []string{
+		"harga 150000 bisa nego?",
+		"akun level 80, rank 1200, 35 skin",
+		"id game 1234567890123 sudah aku kirim",
+		// Amounts next to payment words are not account numbers
+		"harga 15000000, transfer lewat escrow",
+		"transfer Rp 15000000 ya kak",
+		"budget 20 000 000, bisa tf bca",
+		"sudah tf 25000000 rupiah",
+		// Payment words elsewhere in the message do not taint other numbers
+		"id game 1234567890123, bayar via bca",
+	} {
+		result := scanner.Scan(ctx, content)
+		assert.Equal(t, service.ContentActionAllow, result.Action, content)
+		assert.Empty(t, result.Findings, content)
+	}
+}
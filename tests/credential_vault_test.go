@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/infrastructure/crypto"
+)
+
+func TestCredentialVaultRoundTripAcrossKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	keyProvider, err := crypto.NewLocalFileKeyProvider(filepath.Join(t.TempDir(), "keys.json"), true)
+	assert.NoError(t, err)
+
+	vault := crypto.NewEnvelopeVault(keyProvider)
+	credentials := map[string]interface{}{"username": "player1", "password": "secret"}
+
+	sealed, err := vault.Seal(ctx, credentials)
+	assert.NoError(t, err)
+	assert.Equal(t, keyProvider.CurrentKeyID(), sealed.KeyID)
+	assert.NotContains(t, string(sealed.Ciphertext), "secret")
+
+	// Rotating keeps old keys so existing ciphertext still opens
+	_, err = keyProvider.RotateKey()
+	assert.NoError(t, err)
+	assert.True(t, vault.NeedsRotation(sealed))
+
+	opened, err := vault.Open(ctx, sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, credentials, opened)
+}
+
+func TestLocalKeyProviderRefusesMissingKeyFile(t *testing.T) {
+	_, err := crypto.NewLocalFileKeyProvider(filepath.Join(t.TempDir(), "keys.json"), false)
+	assert.Error(t, err)
+}
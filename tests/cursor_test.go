@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/pkg/utils"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	cursor, err := utils.DecodeCursor(utils.EncodeCursor(createdAt, "doc-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "doc-1", cursor.ID)
+	assert.True(t, createdAt.Equal(cursor.Key.(time.Time)))
+
+	cursor, err = utils.DecodeCursor(utils.EncodeCursor(42, "doc-2"))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(42), cursor.Key)
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, token := range []string{"", "not-base64!", "e30"} {
+		_, err := utils.DecodeCursor(token)
+		assert.ErrorIs(t, err, utils.ErrInvalidCursor, token)
+	}
+}
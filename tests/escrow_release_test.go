@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/repository"
+	"pasargamex/internal/usecase"
+	"pasargamex/pkg/errors"
+)
+
+// Stub repositories embed the interface and implement only what the use
+// case under test calls; anything else panics on the nil interface
+
+type approvalsRepo struct {
+	repository.TransactionRepository
+	approvals []*entity.TransactionApproval
+}
+
+func (r *approvalsRepo) GetApprovalsByTransactionID(ctx context.Context, transactionID string) ([]*entity.TransactionApproval, error) {
+	return r.approvals, nil
+}
+
+type checklistRepo struct {
+	repository.HandoverChecklistRepository
+	checklist *entity.HandoverChecklist
+}
+
+func (r *checklistRepo) GetByTransactionID(ctx context.Context, transactionID string) (*entity.HandoverChecklist, error) {
+	if r.checklist == nil {
+		return nil, errors.NotFound("Handover checklist", nil)
+	}
+	return r.checklist, nil
+}
+
+type productRepo struct {
+	repository.ProductRepository
+	product *entity.Product
+}
+
+func (r *productRepo) GetByID(ctx context.Context, id string) (*entity.Product, error) {
+	return r.product, nil
+}
+
+type policyRepo struct {
+	repository.AutoReleasePolicyRepository
+	policies []*entity.AutoReleasePolicy
+}
+
+func (r *policyRepo) List(ctx context.Context, activeOnly bool) ([]*entity.AutoReleasePolicy, error) {
+	policies := []*entity.AutoReleasePolicy{}
+	for _, policy := range r.policies {
+		if !activeOnly || policy.IsActive {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+func approved(approverType string) *entity.TransactionApproval {
+	return &entity.TransactionApproval{ApproverType: approverType, Status: "approved"}
+}
+
+func TestEnsureReleasableRejectsMissingApprovals(t *testing.T) {
+	ctx := context.Background()
+	repo := &approvalsRepo{approvals: []*entity.TransactionApproval{
+		approved("middleman"),
+		{ApproverType: "buyer", Status: "rejected"},
+	}}
+	approvals := usecase.NewApprovalUseCase(repo)
+	transaction := &entity.Transaction{ID: "tx-1", RequiredApprovals: usecase.RequiredApprovalsFor("middleman")}
+
+	err := approvals.EnsureReleasable(ctx, transaction)
+	assert.True(t, errors.Is(err, "BAD_REQUEST"))
+	assert.Contains(t, err.Error(), "seller, buyer")
+	assert.Equal(t, []string{"middleman"}, transaction.CompletedApprovals)
+
+	repo.approvals = append(repo.approvals, approved("seller"), approved("buyer"))
+	assert.NoError(t, approvals.EnsureReleasable(ctx, transaction))
+}
+
+func TestLegacyFlowRequiresOnlyApprovalsItCollects(t *testing.T) {
+	ctx := context.Background()
+	repo := &approvalsRepo{approvals: []*entity.TransactionApproval{approved("middleman"), approved("buyer")}}
+	approvals := usecase.NewApprovalUseCase(repo)
+
+	legacy := &entity.Transaction{ID: "tx-1", RequiredApprovals: usecase.LegacyRequiredApprovalsFor("middleman")}
+	assert.NoError(t, approvals.EnsureReleasable(ctx, legacy))
+
+	// The escrow flow also waits for the seller's delivery approval
+	escrow := &entity.Transaction{ID: "tx-2", RequiredApprovals: usecase.RequiredApprovalsFor("middleman")}
+	assert.True(t, errors.Is(approvals.EnsureReleasable(ctx, escrow), "BAD_REQUEST"))
+
+	assert.Equal(t, []string{"system"}, usecase.LegacyRequiredApprovalsFor("instant"))
+}
+
+func TestEnsureCompleteRejectsIncompleteChecklist(t *testing.T) {
+	ctx := context.Background()
+	checklists := &checklistRepo{checklist: &entity.HandoverChecklist{
+		Status: "in_progress",
+		Steps: []entity.HandoverStep{
+			{Key: "change_email", Required: true, SellerCompleted: true, BuyerConfirmed: true},
+			{Key: "change_password", Required: true, SellerCompleted: true},
+			{Key: "unlink_phone", Required: false},
+		},
+	}}
+	handover := usecase.NewHandoverUseCase(checklists, nil, &productRepo{}, nil, nil)
+	transaction := &entity.Transaction{ID: "tx-1", ProductID: "product-1"}
+
+	err := handover.EnsureComplete(ctx, transaction)
+	assert.True(t, errors.Is(err, "BAD_REQUEST"))
+	assert.Contains(t, err.Error(), "change_password")
+	assert.NotContains(t, err.Error(), "unlink_phone")
+
+	checklists.checklist.Steps[1].BuyerConfirmed = true
+	assert.NoError(t, handover.EnsureComplete(ctx, transaction))
+}
+
+func TestEnsureCompleteRequiresChecklistForAccounts(t *testing.T) {
+	ctx := context.Background()
+	products := &productRepo{product: &entity.Product{ID: "product-1", Type: "account"}}
+	handover := usecase.NewHandoverUseCase(&checklistRepo{}, nil, products, nil, nil)
+	transaction := &entity.Transaction{ID: "tx-1", ProductID: "product-1"}
+
+	assert.True(t, errors.Is(handover.EnsureComplete(ctx, transaction), "BAD_REQUEST"))
+
+	// Other products have no handover to wait for
+	products.product.Type = "item"
+	assert.NoError(t, handover.EnsureComplete(ctx, transaction))
+}
+
+func TestResolvePolicyPrefersMostSpecificMatch(t *testing.T) {
+	ctx := context.Background()
+	policies := &policyRepo{policies: []*entity.AutoReleasePolicy{
+		{ID: "accounts", ProductType: "account", InspectionHours: 48, IsActive: true},
+		{ID: "accounts-high", ProductType: "account", SecurityLevel: "high", InspectionHours: 72, IsActive: true},
+		{ID: "big-accounts", ProductType: "account", MinAmount: 1000000, InspectionHours: 96, Priority: 1, IsActive: true},
+		{ID: "disabled", ProductType: "account", SecurityLevel: "high", GameTitleID: "game-1", InspectionHours: 120},
+	}}
+	products := &productRepo{product: &entity.Product{ID: "product-1", Type: "account", GameTitleID: "game-1"}}
+	policy := usecase.NewAutoReleasePolicyUseCase(policies, products)
+
+	resolved, err := policy.ResolvePolicy(ctx, &entity.Transaction{ProductID: "product-1", SecurityLevel: "high", Amount: 500000})
+	assert.NoError(t, err)
+	assert.Equal(t, "accounts-high", resolved.ID)
+
+	// Equally specific matches fall back to priority
+	resolved, err = policy.ResolvePolicy(ctx, &entity.Transaction{ProductID: "product-1", SecurityLevel: "high", Amount: 2000000})
+	assert.NoError(t, err)
+	assert.Equal(t, "big-accounts", resolved.ID)
+
+	products.product.Type = "item"
+	resolved, err = policy.ResolvePolicy(ctx, &entity.Transaction{ProductID: "product-1", Amount: 500000})
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.DefaultAutoReleasePolicy.ID, resolved.ID)
+
+	// Inactive policies still count, since transactions keep their policy
+	hours, err := policy.MaxWarningHours(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.DefaultAutoReleasePolicy.WarningHours, hours)
+
+	policies.policies[3].WarningHours = 12
+	hours, err = policy.MaxWarningHours(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, hours)
+}
+
+func TestEnsureFraudClearedBlocksHeldTransactions(t *testing.T) {
+	transaction := &entity.Transaction{}
+	assert.NoError(t, usecase.EnsureFraudCleared(transaction))
+
+	transaction.FraudReviewStatus = usecase.FraudReviewPending
+	assert.True(t, errors.Is(usecase.EnsureFraudCleared(transaction), "BAD_REQUEST"))
+
+	transaction.FraudReviewStatus = usecase.FraudReviewRejected
+	assert.True(t, errors.Is(usecase.EnsureFraudCleared(transaction), "BAD_REQUEST"))
+
+	transaction.FraudReviewStatus = usecase.FraudReviewApproved
+	assert.NoError(t, usecase.EnsureFraudCleared(transaction))
+}
+
+func TestAccountLinkage(t *testing.T) {
+	linkage := &usecase.AccountLinkage{
+		UserID: "seller-1",
+		LinkedAccounts: []*usecase.LinkedAccount{
+			{UserID: "buyer-1", SharedDeviceIDs: []string{"device-1"}},
+			{UserID: "buyer-2", SharedIPAddresses: []string{"10.0.0.1"}},
+		},
+	}
+
+	assert.True(t, linkage.IsLinkedTo("buyer-1"))
+	assert.True(t, linkage.IsLinkedTo("buyer-2"))
+	assert.False(t, linkage.IsLinkedTo("buyer-3"))
+	assert.Equal(t, 1, linkage.DeviceLinkedCount())
+}
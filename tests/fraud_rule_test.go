@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestDefaultFraudRulesScoreCheckoutFacts(t *testing.T) {
+	rules := make([]*entity.FraudRule, len(usecase.DefaultFraudRules))
+	for i := range usecase.DefaultFraudRules {
+		rule := usecase.DefaultFraudRules[i]
+		assert.NoError(t, usecase.ValidateFraudRule(&rule))
+		rules[i] = &rule
+	}
+
+	facts := usecase.FraudFacts{
+		"buyer.account_age_hours":      2.0,
+		"buyer.status":                 "active",
+		"buyer.recent_transactions_1h": 0.0,
+		"seller.account_age_hours":     1000.0,
+		"seller.review_count":          20.0,
+		"seller.rating":                4.8,
+		"seller.verification_status":   "verified",
+		"product.title":                "Akun Valorant Immortal",
+		"transaction.total_amount":     1500000.0,
+	}
+
+	result := usecase.EvaluateFraudRules(rules, facts)
+	assert.InDelta(t, 0.6, result.Score, 0.0001)
+	assert.ElementsMatch(t, []string{"new_buyer", "high_value", "high_risk_game"}, result.Flags)
+	assert.Equal(t, "review", result.Action)
+
+	// Disabled rules no longer contribute
+	for _, rule := range rules {
+		if rule.Flag == "high_value" {
+			rule.Enabled = false
+		}
+	}
+	result = usecase.EvaluateFraudRules(rules, facts)
+	assert.InDelta(t, 0.3, result.Score, 0.0001)
+	assert.Equal(t, "allow", result.Action)
+}
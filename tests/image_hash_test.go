@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/infrastructure/imagehash"
+)
+
+// screenshot draws a fake game screenshot: a gradient with a few blocks
+func screenshot(width, height int, inverted bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(255 * x / width)
+			if (x*4/width+y*3/height)%2 == 0 {
+				v = 255 - v
+			}
+			if inverted {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHashSurvivesResizeAndReencode(t *testing.T) {
+	var original bytes.Buffer
+	assert.NoError(t, png.Encode(&original, screenshot(640, 360, false)))
+
+	var copied bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&copied, screenshot(320, 180, false), &jpeg.Options{Quality: 60}))
+
+	originalHash, err := imagehash.Compute(&original)
+	assert.NoError(t, err)
+	copiedHash, err := imagehash.Compute(&copied)
+	assert.NoError(t, err)
+
+	assert.LessOrEqual(t, imagehash.Distance(originalHash, copiedHash), imagehash.MatchDistance)
+	assert.Greater(t, imagehash.Distance(originalHash, imagehash.FromImage(screenshot(640, 360, true))), imagehash.MatchDistance)
+
+	parsed, err := imagehash.Parse(imagehash.Format(originalHash))
+	assert.NoError(t, err)
+	assert.Equal(t, originalHash, parsed)
+
+	_, err = imagehash.Compute(bytes.NewReader([]byte("RIFF....WEBPVP8 ")))
+	assert.Error(t, err)
+}
+
+func TestPerceptualHashBandsFindNearMatches(t *testing.T) {
+	hash := uint64(0x9f3c_51a2_77e0_0c4d)
+	bands := imagehash.Bands(hash)
+	assert.Len(t, bands, len(imagehash.BandWidths))
+
+	// Flip MatchDistance bits spread across the hash: one band still agrees
+	near := hash ^ (1<<63 | 1<<50 | 1<<40 | 1<<25 | 1<<12)
+	shared := 0
+	for i, band := range imagehash.Bands(near) {
+		if band == bands[i] {
+			shared++
+		}
+	}
+	assert.Equal(t, 5, imagehash.Distance(hash, near))
+	assert.GreaterOrEqual(t, shared, 1)
+}
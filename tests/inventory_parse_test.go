@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/usecase"
+)
+
+func TestParseInventoryEntriesFromPastedList(t *testing.T) {
+	entries, err := usecase.ParseInventoryEntries("", "ABCD-EFGH-1234\r\n\n  WXYZ-0000-9876  \n")
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "WXYZ-0000-9876", entries[1].Fields["code"])
+	assert.Equal(t, "••••1234", entries[0].Hint)
+}
+
+func TestParseInventoryEntriesFromCSV(t *testing.T) {
+	content := "\ufeffEmail,Password\nfirst@example.com,secret1\n,\nsecond@example.com,secret2\n"
+
+	entries, err := usecase.ParseInventoryEntries("csv", content)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, map[string]interface{}{"email": "second@example.com", "password": "secret2"}, entries[1].Fields)
+}
+
+func TestParseInventoryEntriesRejectsBadCSV(t *testing.T) {
+	_, err := usecase.ParseInventoryEntries("csv", "code,code\nA,B\n")
+	assert.Error(t, err)
+
+	_, err = usecase.ParseInventoryEntries("csv", "email,password\nonly-one-column\n")
+	assert.Error(t, err)
+
+	_, err = usecase.ParseInventoryEntries("xlsx", "anything")
+	assert.Error(t, err)
+}
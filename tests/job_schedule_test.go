@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/usecase"
+)
+
+func TestLastDailyRunUsesTheScheduledTimeZone(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	// 20:00 UTC is 03:00 WIB, so today's 02:00 WIB run is already due
+	now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	assert.True(t, usecase.LastDailyRun(now, 2*time.Hour, wib).Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, wib)))
+
+	// 18:30 UTC is 01:30 WIB, before the run, so yesterday's run is the latest
+	now = time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	assert.True(t, usecase.LastDailyRun(now, 2*time.Hour, wib).Equal(time.Date(2026, 3, 1, 2, 0, 0, 0, wib)))
+
+	// Exactly at the scheduled time counts as due
+	now = time.Date(2026, 3, 1, 2, 0, 0, 0, wib)
+	assert.True(t, usecase.LastDailyRun(now, 2*time.Hour, wib).Equal(now))
+}
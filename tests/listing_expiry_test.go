@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestListingExpiryRemindsBeforeExpiring(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expireAfter := 30 * 24 * time.Hour
+	remindBefore := 3 * 24 * time.Hour
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	product := &entity.Product{Status: "active", CreatedAt: days(60), BumpedAt: days(26)}
+	assert.Equal(t, usecase.ListingExpiryNone, usecase.ListingExpiryAction(product, days(40), now, expireAfter, remindBefore))
+
+	// Long gone seller: reminded first, even past the expiry date
+	product.BumpedAt = days(45)
+	assert.Equal(t, usecase.ListingExpiryRemind, usecase.ListingExpiryAction(product, days(40), now, expireAfter, remindBefore))
+
+	reminded := days(1)
+	product.ExpiryRemindedAt = &reminded
+	assert.Equal(t, usecase.ListingExpiryNone, usecase.ListingExpiryAction(product, days(40), now, expireAfter, remindBefore))
+
+	reminded = days(3)
+	assert.Equal(t, usecase.ListingExpiryExpire, usecase.ListingExpiryAction(product, days(40), now, expireAfter, remindBefore))
+
+	// A visit after the reminder starts a new inactivity period
+	assert.Equal(t, usecase.ListingExpiryNone, usecase.ListingExpiryAction(product, days(2), now, expireAfter, remindBefore))
+
+	edited := days(1)
+	product.EditedAt = &edited
+	assert.Equal(t, usecase.ListingExpiryNone, usecase.ListingExpiryAction(product, days(40), now, expireAfter, remindBefore))
+
+	product.EditedAt = nil
+	product.Status = "draft"
+	assert.Equal(t, usecase.ListingExpiryNone, usecase.ListingExpiryAction(product, days(40), now, expireAfter, remindBefore))
+
+	product.Status = "active"
+	assert.Equal(t, usecase.ListingExpiryNone, usecase.ListingExpiryAction(product, days(40), now, 0, remindBefore))
+}
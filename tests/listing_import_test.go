@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/infrastructure/spreadsheet"
+	"pasargamex/internal/usecase"
+)
+
+func TestSpreadsheetRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"title", "price", "attr.Rank"},
+		{"Akun <Mythic> & skins", "150000", ""},
+		nil,
+		{"Second", "", "Epic"},
+	}
+
+	for _, format := range []string{spreadsheet.FormatCSV, spreadsheet.FormatXLSX} {
+		data, err := spreadsheet.Write(format, rows)
+		assert.NoError(t, err)
+
+		read, err := spreadsheet.Read(format, data)
+		assert.NoError(t, err, format)
+		assert.Len(t, read, 4, format)
+		assert.Equal(t, "Akun <Mythic> & skins", read[1][0], format)
+		assert.Equal(t, "Epic", read[3][2], format)
+		assert.Empty(t, read[2], format)
+	}
+
+	_, err := spreadsheet.Read(spreadsheet.FormatXLSX, []byte("not a zip"))
+	assert.Error(t, err)
+}
+
+func TestParseImportSheet(t *testing.T) {
+	rows, err := usecase.ParseImportSheet([][]string{
+		{"\ufeffTitle", "Price", "attr.Rank ", ""},
+		{" Akun Mythic ", "150000", "Mythic", "ignored"},
+		{"", "", ""},
+		{"Joki", "", ""},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, 2, rows[0].Row)
+	assert.Equal(t, map[string]string{"title": "Akun Mythic", "price": "150000", "attr.Rank": "Mythic"}, rows[0].Values)
+	assert.Equal(t, 4, rows[1].Row)
+
+	_, err = usecase.ParseImportSheet([][]string{{"title", "colour"}, {"A", "red"}})
+	assert.Error(t, err)
+
+	_, err = usecase.ParseImportSheet([][]string{{"title", "Title"}, {"A", "B"}})
+	assert.Error(t, err)
+}
+
+func TestBuildImportInputReportsRowErrors(t *testing.T) {
+	_, input, images, errs := usecase.BuildImportInput(&entity.ImportRow{Row: 7, Values: map[string]string{
+		"game_title_id":   "mlbb",
+		"title":           "Akun Mythic",
+		"type":            "Account",
+		"price":           "lots",
+		"delivery_method": "middleman",
+		"image_urls":      "https://cdn.example.com/a.png | ftp://x",
+		"attr.Rank":       "Mythic",
+	}})
+
+	assert.Equal(t, "account", input.Type)
+	assert.Equal(t, "active", input.Status)
+	assert.Equal(t, map[string]interface{}{"Rank": "Mythic"}, input.Attributes)
+	assert.Len(t, images, 1)
+	assert.Equal(t, []entity.ImportRowError{
+		{Row: 7, Field: "image_urls", Message: `"ftp://x" is not an image URL`},
+		{Row: 7, Field: "price", Message: "Price must be a number"},
+	}, errs)
+}
+
+func TestCatalogSheetImportsBack(t *testing.T) {
+	product := &entity.Product{
+		ID:             "p1",
+		GameTitleID:    "mlbb",
+		Title:          "Akun Mythic",
+		Type:           "account",
+		Status:         "active",
+		Price:          150000,
+		DeliveryMethod: "middleman",
+		Attributes:     map[string]interface{}{"Rank": "Mythic", "Heroes": []interface{}{"Alucard", "Layla"}},
+		Images: []entity.ProductImage{
+			{URL: "https://cdn.example.com/b.png", DisplayOrder: 1},
+			{URL: "https://cdn.example.com/a.png", DisplayOrder: 0},
+		},
+	}
+
+	sheet := usecase.CatalogSheet([]*entity.Product{product})
+	assert.Equal(t, append(append([]string{}, usecase.ImportColumns...), "attr.Heroes", "attr.Rank"), sheet[0])
+
+	rows, err := usecase.ParseImportSheet(sheet)
+	assert.NoError(t, err)
+
+	productID, input, images, errs := usecase.BuildImportInput(rows[0])
+	assert.Empty(t, errs)
+	assert.Equal(t, "p1", productID)
+	assert.Equal(t, 150000.0, input.Price)
+	assert.Equal(t, map[string]interface{}{"Rank": "Mythic", "Heroes": "Alucard, Layla"}, input.Attributes)
+	assert.Equal(t, "https://cdn.example.com/a.png", images[0].URL)
+	assert.Equal(t, "https://cdn.example.com/b.png", images[1].URL)
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/infrastructure/moderation"
+	"pasargamex/internal/usecase"
+)
+
+func TestRunListingChecks(t *testing.T) {
+	scanner := moderation.NewPatternScanner(nil)
+	banned := usecase.BannedWordsPattern(usecase.DefaultBannedListingWords)
+	schema := []entity.GameTitleAttribute{{Name: "server", Type: "text", Required: true}}
+
+	clean := &entity.Product{
+		Title:       "Mythic account, 120 skins",
+		Description: "All heroes unlocked, email can be changed",
+		Attributes:  map[string]interface{}{"server": "SEA"},
+	}
+	assert.Empty(t, usecase.RunListingChecks(context.Background(), scanner, banned, clean, schema))
+
+	flagged := &entity.Product{
+		Title:       "Account with AIMBOT unlocked",
+		Description: "Fast deal, chat me at wa.me/628123456789",
+	}
+	findings := usecase.RunListingChecks(context.Background(), scanner, banned, flagged, schema)
+
+	checks := map[string]bool{}
+	for _, finding := range findings {
+		checks[finding.Check] = true
+		assert.True(t, finding.Blocking, finding.Message)
+	}
+	assert.True(t, checks[usecase.ListingCheckBannedWord])
+	assert.True(t, checks[usecase.ListingCheckContactInfo])
+	assert.True(t, checks[usecase.ListingCheckMissingAttribute])
+}
+
+func TestPhoneNumberInListingIsFlaggedForAdmin(t *testing.T) {
+	product := &entity.Product{Title: "Starter account", Description: "Questions? 0812 3456 7890"}
+
+	findings := usecase.RunListingChecks(context.Background(), moderation.NewPatternScanner(nil), nil, product, nil)
+
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, usecase.ListingCheckContactInfo, findings[0].Check)
+		assert.False(t, findings[0].Blocking) // phone numbers only warn, so an admin decides
+	}
+}
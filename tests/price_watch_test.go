@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestPriceChangesRecordsListedAndVariantPrices(t *testing.T) {
+	product := &entity.Product{
+		ID: "p1",
+		Variants: []entity.ProductVariant{
+			{ID: "a", Price: 20000, Stock: 5},
+			{ID: "b", Price: 38000, Stock: 5},
+		},
+	}
+	product.SyncVariantTotals()
+	before := usecase.SnapshotPrices(product)
+
+	product.Variants[0].Price = 18000
+	product.SyncVariantTotals()
+
+	points := usecase.PriceChanges(before, product, time.Now())
+
+	if assert.Len(t, points, 2) {
+		assert.Equal(t, "", points[0].VariantID)
+		assert.Equal(t, 18000.0, points[0].Price)
+		assert.Equal(t, 20000.0, points[0].PreviousPrice)
+		assert.Equal(t, "a", points[1].VariantID)
+	}
+	assert.Empty(t, usecase.PriceChanges(usecase.SnapshotPrices(product), product, time.Now()))
+}
+
+func TestPriceDropReason(t *testing.T) {
+	item := &entity.WishlistItem{PriceAtAdd: 100000}
+
+	assert.Equal(t, "", usecase.PriceDropReason(item, 100000))
+	assert.Equal(t, usecase.PriceDropBelowAddedPrice, usecase.PriceDropReason(item, 90000))
+
+	item.TargetPrice = 80000
+	assert.Equal(t, usecase.PriceDropBelowAddedPrice, usecase.PriceDropReason(item, 90000))
+	assert.Equal(t, usecase.PriceDropTargetReached, usecase.PriceDropReason(item, 80000))
+
+	// Already alerted at 85000: only a new low alerts again
+	item.LastAlertedPrice = 85000
+	assert.Equal(t, "", usecase.PriceDropReason(item, 85000))
+	assert.Equal(t, usecase.PriceDropTargetReached, usecase.PriceDropReason(item, 80000))
+}
+
+func TestBuildPriceChartOpensWithPriceInEffect(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -30)
+	changed := now.AddDate(0, 0, -10)
+
+	chart := usecase.BuildPriceChart([]*entity.PricePoint{
+		{Price: 90000, PreviousPrice: 120000, ChangedAt: changed},
+	}, 90000, since, now)
+
+	assert.Equal(t, []usecase.PriceChartPoint{
+		{Date: since, Price: 120000},
+		{Date: changed, Price: 90000},
+		{Date: now, Price: 90000},
+	}, chart.Points)
+	assert.Equal(t, 90000.0, chart.LowestPrice)
+	assert.Equal(t, 120000.0, chart.HighestPrice)
+
+	// No changes in the window: a flat line at the current price
+	flat := usecase.BuildPriceChart(nil, 50000, since, now)
+	assert.Len(t, flat.Points, 2)
+	assert.Equal(t, 50000.0, flat.LowestPrice)
+}
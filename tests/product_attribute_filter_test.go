@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+var mobileLegendsSchema = []entity.GameTitleAttribute{
+	{Name: "rank", Type: "select", Options: []string{"Epic", "Legend", "Mythic"}},
+	{Name: "hero_count", Type: "number"},
+	{Name: "heroes", Type: "multiselect"},
+}
+
+func TestParseAttributeFilters(t *testing.T) {
+	filters, err := usecase.ParseAttributeFilters(map[string][]string{
+		"attr.rank":           {"Mythic,Legend"},
+		"attr.hero_count.min": {"50"},
+		"attr.heroes":         {"Fanny"},
+		"page":                {"2"},
+	}, mobileLegendsSchema)
+
+	assert.NoError(t, err)
+	if assert.Len(t, filters, 3) {
+		// Sorted by attribute name
+		assert.Equal(t, 50.0, *filters[0].Min)
+		assert.Nil(t, filters[0].Max)
+		assert.Equal(t, usecase.AttributeFilterContains, filters[1].Operator)
+		assert.Equal(t, usecase.AttributeFilterEquals, filters[2].Operator)
+		assert.Equal(t, []string{"Mythic", "Legend"}, filters[2].Values)
+	}
+
+	_, err = usecase.ParseAttributeFilters(map[string][]string{"attr.server": {"Asia"}}, mobileLegendsSchema)
+	assert.Error(t, err)
+
+	_, err = usecase.ParseAttributeFilters(map[string][]string{"attr.hero_count.min": {"many"}}, mobileLegendsSchema)
+	assert.Error(t, err)
+}
+
+func TestBuildAttributeFacets(t *testing.T) {
+	products := []map[string]interface{}{
+		{"rank": "Mythic", "hero_count": float64(80), "heroes": []interface{}{"Fanny", "Ling"}},
+		{"rank": "Mythic", "hero_count": "45", "heroes": []interface{}{"Ling"}},
+		{"rank": "Legend", "hero_count": float64(60)},
+	}
+
+	filters, err := usecase.ParseAttributeFilters(map[string][]string{
+		"attr.rank":           {"Mythic"},
+		"attr.hero_count.min": {"50"},
+	}, mobileLegendsSchema)
+	assert.NoError(t, err)
+
+	assert.True(t, usecase.MatchesAttributeFilters(products[0], filters, ""))
+	assert.False(t, usecase.MatchesAttributeFilters(products[1], filters, ""))
+	assert.False(t, usecase.MatchesAttributeFilters(products[2], filters, ""))
+
+	facets := usecase.BuildAttributeFacets(mobileLegendsSchema, products, filters)
+	assert.Len(t, facets, 3)
+
+	// Rank counts ignore the rank filter but respect hero_count >= 50
+	assert.Equal(t, []usecase.FacetValue{{Value: "Legend", Count: 1}, {Value: "Mythic", Count: 1}, {Value: "Epic", Count: 0}}, facets[0].Values)
+
+	// Hero count range ignores its own filter but respects rank = Mythic
+	assert.Equal(t, 45.0, *facets[1].Min)
+	assert.Equal(t, 80.0, *facets[1].Max)
+
+	assert.Equal(t, []usecase.FacetValue{{Value: "Fanny", Count: 1}, {Value: "Ling", Count: 1}}, facets[2].Values)
+}
+
+func TestValidateProductAttributes(t *testing.T) {
+	schema := append([]entity.GameTitleAttribute{
+		{Name: "server", Type: "text", Required: true},
+		{Name: "verified_email", Type: "boolean"},
+	}, mobileLegendsSchema...)
+	schema[2].Required = true // rank
+
+	coerced, violations := usecase.ValidateProductAttributes(schema, map[string]interface{}{
+		"server":         "Asia",
+		"rank":           "mythic",
+		"hero_count":     "112",
+		"heroes":         "fanny, Ling",
+		"verified_email": "ya",
+	})
+	assert.Empty(t, violations)
+	assert.Equal(t, "Mythic", coerced["rank"])
+	assert.Equal(t, 112.0, coerced["hero_count"])
+	assert.Equal(t, []interface{}{"fanny", "Ling"}, coerced["heroes"])
+	assert.Equal(t, true, coerced["verified_email"])
+
+	_, violations = usecase.ValidateProductAttributes(schema, map[string]interface{}{
+		"rank":       "Mytic",
+		"hero_count": "banyak",
+		"skinz":      10,
+	})
+	fields := []string{}
+	for _, violation := range violations {
+		fields = append(fields, violation.Field)
+	}
+	assert.Equal(t, []string{"hero_count", "rank", "server", "skinz"}, fields)
+}
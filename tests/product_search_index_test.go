@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/domain/service"
+	"pasargamex/internal/infrastructure/search"
+)
+
+func searchIDs(t *testing.T, index *search.InvertedIndex, query service.ProductSearchQuery) []string {
+	result, err := index.Search(context.Background(), query)
+	assert.NoError(t, err)
+
+	ids := []string{}
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ProductID)
+	}
+	return ids
+}
+
+func TestInvertedIndexMatching(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	index := search.NewInvertedIndex()
+
+	assert.NoError(t, index.Rebuild(ctx, []*entity.Product{
+		{ID: "ml", Title: "Akun Mobile Legends Mythic", Description: "Banyak skinnya, emblem max", Status: "active", BumpedAt: now},
+		{ID: "genshin", Title: "Genshin Impact AR 58", Description: "Raiden and Nahida", Status: "active", BumpedAt: now},
+		{ID: "sold", Title: "Mobile Legends Legend", Status: "sold", BumpedAt: now},
+	}))
+
+	// Prefix, typo and Indonesian possessive suffix
+	assert.Equal(t, []string{"ml"}, searchIDs(t, index, service.ProductSearchQuery{Text: "myth", Status: "active"}))
+	assert.Equal(t, []string{"genshin"}, searchIDs(t, index, service.ProductSearchQuery{Text: "gensin", Status: "active"}))
+	assert.Equal(t, []string{"ml"}, searchIDs(t, index, service.ProductSearchQuery{Text: "skin", Status: "active"}))
+	assert.ElementsMatch(t, []string{"ml", "sold"}, searchIDs(t, index, service.ProductSearchQuery{Text: "mobile legends"}))
+
+	assert.NoError(t, index.Remove(ctx, "ml"))
+	assert.Empty(t, searchIDs(t, index, service.ProductSearchQuery{Text: "mythic"}))
+}
+
+func TestInvertedIndexRanking(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	index := search.NewInvertedIndex()
+
+	assert.NoError(t, index.Index(ctx, &entity.Product{ID: "title", Title: "Valorant Radiant", Description: "Immortal skins", BumpedAt: now.Add(-72 * time.Hour)}))
+	assert.NoError(t, index.Index(ctx, &entity.Product{ID: "description", Title: "Akun FPS", Description: "Pernah radiant", BumpedAt: now.Add(-72 * time.Hour)}))
+
+	// A title match outranks a description match
+	assert.Equal(t, []string{"title", "description"}, searchIDs(t, index, service.ProductSearchQuery{Text: "radiant"}))
+
+	// Featured and freshly bumped listings move up among equally relevant hits
+	assert.NoError(t, index.Index(ctx, &entity.Product{ID: "featured", Title: "Valorant Radiant", Description: "Immortal skins", Featured: true, BumpedAt: now}))
+	ids := searchIDs(t, index, service.ProductSearchQuery{Text: "valorant radiant", Limit: 1})
+	assert.Equal(t, []string{"featured"}, ids)
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestSyncVariantTotalsUsesCheapestAvailableVariant(t *testing.T) {
+	product := &entity.Product{
+		Variants: []entity.ProductVariant{
+			{ID: "a", Label: "86 Diamonds", Price: 20000, Stock: 10, SoldCount: 10},
+			{ID: "b", Label: "172 Diamonds", Price: 38000, Stock: 5, SoldCount: 1},
+			{ID: "c", Label: "257 Diamonds", Price: 56000, Stock: 5},
+		},
+	}
+
+	product.SyncVariantTotals()
+
+	assert.Equal(t, 38000.0, product.Price) // the sold-out 86 Diamonds no longer sets "from"
+	assert.Equal(t, 56000.0, product.PriceMax)
+	assert.Equal(t, 20, product.Stock)
+	assert.Equal(t, 11, product.SoldCount)
+}
+
+func TestBuildProductVariantsKeepsSoldCount(t *testing.T) {
+	schema := []entity.GameTitleAttribute{
+		{Name: "diamonds", Type: "number", Required: true},
+		{Name: "server", Type: "text", Required: true},
+	}
+	existing := []entity.ProductVariant{{ID: "a", Label: "86 Diamonds", Price: 20000, Stock: 10, SoldCount: 4}}
+
+	variants, violations := usecase.BuildProductVariants(schema, existing, []usecase.ProductVariantInput{
+		{ID: "a", Label: "86 Diamonds", Price: 19000, Stock: 20, Attributes: map[string]interface{}{"diamonds": "86"}},
+		{Label: "172 Diamonds", Price: 38000, Stock: 5},
+	})
+
+	assert.Empty(t, violations)
+	assert.Len(t, variants, 2)
+	assert.Equal(t, 4, variants[0].SoldCount)
+	assert.Equal(t, 86.0, variants[0].Attributes["diamonds"])
+	assert.NotEmpty(t, variants[1].ID)
+}
+
+func TestBuildProductVariantsRejectsInvalidInput(t *testing.T) {
+	existing := []entity.ProductVariant{{ID: "a", Label: "86 Diamonds", Price: 20000, Stock: 10, SoldCount: 4}}
+
+	_, violations := usecase.BuildProductVariants(nil, existing, []usecase.ProductVariantInput{
+		{ID: "a", Label: "86 Diamonds", Price: 20000, Stock: 3},
+		{Label: "86 diamonds", Price: 0},
+	})
+
+	fields := []string{}
+	for _, violation := range violations {
+		fields = append(fields, violation.Field)
+	}
+	assert.ElementsMatch(t, []string{"variants[0].stock", "variants[1].label", "variants[1].price"}, fields)
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestAutoBumpPromotionSchedule(t *testing.T) {
+	start := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	product := &entity.Product{ID: "p1", SellerID: "s1", Status: "active"}
+
+	promotion := usecase.NewPromotion(usecase.PromotionPackageByID("auto_bump_6h_3d"), product, start)
+	usecase.ApplyPromotion(product, promotion, start)
+
+	assert.Equal(t, start, product.BumpedAt)
+	assert.Equal(t, start.Add(6*time.Hour), *promotion.NextBumpAt)
+	assert.True(t, product.IsPromoted(start.Add(48*time.Hour)))
+
+	// Not due yet
+	assert.False(t, usecase.AdvancePromotion(promotion, product, start.Add(5*time.Hour)))
+
+	// The scheduler was down for two intervals: bump once and skip ahead
+	late := start.Add(19 * time.Hour)
+	assert.True(t, usecase.AdvancePromotion(promotion, product, late))
+	assert.Equal(t, late, product.BumpedAt)
+	assert.Equal(t, 2, promotion.BumpCount)
+	assert.Equal(t, start.Add(24*time.Hour), *promotion.NextBumpAt)
+
+	end := start.AddDate(0, 0, 3)
+	usecase.AdvancePromotion(promotion, product, end)
+	assert.Equal(t, usecase.PromotionStatusCompleted, promotion.Status)
+	assert.Nil(t, promotion.NextBumpAt)
+	assert.False(t, product.IsPromoted(end))
+}
+
+func TestFeaturedPromotionExpires(t *testing.T) {
+	start := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	product := &entity.Product{ID: "p1", SellerID: "s1", Status: "active"}
+
+	promotion := usecase.NewPromotion(usecase.PromotionPackageByID("featured_3d"), product, start)
+	usecase.ApplyPromotion(product, promotion, start)
+	assert.True(t, product.Featured)
+
+	assert.False(t, usecase.AdvancePromotion(promotion, product, start.Add(time.Hour)))
+	assert.True(t, usecase.AdvancePromotion(promotion, product, start.AddDate(0, 0, 3)))
+	assert.False(t, product.Featured)
+	assert.Nil(t, product.FeaturedUntil)
+}
+
+func TestSummarizePromotionStats(t *testing.T) {
+	report := usecase.SummarizePromotionStats(&entity.Promotion{}, []*entity.ProductDailyStats{
+		{Date: "2026-05-01", Impressions: 300, Clicks: 12},
+		{Date: "2026-05-02", Impressions: 100, Clicks: 4},
+	})
+
+	assert.Equal(t, int64(400), report.Impressions)
+	assert.Equal(t, int64(16), report.Clicks)
+	assert.InDelta(t, 0.04, report.ClickThroughRate, 1e-9)
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestCoOccurrenceScoresSharedUsers(t *testing.T) {
+	baskets := usecase.RecommendationBaskets{}
+	baskets.Add("alice", "a", usecase.RecommendationViewWeight)
+	baskets.Add("alice", "b", usecase.RecommendationPurchaseWeight)
+	baskets.Add("bob", "a", usecase.RecommendationViewWeight)
+	baskets.Add("bob", "b", usecase.RecommendationViewWeight)
+	baskets.Add("bob", "c", usecase.RecommendationViewWeight)
+	baskets.Add("carol", "a", usecase.RecommendationViewWeight)
+	baskets.Add("carol", "b", usecase.RecommendationViewWeight)
+	// A repeated weaker signal does not lower the strongest one
+	baskets.Add("alice", "b", usecase.RecommendationViewWeight)
+	assert.Equal(t, usecase.RecommendationPurchaseWeight, baskets["alice"]["b"])
+
+	scores := usecase.CoOccurrence(baskets)
+	assert.Greater(t, scores["a"]["b"], scores["a"]["c"])
+	assert.InDelta(t, scores["a"]["c"], scores["c"]["a"], 1e-9)
+	assert.NotContains(t, scores["a"], "a")
+}
+
+func TestAttributeSimilarityStaysWithinGame(t *testing.T) {
+	base := &entity.Product{GameTitleID: "ml", Type: "account", Price: 100000,
+		Attributes: map[string]interface{}{"rank": "Mythic", "server": "Asia"}}
+	close := &entity.Product{GameTitleID: "ml", Type: "account", Price: 90000,
+		Attributes: map[string]interface{}{"Rank": "mythic", "server": "Asia"}}
+	far := &entity.Product{GameTitleID: "ml", Type: "topup", Price: 10000}
+	otherGame := &entity.Product{GameTitleID: "ff", Type: "account", Price: 100000,
+		Attributes: map[string]interface{}{"rank": "Mythic", "server": "Asia"}}
+
+	assert.InDelta(t, 0.98, usecase.AttributeSimilarity(base, close), 1e-9)
+	assert.Greater(t, usecase.AttributeSimilarity(base, close), usecase.AttributeSimilarity(base, far))
+	assert.Equal(t, 0.0, usecase.AttributeSimilarity(base, otherGame))
+
+	base.ID, close.ID, far.ID, otherGame.ID = "base", "close", "far", "other"
+	similar := usecase.SimilarProducts([]*entity.Product{base, close, far, otherGame}, 1)
+	assert.Equal(t, []entity.RecommendedProduct{{ProductID: "close", Score: usecase.AttributeSimilarity(base, close)}}, similar["base"])
+	assert.Empty(t, similar["other"])
+}
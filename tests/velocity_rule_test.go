@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pasargamex/internal/domain/entity"
+	"pasargamex/internal/usecase"
+)
+
+func TestEvaluateVelocityRuleCount(t *testing.T) {
+	now := time.Now()
+	rule := &entity.VelocityRule{Name: "Withdrawals per day", Metric: "count", Limit: 2, WindowHours: 24}
+
+	events := []*entity.VelocityEvent{
+		{CreatedAt: now.Add(-30 * time.Hour)}, // outside the window
+		{CreatedAt: now.Add(-20 * time.Hour)},
+		{CreatedAt: now.Add(-1 * time.Hour)},
+	}
+
+	violation := usecase.EvaluateVelocityRule(rule, events, 0, now)
+	if assert.NotNil(t, violation) {
+		assert.Equal(t, float64(2), violation.Used)
+		assert.Equal(t, now.Add(4*time.Hour), violation.RetryAt)
+		assert.Equal(t, 4*3600, violation.RetryAfterSeconds)
+	}
+
+	assert.Nil(t, usecase.EvaluateVelocityRule(rule, events[:2], 0, now))
+}
+
+func TestEvaluateVelocityRuleAmount(t *testing.T) {
+	now := time.Now()
+	rule := &entity.VelocityRule{Name: "Purchase amount per day", Metric: "amount", Limit: 1000, WindowHours: 24}
+
+	events := []*entity.VelocityEvent{
+		{Amount: 300, CreatedAt: now.Add(-10 * time.Hour)},
+		{Amount: 500, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	assert.Nil(t, usecase.EvaluateVelocityRule(rule, events, 200, now))
+
+	// 800 used + 400 needs the 300 to expire first
+	violation := usecase.EvaluateVelocityRule(rule, events, 400, now)
+	if assert.NotNil(t, violation) {
+		assert.Equal(t, now.Add(14*time.Hour), violation.RetryAt)
+	}
+
+	// 600 fits only once both events have expired
+	violation = usecase.EvaluateVelocityRule(rule, events, 600, now)
+	if assert.NotNil(t, violation) {
+		assert.Equal(t, now.Add(22*time.Hour), violation.RetryAt)
+	}
+
+	// A single purchase above the limit never clears
+	violation = usecase.EvaluateVelocityRule(rule, nil, 1500, now)
+	if assert.NotNil(t, violation) {
+		assert.True(t, violation.RetryAt.IsZero())
+	}
+}